@@ -0,0 +1,259 @@
+// Command mock-idp runs a minimal OpenID Connect provider for trying out and
+// testing single sign-on locally. It signs every user in without a password:
+// the identity comes from the login_hint parameter or the -email flag.
+//
+// Configure an organization with issuer http://localhost:9090, client ID
+// openmind and client secret secret (the defaults) to use it.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	keyID        = "mock-idp"
+	codeTTL      = time.Minute
+	idTokenTTL   = 5 * time.Minute
+	rsaKeyBits   = 2048
+	codeByteSize = 24
+)
+
+type pendingCode struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	email         string
+	expiresAt     time.Time
+}
+
+type provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	name         string
+	groups       []string
+	email        string
+	key          *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]pendingCode
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:9090", "listen address")
+	issuer := flag.String("issuer", "", "issuer URL (default http://<addr>)")
+	clientID := flag.String("client-id", "openmind", "accepted client ID")
+	clientSecret := flag.String("client-secret", "secret", "accepted client secret")
+	email := flag.String("email", "clinician@example.com", "email of the signed-in user when no login_hint is given")
+	name := flag.String("name", "Mock Clinician", "display name of the signed-in user")
+	groups := flag.String("groups", "clinicians", "comma-separated groups claim")
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	if err != nil {
+		log.Fatalf("failed to generate signing key: %v", err)
+	}
+
+	p := &provider{
+		issuer:       strings.TrimRight(*issuer, "/"),
+		clientID:     *clientID,
+		clientSecret: *clientSecret,
+		name:         *name,
+		email:        *email,
+		key:          key,
+		codes:        make(map[string]pendingCode),
+	}
+	if p.issuer == "" {
+		p.issuer = "http://" + *addr
+	}
+	for _, g := range strings.Split(*groups, ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			p.groups = append(p.groups, g)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+	mux.HandleFunc("/jwks", p.jwks)
+
+	log.Printf("mock OpenID provider listening on %s (issuer %s)", *addr, p.issuer)
+	server := &http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	log.Fatal(server.ListenAndServe())
+}
+
+func (p *provider) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+	})
+}
+
+// authorize approves every request straight away and redirects back with a code
+func (p *provider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI := q.Get("redirect_uri")
+	target, err := url.Parse(redirectURI)
+	if err != nil || redirectURI == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if q.Get("response_type") != "code" || q.Get("client_id") != p.clientID {
+		http.Error(w, "unsupported response_type or unknown client_id", http.StatusBadRequest)
+		return
+	}
+	if q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "PKCE with S256 is required", http.StatusBadRequest)
+		return
+	}
+
+	email := q.Get("login_hint")
+	if email == "" {
+		email = p.email
+	}
+
+	b := make([]byte, codeByteSize)
+	if _, err := rand.Read(b); err != nil {
+		http.Error(w, "failed to issue code", http.StatusInternalServerError)
+		return
+	}
+	code := base64.RawURLEncoding.EncodeToString(b)
+
+	p.mu.Lock()
+	p.codes[code] = pendingCode{
+		clientID:      p.clientID,
+		redirectURI:   redirectURI,
+		codeChallenge: q.Get("code_challenge"),
+		nonce:         q.Get("nonce"),
+		email:         strings.ToLower(email),
+		expiresAt:     time.Now().Add(codeTTL),
+	}
+	p.mu.Unlock()
+
+	params := target.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	target.RawQuery = params.Encode()
+
+	log.Printf("authorized %s", email)
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (p *provider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != p.clientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(p.clientSecret)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	pending, found := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	if r.PostForm.Get("grant_type") != "authorization_code" || !found || time.Now().After(pending.expiresAt) ||
+		pending.redirectURI != r.PostForm.Get("redirect_uri") {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != pending.codeChallenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	subject := sha256.Sum256([]byte(pending.email))
+	claims := jwt.MapClaims{
+		"iss":            p.issuer,
+		"sub":            hex.EncodeToString(subject[:16]),
+		"aud":            pending.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(idTokenTTL).Unix(),
+		"nonce":          pending.nonce,
+		"email":          pending.email,
+		"email_verified": true,
+		"name":           p.name,
+		"groups":         p.groups,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	idToken, err := token.SignedString(p.key)
+	if err != nil {
+		http.Error(w, "failed to sign ID token", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": idToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(idTokenTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+func (p *provider) jwks(w http.ResponseWriter, _ *http.Request) {
+	pub := p.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": keyID,
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -31,11 +31,16 @@ import (
 	patientHandler "github.com/sahabatharianmu/OpenMind/internal/modules/patient/handler"
 	patientRepository "github.com/sahabatharianmu/OpenMind/internal/modules/patient/repository"
 	patientService "github.com/sahabatharianmu/OpenMind/internal/modules/patient/service"
+	sessionHandler "github.com/sahabatharianmu/OpenMind/internal/modules/session/handler"
+	sessionRepository "github.com/sahabatharianmu/OpenMind/internal/modules/session/repository"
+	sessionService "github.com/sahabatharianmu/OpenMind/internal/modules/session/service"
 	userHandler "github.com/sahabatharianmu/OpenMind/internal/modules/user/handler"
 	userRepository "github.com/sahabatharianmu/OpenMind/internal/modules/user/repository"
 	userService "github.com/sahabatharianmu/OpenMind/internal/modules/user/service"
 	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
 	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/mail"
+	"github.com/sahabatharianmu/OpenMind/pkg/oidc"
 	"github.com/sahabatharianmu/OpenMind/pkg/security"
 	"go.uber.org/zap"
 )
@@ -61,22 +66,158 @@ func main() {
 	}
 
 	userRepo := userRepository.NewUserRepository(db, appLogger)
-	patientRepo := patientRepository.NewPatientRepository(db, appLogger)
+	refreshTokenRepo := userRepository.NewRefreshTokenRepository(db, appLogger)
+	mfaRecoveryCodeRepo := userRepository.NewMFARecoveryCodeRepository(db, appLogger)
+	identityRepo := userRepository.NewIdentityRepository(db, appLogger)
+	userTokenRepo := userRepository.NewUserTokenRepository(db, appLogger)
+	loginThrottleRepo := userRepository.NewLoginThrottleRepository(db, appLogger)
+	loginIPRepo := userRepository.NewLoginIPRepository(db, appLogger)
+	userAdminRepo := userRepository.NewAdminRepository(db, appLogger)
+	impersonationRepo := userRepository.NewImpersonationRepository(db, appLogger)
+	passwordHistoryRepo := userRepository.NewPasswordHistoryRepository(db, appLogger)
+	careTeamRepo := patientRepository.NewCareTeamRepository(db, appLogger)
 	appointmentRepo := repository.NewAppointmentRepository(db, appLogger)
 	clinicalNoteRepo := clinicalNoteRepository.NewClinicalNoteRepository(db, appLogger)
+	keyRotationRepo := clinicalNoteRepository.NewKeyRotationRepository(db, appLogger)
+	noteSearchRepo := clinicalNoteRepository.NewSearchRepository(db, appLogger)
+	signingKeyRepo := clinicalNoteRepository.NewSigningKeyRepository(db, appLogger)
 	invoiceRepo := invoiceRepository.NewInvoiceRepository(db, appLogger)
 	auditLogRepo := auditLogRepository.NewAuditLogRepository(db, appLogger)
 	organizationRepo := organizationRepository.NewOrganizationRepository(db, appLogger)
+	invitationRepo := organizationRepository.NewInvitationRepository(db, appLogger)
+	roleRepo := organizationRepository.NewRoleRepository(db, appLogger)
+	apiKeyRepo := organizationRepository.NewAPIKeyRepository(db, appLogger)
+	ssoConfigRepo := organizationRepository.NewSSOConfigRepository(db, appLogger)
+	breachedPasswordRepo := organizationRepository.NewBreachedPasswordRepository(db, appLogger)
+	dataKeyRepo := organizationRepository.NewDataKeyRepository(db, appLogger)
+	supervisionRepo := organizationRepository.NewSupervisionRepository(db, appLogger)
+	sessionRepo := sessionRepository.NewSessionRepository(db, appLogger)
 
 	jwtService := security.NewJWTService(cfg)
 	passwordService := crypto.NewPasswordService(cfg)
-	encryptService := crypto.NewEncryptionService(cfg)
+	encryptService, err := crypto.NewEncryptionService(cfg)
+	if err != nil {
+		appLogger.Fatal("Invalid encryption keyring", zap.Error(err))
+	}
+	keyProvider, err := crypto.NewKeyProvider(cfg)
+	if err != nil {
+		appLogger.Fatal("Invalid key provider configuration", zap.Error(err))
+	}
+	if cfg.Security.KeyProvider == crypto.KeyProviderFake {
+		appLogger.Warn("Using the fake key provider; encrypted data will be unreadable after a restart")
+	}
+	mailer := mail.NewSender(cfg, appLogger)
 
-	authService := userService.NewAuthService(userRepo, jwtService, passwordService, appLogger)
+	roleSvc := organizationService.NewRoleService(roleRepo, appLogger)
+	sessionSvc := sessionService.NewSessionService(
+		sessionRepo,
+		organizationRepo,
+		roleSvc,
+		cfg.Security.SessionTimeout,
+		appLogger,
+	)
+	mfaSvc := userService.NewMFAService(
+		userRepo,
+		mfaRecoveryCodeRepo,
+		encryptService,
+		passwordService,
+		cfg.Application.Name,
+		appLogger,
+	)
+	passwordPolicySvc := userService.NewPasswordPolicyService(
+		userRepo,
+		passwordHistoryRepo,
+		breachedPasswordRepo,
+		passwordService,
+		cfg.Security.PasswordHistoryCount,
+		cfg.Security.PasswordCheckBreached,
+		appLogger,
+	)
+	accountSvc := userService.NewAccountService(
+		userRepo,
+		userTokenRepo,
+		sessionSvc,
+		passwordPolicySvc,
+		mailer,
+		cfg.Application.BaseURL,
+		cfg.Security.PasswordResetExpiry,
+		cfg.Security.EmailVerificationExpiry,
+		appLogger,
+	)
+	auditLogSvc := auditLogService.NewAuditLogService(auditLogRepo, appLogger)
+	dataKeySvc := organizationService.NewDataKeyService(
+		dataKeyRepo,
+		organizationRepo,
+		keyProvider,
+		encryptService,
+		auditLogSvc,
+		appLogger,
+	)
+	masterKeyRotationSvc := organizationService.NewMasterKeyRotationService(dataKeyRepo, keyProvider, appLogger)
+	patientRepo := patientRepository.NewPatientRepository(db, dataKeySvc, appLogger)
+	breakGlassRepo := patientRepository.NewBreakGlassRepository(db, dataKeySvc, appLogger)
+	loginThrottleSvc := userService.NewLoginThrottleService(
+		loginThrottleRepo,
+		userRepo,
+		organizationRepo,
+		auditLogSvc,
+		userService.LoginThrottleOptions{
+			FailureWindow:      cfg.Security.LoginFailureWindow,
+			DelayThreshold:     cfg.Security.LoginDelayThreshold,
+			IPDelayThreshold:   cfg.Security.LoginIPDelayThreshold,
+			MaxDelay:           cfg.Security.LoginMaxDelay,
+			LockoutThreshold:   cfg.Security.LoginLockoutThreshold,
+			IPLockoutThreshold: cfg.Security.LoginIPLockoutThreshold,
+			LockoutDuration:    cfg.Security.LoginLockoutDuration,
+			ChallengeAttempts:  cfg.Security.MFAChallengeAttempts,
+		},
+		appLogger,
+	)
+	loginAlertSvc := userService.NewLoginAlertService(loginIPRepo, organizationRepo, auditLogSvc, mailer, appLogger)
+	authService := userService.NewAuthService(
+		userRepo,
+		refreshTokenRepo,
+		invitationRepo,
+		sessionSvc,
+		mfaSvc,
+		accountSvc,
+		loginThrottleSvc,
+		passwordPolicySvc,
+		loginAlertSvc,
+		jwtService,
+		passwordService,
+		cfg.Security.RequireEmailVerification,
+		appLogger,
+	)
 	userSvc := userService.NewUserService(userRepo, appLogger)
-	patientSvc := patientService.NewPatientService(patientRepo, appLogger)
-	appointmentSvc := service.NewAppointmentService(appointmentRepo, appLogger)
-	clinicalNoteSvc := clinicalNoteService.NewClinicalNoteService(clinicalNoteRepo, encryptService, appLogger)
+	userAdminSvc := userService.NewUserAdminService(
+		userRepo,
+		userAdminRepo,
+		organizationRepo,
+		roleSvc,
+		sessionSvc,
+		accountSvc,
+		auditLogSvc,
+		appLogger,
+	)
+	patientSvc := patientService.NewPatientService(patientRepo, careTeamRepo, appLogger)
+	demographicsMigrationSvc := patientService.NewDemographicsMigrationService(patientRepo, appLogger)
+	if err := demographicsMigrationSvc.Run(context.Background()); err != nil {
+		appLogger.Fatal("Failed to encrypt patient demographics", zap.Error(err))
+	}
+	appointmentSvc := service.NewAppointmentService(appointmentRepo, careTeamRepo, appLogger)
+	signingSvc := clinicalNoteService.NewSigningService(signingKeyRepo, dataKeySvc, appLogger)
+	clinicalNoteSvc := clinicalNoteService.NewClinicalNoteService(
+		clinicalNoteRepo,
+		noteSearchRepo,
+		careTeamRepo,
+		supervisionRepo,
+		dataKeySvc,
+		signingSvc,
+		appLogger,
+	)
+	keyRotationSvc := clinicalNoteService.NewKeyRotationService(keyRotationRepo, dataKeySvc, appLogger)
+	noteSearchIndexSvc := clinicalNoteService.NewSearchIndexService(noteSearchRepo, dataKeySvc, appLogger)
 	invoiceSvc := invoiceService.NewInvoiceService(
 		invoiceRepo,
 		organizationRepo,
@@ -85,8 +226,69 @@ func main() {
 		clinicalNoteRepo,
 		appLogger,
 	)
-	auditLogSvc := auditLogService.NewAuditLogService(auditLogRepo, appLogger)
-	organizationSvc := organizationService.NewOrganizationService(organizationRepo, appLogger)
+	careTeamSvc := patientService.NewCareTeamService(
+		patientRepo,
+		careTeamRepo,
+		organizationRepo,
+		auditLogSvc,
+		appLogger,
+	)
+	breakGlassSvc := patientService.NewBreakGlassService(
+		patientRepo,
+		careTeamRepo,
+		breakGlassRepo,
+		organizationRepo,
+		auditLogSvc,
+		mailer,
+		cfg.Security.BreakGlassDuration,
+		cfg.Security.BreakGlassNotifyClinician,
+		cfg.Application.BaseURL,
+		appLogger,
+	)
+	organizationSvc := organizationService.NewOrganizationService(organizationRepo, roleSvc, sessionSvc, appLogger)
+	invitationSvc := organizationService.NewInvitationService(
+		invitationRepo,
+		organizationRepo,
+		roleSvc,
+		userRepo,
+		mailer,
+		cfg.Application.BaseURL,
+		appLogger,
+	)
+	breachedPasswordSvc := organizationService.NewBreachedPasswordService(breachedPasswordRepo, appLogger)
+	apiKeySvc := organizationService.NewAPIKeyService(apiKeyRepo, organizationRepo, roleSvc, appLogger)
+	supervisionSvc := organizationService.NewSupervisionService(supervisionRepo, organizationRepo, appLogger)
+	ipAccessSvc := organizationService.NewIPAccessService(organizationRepo, roleSvc, auditLogSvc, appLogger)
+	impersonationSvc := userService.NewImpersonationService(
+		impersonationRepo,
+		userRepo,
+		organizationRepo,
+		roleSvc,
+		sessionSvc,
+		auditLogSvc,
+		jwtService,
+		cfg.Security.ImpersonationDuration,
+		appLogger,
+	)
+	ssoConfigSvc := organizationService.NewSSOConfigService(
+		ssoConfigRepo,
+		roleSvc,
+		dataKeySvc,
+		cfg.Application.BaseURL,
+		appLogger,
+	)
+	ssoSvc := userService.NewSSOService(
+		userRepo,
+		identityRepo,
+		ssoConfigRepo,
+		organizationRepo,
+		roleSvc,
+		authService,
+		oidc.NewClient(),
+		dataKeySvc,
+		cfg.Application.BaseURL,
+		appLogger,
+	)
 	exportSvc := exportService.NewExportService(
 		organizationRepo,
 		patientRepo,
@@ -98,28 +300,55 @@ func main() {
 	)
 	importSvc := importService.NewImportService(
 		patientRepo,
+		careTeamRepo,
 		clinicalNoteRepo,
 		patientSvc,
 		clinicalNoteSvc,
-		encryptService,
+		dataKeySvc,
 		db,
 		appLogger,
 	)
 
 	authHandler := userHandler.NewAuthHandler(authService)
 	userHdlr := userHandler.NewUserHandler(userSvc, authService)
+	mfaHdlr := userHandler.NewMFAHandler(mfaSvc)
+	ssoHdlr := userHandler.NewSSOHandler(ssoSvc)
+	accountHdlr := userHandler.NewAccountHandler(accountSvc)
+	lockoutHdlr := userHandler.NewLockoutHandler(loginThrottleSvc)
+	userAdminHdlr := userHandler.NewUserAdminHandler(userAdminSvc)
+	impersonationHdlr := userHandler.NewImpersonationHandler(impersonationSvc)
 	patientHdlr := patientHandler.NewPatientHandler(patientSvc)
+	careTeamHdlr := patientHandler.NewCareTeamHandler(careTeamSvc)
+	breakGlassHdlr := patientHandler.NewBreakGlassHandler(breakGlassSvc)
 	appointmentHdlr := handler.NewAppointmentHandler(appointmentSvc)
 	clinicalNoteHdlr := clinicalNoteHandler.NewClinicalNoteHandler(clinicalNoteSvc)
 	invoiceHdlr := invoiceHandler.NewInvoiceHandler(invoiceSvc)
 	auditLogHdlr := auditLogHandler.NewAuditLogHandler(auditLogSvc)
 	organizationHdlr := organizationHandler.NewOrganizationHandler(organizationSvc)
+	invitationHdlr := organizationHandler.NewInvitationHandler(invitationSvc)
+	roleHdlr := organizationHandler.NewRoleHandler(roleSvc)
+	apiKeyHdlr := organizationHandler.NewAPIKeyHandler(apiKeySvc)
+	ssoConfigHdlr := organizationHandler.NewSSOConfigHandler(ssoConfigSvc)
+	breachedPasswordHdlr := organizationHandler.NewBreachedPasswordHandler(breachedPasswordSvc)
+	dataKeyHdlr := organizationHandler.NewDataKeyHandler(dataKeySvc)
+	supervisionHdlr := organizationHandler.NewSupervisionHandler(supervisionSvc)
 	exportHdlr := exportHandler.NewExportHandler(exportSvc)
 	importHdlr := importHandler.NewImportHandler(importSvc)
+	sessionHdlr := sessionHandler.NewSessionHandler(sessionSvc)
 
-	authMiddleware := middleware.NewAuthMiddleware(jwtService)
+	authMiddleware := middleware.NewAuthMiddleware(
+		jwtService,
+		sessionSvc,
+		organizationSvc,
+		apiKeySvc,
+		ipAccessSvc,
+		impersonationSvc,
+		cfg.Security.APIKeyHeader,
+		cfg.Security.EnableAPIKey,
+		cfg.Security.StepUpMaxAge,
+	)
 	auditMiddleware := middleware.NewAuditMiddleware(auditLogSvc)
-	rbacMiddleware := middleware.NewRBACMiddleware()
+	rbacMiddleware := middleware.NewRBACMiddleware(roleSvc)
 
 	h := server.New(
 		server.WithHostPorts(fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)),
@@ -129,25 +358,60 @@ func main() {
 		server.WithExitWaitTime(cfg.Server.ExitTimeout),
 	)
 
+	clientIP, err := security.ClientIPFunc(cfg.Security.TrustedProxies)
+	if err != nil {
+		appLogger.Fatal("Invalid trusted proxies", zap.Error(err))
+	}
+	h.SetClientIPFunc(clientIP)
+
 	router.RegisterRoutes(
 		h,
 		authHandler,
 		userHdlr,
+		mfaHdlr,
+		ssoHdlr,
+		accountHdlr,
+		lockoutHdlr,
+		userAdminHdlr,
+		impersonationHdlr,
 		patientHdlr,
+		careTeamHdlr,
+		breakGlassHdlr,
 		appointmentHdlr,
 		clinicalNoteHdlr,
 		invoiceHdlr,
 		auditLogHdlr,
 		organizationHdlr,
+		invitationHdlr,
+		roleHdlr,
+		apiKeyHdlr,
+		ssoConfigHdlr,
+		breachedPasswordHdlr,
+		dataKeyHdlr,
+		supervisionHdlr,
 		exportHdlr,
 		importHdlr,
+		sessionHdlr,
 		authMiddleware,
 		auditMiddleware,
 		rbacMiddleware,
+		middleware.RateLimit(cfg.Security.RateLimitRequests, cfg.Security.RateLimitWindow),
 	)
 
+	// Move clinical data still under the shared keyring to organization data
+	// keys in the background
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	go keyRotationSvc.Run(backgroundCtx)
+
+	// Re-wrap organization data keys still under a retired master key
+	go masterKeyRotationSvc.Run(backgroundCtx)
+
+	// Keep the note search index of organizations that enabled it up to date
+	go noteSearchIndexSvc.Run(backgroundCtx)
+
 	h.OnShutdown = append(h.OnShutdown, func(_ context.Context) {
 		appLogger.Info("Shutting down server gracefully...")
+		stopBackground()
 
 		// TODO: Add other cleanup logic here (e.g., closing Database connections, Redis, etc.)
 
@@ -0,0 +1,39 @@
+// Command smtp-sink runs a local SMTP server that accepts every message and
+// prints it instead of delivering it. Use it to read password reset,
+// verification and invitation emails during development.
+//
+// Configure email.provider smtp with host localhost, port 2525, tls false
+// and an empty username to send mail to it.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/sahabatharianmu/OpenMind/pkg/mail"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:2525", "listen address")
+	flag.Parse()
+
+	sink, err := mail.NewSink(*addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sink.OnMessage = func(msg mail.ReceivedMessage) {
+		log.Printf("message from %s to %s\n%s", msg.From, strings.Join(msg.To, ", "), msg.Data)
+	}
+
+	log.Printf("SMTP sink listening on %s", sink.Addr())
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	_ = sink.Close()
+}
@@ -28,6 +28,7 @@ type ApplicationConfig struct {
 	Name        string `mapstructure:"name"`
 	Version     string `mapstructure:"version"`
 	Environment string `mapstructure:"environment"`
+	BaseURL     string `mapstructure:"base_url"` // Public URL used in links sent by email
 }
 
 // ServerConfig holds server configuration
@@ -203,6 +204,7 @@ func setDefaults() {
 	viper.SetDefault("application.name", "OpenMind")
 	viper.SetDefault("application.version", "0.0.1")
 	viper.SetDefault("application.environment", "development")
+	viper.SetDefault("application.base_url", "http://localhost:8080")
 
 	// Server defaults
 	viper.SetDefault("server.host", "0.0.0.0")
@@ -237,20 +239,39 @@ func setDefaults() {
 	viper.SetDefault("security.cors_max_age", securityConfig.CORSMaxAge)
 	viper.SetDefault("security.rate_limit_requests", securityConfig.RateLimitRequests)
 	viper.SetDefault("security.rate_limit_window", securityConfig.RateLimitWindow)
+	viper.SetDefault("security.login_failure_window", securityConfig.LoginFailureWindow)
+	viper.SetDefault("security.login_delay_threshold", securityConfig.LoginDelayThreshold)
+	viper.SetDefault("security.login_ip_delay_threshold", securityConfig.LoginIPDelayThreshold)
+	viper.SetDefault("security.login_max_delay", securityConfig.LoginMaxDelay)
+	viper.SetDefault("security.login_lockout_threshold", securityConfig.LoginLockoutThreshold)
+	viper.SetDefault("security.login_ip_lockout_threshold", securityConfig.LoginIPLockoutThreshold)
+	viper.SetDefault("security.login_lockout_duration", securityConfig.LoginLockoutDuration)
 	viper.SetDefault("security.jwt_secret_key", securityConfig.JWTSecretKey)
 	viper.SetDefault("security.jwt_access_expiry", securityConfig.JWTAccessExpiry)
 	viper.SetDefault("security.jwt_refresh_expiry", securityConfig.JWTRefreshExpiry)
+	viper.SetDefault("security.mfa_challenge_expiry", securityConfig.MFAChallengeExpiry)
+	viper.SetDefault("security.mfa_challenge_attempts", securityConfig.MFAChallengeAttempts)
+	viper.SetDefault("security.break_glass_duration", securityConfig.BreakGlassDuration)
+	viper.SetDefault("security.break_glass_notify_clinician", securityConfig.BreakGlassNotifyClinician)
+	viper.SetDefault("security.impersonation_duration", securityConfig.ImpersonationDuration)
+	viper.SetDefault("security.password_reset_expiry", securityConfig.PasswordResetExpiry)
+	viper.SetDefault("security.email_verification_expiry", securityConfig.EmailVerificationExpiry)
+	viper.SetDefault("security.require_email_verification", securityConfig.RequireEmailVerification)
 	viper.SetDefault("security.password_min_length", securityConfig.PasswordMinLength)
 	viper.SetDefault("security.password_require_upper", securityConfig.PasswordRequireUpper)
 	viper.SetDefault("security.password_require_lower", securityConfig.PasswordRequireLower)
 	viper.SetDefault("security.password_require_number", securityConfig.PasswordRequireNumber)
 	viper.SetDefault("security.password_require_special", securityConfig.PasswordRequireSpecial)
+	viper.SetDefault("security.password_history_count", securityConfig.PasswordHistoryCount)
+	viper.SetDefault("security.password_check_breached", securityConfig.PasswordCheckBreached)
 	viper.SetDefault("security.session_timeout", securityConfig.SessionTimeout)
+	viper.SetDefault("security.step_up_max_age", securityConfig.StepUpMaxAge)
 	viper.SetDefault("security.session_secure", securityConfig.SessionSecure)
 	viper.SetDefault("security.session_httponly", securityConfig.SessionHTTPOnly)
 	viper.SetDefault("security.max_file_size", securityConfig.MaxFileSize)
 	viper.SetDefault("security.allowed_file_types", securityConfig.AllowedFileTypes)
 	viper.SetDefault("security.api_key_header", securityConfig.APIKeyHeader)
+	viper.SetDefault("security.trusted_proxies", securityConfig.TrustedProxies)
 	viper.SetDefault("security.enable_api_key", securityConfig.EnableAPIKey)
 	viper.SetDefault("security.enable_csrf", securityConfig.EnableCSRF)
 	viper.SetDefault("security.enable_csp", securityConfig.EnableCSP)
@@ -260,6 +281,12 @@ func setDefaults() {
 	viper.SetDefault("security.enable_security_logging", securityConfig.EnableSecurityLogging)
 	viper.SetDefault("security.log_security_events", securityConfig.LogSecurityEvents)
 	viper.SetDefault("security.encryption_key", securityConfig.EncryptionKey)
+	viper.SetDefault("security.encryption_keys", securityConfig.EncryptionKeys)
+	viper.SetDefault("security.encryption_active_key_id", securityConfig.EncryptionActiveKeyID)
+	viper.SetDefault("security.key_provider", securityConfig.KeyProvider)
+	viper.SetDefault("security.master_key", securityConfig.MasterKey)
+	viper.SetDefault("security.master_key_file", securityConfig.MasterKeyFile)
+	viper.SetDefault("security.retired_master_keys", securityConfig.RetiredMasterKeys)
 	viper.SetDefault("security.hash_cost", securityConfig.HashCost)
 	viper.SetDefault("security.max_request_size", securityConfig.MaxRequestSize)
 	viper.SetDefault("security.request_timeout", securityConfig.RequestTimeout)
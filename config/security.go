@@ -18,20 +18,51 @@ type SecurityConfig struct {
 	RateLimitRequests int           `mapstructure:"rate_limit_requests"`
 	RateLimitWindow   time.Duration `mapstructure:"rate_limit_window"`
 
+	// Login brute-force protection
+	LoginFailureWindow      time.Duration `mapstructure:"login_failure_window"`
+	LoginDelayThreshold     int           `mapstructure:"login_delay_threshold"`
+	LoginIPDelayThreshold   int           `mapstructure:"login_ip_delay_threshold"`
+	LoginMaxDelay           time.Duration `mapstructure:"login_max_delay"`
+	LoginLockoutThreshold   int           `mapstructure:"login_lockout_threshold"`
+	LoginIPLockoutThreshold int           `mapstructure:"login_ip_lockout_threshold"`
+	LoginLockoutDuration    time.Duration `mapstructure:"login_lockout_duration"`
+
 	// JWT settings
 	JWTSecretKey     string        `mapstructure:"jwt_secret_key"`
 	JWTAccessExpiry  time.Duration `mapstructure:"jwt_access_expiry"`
 	JWTRefreshExpiry time.Duration `mapstructure:"jwt_refresh_expiry"`
 
+	// MFA settings. A login's MFA challenge is invalidated after
+	// MFAChallengeAttempts wrong codes.
+	MFAChallengeExpiry   time.Duration `mapstructure:"mfa_challenge_expiry"`
+	MFAChallengeAttempts int           `mapstructure:"mfa_challenge_attempts"`
+
+	// Break-the-glass emergency access
+	BreakGlassDuration        time.Duration `mapstructure:"break_glass_duration"`
+	BreakGlassNotifyClinician bool          `mapstructure:"break_glass_notify_clinician"`
+
+	// How long an administrator's impersonation of a member lasts
+	ImpersonationDuration time.Duration `mapstructure:"impersonation_duration"`
+
+	// Account recovery and email verification
+	PasswordResetExpiry      time.Duration `mapstructure:"password_reset_expiry"`
+	EmailVerificationExpiry  time.Duration `mapstructure:"email_verification_expiry"`
+	RequireEmailVerification bool          `mapstructure:"require_email_verification"`
+
 	// Password settings
 	PasswordMinLength      int  `mapstructure:"password_min_length"`
 	PasswordRequireUpper   bool `mapstructure:"password_require_upper"`
 	PasswordRequireLower   bool `mapstructure:"password_require_lower"`
 	PasswordRequireNumber  bool `mapstructure:"password_require_number"`
 	PasswordRequireSpecial bool `mapstructure:"password_require_special"`
+	PasswordHistoryCount   int  `mapstructure:"password_history_count"`
+	PasswordCheckBreached  bool `mapstructure:"password_check_breached"`
 
-	// Session settings
+	// Session settings. SessionTimeout is the idle time after which a session
+	// is signed out; StepUpMaxAge is how long a password or MFA confirmation
+	// unlocks sensitive actions.
 	SessionTimeout  time.Duration `mapstructure:"session_timeout"`
+	StepUpMaxAge    time.Duration `mapstructure:"step_up_max_age"`
 	SessionSecure   bool          `mapstructure:"session_secure"`
 	SessionHTTPOnly bool          `mapstructure:"session_httponly"`
 
@@ -45,6 +76,10 @@ type SecurityConfig struct {
 	EnableCSRF   bool   `mapstructure:"enable_csrf"`
 	EnableCSP    bool   `mapstructure:"enable_csp"`
 
+	// Proxies allowed to set X-Forwarded-For and X-Real-IP. The client IP
+	// drives rate limiting, organization IP allowlists and new login alerts.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+
 	// Database security
 	DBMaxOpenConns    int           `mapstructure:"db_max_open_conns"`
 	DBMaxIdleConns    int           `mapstructure:"db_max_idle_conns"`
@@ -54,9 +89,22 @@ type SecurityConfig struct {
 	EnableSecurityLogging bool `mapstructure:"enable_security_logging"`
 	LogSecurityEvents     bool `mapstructure:"log_security_events"`
 
-	// Encryption settings
-	EncryptionKey string `mapstructure:"encryption_key"`
-	HashCost      int    `mapstructure:"hash_cost"`
+	// Encryption settings. EncryptionKey is the original key, with ID "v1";
+	// EncryptionKeys adds further keys as "id:key" entries and
+	// EncryptionActiveKeyID selects the one new data is encrypted with.
+	EncryptionKey         string   `mapstructure:"encryption_key"`
+	EncryptionKeys        []string `mapstructure:"encryption_keys"`
+	EncryptionActiveKeyID string   `mapstructure:"encryption_active_key_id"`
+	HashCost              int      `mapstructure:"hash_cost"`
+
+	// Envelope encryption. Each organization's data key is wrapped by the
+	// master key of KeyProvider: "local" reads MasterKey, or MasterKeyFile
+	// when set, and "fake" keeps a random key in memory. RetiredMasterKeys
+	// still unwrap data keys until they are re-wrapped with the master key.
+	KeyProvider       string   `mapstructure:"key_provider"`
+	MasterKey         string   `mapstructure:"master_key"`
+	MasterKeyFile     string   `mapstructure:"master_key_file"`
+	RetiredMasterKeys []string `mapstructure:"retired_master_keys"`
 
 	// Request limits
 	MaxRequestSize    int64         `mapstructure:"max_request_size"`
@@ -71,26 +119,54 @@ func DefaultSecurityConfig() *SecurityConfig {
 		// CORS settings
 		CORSAllowOrigins: []string{"http://localhost:3000", "https://smatax.id"},
 		CORSAllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		CORSAllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization", "X-CSRF-Token", "X-Request-ID"},
+		CORSAllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization", "X-CSRF-Token", "X-Request-ID", "X-Organization-ID", "X-API-Key"},
 		CORSMaxAge:       86400, // 24 hours //nolint:mnd // standard max age
 		// Rate limiting
 		RateLimitRequests: 100, //nolint:mnd // default limit
 		RateLimitWindow:   time.Minute,
 
+		// Login brute-force protection
+		LoginFailureWindow:      15 * time.Minute, //nolint:mnd // default window
+		LoginDelayThreshold:     3,                //nolint:mnd // default threshold
+		LoginIPDelayThreshold:   20,               //nolint:mnd // default threshold
+		LoginMaxDelay:           30 * time.Second, //nolint:mnd // default delay
+		LoginLockoutThreshold:   10,               //nolint:mnd // default threshold
+		LoginIPLockoutThreshold: 100,              //nolint:mnd // default threshold
+		LoginLockoutDuration:    15 * time.Minute, //nolint:mnd // default lockout
+
 		// JWT settings
 		JWTSecretKey:     "your-secret-key-change-this-in-production",
 		JWTAccessExpiry:  15 * time.Minute, //nolint:mnd // default expiry
 		JWTRefreshExpiry: 7 * 24 * time.Hour,
 
+		// MFA settings
+		MFAChallengeExpiry:   5 * time.Minute, //nolint:mnd // default expiry
+		MFAChallengeAttempts: 5,               //nolint:mnd // default attempts
+
+		// Break-the-glass emergency access
+		BreakGlassDuration:        time.Hour,
+		BreakGlassNotifyClinician: true,
+
+		// Administrator impersonation
+		ImpersonationDuration: 30 * time.Minute, //nolint:mnd // default duration
+
+		// Account recovery and email verification
+		PasswordResetExpiry:      time.Hour,
+		EmailVerificationExpiry:  48 * time.Hour, //nolint:mnd // default expiry
+		RequireEmailVerification: false,
+
 		// Password settings
 		PasswordMinLength:      8, //nolint:mnd // standard min length
 		PasswordRequireUpper:   true,
 		PasswordRequireLower:   true,
 		PasswordRequireNumber:  true,
 		PasswordRequireSpecial: true,
+		PasswordHistoryCount:   5, //nolint:mnd // default history
+		PasswordCheckBreached:  true,
 
 		// Session settings
 		SessionTimeout:  30 * time.Minute, //nolint:mnd // default timeout
+		StepUpMaxAge:    5 * time.Minute,  //nolint:mnd // default step-up window
 		SessionSecure:   true,
 		SessionHTTPOnly: true,
 
@@ -104,6 +180,9 @@ func DefaultSecurityConfig() *SecurityConfig {
 		EnableCSRF:   true,
 		EnableCSP:    true,
 
+		// Network
+		TrustedProxies: []string{"127.0.0.1/32", "::1/128"},
+
 		// Database security
 		DBMaxOpenConns:    25, //nolint:mnd // default connections
 		DBMaxIdleConns:    5,  //nolint:mnd // default connections
@@ -114,8 +193,16 @@ func DefaultSecurityConfig() *SecurityConfig {
 		LogSecurityEvents:     true,
 
 		// Encryption settings
-		EncryptionKey: "your-encryption-key-change-this-in-production",
-		HashCost:      12, // bcrypt cost factor //nolint:mnd // standard cost
+		EncryptionKey:         "your-encryption-key-change-this-in-production",
+		EncryptionKeys:        []string{},
+		EncryptionActiveKeyID: "v1",
+		HashCost:              12, // bcrypt cost factor //nolint:mnd // standard cost
+
+		// Envelope encryption
+		KeyProvider:       "local",
+		MasterKey:         "",
+		MasterKeyFile:     "",
+		RetiredMasterKeys: []string{},
 
 		// Request limits
 		MaxRequestSize:    10 * 1024 * 1024, // 10MB //nolint:mnd // default size
@@ -135,6 +222,46 @@ func (s *SecurityConfig) Validate() error {
 		return fmt.Errorf("JWT access expiry must be at least 5 minutes")
 	}
 
+	if s.BreakGlassDuration <= 0 || s.BreakGlassDuration > 24*time.Hour {
+		return fmt.Errorf("break glass duration must be positive and at most 24 hours")
+	}
+
+	if s.ImpersonationDuration <= 0 || s.ImpersonationDuration > 4*time.Hour {
+		return fmt.Errorf("impersonation duration must be positive and at most 4 hours")
+	}
+
+	if s.PasswordResetExpiry <= 0 || s.PasswordResetExpiry > 24*time.Hour {
+		return fmt.Errorf("password reset expiry must be positive and at most 24 hours")
+	}
+
+	if s.EmailVerificationExpiry <= 0 {
+		return fmt.Errorf("email verification expiry must be positive")
+	}
+
+	if s.LoginFailureWindow <= 0 || s.LoginMaxDelay <= 0 || s.LoginLockoutDuration <= 0 {
+		return fmt.Errorf("login failure window, max delay and lockout duration must be positive")
+	}
+
+	if s.LoginDelayThreshold < 1 || s.LoginLockoutThreshold <= s.LoginDelayThreshold {
+		return fmt.Errorf("login lockout threshold must be greater than the delay threshold")
+	}
+
+	if s.LoginIPDelayThreshold < 1 || s.LoginIPLockoutThreshold <= s.LoginIPDelayThreshold {
+		return fmt.Errorf("login IP lockout threshold must be greater than the IP delay threshold")
+	}
+
+	if s.PasswordHistoryCount < 0 || s.PasswordHistoryCount > 24 {
+		return fmt.Errorf("password history count must be between 0 and 24")
+	}
+
+	if s.SessionTimeout < time.Minute*5 {
+		return fmt.Errorf("session timeout must be at least 5 minutes")
+	}
+
+	if s.StepUpMaxAge <= 0 || s.StepUpMaxAge > s.SessionTimeout {
+		return fmt.Errorf("step-up max age must be positive and at most the session timeout")
+	}
+
 	if s.MaxFileSize > 50*1024*1024 {
 		return fmt.Errorf("max file size cannot exceed 50MB")
 	}
@@ -143,6 +270,14 @@ func (s *SecurityConfig) Validate() error {
 		return fmt.Errorf("rate limit requests must be at least 10")
 	}
 
+	if s.EncryptionActiveKeyID == "" {
+		return fmt.Errorf("encryption active key ID is required")
+	}
+
+	if s.KeyProvider != "local" && s.KeyProvider != "fake" {
+		return fmt.Errorf("key provider must be local or fake")
+	}
+
 	if s.HashCost < 10 || s.HashCost > 20 {
 		return fmt.Errorf("hash cost must be between 10 and 20")
 	}
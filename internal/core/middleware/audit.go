@@ -8,6 +8,7 @@ import (
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/google/uuid"
 	auditLogService "github.com/sahabatharianmu/OpenMind/internal/modules/audit_log/service"
+	organizationEntity "github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
 )
 
 type AuditMiddleware struct {
@@ -40,12 +41,13 @@ func (m *AuditMiddleware) Middleware() app.HandlerFunc {
 		}
 		userID := userIDVal.(uuid.UUID)
 
-		// Get organization ID
-		orgID, err := m.auditSvc.GetOrganizationID(context.Background(), userID)
-		if err != nil {
+		// Active organization resolved by the auth middleware
+		orgIDVal, exists := c.Get("organizationID")
+		if !exists {
 			c.Next(ctx)
 			return
 		}
+		orgID := orgIDVal.(uuid.UUID)
 
 		// Continue processing the request
 		c.Next(ctx)
@@ -56,6 +58,12 @@ func (m *AuditMiddleware) Middleware() app.HandlerFunc {
 			return
 		}
 
+		// Handlers may leave out requests an earlier entry already covers,
+		// such as the autosaves of one editing session
+		if suppressed, _ := c.Get("auditSuppressed"); suppressed == true {
+			return
+		}
+
 		// Determine action and resource type from method and path
 		action := methodToAction(method)
 		resourceType, resourceID, isListOperation := parseResourceFromPath(path, c)
@@ -87,10 +95,20 @@ func (m *AuditMiddleware) Middleware() app.HandlerFunc {
 		ipAddr := c.ClientIP()
 		userAgent := string(c.UserAgent())
 
+		// Calls made with an API key are attributed to the key
+		logCtx := context.Background()
+		if keyVal, exists := c.Get("apiKey"); exists {
+			logCtx = auditLogService.WithAPIKey(logCtx, keyVal.(*organizationEntity.APIKey).ID)
+		}
+		// Requests made while impersonating also name the administrator
+		if impersonatorVal, exists := c.Get("impersonatorID"); exists {
+			logCtx = auditLogService.WithImpersonator(logCtx, impersonatorVal.(uuid.UUID))
+		}
+
 		// Log the action (non-blocking)
 		go func() {
 			_ = m.auditSvc.Log(
-				context.Background(),
+				logCtx,
 				action,
 				resourceType,
 				resourceID,
@@ -2,26 +2,83 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	organizationEntity "github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	organizationService "github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	sessionService "github.com/sahabatharianmu/OpenMind/internal/modules/session/service"
+	userService "github.com/sahabatharianmu/OpenMind/internal/modules/user/service"
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
 	"github.com/sahabatharianmu/OpenMind/pkg/security"
 )
 
+// OrganizationHeader selects the active organization for a single request,
+// overriding the organization carried in the access token
+const OrganizationHeader = "X-Organization-ID"
+
+// Response headers sent on every request made with an impersonation token
+const (
+	ImpersonatedByHeader        = "X-Impersonated-By"
+	ImpersonatedUserHeader      = "X-Impersonated-User"
+	ImpersonationExpiresHeader  = "X-Impersonation-Expires"
+	ImpersonationReadOnlyHeader = "X-Impersonation-Read-Only"
+)
+
+// impersonationEndPath ends an impersonation and stays usable when the
+// impersonation is read-only
+const impersonationEndPath = "/auth/impersonation"
+
 type AuthMiddleware struct {
-	jwtService *security.JWTService
+	jwtService   *security.JWTService
+	sessionSvc   sessionService.SessionService
+	orgSvc       organizationService.OrganizationService
+	apiKeySvc    organizationService.APIKeyService
+	ipAccessSvc  organizationService.IPAccessService
+	impersonSvc  userService.ImpersonationService
+	apiKeyHeader string
+	enableAPIKey bool
+	stepUpMaxAge time.Duration
 }
 
-func NewAuthMiddleware(jwtService *security.JWTService) *AuthMiddleware {
+func NewAuthMiddleware(
+	jwtService *security.JWTService,
+	sessionSvc sessionService.SessionService,
+	orgSvc organizationService.OrganizationService,
+	apiKeySvc organizationService.APIKeyService,
+	ipAccessSvc organizationService.IPAccessService,
+	impersonSvc userService.ImpersonationService,
+	apiKeyHeader string,
+	enableAPIKey bool,
+	stepUpMaxAge time.Duration,
+) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtService: jwtService,
+		jwtService:   jwtService,
+		sessionSvc:   sessionSvc,
+		orgSvc:       orgSvc,
+		apiKeySvc:    apiKeySvc,
+		ipAccessSvc:  ipAccessSvc,
+		impersonSvc:  impersonSvc,
+		apiKeyHeader: apiKeyHeader,
+		enableAPIKey: enableAPIKey,
+		stepUpMaxAge: stepUpMaxAge,
 	}
 }
 
 func (m *AuthMiddleware) Middleware() app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
 		authHeader := string(c.GetHeader("Authorization"))
+		if authHeader == "" && m.enableAPIKey {
+			if rawKey := string(c.GetHeader(m.apiKeyHeader)); rawKey != "" {
+				m.authenticateAPIKey(ctx, c, rawKey)
+				return
+			}
+		}
 		if authHeader == "" {
 			response.Unauthorized(c, "Missing authorization header")
 			c.Abort()
@@ -43,10 +100,240 @@ func (m *AuthMiddleware) Middleware() app.HandlerFunc {
 			return
 		}
 
+		// A valid signature is not enough: the token must still belong to a live session
+		tokenID, err := uuid.Parse(claims.ID)
+		if err != nil {
+			response.Unauthorized(c, "Invalid or expired token")
+			c.Abort()
+			return
+		}
+
+		if claims.ImpersonatorID != nil {
+			m.authenticateImpersonation(ctx, c, claims, tokenID)
+			return
+		}
+
+		session, err := m.sessionSvc.Validate(tokenID)
+		if errors.Is(err, sessionService.ErrSessionIdle) {
+			response.Error(c, consts.StatusUnauthorized, response.ErrorCodeSessionIdle, err.Error(), nil)
+			c.Abort()
+			return
+		}
+		if err != nil {
+			response.Unauthorized(c, "Session has been revoked or has expired")
+			c.Abort()
+			return
+		}
+
+		orgID := claims.OrganizationID
+		if header := string(c.GetHeader(OrganizationHeader)); header != "" {
+			orgID, err = uuid.Parse(header)
+			if err != nil {
+				response.BadRequest(c, "Invalid organization ID", nil)
+				c.Abort()
+				return
+			}
+		}
+
+		// The role comes from the current membership rather than the token so
+		// role changes and removals apply to the very next request
+		member, err := m.orgSvc.GetMembership(claims.UserID, orgID)
+		if err != nil {
+			response.HandleError(c, err)
+			c.Abort()
+			return
+		}
+
+		err = m.ipAccessSvc.Check(
+			member.OrganizationID,
+			claims.UserID,
+			member.Role,
+			c.ClientIP(),
+			string(c.UserAgent()),
+			uuid.Nil,
+		)
+		if err != nil {
+			response.HandleError(c, err)
+			c.Abort()
+			return
+		}
+
 		c.Set("userID", claims.UserID)
+		c.Set("sessionID", session.ID)
+		c.Set("recentlyAuthenticated", session.RecentlyAuthenticated(time.Now(), m.stepUpMaxAge))
 		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
+		c.Set("organizationID", member.OrganizationID)
+		c.Set("role", member.Role)
 
 		c.Next(ctx)
 	}
 }
+
+// authenticateAPIKey authorizes a server-to-server call. The request acts as
+// the key's creator within the key's organization, and "apiKey" is set so
+// permission checks are narrowed to the key's scope and audits name the key.
+func (m *AuthMiddleware) authenticateAPIKey(ctx context.Context, c *app.RequestContext, rawKey string) {
+	key, err := m.apiKeySvc.Authenticate(rawKey)
+	if err != nil {
+		response.HandleError(c, err)
+		c.Abort()
+		return
+	}
+
+	// A key stops working as soon as its creator leaves the organization
+	member, err := m.orgSvc.GetMembership(key.CreatedBy, key.OrganizationID)
+	if err != nil {
+		response.Unauthorized(c, "API key is no longer valid")
+		c.Abort()
+		return
+	}
+
+	err = m.ipAccessSvc.Check(
+		member.OrganizationID,
+		key.CreatedBy,
+		member.Role,
+		c.ClientIP(),
+		string(c.UserAgent()),
+		key.ID,
+	)
+	if err != nil {
+		response.HandleError(c, err)
+		c.Abort()
+		return
+	}
+
+	c.Set("userID", key.CreatedBy)
+	c.Set("organizationID", member.OrganizationID)
+	c.Set("role", member.Role)
+	c.Set("apiKey", key)
+
+	c.Next(ctx)
+}
+
+// authenticateImpersonation authorizes a request an administrator makes as
+// one of their members. The request acts as the member, "impersonatorID"
+// names the administrator for audits, and the response headers show that the
+// impersonation is in effect.
+func (m *AuthMiddleware) authenticateImpersonation(
+	ctx context.Context,
+	c *app.RequestContext,
+	claims *security.JWTClaims,
+	impersonationID uuid.UUID,
+) {
+	impersonation, err := m.impersonSvc.Validate(impersonationID)
+	if err != nil {
+		response.HandleError(c, err)
+		c.Abort()
+		return
+	}
+
+	member, err := m.orgSvc.GetMembership(impersonation.UserID, impersonation.OrganizationID)
+	if err != nil {
+		response.HandleError(c, err)
+		c.Abort()
+		return
+	}
+
+	// The administrator is the one connecting, so the allowlist applies to
+	// them under their own role
+	admin, err := m.orgSvc.GetMembership(impersonation.ImpersonatorID, impersonation.OrganizationID)
+	if err != nil {
+		response.HandleError(c, err)
+		c.Abort()
+		return
+	}
+
+	err = m.ipAccessSvc.Check(
+		admin.OrganizationID,
+		admin.UserID,
+		admin.Role,
+		c.ClientIP(),
+		string(c.UserAgent()),
+		uuid.Nil,
+	)
+	if err != nil {
+		response.HandleError(c, err)
+		c.Abort()
+		return
+	}
+
+	c.Response.Header.Set(ImpersonatedByHeader, impersonation.ImpersonatorID.String())
+	c.Response.Header.Set(ImpersonatedUserHeader, impersonation.UserID.String())
+	c.Response.Header.Set(ImpersonationExpiresHeader, impersonation.ExpiresAt.UTC().Format(time.RFC3339))
+	c.Response.Header.Set(ImpersonationReadOnlyHeader, strconv.FormatBool(impersonation.ReadOnly))
+
+	if impersonation.ReadOnly && !isSafeMethod(c) && !strings.HasSuffix(string(c.Path()), impersonationEndPath) {
+		response.Forbidden(c, "This impersonation is read-only")
+		c.Abort()
+		return
+	}
+
+	c.Set("userID", impersonation.UserID)
+	c.Set("impersonatorID", impersonation.ImpersonatorID)
+	c.Set("impersonation", impersonation)
+	c.Set("recentlyAuthenticated", false)
+	c.Set("email", claims.Email)
+	c.Set("organizationID", member.OrganizationID)
+	c.Set("role", member.Role)
+
+	c.Next(ctx)
+}
+
+// RequireUserSession rejects API keys on routes that act on the signed-in
+// user's own account or session rather than on organization data. While
+// impersonating, an administrator may only look at the member's account.
+func (m *AuthMiddleware) RequireUserSession() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		if _, isAPIKey := c.Get("apiKey"); isAPIKey {
+			response.Forbidden(c, "This endpoint cannot be used with an API key")
+			c.Abort()
+			return
+		}
+		if _, impersonating := c.Get("impersonation"); impersonating && !isSafeMethod(c) {
+			response.Forbidden(c, "This endpoint cannot be used while impersonating")
+			c.Abort()
+			return
+		}
+		c.Next(ctx)
+	}
+}
+
+// RequireRecentAuth guards sensitive actions behind step-up authentication:
+// the user must have confirmed their password or MFA code on this session
+// within the step-up window. API keys cannot confirm anything and are refused.
+func (m *AuthMiddleware) RequireRecentAuth() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		if _, isAPIKey := c.Get("apiKey"); isAPIKey {
+			response.Forbidden(c, "This endpoint cannot be used with an API key")
+			c.Abort()
+			return
+		}
+		if _, impersonating := c.Get("impersonation"); impersonating {
+			response.Forbidden(c, "This endpoint cannot be used while impersonating")
+			c.Abort()
+			return
+		}
+		if recent, _ := c.Get("recentlyAuthenticated"); recent != true {
+			response.ReauthenticationRequired(c)
+			c.Abort()
+			return
+		}
+		c.Next(ctx)
+	}
+}
+
+// isSafeMethod reports whether the request only reads
+func isSafeMethod(c *app.RequestContext) bool {
+	method := string(c.Method())
+	return method == consts.MethodGet || method == consts.MethodHead || method == consts.MethodOptions
+}
+
+// apiKeyAllows reports whether an API key used for the request, if any,
+// was granted the permission
+func apiKeyAllows(c *app.RequestContext, permission string) bool {
+	keyVal, exists := c.Get("apiKey")
+	if !exists {
+		return true
+	}
+	return keyVal.(*organizationEntity.APIKey).HasPermission(permission)
+}
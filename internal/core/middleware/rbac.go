@@ -4,16 +4,25 @@ import (
 	"context"
 
 	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/google/uuid"
+	organizationEntity "github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	organizationService "github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	patientEntity "github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
 )
 
-type RBACMiddleware struct{}
+type RBACMiddleware struct {
+	roleSvc organizationService.RoleService
+}
 
-func NewRBACMiddleware() *RBACMiddleware {
-	return &RBACMiddleware{}
+func NewRBACMiddleware(roleSvc organizationService.RoleService) *RBACMiddleware {
+	return &RBACMiddleware{roleSvc: roleSvc}
 }
 
-func (m *RBACMiddleware) HasRole(allowedRoles ...string) app.HandlerFunc {
+// RequirePermission only lets the request through when the member's role in
+// the active organization grants the permission. Requests made with an API
+// key also need the permission on the key.
+func (m *RBACMiddleware) RequirePermission(permission string) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
 		roleVal, exists := c.Get("role")
 		if !exists {
@@ -22,23 +31,21 @@ func (m *RBACMiddleware) HasRole(allowedRoles ...string) app.HandlerFunc {
 			return
 		}
 
-		role := roleVal.(string)
-
-		// Admin can access everything
-		if role == "admin" {
-			c.Next(ctx)
+		orgIDVal, exists := c.Get("organizationID")
+		if !exists {
+			response.Unauthorized(c, "User not authenticated")
+			c.Abort()
 			return
 		}
 
-		isAllowed := false
-		for _, r := range allowedRoles {
-			if r == role {
-				isAllowed = true
-				break
-			}
+		allowed, err := m.roleSvc.HasPermission(orgIDVal.(uuid.UUID), roleVal.(string), permission)
+		if err != nil {
+			response.HandleError(c, err)
+			c.Abort()
+			return
 		}
 
-		if !isAllowed {
+		if !allowed || !apiKeyAllows(c, permission) {
 			response.Forbidden(c, "You do not have permission to perform this action")
 			c.Abort()
 			return
@@ -47,3 +54,45 @@ func (m *RBACMiddleware) HasRole(allowedRoles ...string) app.HandlerFunc {
 		c.Next(ctx)
 	}
 }
+
+// PatientAccess stores the member's patient scope as "patientAccess". Members
+// without the patients:all permission only reach patients on their care teams.
+func (m *RBACMiddleware) PatientAccess() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		userIDVal, exists := c.Get("userID")
+		if !exists {
+			response.Unauthorized(c, "User not authenticated")
+			c.Abort()
+			return
+		}
+
+		orgIDVal, exists := c.Get("organizationID")
+		if !exists {
+			response.Unauthorized(c, "User not authenticated")
+			c.Abort()
+			return
+		}
+
+		roleVal, exists := c.Get("role")
+		if !exists {
+			response.Unauthorized(c, "User role not found")
+			c.Abort()
+			return
+		}
+
+		orgID := orgIDVal.(uuid.UUID)
+		allPatients, err := m.roleSvc.HasPermission(orgID, roleVal.(string), organizationEntity.PermPatientsAll)
+		if err != nil {
+			response.HandleError(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set("patientAccess", patientEntity.Access{
+			OrganizationID: orgID,
+			UserID:         userIDVal.(uuid.UUID),
+			AllPatients:    allPatients && apiKeyAllows(c, organizationEntity.PermPatientsAll),
+		})
+		c.Next(ctx)
+	}
+}
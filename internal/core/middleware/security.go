@@ -82,6 +82,13 @@ func CORS(cfg *config.Config) app.HandlerFunc {
 		}
 		// Credentials
 		c.Response.Header.Set("Access-Control-Allow-Credentials", "true")
+		// Let the frontend show that an impersonation is in effect
+		c.Response.Header.Set("Access-Control-Expose-Headers", strings.Join([]string{
+			ImpersonatedByHeader,
+			ImpersonatedUserHeader,
+			ImpersonationExpiresHeader,
+			ImpersonationReadOnlyHeader,
+		}, ", "))
 
 		// Handle preflight
 		if string(c.Request.Header.Method()) == "OPTIONS" {
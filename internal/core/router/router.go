@@ -12,8 +12,10 @@ import (
 	exportHandler "github.com/sahabatharianmu/OpenMind/internal/modules/export/handler"
 	importHandler "github.com/sahabatharianmu/OpenMind/internal/modules/import/handler"
 	invoiceHandler "github.com/sahabatharianmu/OpenMind/internal/modules/invoice/handler"
+	organizationEntity "github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
 	organizationHandler "github.com/sahabatharianmu/OpenMind/internal/modules/organization/handler"
 	patientHandler "github.com/sahabatharianmu/OpenMind/internal/modules/patient/handler"
+	sessionHandler "github.com/sahabatharianmu/OpenMind/internal/modules/session/handler"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/user/handler"
 )
 
@@ -21,26 +23,74 @@ func RegisterRoutes(
 	h *server.Hertz,
 	authHandler *handler.AuthHandler,
 	userHandler *handler.UserHandler,
+	mfaHandler *handler.MFAHandler,
+	ssoHandler *handler.SSOHandler,
+	accountHandler *handler.AccountHandler,
+	lockoutHandler *handler.LockoutHandler,
+	userAdminHandler *handler.UserAdminHandler,
+	impersonationHandler *handler.ImpersonationHandler,
 	patientHandler *patientHandler.PatientHandler,
+	careTeamHandler *patientHandler.CareTeamHandler,
+	breakGlassHandler *patientHandler.BreakGlassHandler,
 	appointmentHandler *appointmentHandler.AppointmentHandler,
 	clinicalNoteHandler *clinicalNoteHandler.ClinicalNoteHandler,
 	invoiceHandler *invoiceHandler.InvoiceHandler,
 	auditLogHandler *auditLogHandler.AuditLogHandler,
 	organizationHandler *organizationHandler.OrganizationHandler,
+	invitationHandler *organizationHandler.InvitationHandler,
+	roleHandler *organizationHandler.RoleHandler,
+	apiKeyHandler *organizationHandler.APIKeyHandler,
+	ssoConfigHandler *organizationHandler.SSOConfigHandler,
+	breachedPasswordHandler *organizationHandler.BreachedPasswordHandler,
+	dataKeyHandler *organizationHandler.DataKeyHandler,
+	supervisionHandler *organizationHandler.SupervisionHandler,
 	exportHandler *exportHandler.ExportHandler,
 	importHandler *importHandler.ImportHandler,
+	sessionHandler *sessionHandler.SessionHandler,
 	authMiddleware *middleware.AuthMiddleware,
 	auditMiddleware *middleware.AuditMiddleware,
 	rbacMiddleware *middleware.RBACMiddleware,
+	rateLimit app.HandlerFunc,
 ) {
+	// Permission guards shared by several routes
+	readPatients := rbacMiddleware.RequirePermission(organizationEntity.PermPatientsRead)
+	writePatients := rbacMiddleware.RequirePermission(organizationEntity.PermPatientsWrite)
+	readAppointments := rbacMiddleware.RequirePermission(organizationEntity.PermAppointmentsRead)
+	writeAppointments := rbacMiddleware.RequirePermission(organizationEntity.PermAppointmentsWrite)
+	readNotes := rbacMiddleware.RequirePermission(organizationEntity.PermNotesRead)
+	writeNotes := rbacMiddleware.RequirePermission(organizationEntity.PermNotesWrite)
+	readInvoices := rbacMiddleware.RequirePermission(organizationEntity.PermInvoicesRead)
+	writeInvoices := rbacMiddleware.RequirePermission(organizationEntity.PermInvoicesWrite)
+	readMembers := rbacMiddleware.RequirePermission(organizationEntity.PermMembersRead)
+	manageMembers := rbacMiddleware.RequirePermission(organizationEntity.PermMembersManage)
+	manageOrganization := rbacMiddleware.RequirePermission(organizationEntity.PermOrganizationManage)
+	manageCareTeam := rbacMiddleware.RequirePermission(organizationEntity.PermCareTeamManage)
+	patientAccess := rbacMiddleware.PatientAccess()
+	userSession := authMiddleware.RequireUserSession()
+	recentAuth := authMiddleware.RequireRecentAuth()
+
 	api := h.Group("/api")
 	v1 := api.Group("/v1")
 
-	auth := v1.Group("/auth")
+	// Unauthenticated endpoints are limited per client IP
+	auth := v1.Group("/auth", rateLimit)
 	{
 		auth.GET("/setup/status", authHandler.SetupStatus)
 		auth.POST("/register", authHandler.Register)
 		auth.POST("/login", authHandler.Login)
+		auth.POST("/refresh", authHandler.Refresh)
+		auth.POST("/mfa/verify", authHandler.VerifyMFA)
+		auth.POST("/mfa/setup", authHandler.SetupMFA)
+		auth.GET("/invitations/:token", invitationHandler.Preview)
+		auth.POST("/invitations/accept", authHandler.AcceptInvitation)
+		auth.POST("/sso/start", ssoHandler.Start)
+		auth.POST("/sso/callback", ssoHandler.Callback)
+		auth.POST("/sso/link", ssoHandler.ConfirmLink)
+		auth.POST("/password/forgot", accountHandler.ForgotPassword)
+		auth.POST("/password/reset", accountHandler.ResetPassword)
+		auth.POST("/password/expired", authHandler.ChangeExpiredPassword)
+		auth.POST("/email/verify", accountHandler.VerifyEmail)
+		auth.POST("/email/resend", accountHandler.ResendVerification)
 	}
 	protected := v1.Group("/")
 	protected.Use(authMiddleware.Middleware())
@@ -48,23 +98,104 @@ func RegisterRoutes(
 	{
 		// User routes
 		users := protected.Group("/users")
+		users.Use(userSession)
 		{
 			users.GET("/me", userHandler.GetProfile)
 			users.PUT("/me", userHandler.UpdateProfile)
+			users.POST("/me/email/verification", accountHandler.SendVerification)
+			users.GET("/me/sessions", sessionHandler.ListMySessions)
+			users.DELETE("/me/sessions/:id", sessionHandler.RevokeMySession)
+			users.GET("/me/mfa", mfaHandler.Status)
+			users.POST("/me/mfa/setup", mfaHandler.Setup)
+			users.POST("/me/mfa/enable", mfaHandler.Enable)
+			users.POST("/me/mfa/disable", mfaHandler.Disable)
+			users.POST("/me/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+			users.DELETE("/:id/sessions", manageMembers, sessionHandler.RevokeUserSessions)
+			users.POST("/:id/unlock", manageMembers, lockoutHandler.Unlock)
+			users.GET("", manageMembers, userAdminHandler.List)
+			users.PUT("/:id", manageMembers, userAdminHandler.Update)
+			users.POST("/:id/deactivate", manageMembers, userAdminHandler.Deactivate)
+			users.POST("/:id/reactivate", manageMembers, userAdminHandler.Reactivate)
+			users.POST("/:id/password-reset", manageMembers, userAdminHandler.ForcePasswordReset)
+			users.POST("/:id/reassign", manageMembers, userAdminHandler.Reassign)
+			users.POST(
+				"/:id/impersonate",
+				rbacMiddleware.RequirePermission(organizationEntity.PermUsersImpersonate),
+				recentAuth,
+				impersonationHandler.Start,
+			)
 		}
 
-		protected.PUT("/auth/password", userHandler.ChangePassword)
+		protected.PUT("/auth/password", userSession, userHandler.ChangePassword)
+		protected.POST("/auth/reauthenticate", userSession, authHandler.Reauthenticate)
+		protected.POST("/auth/logout", userSession, sessionHandler.Logout)
+		protected.POST("/auth/logout-all", userSession, sessionHandler.LogoutAll)
+		protected.DELETE("/auth/impersonation", impersonationHandler.End)
 
 		organizations := protected.Group("/organizations")
 		{
+			organizations.GET("", organizationHandler.ListOrganizations)
+			organizations.POST("/switch", userSession, authHandler.SwitchOrganization)
 			organizations.GET("/me", organizationHandler.GetMyOrganization)
-			organizations.PUT("/me", rbacMiddleware.HasRole("admin"), organizationHandler.UpdateOrganization)
+			organizations.PUT("/me", manageOrganization, organizationHandler.UpdateOrganization)
+			organizations.GET("/me/members", readMembers, organizationHandler.ListMembers)
+			organizations.PUT("/me/members/:id", manageMembers, organizationHandler.UpdateMemberRole)
+			organizations.DELETE("/me/members/:id", manageMembers, organizationHandler.RemoveMember)
+			organizations.GET("/me/invitations", manageMembers, invitationHandler.List)
+			organizations.POST("/me/invitations", manageMembers, invitationHandler.Create)
+			organizations.DELETE("/me/invitations/:id", manageMembers, invitationHandler.Revoke)
+			organizations.GET("/me/supervisions", manageMembers, supervisionHandler.List)
+			organizations.POST("/me/supervisions", manageMembers, supervisionHandler.Create)
+			organizations.DELETE("/me/supervisions/:id", manageMembers, supervisionHandler.End)
+			organizations.GET("/permissions", roleHandler.ListPermissions)
+			organizations.GET("/me/permissions", roleHandler.MyPermissions)
+			organizations.GET("/me/roles", roleHandler.List)
+			organizations.POST("/me/roles", userSession, manageOrganization, roleHandler.Create)
+			organizations.PUT("/me/roles/:name", userSession, manageOrganization, roleHandler.Update)
+			organizations.DELETE("/me/roles/:name", userSession, manageOrganization, roleHandler.Delete)
+			organizations.GET("/me/api-keys", userSession, manageOrganization, apiKeyHandler.List)
+			organizations.POST("/me/api-keys", userSession, manageOrganization, apiKeyHandler.Create)
+			organizations.DELETE("/me/api-keys/:id", userSession, manageOrganization, apiKeyHandler.Revoke)
+			organizations.GET("/me/sso", manageOrganization, ssoConfigHandler.Get)
+			organizations.PUT("/me/sso", userSession, manageOrganization, ssoConfigHandler.Update)
+			organizations.DELETE("/me/sso", userSession, manageOrganization, ssoConfigHandler.Delete)
+			organizations.POST(
+				"/me/sso/domains/:domain/verify",
+				userSession,
+				manageOrganization,
+				ssoConfigHandler.VerifyDomain,
+			)
+			organizations.GET("/me/breached-passwords", manageOrganization, breachedPasswordHandler.Get)
+			organizations.PUT(
+				"/me/breached-passwords",
+				userSession,
+				manageOrganization,
+				breachedPasswordHandler.Upload,
+			)
+			organizations.DELETE(
+				"/me/breached-passwords",
+				userSession,
+				manageOrganization,
+				breachedPasswordHandler.Clear,
+			)
+			organizations.POST(
+				"/me/data-key/destroy",
+				userSession,
+				manageOrganization,
+				recentAuth,
+				dataKeyHandler.Destroy,
+			)
 		}
 
-		protected.GET("/export", rbacMiddleware.HasRole("admin"), exportHandler.ExportData)
+		protected.GET(
+			"/export",
+			rbacMiddleware.RequirePermission(organizationEntity.PermDataExport),
+			recentAuth,
+			exportHandler.ExportData,
+		)
 
 		imports := protected.Group("/import")
-		imports.Use(rbacMiddleware.HasRole("admin"))
+		imports.Use(rbacMiddleware.RequirePermission(organizationEntity.PermDataImport), patientAccess)
 		{
 			imports.GET("/template/:type", importHandler.DownloadTemplate)
 			imports.POST("/preview", importHandler.PreviewImport)
@@ -72,48 +203,83 @@ func RegisterRoutes(
 		}
 
 		patients := protected.Group("/patients")
+		patients.Use(patientAccess)
+		{
+			patients.POST("", writePatients, patientHandler.Create)
+			patients.GET("", readPatients, patientHandler.List)
+			patients.GET("/lookup", readPatients, patientHandler.Lookup)
+			patients.GET("/:id", readPatients, patientHandler.Get)
+			patients.PUT("/:id", writePatients, patientHandler.Update)
+			patients.DELETE(
+				"/:id",
+				rbacMiddleware.RequirePermission(organizationEntity.PermPatientsDelete),
+				recentAuth,
+				patientHandler.Delete,
+			)
+			patients.GET("/:id/care-team", readPatients, careTeamHandler.Get)
+			patients.POST("/:id/care-team", manageCareTeam, careTeamHandler.Grant)
+			patients.DELETE("/:id/care-team/:user_id", manageCareTeam, careTeamHandler.Revoke)
+			patients.PUT("/:id/primary-clinician", manageCareTeam, careTeamHandler.SetPrimaryClinician)
+			patients.POST("/:id/break-glass", userSession, readPatients, recentAuth, breakGlassHandler.Request)
+		}
+
+		breakGlass := protected.Group("/break-glass")
+		breakGlass.Use(rbacMiddleware.RequirePermission(organizationEntity.PermBreakGlassReview))
 		{
-			patients.POST("", rbacMiddleware.HasRole("clinician"), patientHandler.Create)
-			patients.GET("", patientHandler.List)
-			patients.GET("/:id", patientHandler.Get)
-			patients.PUT("/:id", rbacMiddleware.HasRole("clinician"), patientHandler.Update)
-			patients.DELETE("/:id", rbacMiddleware.HasRole("admin"), patientHandler.Delete)
+			breakGlass.GET("", breakGlassHandler.List)
+			breakGlass.POST("/:id/review", breakGlassHandler.Review)
 		}
 
 		appointments := protected.Group("/appointments")
+		appointments.Use(patientAccess)
 		{
-			appointments.POST("", rbacMiddleware.HasRole("clinician"), appointmentHandler.Create)
-			appointments.GET("", appointmentHandler.List)
-			appointments.GET("/:id", appointmentHandler.Get)
-			appointments.PUT("/:id", rbacMiddleware.HasRole("clinician"), appointmentHandler.Update)
-			appointments.DELETE("/:id", rbacMiddleware.HasRole("clinician"), appointmentHandler.Delete)
+			appointments.POST("", writeAppointments, appointmentHandler.Create)
+			appointments.GET("", readAppointments, appointmentHandler.List)
+			appointments.GET("/:id", readAppointments, appointmentHandler.Get)
+			appointments.PUT("/:id", writeAppointments, appointmentHandler.Update)
+			appointments.DELETE("/:id", writeAppointments, appointmentHandler.Delete)
 		}
 
 		clinicalNotes := protected.Group("/clinical-notes")
-		clinicalNotes.Use(rbacMiddleware.HasRole("clinician"))
+		clinicalNotes.Use(patientAccess)
 		{
-			clinicalNotes.POST("", clinicalNoteHandler.Create)
-			clinicalNotes.GET("", clinicalNoteHandler.List)
-			clinicalNotes.GET("/:id", clinicalNoteHandler.Get)
-			clinicalNotes.PUT("/:id", clinicalNoteHandler.Update)
-			clinicalNotes.DELETE("/:id", clinicalNoteHandler.Delete)
-			clinicalNotes.POST("/:id/addendums", clinicalNoteHandler.AddAddendum)
-			clinicalNotes.POST("/:id/attachments", clinicalNoteHandler.UploadAttachment)
-			clinicalNotes.GET("/attachments/:attachment_id", clinicalNoteHandler.DownloadAttachment)
+			clinicalNotes.POST("", writeNotes, clinicalNoteHandler.Create)
+			clinicalNotes.GET("", readNotes, clinicalNoteHandler.List)
+			clinicalNotes.GET("/search", readNotes, clinicalNoteHandler.Search)
+			clinicalNotes.GET("/review-queue", readNotes, clinicalNoteHandler.ReviewQueue)
+			clinicalNotes.GET("/:id", readNotes, clinicalNoteHandler.Get)
+			clinicalNotes.GET("/:id/signature", readNotes, clinicalNoteHandler.VerifySignature)
+			clinicalNotes.PUT("/:id", writeNotes, clinicalNoteHandler.Update)
+			clinicalNotes.PUT("/:id/autosave", writeNotes, clinicalNoteHandler.Autosave)
+			clinicalNotes.GET("/:id/revisions", readNotes, clinicalNoteHandler.ListRevisions)
+			clinicalNotes.GET("/:id/revisions/diff", readNotes, clinicalNoteHandler.DiffRevisions)
+			clinicalNotes.GET("/:id/revisions/:revision", readNotes, clinicalNoteHandler.GetRevision)
+			clinicalNotes.DELETE("/:id", writeNotes, clinicalNoteHandler.Delete)
+			clinicalNotes.POST("/:id/submit", writeNotes, clinicalNoteHandler.Submit)
+			clinicalNotes.POST("/:id/return", writeNotes, clinicalNoteHandler.Return)
+			clinicalNotes.POST("/:id/co-sign", writeNotes, clinicalNoteHandler.CoSign)
+			clinicalNotes.POST("/:id/addendums", writeNotes, clinicalNoteHandler.AddAddendum)
+			clinicalNotes.POST("/:id/attachments", writeNotes, clinicalNoteHandler.UploadAttachment)
+			clinicalNotes.GET(
+				"/attachments/:attachment_id",
+				readNotes,
+				recentAuth,
+				clinicalNoteHandler.DownloadAttachment,
+			)
 		}
 
 		invoices := protected.Group("/invoices")
 		{
-			invoices.POST("", rbacMiddleware.HasRole("admin"), invoiceHandler.Create)
-			invoices.GET("", invoiceHandler.List)
-			invoices.GET("/:id", invoiceHandler.Get)
-			invoices.PUT("/:id", rbacMiddleware.HasRole("admin"), invoiceHandler.Update)
-			invoices.DELETE("/:id", rbacMiddleware.HasRole("admin"), invoiceHandler.Delete)
-			invoices.GET("/:id/superbill", invoiceHandler.DownloadSuperbill)
+			invoices.POST("", writeInvoices, invoiceHandler.Create)
+			invoices.GET("", readInvoices, invoiceHandler.List)
+			invoices.GET("/:id", readInvoices, invoiceHandler.Get)
+			invoices.PUT("/:id", writeInvoices, invoiceHandler.Update)
+			invoices.DELETE("/:id", writeInvoices, invoiceHandler.Delete)
+			invoices.GET("/:id/superbill", readInvoices, invoiceHandler.DownloadSuperbill)
 		}
 
 		auditLogs := protected.Group("/audit-logs")
-		auditLogs.Use(rbacMiddleware.HasRole("admin"))
+		auditLogs.Use(rbacMiddleware.RequirePermission(organizationEntity.PermAuditRead))
 		{
 			auditLogs.GET("", auditLogHandler.List)
 		}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/appointment/dto"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/appointment/service"
+	patientEntity "github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
 )
 
@@ -21,18 +22,12 @@ func NewAppointmentHandler(svc service.AppointmentService) *AppointmentHandler {
 }
 
 func (h *AppointmentHandler) Create(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(patientEntity.Access)
 
 	var req dto.CreateAppointmentRequest
 	if err := c.BindAndValidate(&req); err != nil {
@@ -40,7 +35,7 @@ func (h *AppointmentHandler) Create(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
-	resp, err := h.svc.Create(context.Background(), req, orgID)
+	resp, err := h.svc.Create(context.Background(), req, access)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -50,18 +45,12 @@ func (h *AppointmentHandler) Create(_ context.Context, c *app.RequestContext) {
 }
 
 func (h *AppointmentHandler) List(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(patientEntity.Access)
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
@@ -72,7 +61,7 @@ func (h *AppointmentHandler) List(_ context.Context, c *app.RequestContext) {
 		pageSize = 10
 	}
 
-	resp, total, err := h.svc.List(context.Background(), orgID, page, pageSize)
+	resp, total, err := h.svc.List(context.Background(), access, page, pageSize)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -87,18 +76,12 @@ func (h *AppointmentHandler) List(_ context.Context, c *app.RequestContext) {
 }
 
 func (h *AppointmentHandler) Get(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(patientEntity.Access)
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -107,7 +90,7 @@ func (h *AppointmentHandler) Get(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
-	resp, err := h.svc.Get(context.Background(), id, orgID)
+	resp, err := h.svc.Get(context.Background(), id, access)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -117,18 +100,12 @@ func (h *AppointmentHandler) Get(_ context.Context, c *app.RequestContext) {
 }
 
 func (h *AppointmentHandler) Update(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(patientEntity.Access)
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -143,7 +120,7 @@ func (h *AppointmentHandler) Update(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
-	resp, err := h.svc.Update(context.Background(), id, orgID, req)
+	resp, err := h.svc.Update(context.Background(), id, access, req)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -153,18 +130,12 @@ func (h *AppointmentHandler) Update(_ context.Context, c *app.RequestContext) {
 }
 
 func (h *AppointmentHandler) Delete(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(patientEntity.Access)
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -173,7 +144,7 @@ func (h *AppointmentHandler) Delete(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
-	if err := h.svc.Delete(context.Background(), id, orgID); err != nil {
+	if err := h.svc.Delete(context.Background(), id, access); err != nil {
 		response.HandleError(c, err)
 		return
 	}
@@ -16,14 +16,17 @@ type AppointmentRepository interface {
 	Update(appointment *entity.Appointment) error
 	Delete(id uuid.UUID) error
 	FindByID(id uuid.UUID) (*entity.Appointment, error)
-	List(organizationID uuid.UUID, limit, offset int) ([]entity.Appointment, int64, error)
+	List(
+		organizationID uuid.UUID,
+		limit, offset int,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) ([]entity.Appointment, int64, error)
 	CheckOverlap(
 		organizationID uuid.UUID,
 		clinicianID uuid.UUID,
 		startTime, endTime time.Time,
 		excludeID *uuid.UUID,
 	) (bool, error)
-	GetOrganizationID(userID uuid.UUID) (uuid.UUID, error)
 }
 
 type appointmentRepository struct {
@@ -73,11 +76,15 @@ func (r *appointmentRepository) FindByID(id uuid.UUID) (*entity.Appointment, err
 	return &appointment, nil
 }
 
-func (r *appointmentRepository) List(organizationID uuid.UUID, limit, offset int) ([]entity.Appointment, int64, error) {
+func (r *appointmentRepository) List(
+	organizationID uuid.UUID,
+	limit, offset int,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]entity.Appointment, int64, error) {
 	var appointments []entity.Appointment
 	var total int64
 
-	query := r.db.Model(&entity.Appointment{}).Where("organization_id = ?", organizationID)
+	query := r.db.Model(&entity.Appointment{}).Where("organization_id = ?", organizationID).Scopes(scopes...)
 
 	if err := query.Count(&total).Error; err != nil {
 		r.log.Error("Failed to count appointments", zap.Error(err))
@@ -116,15 +123,3 @@ func (r *appointmentRepository) CheckOverlap(
 
 	return count > 0, nil
 }
-
-func (r *appointmentRepository) GetOrganizationID(userID uuid.UUID) (uuid.UUID, error) {
-	var orgIDStr string
-	if err := r.db.Table("organization_members").Select("organization_id").Where("user_id = ?", userID).Limit(1).Scan(&orgIDStr).Error; err != nil {
-		r.log.Error("Failed to get organization ID", zap.Error(err), zap.String("user_id", userID.String()))
-		return uuid.Nil, err
-	}
-	if orgIDStr == "" {
-		return uuid.Nil, errors.New("organization not found for user")
-	}
-	return uuid.Parse(orgIDStr)
-}
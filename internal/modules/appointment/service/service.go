@@ -9,45 +9,63 @@ import (
 	"github.com/sahabatharianmu/OpenMind/internal/modules/appointment/dto"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/appointment/entity"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/appointment/repository"
+	patientEntity "github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	patientRepo "github.com/sahabatharianmu/OpenMind/internal/modules/patient/repository"
 	"github.com/sahabatharianmu/OpenMind/pkg/logger"
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
 )
 
+// AppointmentService manages appointments of the patients a member may
+// access. Appointments of patients outside the member's care teams are
+// reported as not found.
 type AppointmentService interface {
 	Create(
 		ctx context.Context,
 		req dto.CreateAppointmentRequest,
-		organizationID uuid.UUID,
+		access patientEntity.Access,
 	) (*dto.AppointmentResponse, error)
 	Update(
 		ctx context.Context,
 		id uuid.UUID,
-		organizationID uuid.UUID,
+		access patientEntity.Access,
 		req dto.UpdateAppointmentRequest,
 	) (*dto.AppointmentResponse, error)
-	Delete(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) error
-	Get(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (*dto.AppointmentResponse, error)
-	List(ctx context.Context, organizationID uuid.UUID, page, pageSize int) ([]dto.AppointmentResponse, int64, error)
-	GetOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
+	Delete(ctx context.Context, id uuid.UUID, access patientEntity.Access) error
+	Get(ctx context.Context, id uuid.UUID, access patientEntity.Access) (*dto.AppointmentResponse, error)
+	List(
+		ctx context.Context,
+		access patientEntity.Access,
+		page, pageSize int,
+	) ([]dto.AppointmentResponse, int64, error)
 }
 
 type appointmentService struct {
-	repo repository.AppointmentRepository
-	log  logger.Logger
+	repo         repository.AppointmentRepository
+	careTeamRepo patientRepo.CareTeamRepository
+	log          logger.Logger
 }
 
-func NewAppointmentService(repo repository.AppointmentRepository, log logger.Logger) AppointmentService {
+func NewAppointmentService(
+	repo repository.AppointmentRepository,
+	careTeamRepo patientRepo.CareTeamRepository,
+	log logger.Logger,
+) AppointmentService {
 	return &appointmentService{
-		repo: repo,
-		log:  log,
+		repo:         repo,
+		careTeamRepo: careTeamRepo,
+		log:          log,
 	}
 }
 
 func (s *appointmentService) Create(
 	ctx context.Context,
 	req dto.CreateAppointmentRequest,
-	organizationID uuid.UUID,
+	access patientEntity.Access,
 ) (*dto.AppointmentResponse, error) {
+	if err := s.checkPatientAccess(access, req.PatientID); err != nil {
+		return nil, err
+	}
+
 	startTime, err := time.Parse(time.RFC3339, req.StartTime)
 	if err != nil {
 		return nil, err
@@ -64,7 +82,7 @@ func (s *appointmentService) Create(
 
 	appointment := &entity.Appointment{
 		ID:             uuid.New(),
-		OrganizationID: organizationID,
+		OrganizationID: access.OrganizationID,
 		PatientID:      req.PatientID,
 		ClinicianID:    req.ClinicianID,
 		StartTime:      startTime,
@@ -76,7 +94,7 @@ func (s *appointmentService) Create(
 	}
 
 	// Conflict Detection
-	overlap, err := s.repo.CheckOverlap(organizationID, req.ClinicianID, startTime, endTime, nil)
+	overlap, err := s.repo.CheckOverlap(access.OrganizationID, req.ClinicianID, startTime, endTime, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check for schedule conflicts: %w", err)
 	}
@@ -96,18 +114,14 @@ func (s *appointmentService) Create(
 func (s *appointmentService) Update(
 	ctx context.Context,
 	id uuid.UUID,
-	organizationID uuid.UUID,
+	access patientEntity.Access,
 	req dto.UpdateAppointmentRequest,
 ) (*dto.AppointmentResponse, error) {
-	appointment, err := s.repo.FindByID(id)
+	appointment, err := s.find(id, access)
 	if err != nil {
 		return nil, err
 	}
 
-	if appointment.OrganizationID != organizationID {
-		return nil, response.NewNotFound("Appointment not found")
-	}
-
 	if req.StartTime != nil {
 		startTime, err := time.Parse(time.RFC3339, *req.StartTime)
 		if err != nil {
@@ -137,7 +151,7 @@ func (s *appointmentService) Update(
 
 	// Conflict Detection for Update
 	overlap, err := s.repo.CheckOverlap(
-		organizationID,
+		access.OrganizationID,
 		appointment.ClinicianID,
 		appointment.StartTime,
 		appointment.EndTime,
@@ -159,43 +173,39 @@ func (s *appointmentService) Update(
 	return s.mapEntityToResponse(appointment), nil
 }
 
-func (s *appointmentService) Delete(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) error {
-	appointment, err := s.repo.FindByID(id)
-	if err != nil {
+func (s *appointmentService) Delete(ctx context.Context, id uuid.UUID, access patientEntity.Access) error {
+	if _, err := s.find(id, access); err != nil {
 		return err
 	}
 
-	if appointment.OrganizationID != organizationID {
-		return response.ErrNotFound
-	}
-
 	return s.repo.Delete(id)
 }
 
 func (s *appointmentService) Get(
 	ctx context.Context,
 	id uuid.UUID,
-	organizationID uuid.UUID,
+	access patientEntity.Access,
 ) (*dto.AppointmentResponse, error) {
-	appointment, err := s.repo.FindByID(id)
+	appointment, err := s.find(id, access)
 	if err != nil {
 		return nil, err
 	}
 
-	if appointment.OrganizationID != organizationID {
-		return nil, response.ErrNotFound
-	}
-
 	return s.mapEntityToResponse(appointment), nil
 }
 
 func (s *appointmentService) List(
 	ctx context.Context,
-	organizationID uuid.UUID,
+	access patientEntity.Access,
 	page, pageSize int,
 ) ([]dto.AppointmentResponse, int64, error) {
 	offset := (page - 1) * pageSize
-	appointments, total, err := s.repo.List(organizationID, pageSize, offset)
+	appointments, total, err := s.repo.List(
+		access.OrganizationID,
+		pageSize,
+		offset,
+		patientRepo.CareTeamScope(access, "patient_id"),
+	)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -208,8 +218,38 @@ func (s *appointmentService) List(
 	return responses, total, nil
 }
 
-func (s *appointmentService) GetOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
-	return s.repo.GetOrganizationID(userID)
+// find loads an appointment of the organization whose patient the member may
+// access
+func (s *appointmentService) find(id uuid.UUID, access patientEntity.Access) (*entity.Appointment, error) {
+	appointment, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.OrganizationID != access.OrganizationID {
+		return nil, response.NewNotFound("Appointment not found")
+	}
+
+	allowed, err := s.careTeamRepo.CanAccess(access, appointment.PatientID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if !allowed {
+		return nil, response.NewNotFound("Appointment not found")
+	}
+
+	return appointment, nil
+}
+
+func (s *appointmentService) checkPatientAccess(access patientEntity.Access, patientID uuid.UUID) error {
+	allowed, err := s.careTeamRepo.CanAccess(access, patientID)
+	if err != nil {
+		return response.ErrInternalServerError
+	}
+	if !allowed {
+		return response.NewNotFound("Patient not found")
+	}
+	return nil
 }
 
 func (s *appointmentService) mapEntityToResponse(a *entity.Appointment) *dto.AppointmentResponse {
@@ -11,6 +11,8 @@ type AuditLogResponse struct {
 	OrganizationID uuid.UUID   `json:"organization_id"`
 	UserID         uuid.UUID   `json:"user_id"`
 	UserName       string      `json:"user_name"`
+	APIKeyID       *uuid.UUID  `json:"api_key_id"`
+	ImpersonatorID *uuid.UUID  `json:"impersonator_id"`
 	Action         string      `json:"action"`
 	ResourceType   string      `json:"resource_type"`
 	ResourceID     *uuid.UUID  `json:"resource_id"`
@@ -13,6 +13,8 @@ type AuditLog struct {
 	ID             uuid.UUID      `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
 	OrganizationID uuid.UUID      `gorm:"type:uuid;not null;index:idx_audit_logs_org"     json:"organization_id"`
 	UserID         uuid.UUID      `gorm:"type:uuid;not null;index:idx_audit_logs_user"    json:"user_id"`
+	APIKeyID       *uuid.UUID     `gorm:"type:uuid;index"                                 json:"api_key_id"`
+	ImpersonatorID *uuid.UUID     `gorm:"type:uuid;index"                                 json:"impersonator_id"`
 	Action         string         `gorm:"type:varchar(50);not null"                       json:"action"`
 	ResourceType   string         `gorm:"type:varchar(50);not null;index"                 json:"resource_type"`
 	ResourceID     *uuid.UUID     `gorm:"type:uuid;index"                                 json:"resource_id"`
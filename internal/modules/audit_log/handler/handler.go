@@ -22,18 +22,12 @@ func NewAuditLogHandler(svc service.AuditLogService) *AuditLogHandler {
 }
 
 func (h *AuditLogHandler) List(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	orgIDVal, exists := c.Get("organizationID")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	orgID := orgIDVal.(uuid.UUID)
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
@@ -1,7 +1,6 @@
 package repository
 
 import (
-	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,7 +18,6 @@ type AuditLogRepository interface {
 		limit, offset int,
 		filters *dto.FilterOptions,
 	) ([]entity.AuditLog, int64, error)
-	GetOrganizationID(userID uuid.UUID) (uuid.UUID, error)
 }
 
 type auditLogRepository struct {
@@ -85,15 +83,3 @@ func (r *auditLogRepository) List(
 
 	return logs, total, nil
 }
-
-func (r *auditLogRepository) GetOrganizationID(userID uuid.UUID) (uuid.UUID, error) {
-	var orgIDStr string
-	if err := r.db.Table("organization_members").Select("organization_id").Where("user_id = ?", userID).Limit(1).Scan(&orgIDStr).Error; err != nil {
-		r.log.Error("Failed to get organization ID", zap.Error(err), zap.String("user_id", userID.String()))
-		return uuid.Nil, err
-	}
-	if orgIDStr == "" {
-		return uuid.Nil, errors.New("organization not found for user")
-	}
-	return uuid.Parse(orgIDStr)
-}
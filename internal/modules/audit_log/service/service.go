@@ -13,6 +13,36 @@ import (
 	"gorm.io/datatypes"
 )
 
+type apiKeyContextKey struct{}
+
+// WithAPIKey marks ctx as belonging to a request authenticated with an API
+// key, so entries logged with it are attributed to the key
+func WithAPIKey(ctx context.Context, apiKeyID uuid.UUID) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, apiKeyID)
+}
+
+func apiKeyFromContext(ctx context.Context) *uuid.UUID {
+	if id, ok := ctx.Value(apiKeyContextKey{}).(uuid.UUID); ok {
+		return &id
+	}
+	return nil
+}
+
+type impersonatorContextKey struct{}
+
+// WithImpersonator marks ctx as belonging to a request an administrator made
+// while impersonating the user, so entries logged with it name both
+func WithImpersonator(ctx context.Context, impersonatorID uuid.UUID) context.Context {
+	return context.WithValue(ctx, impersonatorContextKey{}, impersonatorID)
+}
+
+func impersonatorFromContext(ctx context.Context) *uuid.UUID {
+	if id, ok := ctx.Value(impersonatorContextKey{}).(uuid.UUID); ok {
+		return &id
+	}
+	return nil
+}
+
 type AuditLogService interface {
 	Log(
 		ctx context.Context,
@@ -31,7 +61,6 @@ type AuditLogService interface {
 		page, pageSize int,
 		filters *dto.FilterOptions,
 	) ([]dto.AuditLogResponse, int64, error)
-	GetOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
 }
 
 type auditLogService struct {
@@ -71,6 +100,8 @@ func (s *auditLogService) Log(
 		ID:             uuid.New(),
 		OrganizationID: orgID,
 		UserID:         userID,
+		APIKeyID:       apiKeyFromContext(ctx),
+		ImpersonatorID: impersonatorFromContext(ctx),
 		Action:         action,
 		ResourceType:   resourceType,
 		ResourceID:     resourceID,
@@ -108,10 +139,6 @@ func (s *auditLogService) List(
 	return responses, total, nil
 }
 
-func (s *auditLogService) GetOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
-	return s.repo.GetOrganizationID(userID)
-}
-
 func (s *auditLogService) mapEntityToResponse(log *entity.AuditLog) *dto.AuditLogResponse {
 	var details interface{}
 	if log.Details != nil {
@@ -123,6 +150,8 @@ func (s *auditLogService) mapEntityToResponse(log *entity.AuditLog) *dto.AuditLo
 		OrganizationID: log.OrganizationID,
 		UserID:         log.UserID,
 		UserName:       "", // Will be populated by joining with users table if needed
+		APIKeyID:       log.APIKeyID,
+		ImpersonatorID: log.ImpersonatorID,
 		Action:         log.Action,
 		ResourceType:   log.ResourceType,
 		ResourceID:     log.ResourceID,
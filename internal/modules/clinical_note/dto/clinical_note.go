@@ -8,7 +8,6 @@ import (
 
 type CreateClinicalNoteRequest struct {
 	PatientID     uuid.UUID  `json:"patient_id"     validate:"required"`
-	ClinicianID   uuid.UUID  `json:"clinician_id"   validate:"required"`
 	AppointmentID *uuid.UUID `json:"appointment_id"`
 	NoteType      string     `json:"note_type"      validate:"required"`
 	Subjective    *string    `json:"subjective"`
@@ -40,12 +39,81 @@ type ClinicalNoteResponse struct {
 	Plan           *string              `json:"plan"`
 	IsSigned       bool                 `json:"is_signed"`
 	SignedAt       *time.Time           `json:"signed_at"`
+	Status         string               `json:"status"`
+	SubmittedAt    *time.Time           `json:"submitted_at"`
+	CoSignedBy     *uuid.UUID           `json:"co_signed_by"`
+	CoSignedAt     *time.Time           `json:"co_signed_at"`
 	Addendums      []AddendumResponse   `json:"addendums,omitempty"`
 	Attachments    []AttachmentResponse `json:"attachments,omitempty"`
+	Reviews        []ReviewResponse     `json:"reviews,omitempty"`
 	CreatedAt      time.Time            `json:"created_at"`
 	UpdatedAt      time.Time            `json:"updated_at"`
 }
 
+// AutosaveClinicalNoteRequest saves the content of an unsigned note while it
+// is edited. Omitted fields are left unchanged.
+type AutosaveClinicalNoteRequest struct {
+	NoteType   string  `json:"note_type"`
+	Subjective *string `json:"subjective"`
+	Objective  *string `json:"objective"`
+	Assessment *string `json:"assessment"`
+	Plan       *string `json:"plan"`
+}
+
+// AutosaveResponse reports the revision holding the note's saved content.
+// Changed is false when the content was already saved.
+type AutosaveResponse struct {
+	NoteID   uuid.UUID `json:"note_id"`
+	Revision int       `json:"revision"`
+	Changed  bool      `json:"changed"`
+	SavedAt  time.Time `json:"saved_at"`
+}
+
+type RevisionSummary struct {
+	ID             uuid.UUID `json:"id"`
+	RevisionNumber int       `json:"revision_number"`
+	AuthorID       uuid.UUID `json:"author_id"`
+	Autosave       bool      `json:"autosave"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type RevisionResponse struct {
+	RevisionSummary
+	NoteType   string  `json:"note_type"`
+	Subjective *string `json:"subjective"`
+	Objective  *string `json:"objective"`
+	Assessment *string `json:"assessment"`
+	Plan       *string `json:"plan"`
+}
+
+// Diff line operations
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// FieldDiff compares one field of two revisions line by line
+type FieldDiff struct {
+	Field   string     `json:"field"`
+	Changed bool       `json:"changed"`
+	Lines   []DiffLine `json:"lines"`
+}
+
+// RevisionDiffResponse lists the changes from one revision of a note to
+// another
+type RevisionDiffResponse struct {
+	NoteID uuid.UUID   `json:"note_id"`
+	From   int         `json:"from"`
+	To     int         `json:"to"`
+	Fields []FieldDiff `json:"fields"`
+}
+
 type AddAddendumRequest struct {
 	Content     string    `json:"content"      validate:"required"`
 	ClinicianID uuid.UUID `json:"clinician_id" validate:"required"`
@@ -58,6 +126,20 @@ type AddendumResponse struct {
 	SignedAt    time.Time `json:"signed_at"`
 }
 
+// ReviewNoteRequest carries a supervisor's comment on a submitted note. A
+// comment is required to return a note and optional to co-sign it.
+type ReviewNoteRequest struct {
+	Comment string `json:"comment"`
+}
+
+type ReviewResponse struct {
+	ID         uuid.UUID `json:"id"`
+	ReviewerID uuid.UUID `json:"reviewer_id"`
+	Action     string    `json:"action"`
+	Comment    string    `json:"comment,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 type AttachmentResponse struct {
 	ID          uuid.UUID `json:"id"`
 	FileName    string    `json:"file_name"`
@@ -65,3 +147,49 @@ type AttachmentResponse struct {
 	Size        int64     `json:"size"`
 	CreatedAt   time.Time `json:"created_at"`
 }
+
+// NoteSearchResult is a note matching a search query. MatchedTerms counts
+// the distinct query words found in the note and Hits how often they occur.
+type NoteSearchResult struct {
+	NoteID       uuid.UUID `json:"note_id"`
+	MatchedTerms int       `json:"matched_terms"`
+	Hits         int       `json:"hits"`
+}
+
+// Signature verification statuses
+const (
+	SignatureValid    = "valid"
+	SignatureInvalid  = "invalid"
+	SignatureMissing  = "missing"
+	SignatureUnsigned = "unsigned"
+)
+
+// SignatureVerification is the result of checking one signature. Digest is
+// the hex SHA-256 of the signed payload as rebuilt now, and PublicKey the
+// base64 signing key, so the signature can also be checked independently.
+type SignatureVerification struct {
+	Status         string     `json:"status"`
+	Reason         string     `json:"reason,omitempty"`
+	SignedBy       *uuid.UUID `json:"signed_by,omitempty"`
+	SignedAt       *time.Time `json:"signed_at,omitempty"`
+	KeyFingerprint *string    `json:"key_fingerprint,omitempty"`
+	PublicKey      string     `json:"public_key,omitempty"`
+	Digest         string     `json:"digest,omitempty"`
+}
+
+type AddendumSignatureVerification struct {
+	AddendumID uuid.UUID `json:"addendum_id"`
+	SignatureVerification
+}
+
+// NoteSignatureVerificationResponse reports whether a note and each of its
+// addendums are unchanged since they were signed. CoSignature is set for
+// notes co-signed by a supervisor. Valid is true only when every signature
+// is.
+type NoteSignatureVerificationResponse struct {
+	NoteID      uuid.UUID                       `json:"note_id"`
+	Valid       bool                            `json:"valid"`
+	Note        SignatureVerification           `json:"note"`
+	CoSignature *SignatureVerification          `json:"co_signature,omitempty"`
+	Addendums   []AddendumSignatureVerification `json:"addendums"`
+}
@@ -8,38 +8,76 @@ import (
 	"gorm.io/gorm"
 )
 
+// Note statuses. A note is a draft until it is signed, unless its clinician
+// is supervised: then it is submitted for review, and is either returned with
+// comments, to be submitted again, or co-signed by a supervisor, which signs
+// it.
+const (
+	NoteStatusDraft         = "draft"
+	NoteStatusPendingReview = "pending_review"
+	NoteStatusReturned      = "returned"
+	NoteStatusSigned        = "signed"
+	NoteStatusCoSigned      = "co_signed"
+)
+
+// Review actions of a supervisor on a submitted note
+const (
+	ReviewActionReturned = "returned"
+	ReviewActionCoSigned = "co_signed"
+)
+
+// ClinicalNote is a SOAP note, stored encrypted in ContentEncrypted. When the
+// organization has note search enabled, SearchTokens holds the note's search
+// index entries to write along with it; nil leaves the index untouched.
+// NewRevisions holds the revisions to record along with it, if any.
 type ClinicalNote struct {
-	ID               uuid.UUID      `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
-	OrganizationID   uuid.UUID      `gorm:"type:uuid;not null"                              json:"organization_id"`
-	PatientID        uuid.UUID      `gorm:"type:uuid;not null"                              json:"patient_id"`
-	ClinicianID      uuid.UUID      `gorm:"type:uuid;not null"                              json:"clinician_id"`
-	AppointmentID    *uuid.UUID     `gorm:"type:uuid"                                       json:"appointment_id"`
-	NoteType         string         `gorm:"not null"                                        json:"note_type"`
-	ICD10Code        string         `gorm:"type:varchar(20)"                                json:"icd10_code"`
-	Subjective       *string        `gorm:"-"                                               json:"subjective"`
-	Objective        *string        `gorm:"-"                                               json:"objective"`
-	Assessment       *string        `gorm:"-"                                               json:"assessment"`
-	Plan             *string        `gorm:"-"                                               json:"plan"`
-	ContentEncrypted []byte         `gorm:"type:bytea"                                      json:"-"`
-	KeyID            string         `gorm:"type:varchar(255)"                               json:"key_id"`
-	Nonce            []byte         `gorm:"type:bytea"                                      json:"-"`
-	IsSigned         bool           `gorm:"not null;default:false"                          json:"is_signed"`
-	SignedAt         *time.Time     `gorm:""                                                json:"signed_at"`
-	Addendums        []Addendum     `gorm:"foreignKey:NoteID"                               json:"addendums,omitempty"`
-	Attachments      []Attachment   `gorm:"foreignKey:NoteID"                               json:"attachments,omitempty"`
-	CreatedAt        time.Time      `gorm:"autoCreateTime"                                  json:"created_at"`
-	UpdatedAt        time.Time      `gorm:"autoUpdateTime"                                  json:"updated_at"`
-	DeletedAt        gorm.DeletedAt `gorm:"index"                                           json:"-"`
+	ID                      uuid.UUID      `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	OrganizationID          uuid.UUID      `gorm:"type:uuid;not null"                              json:"organization_id"`
+	PatientID               uuid.UUID      `gorm:"type:uuid;not null"                              json:"patient_id"`
+	ClinicianID             uuid.UUID      `gorm:"type:uuid;not null"                              json:"clinician_id"`
+	AppointmentID           *uuid.UUID     `gorm:"type:uuid"                                       json:"appointment_id"`
+	NoteType                string         `gorm:"not null"                                        json:"note_type"`
+	ICD10Code               string         `gorm:"type:varchar(20)"                                json:"icd10_code"`
+	Subjective              *string        `gorm:"-"                                               json:"subjective"`
+	Objective               *string        `gorm:"-"                                               json:"objective"`
+	Assessment              *string        `gorm:"-"                                               json:"assessment"`
+	Plan                    *string        `gorm:"-"                                               json:"plan"`
+	ContentEncrypted        []byte         `gorm:"type:bytea"                                      json:"-"`
+	KeyID                   string         `gorm:"type:varchar(255)"                               json:"key_id"`
+	Nonce                   []byte         `gorm:"type:bytea"                                      json:"-"`
+	IsSigned                bool           `gorm:"not null;default:false"                          json:"is_signed"`
+	SignedAt                *time.Time     `gorm:""                                                json:"signed_at"`
+	SignedBy                *uuid.UUID     `gorm:"type:uuid"                                       json:"signed_by"`
+	Signature               []byte         `gorm:"type:bytea"                                      json:"-"`
+	SigningKeyFingerprint   *string        `gorm:"type:varchar(64)"                                json:"signing_key_fingerprint"`
+	Status                  string         `gorm:"type:varchar(20);not null;default:'draft'"       json:"status"`
+	SubmittedAt             *time.Time     `gorm:""                                                json:"submitted_at"`
+	CoSignedBy              *uuid.UUID     `gorm:"type:uuid"                                       json:"co_signed_by"`
+	CoSignedAt              *time.Time     `gorm:""                                                json:"co_signed_at"`
+	CoSignature             []byte         `gorm:"type:bytea"                                      json:"-"`
+	CoSigningKeyFingerprint *string        `gorm:"type:varchar(64)"                                json:"co_signing_key_fingerprint"`
+	Addendums               []Addendum     `gorm:"foreignKey:NoteID"                               json:"addendums,omitempty"`
+	Attachments             []Attachment   `gorm:"foreignKey:NoteID"                               json:"attachments,omitempty"`
+	Reviews                 []Review       `gorm:"foreignKey:NoteID"                               json:"reviews,omitempty"`
+	SearchTokens            []SearchToken  `gorm:"-"                                               json:"-"`
+	NewRevisions            []Revision     `gorm:"-"                                               json:"-"`
+	CreatedAt               time.Time      `gorm:"autoCreateTime"                                  json:"created_at"`
+	UpdatedAt               time.Time      `gorm:"autoUpdateTime"                                  json:"updated_at"`
+	DeletedAt               gorm.DeletedAt `gorm:"index"                                           json:"-"`
 }
 
 type Addendum struct {
-	ID               uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
-	NoteID           uuid.UUID `gorm:"type:uuid;not null"                              json:"note_id"`
-	ClinicianID      uuid.UUID `gorm:"type:uuid;not null"                              json:"clinician_id"`
-	Content          string    `gorm:"-"                                               json:"content"`
-	ContentEncrypted []byte    `gorm:"type:bytea"                                      json:"-"`
-	Nonce            []byte    `gorm:"type:bytea"                                      json:"-"`
-	SignedAt         time.Time `gorm:"not null;autoCreateTime"                         json:"signed_at"`
+	ID                    uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	NoteID                uuid.UUID  `gorm:"type:uuid;not null"                              json:"note_id"`
+	ClinicianID           uuid.UUID  `gorm:"type:uuid;not null"                              json:"clinician_id"`
+	Content               string     `gorm:"-"                                               json:"content"`
+	ContentEncrypted      []byte     `gorm:"type:bytea"                                      json:"-"`
+	Nonce                 []byte     `gorm:"type:bytea"                                      json:"-"`
+	KeyID                 string     `gorm:"type:varchar(255);not null;default:'v1'"         json:"-"`
+	SignedAt              time.Time  `gorm:"not null;autoCreateTime"                         json:"signed_at"`
+	SignedBy              *uuid.UUID `gorm:"type:uuid"                                       json:"signed_by"`
+	Signature             []byte     `gorm:"type:bytea"                                      json:"-"`
+	SigningKeyFingerprint *string    `gorm:"type:varchar(64)"                                json:"signing_key_fingerprint"`
 }
 
 func (Addendum) TableName() string {
@@ -54,6 +92,7 @@ type Attachment struct {
 	Size          int64     `gorm:"not null"                                        json:"size"`
 	DataEncrypted []byte    `gorm:"type:bytea;not null"                             json:"-"`
 	Nonce         []byte    `gorm:"type:bytea;not null"                             json:"-"`
+	KeyID         string    `gorm:"type:varchar(255);not null;default:'v1'"         json:"-"`
 	CreatedAt     time.Time `gorm:"autoCreateTime"                                  json:"created_at"`
 }
 
@@ -61,6 +100,23 @@ func (Attachment) TableName() string {
 	return "clinical_note_attachments"
 }
 
+// Review is a supervisor's decision on a submitted note, with an optional
+// comment stored encrypted in CommentEncrypted
+type Review struct {
+	ID               uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	NoteID           uuid.UUID `gorm:"type:uuid;not null"                              json:"note_id"`
+	ReviewerID       uuid.UUID `gorm:"type:uuid;not null"                              json:"reviewer_id"`
+	Action           string    `gorm:"type:varchar(20);not null"                       json:"action"`
+	Comment          string    `gorm:"-"                                               json:"comment"`
+	CommentEncrypted []byte    `gorm:"type:bytea"                                      json:"-"`
+	KeyID            string    `gorm:"type:varchar(255)"                               json:"-"`
+	CreatedAt        time.Time `gorm:"autoCreateTime"                                  json:"created_at"`
+}
+
+func (Review) TableName() string {
+	return "clinical_note_reviews"
+}
+
 func (ClinicalNote) TableName() string {
 	return "clinical_notes"
 }
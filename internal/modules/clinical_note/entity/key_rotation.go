@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Key rotation statuses
+const (
+	KeyRotationRunning    = "running"
+	KeyRotationCompleted  = "completed"
+	KeyRotationSuperseded = "superseded"
+)
+
+// KeyRotationTargetDataKeys is the target of rotations that move records
+// from the shared keyring to their organization's data key
+const KeyRotationTargetDataKeys = "organization"
+
+// Kinds of encrypted records covered by key rotation, in the order they are
+// processed
+const (
+	KeyRotationNotes       = "notes"
+	KeyRotationAddendums   = "addendums"
+	KeyRotationAttachments = "attachments"
+	KeyRotationSSOSecrets  = "sso_secrets"
+)
+
+// KeyRotation records the progress of re-encrypting clinical notes, addendums,
+// attachments and SSO client secrets under TargetKeyID, which is KeyRotationTargetDataKeys for
+// rotations to organization data keys. The cursors hold the last record ID
+// handled of each kind, so an interrupted rotation resumes where it stopped.
+type KeyRotation struct {
+	ID                 uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	TargetKeyID        string     `gorm:"type:varchar(255);not null"                      json:"target_key_id"`
+	Status             string     `gorm:"type:varchar(20);not null;default:'running'"     json:"status"`
+	NotesCursor        *uuid.UUID `gorm:"type:uuid"                                       json:"-"`
+	AddendumsCursor    *uuid.UUID `gorm:"type:uuid"                                       json:"-"`
+	AttachmentsCursor  *uuid.UUID `gorm:"type:uuid"                                       json:"-"`
+	SSOSecretsCursor   *uuid.UUID `gorm:"type:uuid"                                       json:"-"`
+	NotesRotated       int64      `gorm:"not null;default:0"                              json:"notes_rotated"`
+	AddendumsRotated   int64      `gorm:"not null;default:0"                              json:"addendums_rotated"`
+	AttachmentsRotated int64      `gorm:"not null;default:0"                              json:"attachments_rotated"`
+	SSOSecretsRotated  int64      `gorm:"not null;default:0"                              json:"sso_secrets_rotated"`
+	Failures           int64      `gorm:"not null;default:0"                              json:"failures"`
+	LastError          *string    `gorm:"type:text"                                       json:"last_error"`
+	StartedAt          time.Time  `gorm:"not null"                                        json:"started_at"`
+	UpdatedAt          time.Time  `gorm:"autoUpdateTime"                                  json:"updated_at"`
+	CompletedAt        *time.Time `gorm:""                                                json:"completed_at"`
+}
+
+func (KeyRotation) TableName() string {
+	return "encryption_key_rotations"
+}
+
+// Cursor returns the last record ID handled of the given kind
+func (r *KeyRotation) Cursor(kind string) uuid.UUID {
+	var cursor *uuid.UUID
+	switch kind {
+	case KeyRotationNotes:
+		cursor = r.NotesCursor
+	case KeyRotationAddendums:
+		cursor = r.AddendumsCursor
+	case KeyRotationAttachments:
+		cursor = r.AttachmentsCursor
+	case KeyRotationSSOSecrets:
+		cursor = r.SSOSecretsCursor
+	}
+	if cursor == nil {
+		return uuid.Nil
+	}
+	return *cursor
+}
+
+// Advance moves the cursor of the given kind past id
+func (r *KeyRotation) Advance(kind string, id uuid.UUID) {
+	switch kind {
+	case KeyRotationNotes:
+		r.NotesCursor = &id
+	case KeyRotationAddendums:
+		r.AddendumsCursor = &id
+	case KeyRotationAttachments:
+		r.AttachmentsCursor = &id
+	case KeyRotationSSOSecrets:
+		r.SSOSecretsCursor = &id
+	}
+}
+
+// CountRotated adds one re-encrypted record of the given kind
+func (r *KeyRotation) CountRotated(kind string) {
+	switch kind {
+	case KeyRotationNotes:
+		r.NotesRotated++
+	case KeyRotationAddendums:
+		r.AddendumsRotated++
+	case KeyRotationAttachments:
+		r.AttachmentsRotated++
+	case KeyRotationSSOSecrets:
+		r.SSOSecretsRotated++
+	}
+}
+
+// EncryptedRecord is the ciphertext of a note, addendum, attachment or SSO
+// client secret together with the ID of the key it is encrypted with
+type EncryptedRecord struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Encrypted      []byte
+	KeyID          string
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Revision is a saved version of an unsigned note, numbered from 1 per note.
+// Its note type and SOAP content are stored encrypted in ContentEncrypted.
+// Autosave marks revisions saved automatically while the note was edited.
+type Revision struct {
+	ID               uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	NoteID           uuid.UUID `gorm:"type:uuid;not null"                              json:"note_id"`
+	OrganizationID   uuid.UUID `gorm:"type:uuid;not null"                              json:"organization_id"`
+	RevisionNumber   int       `gorm:"not null"                                        json:"revision_number"`
+	AuthorID         uuid.UUID `gorm:"type:uuid;not null"                              json:"author_id"`
+	Autosave         bool      `gorm:"not null;default:false"                          json:"autosave"`
+	NoteType         string    `gorm:"-"                                               json:"note_type"`
+	Subjective       *string   `gorm:"-"                                               json:"subjective"`
+	Objective        *string   `gorm:"-"                                               json:"objective"`
+	Assessment       *string   `gorm:"-"                                               json:"assessment"`
+	Plan             *string   `gorm:"-"                                               json:"plan"`
+	ContentEncrypted []byte    `gorm:"type:bytea;not null"                             json:"-"`
+	KeyID            string    `gorm:"type:varchar(255);not null"                      json:"-"`
+	CreatedAt        time.Time `gorm:"autoCreateTime"                                  json:"created_at"`
+}
+
+func (Revision) TableName() string {
+	return "clinical_note_revisions"
+}
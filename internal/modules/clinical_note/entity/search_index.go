@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchDocument marks a clinical note as indexed for keyword search
+type SearchDocument struct {
+	NoteID         uuid.UUID `gorm:"primaryKey;type:uuid"    json:"note_id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null"      json:"organization_id"`
+	IndexedAt      time.Time `gorm:"not null;autoCreateTime" json:"indexed_at"`
+}
+
+func (SearchDocument) TableName() string {
+	return "clinical_note_search_documents"
+}
+
+// SearchToken is a keyed hash of a word of a clinical note, with the number
+// of times the word appears in the note
+type SearchToken struct {
+	NoteID         uuid.UUID `gorm:"primaryKey;type:uuid"        json:"note_id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null"          json:"organization_id"`
+	Token          string    `gorm:"primaryKey;type:varchar(64)" json:"token"`
+	Frequency      int       `gorm:"not null;default:1"          json:"frequency"`
+}
+
+func (SearchToken) TableName() string {
+	return "clinical_note_search_tokens"
+}
+
+// SearchMatch is a note matching a keyword search. MatchedTokens counts the
+// distinct query words found in the note and Hits how often they occur.
+type SearchMatch struct {
+	NoteID        uuid.UUID
+	MatchedTokens int
+	Hits          int
+}
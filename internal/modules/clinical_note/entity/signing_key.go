@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SigningKey is a clinician's Ed25519 key for signing notes and addendums in
+// an organization. The private key is encrypted with the organization's data
+// key under KeyID; the public key and its fingerprint stay readable so that
+// signatures remain verifiable.
+type SigningKey struct {
+	ID                  uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	OrganizationID      uuid.UUID  `gorm:"type:uuid;not null"                              json:"organization_id"`
+	UserID              uuid.UUID  `gorm:"type:uuid;not null"                              json:"user_id"`
+	PublicKey           []byte     `gorm:"type:bytea;not null"                             json:"-"`
+	PrivateKeyEncrypted []byte     `gorm:"type:bytea;not null"                             json:"-"`
+	KeyID               string     `gorm:"type:varchar(255);not null"                      json:"-"`
+	Fingerprint         string     `gorm:"type:varchar(64);not null"                       json:"fingerprint"`
+	CreatedAt           time.Time  `gorm:"autoCreateTime"                                  json:"created_at"`
+	RevokedAt           *time.Time `gorm:""                                                json:"revoked_at"`
+}
+
+func (SigningKey) TableName() string {
+	return "clinician_signing_keys"
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/dto"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/service"
+	patientEntity "github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
 )
 
@@ -22,18 +23,12 @@ func NewClinicalNoteHandler(svc service.ClinicalNoteService) *ClinicalNoteHandle
 }
 
 func (h *ClinicalNoteHandler) Create(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(patientEntity.Access)
 
 	var req dto.CreateClinicalNoteRequest
 	if err := c.BindAndValidate(&req); err != nil {
@@ -41,11 +36,16 @@ func (h *ClinicalNoteHandler) Create(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
+	if req.IsSigned && !recentlyAuthenticated(c) {
+		response.ReauthenticationRequired(c)
+		return
+	}
+
 	// Ensure clinician_id is set to the current user if not provided or valid?
 	// For now, trust the input but maybe validate it belongs to org?
 	// The requirement says "trust me bro" for now, so let's stick to simple.
 
-	resp, err := h.svc.Create(context.Background(), req, orgID)
+	resp, err := h.svc.Create(context.Background(), req, access)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -54,19 +54,21 @@ func (h *ClinicalNoteHandler) Create(_ context.Context, c *app.RequestContext) {
 	response.Created(c, resp, "Clinical note created successfully")
 }
 
+// recentlyAuthenticated reports whether the user confirmed their identity
+// within the step-up window, which signing a note requires. It is always
+// false for API keys.
+func recentlyAuthenticated(c *app.RequestContext) bool {
+	recent, _ := c.Get("recentlyAuthenticated")
+	return recent == true
+}
+
 func (h *ClinicalNoteHandler) List(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(patientEntity.Access)
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
@@ -77,7 +79,7 @@ func (h *ClinicalNoteHandler) List(_ context.Context, c *app.RequestContext) {
 		pageSize = 10
 	}
 
-	resp, total, err := h.svc.List(context.Background(), orgID, page, pageSize)
+	resp, total, err := h.svc.List(context.Background(), access, page, pageSize)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -91,20 +93,44 @@ func (h *ClinicalNoteHandler) List(_ context.Context, c *app.RequestContext) {
 	}))
 }
 
-func (h *ClinicalNoteHandler) Get(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+// Search returns the IDs of notes matching the words of the q query
+// parameter, best matches first
+func (h *ClinicalNoteHandler) Search(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
+	access := accessVal.(patientEntity.Access)
+
+	query := c.Query("q")
+	if query == "" {
+		response.BadRequest(c, "Search query is required", nil)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
 
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
+	resp, err := h.svc.Search(context.Background(), access, query, limit)
 	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
+		response.HandleError(c, err)
 		return
 	}
 
+	c.JSON(consts.StatusOK, response.Success("Clinical notes searched successfully", resp))
+}
+
+func (h *ClinicalNoteHandler) Get(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(patientEntity.Access)
+
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -112,7 +138,7 @@ func (h *ClinicalNoteHandler) Get(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
-	resp, err := h.svc.Get(context.Background(), id, orgID)
+	resp, err := h.svc.Get(context.Background(), id, access)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -121,20 +147,166 @@ func (h *ClinicalNoteHandler) Get(_ context.Context, c *app.RequestContext) {
 	c.JSON(consts.StatusOK, response.Success("Clinical note retrieved successfully", resp))
 }
 
-func (h *ClinicalNoteHandler) Update(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+// VerifySignature reports whether the note and its addendums are unchanged
+// since they were signed
+func (h *ClinicalNoteHandler) VerifySignature(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
+	access := accessVal.(patientEntity.Access)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid clinical note ID", nil)
+		return
+	}
+
+	resp, err := h.svc.VerifySignature(context.Background(), id, access)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Clinical note signatures verified", resp))
+}
+
+// ReviewQueue lists the notes awaiting review by the current user as a
+// supervisor
+func (h *ClinicalNoteHandler) ReviewQueue(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(patientEntity.Access)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	resp, total, err := h.svc.ReviewQueue(context.Background(), access, page, pageSize)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
 
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
+	c.JSON(consts.StatusOK, response.Success("Review queue retrieved successfully", map[string]interface{}{
+		"items":     resp,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
+
+// Submit signs the note of a supervised clinician and sends it for review
+func (h *ClinicalNoteHandler) Submit(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(patientEntity.Access)
+
+	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
+		response.BadRequest(c, "Invalid clinical note ID", nil)
 		return
 	}
 
+	if !recentlyAuthenticated(c) {
+		response.ReauthenticationRequired(c)
+		return
+	}
+
+	resp, err := h.svc.Submit(context.Background(), id, access)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Clinical note submitted for review", resp))
+}
+
+// Return sends a submitted note back to its clinician with comments
+func (h *ClinicalNoteHandler) Return(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(patientEntity.Access)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid clinical note ID", nil)
+		return
+	}
+
+	var req dto.ReviewNoteRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.Return(context.Background(), id, access, req)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Clinical note returned to its clinician", resp))
+}
+
+// CoSign countersigns a submitted note as its clinician's supervisor
+func (h *ClinicalNoteHandler) CoSign(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(patientEntity.Access)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid clinical note ID", nil)
+		return
+	}
+
+	var req dto.ReviewNoteRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	if !recentlyAuthenticated(c) {
+		response.ReauthenticationRequired(c)
+		return
+	}
+
+	resp, err := h.svc.CoSign(context.Background(), id, access, req)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Clinical note co-signed successfully", resp))
+}
+
+func (h *ClinicalNoteHandler) Update(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(patientEntity.Access)
+
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -148,7 +320,12 @@ func (h *ClinicalNoteHandler) Update(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
-	resp, err := h.svc.Update(context.Background(), id, orgID, req)
+	if req.IsSigned != nil && *req.IsSigned && !recentlyAuthenticated(c) {
+		response.ReauthenticationRequired(c)
+		return
+	}
+
+	resp, err := h.svc.Update(context.Background(), id, access, req)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -157,20 +334,150 @@ func (h *ClinicalNoteHandler) Update(_ context.Context, c *app.RequestContext) {
 	c.JSON(consts.StatusOK, response.Success("Clinical note updated successfully", resp))
 }
 
-func (h *ClinicalNoteHandler) Delete(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+// Autosave saves the content of a note while it is edited. Autosaves that
+// continue an editing session are left out of the audit log.
+func (h *ClinicalNoteHandler) Autosave(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
+	access := accessVal.(patientEntity.Access)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid clinical note ID", nil)
+		return
+	}
 
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
+	var req dto.AutosaveClinicalNoteRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, audit, err := h.svc.Autosave(context.Background(), id, access, req)
 	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
+		response.HandleError(c, err)
 		return
 	}
 
+	if !audit {
+		c.Set("auditSuppressed", true)
+	}
+	c.JSON(consts.StatusOK, response.Success("Clinical note saved", resp))
+}
+
+func (h *ClinicalNoteHandler) ListRevisions(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(patientEntity.Access)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid clinical note ID", nil)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	resp, total, err := h.svc.ListRevisions(context.Background(), id, access, page, pageSize)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Clinical note revisions retrieved successfully", map[string]interface{}{
+		"items":     resp,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
+
+func (h *ClinicalNoteHandler) GetRevision(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(patientEntity.Access)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid clinical note ID", nil)
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || number < 1 {
+		response.BadRequest(c, "Invalid revision number", nil)
+		return
+	}
+
+	resp, err := h.svc.GetRevision(context.Background(), id, access, number)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Clinical note revision retrieved successfully", resp))
+}
+
+// DiffRevisions compares the revisions given by the from and to query
+// parameters
+func (h *ClinicalNoteHandler) DiffRevisions(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(patientEntity.Access)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid clinical note ID", nil)
+		return
+	}
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from < 1 {
+		response.BadRequest(c, "Invalid from revision number", nil)
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil || to < 1 {
+		response.BadRequest(c, "Invalid to revision number", nil)
+		return
+	}
+
+	resp, err := h.svc.DiffRevisions(context.Background(), id, access, from, to)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Clinical note revisions compared successfully", resp))
+}
+
+func (h *ClinicalNoteHandler) Delete(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(patientEntity.Access)
+
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -178,7 +485,7 @@ func (h *ClinicalNoteHandler) Delete(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
-	if err := h.svc.Delete(context.Background(), id, orgID); err != nil {
+	if err := h.svc.Delete(context.Background(), id, access); err != nil {
 		response.HandleError(c, err)
 		return
 	}
@@ -194,11 +501,12 @@ func (h *ClinicalNoteHandler) AddAddendum(_ context.Context, c *app.RequestConte
 	}
 	userID := userIDVal.(uuid.UUID)
 
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
 		return
 	}
+	access := accessVal.(patientEntity.Access)
 
 	idStr := c.Param("id")
 	noteID, err := uuid.Parse(idStr)
@@ -213,10 +521,16 @@ func (h *ClinicalNoteHandler) AddAddendum(_ context.Context, c *app.RequestConte
 		return
 	}
 
+	// Addendums are signed with the clinician's key as they are added
+	if !recentlyAuthenticated(c) {
+		response.ReauthenticationRequired(c)
+		return
+	}
+
 	// Override clinician_id with current user for security
 	req.ClinicianID = userID
 
-	resp, err := h.svc.AddAddendum(context.Background(), noteID, orgID, req)
+	resp, err := h.svc.AddAddendum(context.Background(), noteID, access, req)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -226,18 +540,12 @@ func (h *ClinicalNoteHandler) AddAddendum(_ context.Context, c *app.RequestConte
 }
 
 func (h *ClinicalNoteHandler) UploadAttachment(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(patientEntity.Access)
 
 	idStr := c.Param("id")
 	noteID, err := uuid.Parse(idStr)
@@ -269,7 +577,7 @@ func (h *ClinicalNoteHandler) UploadAttachment(_ context.Context, c *app.Request
 	resp, err := h.svc.UploadAttachment(
 		context.Background(),
 		noteID,
-		orgID,
+		access,
 		file.Filename,
 		file.Header.Get("Content-Type"),
 		data,
@@ -283,18 +591,12 @@ func (h *ClinicalNoteHandler) UploadAttachment(_ context.Context, c *app.Request
 }
 
 func (h *ClinicalNoteHandler) DownloadAttachment(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(patientEntity.Access)
 
 	attachmentIDStr := c.Param("attachment_id")
 	attachmentID, err := uuid.Parse(attachmentIDStr)
@@ -303,7 +605,7 @@ func (h *ClinicalNoteHandler) DownloadAttachment(_ context.Context, c *app.Reque
 		return
 	}
 
-	fileName, data, contentType, err := h.svc.DownloadAttachment(context.Background(), attachmentID, orgID)
+	fileName, data, contentType, err := h.svc.DownloadAttachment(context.Background(), attachmentID, access)
 	if err != nil {
 		response.HandleError(c, err)
 		return
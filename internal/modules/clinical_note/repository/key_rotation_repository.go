@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/entity"
+	organizationEntity "github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// encryptedTable is where records of one kind keep their ciphertext.
+// Addendums and attachments belong to an organization through their note.
+// Encoded columns hold text with the key ID in front, as SSO client secrets
+// do, instead of separate nonce and key ID columns.
+type encryptedTable struct {
+	name    string
+	column  string
+	viaNote bool
+	encoded bool
+}
+
+var encryptedTables = map[string]encryptedTable{
+	entity.KeyRotationNotes:       {name: "clinical_notes", column: "content_encrypted"},
+	entity.KeyRotationAddendums:   {name: "clinical_note_addendums", column: "content_encrypted", viaNote: true},
+	entity.KeyRotationAttachments: {name: "clinical_note_attachments", column: "data_encrypted", viaNote: true},
+	entity.KeyRotationSSOSecrets:  {name: "organization_sso_configs", column: "client_secret", encoded: true},
+}
+
+// sharedKeyCondition matches records still encrypted with a shared keyring
+// key rather than their organization's data key
+func (t encryptedTable) sharedKeyCondition(alias string) string {
+	column := alias + "key_id"
+	if t.encoded {
+		column = alias + t.column
+	}
+	return column + " NOT LIKE '" + organizationEntity.DataKeyIDPrefix + "%'"
+}
+
+// KeyRotationRepository stores key rotation progress and reads and rewrites
+// encrypted records still under a shared keyring key. It works on the tables
+// directly, bypassing model hooks, so that signed and soft-deleted notes are
+// re-encrypted too.
+type KeyRotationRepository interface {
+	FindRunning() (*entity.KeyRotation, error)
+	Create(rotation *entity.KeyRotation) error
+	Save(rotation *entity.KeyRotation) error
+	CountPending() (int64, error)
+	ListPending(kind string, after uuid.UUID, limit int) ([]entity.EncryptedRecord, error)
+	Reencrypt(kind string, id uuid.UUID, oldKeyID string, encrypted, nonce []byte, keyID string) (bool, error)
+}
+
+type keyRotationRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewKeyRotationRepository(db *gorm.DB, log logger.Logger) KeyRotationRepository {
+	return &keyRotationRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *keyRotationRepository) FindRunning() (*entity.KeyRotation, error) {
+	var rotation entity.KeyRotation
+	err := r.db.Where("status = ?", entity.KeyRotationRunning).Order("started_at DESC").First(&rotation).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find running key rotation", zap.Error(err))
+		}
+		return nil, err
+	}
+	return &rotation, nil
+}
+
+func (r *keyRotationRepository) Create(rotation *entity.KeyRotation) error {
+	if err := r.db.Create(rotation).Error; err != nil {
+		r.log.Error("Failed to create key rotation", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *keyRotationRepository) Save(rotation *entity.KeyRotation) error {
+	if err := r.db.Save(rotation).Error; err != nil {
+		r.log.Error("Failed to save key rotation", zap.Error(err), zap.String("id", rotation.ID.String()))
+		return err
+	}
+	return nil
+}
+
+// CountPending counts records of every kind still under a shared key
+func (r *keyRotationRepository) CountPending() (int64, error) {
+	var total int64
+	for _, table := range encryptedTables {
+		var count int64
+		err := r.db.Table(table.name).
+			Where(table.column + " IS NOT NULL AND " + table.sharedKeyCondition("")).
+			Count(&count).Error
+		if err != nil {
+			r.log.Error("Failed to count records pending key rotation", zap.Error(err), zap.String("table", table.name))
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// ListPending returns records of the given kind still under a shared key,
+// ordered by ID and starting after the given one
+func (r *keyRotationRepository) ListPending(kind string, after uuid.UUID, limit int) ([]entity.EncryptedRecord, error) {
+	table, ok := encryptedTables[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown encrypted record kind %q", kind)
+	}
+
+	orgColumn := "t.organization_id"
+	query := r.db.Table(table.name + " t")
+	if table.viaNote {
+		orgColumn = "n.organization_id"
+		query = query.Joins("JOIN clinical_notes n ON n.id = t.note_id")
+	}
+
+	query = query.
+		Where("t."+table.column+" IS NOT NULL AND "+table.sharedKeyCondition("t.")+" AND t.id > ?", after).
+		Order("t.id").
+		Limit(limit)
+
+	var records []entity.EncryptedRecord
+	var err error
+	if table.encoded {
+		records, err = r.listEncoded(query, table, orgColumn)
+	} else {
+		err = query.
+			Select("t.id, t." + table.column + " AS encrypted, t.key_id, " + orgColumn + " AS organization_id").
+			Scan(&records).Error
+	}
+	if err != nil {
+		r.log.Error("Failed to list records pending key rotation", zap.Error(err), zap.String("table", table.name))
+		return nil, err
+	}
+	return records, nil
+}
+
+// listEncoded reads records whose key ID is stored in front of the ciphertext
+func (r *keyRotationRepository) listEncoded(
+	query *gorm.DB,
+	table encryptedTable,
+	orgColumn string,
+) ([]entity.EncryptedRecord, error) {
+	var rows []struct {
+		ID             uuid.UUID
+		OrganizationID uuid.UUID
+		Encoded        string
+	}
+	err := query.
+		Select("t.id, t." + table.column + " AS encoded, " + orgColumn + " AS organization_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]entity.EncryptedRecord, 0, len(rows))
+	for _, row := range rows {
+		ciphertext, keyID, err := organizationEntity.DecodeClientSecret(row.Encoded)
+		if err != nil {
+			return nil, fmt.Errorf("record %s: %w", row.ID, err)
+		}
+		records = append(records, entity.EncryptedRecord{
+			ID:             row.ID,
+			OrganizationID: row.OrganizationID,
+			Encrypted:      ciphertext,
+			KeyID:          keyID,
+		})
+	}
+	return records, nil
+}
+
+// Reencrypt replaces the ciphertext of a record, provided it is still
+// encrypted with oldKeyID. It reports whether the record was updated.
+func (r *keyRotationRepository) Reencrypt(
+	kind string,
+	id uuid.UUID,
+	oldKeyID string,
+	encrypted, nonce []byte,
+	keyID string,
+) (bool, error) {
+	table, ok := encryptedTables[kind]
+	if !ok {
+		return false, fmt.Errorf("unknown encrypted record kind %q", kind)
+	}
+
+	var result *gorm.DB
+	if table.encoded {
+		// The old key ID is part of the stored text, and a secret replaced in
+		// the meantime is already under the data key
+		result = r.db.Table(table.name).
+			Where("id = ? AND "+table.sharedKeyCondition(""), id).
+			UpdateColumn(table.column, organizationEntity.EncodeClientSecret(encrypted, keyID))
+	} else {
+		result = r.db.Table(table.name).
+			Where("id = ? AND key_id = ?", id, oldKeyID).
+			UpdateColumns(map[string]interface{}{
+				table.column: encrypted,
+				"nonce":      nonce,
+				"key_id":     keyID,
+			})
+	}
+	if result.Error != nil {
+		r.log.Error(
+			"Failed to re-encrypt record",
+			zap.Error(result.Error),
+			zap.String("table", table.name),
+			zap.String("id", id.String()),
+		)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
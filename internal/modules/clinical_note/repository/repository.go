@@ -8,19 +8,36 @@ import (
 	"github.com/sahabatharianmu/OpenMind/pkg/logger"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrNoteLocked is returned when saving a note that was signed or submitted
+// for review after it was loaded
+var ErrNoteLocked = errors.New("clinical note was signed or submitted for review in the meantime")
+
 type ClinicalNoteRepository interface {
 	Create(note *entity.ClinicalNote) error
 	Update(note *entity.ClinicalNote) error
 	Delete(id uuid.UUID) error
 	FindByID(id uuid.UUID) (*entity.ClinicalNote, error)
 	FindByAppointmentID(appointmentID uuid.UUID) (*entity.ClinicalNote, error)
-	List(organizationID uuid.UUID, limit, offset int) ([]entity.ClinicalNote, int64, error)
+	List(
+		organizationID uuid.UUID,
+		limit, offset int,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) ([]entity.ClinicalNote, int64, error)
+	ListPendingReview(
+		organizationID uuid.UUID,
+		clinicianIDs []uuid.UUID,
+		limit, offset int,
+	) ([]entity.ClinicalNote, int64, error)
+	AddReview(note *entity.ClinicalNote, review *entity.Review) error
+	ListRevisions(noteID uuid.UUID, limit, offset int) ([]entity.Revision, int64, error)
+	FindRevision(noteID uuid.UUID, number int) (*entity.Revision, error)
+	LatestRevision(noteID uuid.UUID) (*entity.Revision, error)
 	AddAddendum(addendum *entity.Addendum) error
 	AddAttachment(attachment *entity.Attachment) error
 	GetAttachmentByID(id uuid.UUID) (*entity.Attachment, error)
-	GetOrganizationID(userID uuid.UUID) (uuid.UUID, error)
 }
 
 type clinicalNoteRepository struct {
@@ -35,22 +52,100 @@ func NewClinicalNoteRepository(db *gorm.DB, log logger.Logger) ClinicalNoteRepos
 	}
 }
 
+// Create stores the note along with its search index entries and new
+// revisions, if it has any
 func (r *clinicalNoteRepository) Create(note *entity.ClinicalNote) error {
-	if err := r.db.Create(note).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(note).Error; err != nil {
+			return err
+		}
+		if err := writeRevisions(tx, note); err != nil {
+			return err
+		}
+		return writeSearchTokens(tx, note)
+	})
+	if err != nil {
 		r.log.Error("Failed to create clinical note", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
+// Update saves the note along with its search index entries and new
+// revisions, if it has any. The note's row is locked first, so concurrent
+// saves of the note run one after the other, and the save fails with
+// ErrNoteLocked if the note has been signed or submitted for review since it
+// was loaded. Revisions are written before the note, since none may be added
+// once the note is signed.
 func (r *clinicalNoteRepository) Update(note *entity.ClinicalNote) error {
-	if err := r.db.Save(note).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockEditable(tx, note.ID); err != nil {
+			return err
+		}
+		if err := writeRevisions(tx, note); err != nil {
+			return err
+		}
+		if err := tx.Save(note).Error; err != nil {
+			return err
+		}
+		return writeSearchTokens(tx, note)
+	})
+	if err != nil && !errors.Is(err, ErrNoteLocked) {
 		r.log.Error("Failed to update clinical note", zap.Error(err), zap.String("id", note.ID.String()))
+	}
+	return err
+}
+
+// lockEditable locks the note's row until the end of the transaction and
+// checks that the note can still be changed
+func lockEditable(tx *gorm.DB, id uuid.UUID) error {
+	var stored entity.ClinicalNote
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "is_signed", "status").
+		First(&stored, "id = ?", id).Error
+	if err != nil {
 		return err
 	}
+	if stored.IsSigned || stored.Status == entity.NoteStatusPendingReview {
+		return ErrNoteLocked
+	}
 	return nil
 }
 
+// writeSearchTokens replaces the note's search index entries when the note
+// carries them
+func writeSearchTokens(tx *gorm.DB, note *entity.ClinicalNote) error {
+	if note.SearchTokens == nil {
+		return nil
+	}
+	return replaceSearchTokens(tx, note.ID, note.OrganizationID, note.SearchTokens)
+}
+
+// writeRevisions numbers the note's new revisions after its existing ones
+// and stores them. Callers updating an existing note hold its row lock, so
+// the numbers cannot be taken by a concurrent save.
+func writeRevisions(tx *gorm.DB, note *entity.ClinicalNote) error {
+	if len(note.NewRevisions) == 0 {
+		return nil
+	}
+
+	var last int
+	err := tx.Model(&entity.Revision{}).
+		Select("COALESCE(MAX(revision_number), 0)").
+		Where("note_id = ?", note.ID).
+		Scan(&last).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range note.NewRevisions {
+		note.NewRevisions[i].NoteID = note.ID
+		note.NewRevisions[i].OrganizationID = note.OrganizationID
+		note.NewRevisions[i].RevisionNumber = last + i + 1
+	}
+	return tx.Create(&note.NewRevisions).Error
+}
+
 func (r *clinicalNoteRepository) Delete(id uuid.UUID) error {
 	if err := r.db.Delete(&entity.ClinicalNote{}, "id = ?", id).Error; err != nil {
 		r.log.Error("Failed to delete clinical note", zap.Error(err), zap.String("id", id.String()))
@@ -61,7 +156,11 @@ func (r *clinicalNoteRepository) Delete(id uuid.UUID) error {
 
 func (r *clinicalNoteRepository) FindByID(id uuid.UUID) (*entity.ClinicalNote, error) {
 	var note entity.ClinicalNote
-	if err := r.db.Preload("Addendums").Preload("Attachments").First(&note, "id = ?", id).Error; err != nil {
+	err := r.db.Preload("Addendums").
+		Preload("Attachments").
+		Preload("Reviews", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		First(&note, "id = ?", id).Error
+	if err != nil {
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			r.log.Error("Failed to find clinical note", zap.Error(err), zap.String("id", id.String()))
 		}
@@ -88,11 +187,12 @@ func (r *clinicalNoteRepository) FindByAppointmentID(appointmentID uuid.UUID) (*
 func (r *clinicalNoteRepository) List(
 	organizationID uuid.UUID,
 	limit, offset int,
+	scopes ...func(*gorm.DB) *gorm.DB,
 ) ([]entity.ClinicalNote, int64, error) {
 	var notes []entity.ClinicalNote
 	var total int64
 
-	query := r.db.Model(&entity.ClinicalNote{}).Where("organization_id = ?", organizationID)
+	query := r.db.Model(&entity.ClinicalNote{}).Where("organization_id = ?", organizationID).Scopes(scopes...)
 
 	if err := query.Count(&total).Error; err != nil {
 		r.log.Error("Failed to count clinical notes", zap.Error(err))
@@ -107,6 +207,117 @@ func (r *clinicalNoteRepository) List(
 	return notes, total, nil
 }
 
+// ListPendingReview returns the notes of the clinicians that await review,
+// oldest submission first
+func (r *clinicalNoteRepository) ListPendingReview(
+	organizationID uuid.UUID,
+	clinicianIDs []uuid.UUID,
+	limit, offset int,
+) ([]entity.ClinicalNote, int64, error) {
+	var notes []entity.ClinicalNote
+	var total int64
+
+	query := r.db.Model(&entity.ClinicalNote{}).
+		Where("organization_id = ? AND status = ? AND clinician_id IN ?",
+			organizationID, entity.NoteStatusPendingReview, clinicianIDs)
+
+	if err := query.Count(&total).Error; err != nil {
+		r.log.Error("Failed to count clinical notes pending review", zap.Error(err))
+		return nil, 0, err
+	}
+
+	err := query.Preload("Addendums").
+		Preload("Attachments").
+		Preload("Reviews", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Limit(limit).
+		Offset(offset).
+		Order("submitted_at asc").
+		Find(&notes).Error
+	if err != nil {
+		r.log.Error("Failed to list clinical notes pending review", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return notes, total, nil
+}
+
+// AddReview saves the note's review state along with the supervisor's review
+func (r *clinicalNoteRepository) AddReview(note *entity.ClinicalNote, review *entity.Review) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(note).Error; err != nil {
+			return err
+		}
+		return tx.Create(review).Error
+	})
+	if err != nil {
+		r.log.Error("Failed to review clinical note", zap.Error(err), zap.String("id", note.ID.String()))
+		return err
+	}
+	return nil
+}
+
+// ListRevisions returns the note's revisions without their content, newest
+// first
+func (r *clinicalNoteRepository) ListRevisions(noteID uuid.UUID, limit, offset int) ([]entity.Revision, int64, error) {
+	var revisions []entity.Revision
+	var total int64
+
+	query := r.db.Model(&entity.Revision{}).Where("note_id = ?", noteID)
+
+	if err := query.Count(&total).Error; err != nil {
+		r.log.Error("Failed to count clinical note revisions", zap.Error(err), zap.String("note_id", noteID.String()))
+		return nil, 0, err
+	}
+
+	err := query.Omit("content_encrypted").
+		Limit(limit).
+		Offset(offset).
+		Order("revision_number desc").
+		Find(&revisions).Error
+	if err != nil {
+		r.log.Error("Failed to list clinical note revisions", zap.Error(err), zap.String("note_id", noteID.String()))
+		return nil, 0, err
+	}
+
+	return revisions, total, nil
+}
+
+func (r *clinicalNoteRepository) FindRevision(noteID uuid.UUID, number int) (*entity.Revision, error) {
+	var revision entity.Revision
+	if err := r.db.First(&revision, "note_id = ? AND revision_number = ?", noteID, number).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error(
+				"Failed to find clinical note revision",
+				zap.Error(err),
+				zap.String("note_id", noteID.String()),
+				zap.Int("revision", number),
+			)
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// LatestRevision returns the note's last revision without its content
+func (r *clinicalNoteRepository) LatestRevision(noteID uuid.UUID) (*entity.Revision, error) {
+	var revision entity.Revision
+	err := r.db.Omit("content_encrypted").
+		Where("note_id = ?", noteID).
+		Order("revision_number desc").
+		First(&revision).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error(
+				"Failed to find latest clinical note revision",
+				zap.Error(err),
+				zap.String("note_id", noteID.String()),
+			)
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
 func (r *clinicalNoteRepository) AddAddendum(addendum *entity.Addendum) error {
 	if err := r.db.Create(addendum).Error; err != nil {
 		r.log.Error("Failed to add addendum", zap.Error(err), zap.String("note_id", addendum.NoteID.String()))
@@ -133,15 +344,3 @@ func (r *clinicalNoteRepository) GetAttachmentByID(id uuid.UUID) (*entity.Attach
 	}
 	return &attachment, nil
 }
-
-func (r *clinicalNoteRepository) GetOrganizationID(userID uuid.UUID) (uuid.UUID, error) {
-	var orgIDStr string
-	if err := r.db.Table("organization_members").Select("organization_id").Where("user_id = ?", userID).Limit(1).Scan(&orgIDStr).Error; err != nil {
-		r.log.Error("Failed to get organization ID", zap.Error(err), zap.String("user_id", userID.String()))
-		return uuid.Nil, err
-	}
-	if orgIDStr == "" {
-		return uuid.Nil, errors.New("organization not found for user")
-	}
-	return uuid.Parse(orgIDStr)
-}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SearchRepository stores and queries the keyword search index of clinical
+// notes. It only ever handles keyed hashes of words, never the words.
+type SearchRepository interface {
+	SearchEnabled(organizationID uuid.UUID) (bool, error)
+	Search(
+		organizationID uuid.UUID,
+		tokens []string,
+		limit int,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) ([]entity.SearchMatch, error)
+	ListUnindexed(after uuid.UUID, limit int) ([]entity.EncryptedRecord, error)
+	Index(noteID, organizationID uuid.UUID, tokens []entity.SearchToken) error
+	PurgeDisabled() (int64, error)
+}
+
+type searchRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewSearchRepository(db *gorm.DB, log logger.Logger) SearchRepository {
+	return &searchRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// SearchEnabled reports whether the organization has opted in to note search
+func (r *searchRepository) SearchEnabled(organizationID uuid.UUID) (bool, error) {
+	var enabled bool
+	err := r.db.Table("organizations").
+		Select("note_search_enabled").
+		Where("id = ?", organizationID).
+		Scan(&enabled).Error
+	if err != nil {
+		r.log.Error("Failed to check note search setting", zap.Error(err), zap.String("org_id", organizationID.String()))
+		return false, err
+	}
+	return enabled, nil
+}
+
+// Search returns the organization's notes containing any of the tokens. Notes
+// matching more distinct tokens rank first, then notes where they occur more
+// often, then newer notes.
+func (r *searchRepository) Search(
+	organizationID uuid.UUID,
+	tokens []string,
+	limit int,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]entity.SearchMatch, error) {
+	var matches []entity.SearchMatch
+	err := r.db.Table("clinical_note_search_tokens t").
+		Select("t.note_id, COUNT(*) AS matched_tokens, SUM(t.frequency) AS hits").
+		Joins("JOIN clinical_notes n ON n.id = t.note_id AND n.deleted_at IS NULL").
+		Where("t.organization_id = ? AND t.token IN ?", organizationID, tokens).
+		Scopes(scopes...).
+		Group("t.note_id, n.created_at").
+		Order("matched_tokens DESC, hits DESC, n.created_at DESC").
+		Limit(limit).
+		Scan(&matches).Error
+	if err != nil {
+		r.log.Error("Failed to search clinical notes", zap.Error(err), zap.String("org_id", organizationID.String()))
+		return nil, err
+	}
+	return matches, nil
+}
+
+// ListUnindexed returns notes of organizations with search enabled that are
+// not indexed yet, ordered by ID and starting after the given one
+func (r *searchRepository) ListUnindexed(after uuid.UUID, limit int) ([]entity.EncryptedRecord, error) {
+	var records []entity.EncryptedRecord
+	err := r.db.Table("clinical_notes n").
+		Select("n.id, n.organization_id, n.content_encrypted AS encrypted, n.key_id").
+		Joins("JOIN organizations o ON o.id = n.organization_id AND o.note_search_enabled").
+		Where("n.deleted_at IS NULL AND n.content_encrypted IS NOT NULL AND n.id > ?", after).
+		Where("NOT EXISTS (SELECT 1 FROM clinical_note_search_documents d WHERE d.note_id = n.id)").
+		Order("n.id").
+		Limit(limit).
+		Scan(&records).Error
+	if err != nil {
+		r.log.Error("Failed to list unindexed clinical notes", zap.Error(err))
+		return nil, err
+	}
+	return records, nil
+}
+
+// Index replaces the search index entries of a note
+func (r *searchRepository) Index(noteID, organizationID uuid.UUID, tokens []entity.SearchToken) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceSearchTokens(tx, noteID, organizationID, tokens)
+	})
+	if err != nil {
+		r.log.Error("Failed to index clinical note", zap.Error(err), zap.String("note_id", noteID.String()))
+		return err
+	}
+	return nil
+}
+
+// PurgeDisabled removes the index of every organization that has turned note
+// search off. It returns the number of notes removed from the index.
+func (r *searchRepository) PurgeDisabled() (int64, error) {
+	disabled := r.db.Table("organizations").Select("id").Where("NOT note_search_enabled")
+
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_id IN (?)", disabled).Delete(&entity.SearchToken{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("organization_id IN (?)", disabled).Delete(&entity.SearchDocument{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		r.log.Error("Failed to purge disabled note search indexes", zap.Error(err))
+		return 0, err
+	}
+	return purged, nil
+}
+
+// replaceSearchTokens writes the search index entries of a note in place of
+// its previous ones and marks it as indexed
+func replaceSearchTokens(tx *gorm.DB, noteID, organizationID uuid.UUID, tokens []entity.SearchToken) error {
+	if err := tx.Where("note_id = ?", noteID).Delete(&entity.SearchToken{}).Error; err != nil {
+		return err
+	}
+	for i := range tokens {
+		tokens[i].NoteID = noteID
+		tokens[i].OrganizationID = organizationID
+	}
+	if len(tokens) > 0 {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tokens).Error; err != nil {
+			return err
+		}
+	}
+
+	document := entity.SearchDocument{NoteID: noteID, OrganizationID: organizationID}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "note_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"indexed_at": gorm.Expr("CURRENT_TIMESTAMP")}),
+	}).Create(&document).Error
+}
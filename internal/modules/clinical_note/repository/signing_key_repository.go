@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type SigningKeyRepository interface {
+	FindActive(organizationID, userID uuid.UUID) (*entity.SigningKey, error)
+	FindByFingerprint(fingerprint string) (*entity.SigningKey, error)
+	Create(key *entity.SigningKey) error
+}
+
+type signingKeyRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewSigningKeyRepository(db *gorm.DB, log logger.Logger) SigningKeyRepository {
+	return &signingKeyRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *signingKeyRepository) FindActive(organizationID, userID uuid.UUID) (*entity.SigningKey, error) {
+	var key entity.SigningKey
+	err := r.db.First(&key, "organization_id = ? AND user_id = ? AND revoked_at IS NULL", organizationID, userID).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find signing key", zap.Error(err), zap.String("user_id", userID.String()))
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByFingerprint finds a key whether or not it has been revoked, since
+// signatures made before remain valid
+func (r *signingKeyRepository) FindByFingerprint(fingerprint string) (*entity.SigningKey, error) {
+	var key entity.SigningKey
+	if err := r.db.First(&key, "fingerprint = ?", fingerprint).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find signing key", zap.Error(err), zap.String("fingerprint", fingerprint))
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Create fails when the clinician already has a key in use in the
+// organization
+func (r *signingKeyRepository) Create(key *entity.SigningKey) error {
+	if err := r.db.Create(key).Error; err != nil {
+		r.log.Error("Failed to create signing key", zap.Error(err), zap.String("user_id", key.UserID.String()))
+		return err
+	}
+	return nil
+}
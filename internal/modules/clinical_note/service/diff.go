@@ -0,0 +1,75 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/dto"
+)
+
+// maxDiffCells bounds the work of comparing two texts, which grows with the
+// product of their line counts. Texts beyond it are shown as replaced whole.
+const maxDiffCells = 1_000_000
+
+// diffLines lists the lines of from and to in order, marking each as kept,
+// deleted from from or inserted in to, with as few changes as possible
+func diffLines(from, to string) []dto.DiffLine {
+	a, b := splitLines(from), splitLines(to)
+	lines := make([]dto.DiffLine, 0, len(a)+len(b))
+
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			lines = append(lines, dto.DiffLine{Op: dto.DiffDelete, Text: line})
+		}
+		for _, line := range b {
+			lines = append(lines, dto.DiffLine{Op: dto.DiffInsert, Text: line})
+		}
+		return lines
+	}
+
+	// common[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, dto.DiffLine{Op: dto.DiffEqual, Text: a[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, dto.DiffLine{Op: dto.DiffDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, dto.DiffLine{Op: dto.DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, dto.DiffLine{Op: dto.DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, dto.DiffLine{Op: dto.DiffInsert, Text: b[j]})
+	}
+	return lines
+}
+
+// splitLines splits text into lines, an empty text having none
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/repository"
+	organizationService "github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	keyRotationBatchSize = 100
+	// keyRotationPause spaces out batches so rotation does not starve
+	// regular traffic of database capacity
+	keyRotationPause = 200 * time.Millisecond
+)
+
+// KeyRotationService re-encrypts clinical notes, addendums, attachments and
+// SSO client secrets still under a shared keyring key with their organization's data key.
+// Progress is saved after every batch, so a rotation interrupted by a restart
+// resumes where it stopped. Records that fail, for instance because their key
+// has been removed from the keyring, are counted and skipped; they are
+// retried the next time the service runs.
+type KeyRotationService interface {
+	Run(ctx context.Context)
+}
+
+type keyRotationService struct {
+	repo       repository.KeyRotationRepository
+	dataKeySvc organizationService.DataKeyService
+	log        logger.Logger
+}
+
+func NewKeyRotationService(
+	repo repository.KeyRotationRepository,
+	dataKeySvc organizationService.DataKeyService,
+	log logger.Logger,
+) KeyRotationService {
+	return &keyRotationService{
+		repo:       repo,
+		dataKeySvc: dataKeySvc,
+		log:        log,
+	}
+}
+
+// Run rotates until no record is left under a shared key or ctx is cancelled
+func (s *keyRotationService) Run(ctx context.Context) {
+	rotation, err := s.start()
+	if err != nil || rotation == nil {
+		return
+	}
+
+	for _, kind := range []string{
+		entity.KeyRotationNotes,
+		entity.KeyRotationAddendums,
+		entity.KeyRotationAttachments,
+		entity.KeyRotationSSOSecrets,
+	} {
+		if err := s.rotate(ctx, rotation, kind); err != nil {
+			s.log.Warn(
+				"Encryption key rotation paused; it resumes on next start",
+				zap.Error(err),
+				zap.String("rotation_id", rotation.ID.String()),
+			)
+			return
+		}
+	}
+
+	now := time.Now()
+	rotation.Status = entity.KeyRotationCompleted
+	rotation.CompletedAt = &now
+	if err := s.repo.Save(rotation); err != nil {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("rotation_id", rotation.ID.String()),
+		zap.String("key_id", rotation.TargetKeyID),
+		zap.Int64("notes", rotation.NotesRotated),
+		zap.Int64("addendums", rotation.AddendumsRotated),
+		zap.Int64("attachments", rotation.AttachmentsRotated),
+		zap.Int64("sso_secrets", rotation.SSOSecretsRotated),
+		zap.Int64("failures", rotation.Failures),
+	}
+	if rotation.Failures > 0 {
+		s.log.Warn("Encryption key rotation finished with failures", fields...)
+		return
+	}
+	s.log.Info("Encryption key rotation finished", fields...)
+}
+
+// start resumes the running rotation, or begins a new one when records remain
+// under shared keys. A running rotation to a keyring key, started before
+// organizations had data keys, is superseded.
+func (s *keyRotationService) start() (*entity.KeyRotation, error) {
+	target := entity.KeyRotationTargetDataKeys
+
+	rotation, err := s.repo.FindRunning()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if rotation != nil {
+		if rotation.TargetKeyID == target {
+			s.log.Info(
+				"Resuming encryption key rotation",
+				zap.String("rotation_id", rotation.ID.String()),
+				zap.String("key_id", target),
+			)
+			return rotation, nil
+		}
+
+		now := time.Now()
+		rotation.Status = entity.KeyRotationSuperseded
+		rotation.CompletedAt = &now
+		if err := s.repo.Save(rotation); err != nil {
+			return nil, err
+		}
+	}
+
+	pending, err := s.repo.CountPending()
+	if err != nil || pending == 0 {
+		return nil, err
+	}
+
+	rotation = &entity.KeyRotation{
+		ID:          uuid.New(),
+		TargetKeyID: target,
+		Status:      entity.KeyRotationRunning,
+		StartedAt:   time.Now(),
+	}
+	if err := s.repo.Create(rotation); err != nil {
+		return nil, err
+	}
+
+	s.log.Info(
+		"Starting encryption key rotation",
+		zap.String("rotation_id", rotation.ID.String()),
+		zap.String("key_id", target),
+		zap.Int64("pending", pending),
+	)
+	return rotation, nil
+}
+
+// rotate re-encrypts every pending record of one kind, batch by batch
+func (s *keyRotationService) rotate(ctx context.Context, rotation *entity.KeyRotation, kind string) error {
+	for {
+		records, err := s.repo.ListPending(kind, rotation.Cursor(kind), keyRotationBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		for _, record := range records {
+			rotated, err := s.reencrypt(ctx, kind, record)
+			if err != nil {
+				s.log.Warn(
+					"Failed to re-encrypt record",
+					zap.Error(err),
+					zap.String("kind", kind),
+					zap.String("id", record.ID.String()),
+					zap.String("key_id", record.KeyID),
+				)
+				message := err.Error()
+				rotation.Failures++
+				rotation.LastError = &message
+				continue
+			}
+			if rotated {
+				rotation.CountRotated(kind)
+			}
+		}
+
+		rotation.Advance(kind, records[len(records)-1].ID)
+		if err := s.repo.Save(rotation); err != nil {
+			return err
+		}
+
+		s.log.Info(
+			"Encryption key rotation progress",
+			zap.String("rotation_id", rotation.ID.String()),
+			zap.String("kind", kind),
+			zap.Int64("notes", rotation.NotesRotated),
+			zap.Int64("addendums", rotation.AddendumsRotated),
+			zap.Int64("attachments", rotation.AttachmentsRotated),
+			zap.Int64("sso_secrets", rotation.SSOSecretsRotated),
+			zap.Int64("failures", rotation.Failures),
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(keyRotationPause):
+		}
+	}
+}
+
+// reencrypt decrypts a record with its shared key and stores it encrypted
+// with its organization's data key. The plaintext is kept byte for byte, so
+// readers of every kind of record are unaffected.
+func (s *keyRotationService) reencrypt(ctx context.Context, kind string, record entity.EncryptedRecord) (bool, error) {
+	plaintext, err := s.dataKeySvc.Decrypt(ctx, record.OrganizationID, record.Encrypted, record.KeyID)
+	if err != nil {
+		return false, err
+	}
+
+	encrypted, keyID, err := s.dataKeySvc.Encrypt(ctx, record.OrganizationID, plaintext)
+	if err != nil {
+		return false, err
+	}
+
+	// In GCM, nonce size is 12 bytes
+	const nonceSize = 12
+	return s.repo.Reencrypt(kind, record.ID, record.KeyID, encrypted, encrypted[:nonceSize], keyID)
+}
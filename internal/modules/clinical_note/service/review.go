@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/entity"
+	patientEntity "github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+)
+
+// requireDirectSigning refuses to sign a note when any of the users, its
+// clinician or its signer, is supervised. Their notes are submitted for
+// review and signed by a supervisor's co-signature instead.
+func (s *clinicalNoteService) requireDirectSigning(orgID uuid.UUID, userIDs ...uuid.UUID) error {
+	for _, userID := range userIDs {
+		supervised, err := s.supervisionRepo.IsSupervised(orgID, userID)
+		if err != nil {
+			return response.ErrInternalServerError
+		}
+		if supervised {
+			return response.NewForbidden("Notes of supervised clinicians must be submitted for co-signature")
+		}
+	}
+	return nil
+}
+
+// Submit signs a draft or returned note on behalf of its supervised clinician
+// and sends it to their supervisors for review. The note stays unsigned, and
+// cannot be changed, until a supervisor co-signs or returns it.
+func (s *clinicalNoteService) Submit(
+	ctx context.Context,
+	id uuid.UUID,
+	access patientEntity.Access,
+) (*dto.ClinicalNoteResponse, error) {
+	note, err := s.find(id, access)
+	if err != nil {
+		return nil, err
+	}
+
+	if note.IsSigned {
+		return nil, response.NewConflict("Clinical note has already been signed")
+	}
+	if note.Status == entity.NoteStatusPendingReview {
+		return nil, response.NewConflict("Clinical note is already awaiting review")
+	}
+	if note.ClinicianID != access.UserID {
+		return nil, response.NewForbidden("Only the note's clinician can submit it for review")
+	}
+
+	supervised, err := s.supervisionRepo.IsSupervised(access.OrganizationID, access.UserID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if !supervised {
+		return nil, response.NewBadRequest("You have no supervisor, sign the note instead")
+	}
+
+	if err := s.decryptNote(ctx, note); err != nil {
+		return nil, fmt.Errorf("failed to decrypt note: %w", err)
+	}
+
+	now := time.Now()
+	note.SubmittedAt = &now
+	note.SignedAt = nil
+	if err := s.signingSvc.SignNote(ctx, note, access.UserID); err != nil {
+		return nil, fmt.Errorf("failed to sign note: %w", err)
+	}
+	note.Status = entity.NoteStatusPendingReview
+
+	if err := s.save(note); err != nil {
+		return nil, err
+	}
+
+	return s.mapEntityToResponse(note), nil
+}
+
+// Return sends a submitted note back to its clinician with the supervisor's
+// comments. The clinician's signature is dropped, so the note can be edited
+// and submitted again.
+func (s *clinicalNoteService) Return(
+	ctx context.Context,
+	id uuid.UUID,
+	access patientEntity.Access,
+	req dto.ReviewNoteRequest,
+) (*dto.ClinicalNoteResponse, error) {
+	comment := strings.TrimSpace(req.Comment)
+	if comment == "" {
+		return nil, response.NewBadRequest("A comment is required to return a note")
+	}
+
+	note, err := s.findForReview(id, access)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.decryptNote(ctx, note); err != nil {
+		return nil, fmt.Errorf("failed to decrypt note: %w", err)
+	}
+
+	note.Status = entity.NoteStatusReturned
+	note.SignedAt = nil
+	note.SignedBy = nil
+	note.Signature = nil
+	note.SigningKeyFingerprint = nil
+
+	if err := s.review(ctx, note, access.UserID, entity.ReviewActionReturned, comment); err != nil {
+		return nil, err
+	}
+
+	return s.mapEntityToResponse(note), nil
+}
+
+// CoSign countersigns a submitted note, which signs it. The clinician's
+// signature must still match the note, so that the supervisor co-signs what
+// was submitted.
+func (s *clinicalNoteService) CoSign(
+	ctx context.Context,
+	id uuid.UUID,
+	access patientEntity.Access,
+	req dto.ReviewNoteRequest,
+) (*dto.ClinicalNoteResponse, error) {
+	note, err := s.findForReview(id, access)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.decryptNote(ctx, note); err != nil {
+		return nil, fmt.Errorf("failed to decrypt note: %w", err)
+	}
+
+	verification, err := s.signingSvc.VerifyNote(ctx, note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify note signature: %w", err)
+	}
+	if verification.Note.Status != dto.SignatureValid {
+		return nil, response.NewConflict("The note changed after it was submitted and must be returned")
+	}
+
+	if err := s.signingSvc.CoSignNote(ctx, note, access.UserID); err != nil {
+		return nil, fmt.Errorf("failed to co-sign note: %w", err)
+	}
+	note.IsSigned = true
+	note.Status = entity.NoteStatusCoSigned
+
+	comment := strings.TrimSpace(req.Comment)
+	if err := s.review(ctx, note, access.UserID, entity.ReviewActionCoSigned, comment); err != nil {
+		return nil, err
+	}
+
+	return s.mapEntityToResponse(note), nil
+}
+
+// ReviewQueue lists the notes awaiting review by the member, from the
+// clinicians they supervise, oldest submission first
+func (s *clinicalNoteService) ReviewQueue(
+	ctx context.Context,
+	access patientEntity.Access,
+	page, pageSize int,
+) ([]dto.ClinicalNoteResponse, int64, error) {
+	superviseeIDs, err := s.supervisionRepo.ListSuperviseeIDs(access.OrganizationID, access.UserID)
+	if err != nil {
+		return nil, 0, response.ErrInternalServerError
+	}
+	if len(superviseeIDs) == 0 {
+		return []dto.ClinicalNoteResponse{}, 0, nil
+	}
+
+	offset := (page - 1) * pageSize
+	notes, total, err := s.repo.ListPendingReview(access.OrganizationID, superviseeIDs, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]dto.ClinicalNoteResponse, 0, len(notes))
+	for i := range notes {
+		if err := s.decryptNote(ctx, &notes[i]); err != nil {
+			s.log.Error("Failed to decrypt note", zap.String("note_id", notes[i].ID.String()))
+		}
+		responses = append(responses, *s.mapEntityToResponse(&notes[i]))
+	}
+
+	return responses, total, nil
+}
+
+// findForReview loads a note awaiting review by one of its clinician's
+// current supervisors
+func (s *clinicalNoteService) findForReview(id uuid.UUID, access patientEntity.Access) (*entity.ClinicalNote, error) {
+	note, err := s.find(id, access)
+	if err != nil {
+		return nil, err
+	}
+
+	if note.Status != entity.NoteStatusPendingReview {
+		return nil, response.NewConflict("Clinical note is not awaiting review")
+	}
+
+	supervisor, err := s.supervisionRepo.IsSupervisor(access.OrganizationID, access.UserID, note.ClinicianID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if !supervisor {
+		return nil, response.NewForbidden("Only a supervisor of the note's clinician can review it")
+	}
+
+	return note, nil
+}
+
+// review saves the note's new review state with a review by the supervisor
+func (s *clinicalNoteService) review(
+	ctx context.Context,
+	note *entity.ClinicalNote,
+	reviewerID uuid.UUID,
+	action, comment string,
+) error {
+	review := &entity.Review{
+		ID:         uuid.New(),
+		NoteID:     note.ID,
+		ReviewerID: reviewerID,
+		Action:     action,
+		Comment:    comment,
+	}
+	if err := s.encryptReview(ctx, note.OrganizationID, review); err != nil {
+		return fmt.Errorf("failed to encrypt review: %w", err)
+	}
+
+	if err := s.repo.AddReview(note, review); err != nil {
+		return err
+	}
+
+	note.Reviews = append(note.Reviews, *review)
+	return nil
+}
+
+func (s *clinicalNoteService) encryptReview(ctx context.Context, orgID uuid.UUID, r *entity.Review) error {
+	if r.Comment == "" {
+		return nil
+	}
+
+	encryptedBytes, keyID, err := s.dataKeySvc.Encrypt(ctx, orgID, []byte(r.Comment))
+	if err != nil {
+		return err
+	}
+
+	r.CommentEncrypted = encryptedBytes
+	r.KeyID = keyID
+	return nil
+}
+
+func (s *clinicalNoteService) decryptReview(ctx context.Context, orgID uuid.UUID, r *entity.Review) error {
+	if len(r.CommentEncrypted) == 0 {
+		return nil
+	}
+
+	decryptedComment, err := s.dataKeySvc.Decrypt(ctx, orgID, r.CommentEncrypted, r.KeyID)
+	if err != nil {
+		return err
+	}
+
+	r.Comment = string(decryptedComment)
+	return nil
+}
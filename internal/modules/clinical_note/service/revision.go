@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/entity"
+	patientEntity "github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"gorm.io/gorm"
+)
+
+// autosaveSessionGap separates editing sessions: an autosave by the same
+// author within this time of their previous one continues the session, and
+// only the first autosave of a session is audited
+const autosaveSessionGap = 15 * time.Minute
+
+// revisionContent is the encrypted payload of a revision
+type revisionContent struct {
+	NoteType   string  `json:"note_type"`
+	Subjective *string `json:"subjective,omitempty"`
+	Objective  *string `json:"objective,omitempty"`
+	Assessment *string `json:"assessment,omitempty"`
+	Plan       *string `json:"plan,omitempty"`
+}
+
+func noteRevisionContent(n *entity.ClinicalNote) revisionContent {
+	return revisionContent{
+		NoteType:   n.NoteType,
+		Subjective: n.Subjective,
+		Objective:  n.Objective,
+		Assessment: n.Assessment,
+		Plan:       n.Plan,
+	}
+}
+
+// applyNoteChanges sets the note's content from a request, leaving fields the
+// request omits unchanged
+func applyNoteChanges(n *entity.ClinicalNote, noteType string, subjective, objective, assessment, plan *string) {
+	if noteType != "" {
+		n.NoteType = noteType
+	}
+	if subjective != nil {
+		n.Subjective = subjective
+	}
+	if objective != nil {
+		n.Objective = objective
+	}
+	if assessment != nil {
+		n.Assessment = assessment
+	}
+	if plan != nil {
+		n.Plan = plan
+	}
+}
+
+// Autosave saves the content of an unsigned note while it is edited, keeping
+// it as a revision like any other save. It does not sign the note or change
+// its status. The returned flag reports whether the autosave starts a new
+// editing session, so that the rest of the session can be left out of the
+// audit log.
+func (s *clinicalNoteService) Autosave(
+	ctx context.Context,
+	id uuid.UUID,
+	access patientEntity.Access,
+	req dto.AutosaveClinicalNoteRequest,
+) (*dto.AutosaveResponse, bool, error) {
+	note, err := s.find(id, access)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if note.IsSigned {
+		return nil, false, response.NewForbidden("Cannot update a signed clinical note")
+	}
+	if note.Status == entity.NoteStatusPendingReview {
+		return nil, false, response.NewForbidden("Cannot update a clinical note awaiting review")
+	}
+
+	if err := s.decryptNote(ctx, note); err != nil {
+		return nil, false, fmt.Errorf("failed to decrypt note: %w", err)
+	}
+	before := noteRevisionContent(note)
+
+	applyNoteChanges(note, req.NoteType, req.Subjective, req.Objective, req.Assessment, req.Plan)
+
+	latest, changed, err := s.recordRevision(ctx, note, before, access.UserID, true)
+	if err != nil {
+		return nil, false, err
+	}
+	if !changed {
+		resp := &dto.AutosaveResponse{NoteID: note.ID, SavedAt: note.UpdatedAt}
+		if latest != nil {
+			resp.Revision = latest.RevisionNumber
+		}
+		return resp, false, nil
+	}
+
+	if err := s.encryptNote(ctx, note); err != nil {
+		return nil, false, fmt.Errorf("failed to encrypt note: %w", err)
+	}
+
+	if err := s.save(note); err != nil {
+		return nil, false, err
+	}
+
+	continued := latest != nil &&
+		latest.Autosave &&
+		latest.AuthorID == access.UserID &&
+		time.Since(latest.CreatedAt) < autosaveSessionGap
+
+	return &dto.AutosaveResponse{
+		NoteID:   note.ID,
+		Revision: note.NewRevisions[len(note.NewRevisions)-1].RevisionNumber,
+		Changed:  true,
+		SavedAt:  note.UpdatedAt,
+	}, !continued, nil
+}
+
+func (s *clinicalNoteService) ListRevisions(
+	ctx context.Context,
+	id uuid.UUID,
+	access patientEntity.Access,
+	page, pageSize int,
+) ([]dto.RevisionSummary, int64, error) {
+	note, err := s.find(id, access)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	revisions, total, err := s.repo.ListRevisions(note.ID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	summaries := make([]dto.RevisionSummary, 0, len(revisions))
+	for i := range revisions {
+		summaries = append(summaries, mapRevisionSummary(&revisions[i]))
+	}
+	return summaries, total, nil
+}
+
+func (s *clinicalNoteService) GetRevision(
+	ctx context.Context,
+	id uuid.UUID,
+	access patientEntity.Access,
+	number int,
+) (*dto.RevisionResponse, error) {
+	note, err := s.find(id, access)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.findRevision(ctx, note, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RevisionResponse{
+		RevisionSummary: mapRevisionSummary(revision),
+		NoteType:        revision.NoteType,
+		Subjective:      revision.Subjective,
+		Objective:       revision.Objective,
+		Assessment:      revision.Assessment,
+		Plan:            revision.Plan,
+	}, nil
+}
+
+// DiffRevisions compares each field of two revisions of a note line by line
+func (s *clinicalNoteService) DiffRevisions(
+	ctx context.Context,
+	id uuid.UUID,
+	access patientEntity.Access,
+	from, to int,
+) (*dto.RevisionDiffResponse, error) {
+	note, err := s.find(id, access)
+	if err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := s.findRevision(ctx, note, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.findRevision(ctx, note, to)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"note_type", fromRevision.NoteType, toRevision.NoteType},
+		{"subjective", stringValue(fromRevision.Subjective), stringValue(toRevision.Subjective)},
+		{"objective", stringValue(fromRevision.Objective), stringValue(toRevision.Objective)},
+		{"assessment", stringValue(fromRevision.Assessment), stringValue(toRevision.Assessment)},
+		{"plan", stringValue(fromRevision.Plan), stringValue(toRevision.Plan)},
+	}
+
+	resp := &dto.RevisionDiffResponse{
+		NoteID: note.ID,
+		From:   from,
+		To:     to,
+		Fields: make([]dto.FieldDiff, 0, len(fields)),
+	}
+	for _, f := range fields {
+		resp.Fields = append(resp.Fields, dto.FieldDiff{
+			Field:   f.name,
+			Changed: f.from != f.to,
+			Lines:   diffLines(f.from, f.to),
+		})
+	}
+	return resp, nil
+}
+
+// recordRevision adds a revision of the note's content to save along with
+// it, unless the content is the same as before. A note without revisions,
+// written before they were kept, first gets a revision of its previous
+// content, so that the change can still be undone. It returns the note's
+// latest revision before this save, if any, and whether a revision was added.
+func (s *clinicalNoteService) recordRevision(
+	ctx context.Context,
+	n *entity.ClinicalNote,
+	before revisionContent,
+	authorID uuid.UUID,
+	autosave bool,
+) (*entity.Revision, bool, error) {
+	latest, err := s.repo.LatestRevision(n.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, response.ErrInternalServerError
+	}
+
+	after := noteRevisionContent(n)
+	if reflect.DeepEqual(before, after) {
+		return latest, false, nil
+	}
+
+	if latest == nil {
+		baseline, err := s.newRevision(ctx, n.OrganizationID, before, n.ClinicianID, false)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to encrypt revision: %w", err)
+		}
+		n.NewRevisions = append(n.NewRevisions, *baseline)
+	}
+
+	revision, err := s.newRevision(ctx, n.OrganizationID, after, authorID, autosave)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encrypt revision: %w", err)
+	}
+	n.NewRevisions = append(n.NewRevisions, *revision)
+
+	return latest, true, nil
+}
+
+// newRevision encrypts content as a revision by the author. The repository
+// numbers it when it is stored with its note.
+func (s *clinicalNoteService) newRevision(
+	ctx context.Context,
+	orgID uuid.UUID,
+	content revisionContent,
+	authorID uuid.UUID,
+	autosave bool,
+) (*entity.Revision, error) {
+	payload, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedBytes, keyID, err := s.dataKeySvc.Encrypt(ctx, orgID, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.Revision{
+		ID:               uuid.New(),
+		OrganizationID:   orgID,
+		AuthorID:         authorID,
+		Autosave:         autosave,
+		ContentEncrypted: encryptedBytes,
+		KeyID:            keyID,
+	}, nil
+}
+
+// findRevision loads and decrypts a revision of the note
+func (s *clinicalNoteService) findRevision(
+	ctx context.Context,
+	note *entity.ClinicalNote,
+	number int,
+) (*entity.Revision, error) {
+	revision, err := s.repo.FindRevision(note.ID, number)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NewNotFound(fmt.Sprintf("Revision %d not found", number))
+		}
+		return nil, response.ErrInternalServerError
+	}
+
+	payload, err := s.dataKeySvc.Decrypt(ctx, note.OrganizationID, revision.ContentEncrypted, revision.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt revision: %w", err)
+	}
+
+	var content revisionContent
+	if err := json.Unmarshal(payload, &content); err != nil {
+		return nil, fmt.Errorf("failed to decode revision: %w", err)
+	}
+
+	revision.NoteType = content.NoteType
+	revision.Subjective = content.Subjective
+	revision.Objective = content.Objective
+	revision.Assessment = content.Assessment
+	revision.Plan = content.Plan
+	return revision, nil
+}
+
+func mapRevisionSummary(r *entity.Revision) dto.RevisionSummary {
+	return dto.RevisionSummary{
+		ID:             r.ID,
+		RevisionNumber: r.RevisionNumber,
+		AuthorID:       r.AuthorID,
+		Autosave:       r.Autosave,
+		CreatedAt:      r.CreatedAt,
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/entity"
+	organizationService "github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	patientEntity "github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	patientRepo "github.com/sahabatharianmu/OpenMind/internal/modules/patient/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+// searchTokenPurpose keys the blind index of note words apart from other
+// blind indexes of the organization
+const searchTokenPurpose = "clinical_note_search"
+
+const (
+	minSearchWordLength = 2
+	maxSearchQueryWords = 20
+)
+
+// searchStopWords are too common to be worth indexing
+var searchStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "has": true, "he": true, "in": true, "is": true, "it": true, "its": true,
+	"of": true, "on": true, "or": true, "she": true, "that": true, "the": true, "they": true, "to": true,
+	"was": true, "were": true, "with": true,
+}
+
+// searchWords splits texts into lowercased words of letters and digits and
+// counts each, skipping stop words and single characters
+func searchWords(texts ...*string) map[string]int {
+	words := make(map[string]int)
+	for _, text := range texts {
+		if text == nil {
+			continue
+		}
+		fields := strings.FieldsFunc(strings.ToLower(*text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range fields {
+			if utf8.RuneCountInString(word) < minSearchWordLength || searchStopWords[word] {
+				continue
+			}
+			words[word]++
+		}
+	}
+	return words
+}
+
+// buildSearchTokens hashes the words of a note's content into its search
+// index entries. The result is never nil, so that a note without words is
+// still marked as indexed.
+func buildSearchTokens(
+	ctx context.Context,
+	dataKeySvc organizationService.DataKeyService,
+	orgID uuid.UUID,
+	content clinicalNoteContent,
+) ([]entity.SearchToken, error) {
+	words := searchWords(content.Subjective, content.Objective, content.Assessment, content.Plan)
+	tokens := make([]entity.SearchToken, 0, len(words))
+	for word, frequency := range words {
+		token, err := dataKeySvc.BlindIndex(ctx, orgID, searchTokenPurpose, word)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, entity.SearchToken{Token: token, Frequency: frequency})
+	}
+	return tokens, nil
+}
+
+// Search returns the IDs of the notes the member may access that contain
+// words of the query, best matches first. Both the query and the index are
+// keyed hashes, so no plaintext is stored or compared.
+func (s *clinicalNoteService) Search(
+	ctx context.Context,
+	access patientEntity.Access,
+	query string,
+	limit int,
+) ([]dto.NoteSearchResult, error) {
+	enabled, err := s.searchRepo.SearchEnabled(access.OrganizationID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if !enabled {
+		return nil, response.NewForbidden("Note search is not enabled for this organization")
+	}
+
+	words := searchWords(&query)
+	if len(words) == 0 {
+		return nil, response.NewBadRequest("Search query has no searchable words")
+	}
+	if len(words) > maxSearchQueryWords {
+		return nil, response.NewBadRequest("Search query has too many words")
+	}
+
+	tokens := make([]string, 0, len(words))
+	for word := range words {
+		token, err := s.dataKeySvc.BlindIndex(ctx, access.OrganizationID, searchTokenPurpose, word)
+		if err != nil {
+			return nil, response.ErrInternalServerError
+		}
+		tokens = append(tokens, token)
+	}
+
+	matches, err := s.searchRepo.Search(
+		access.OrganizationID,
+		tokens,
+		limit,
+		patientRepo.CareTeamScope(access, "n.patient_id"),
+	)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	results := make([]dto.NoteSearchResult, 0, len(matches))
+	for _, match := range matches {
+		results = append(results, dto.NoteSearchResult{
+			NoteID:       match.NoteID,
+			MatchedTerms: match.MatchedTokens,
+			Hits:         match.Hits,
+		})
+	}
+	return results, nil
+}
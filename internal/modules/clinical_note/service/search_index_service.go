@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/repository"
+	organizationService "github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	searchIndexBatchSize = 100
+	// searchIndexPause spaces out batches so indexing does not starve regular
+	// traffic of database capacity
+	searchIndexPause = 200 * time.Millisecond
+	// searchIndexInterval is how often the index is brought up to date, and so
+	// how long it takes for search to cover existing notes once enabled
+	searchIndexInterval = 5 * time.Minute
+)
+
+// SearchIndexService keeps the note search index in line with each
+// organization's setting. It indexes the notes of organizations that enabled
+// search which are not indexed yet, such as notes written before search was
+// enabled or imported, and removes the index of organizations that disabled
+// it. Notes that cannot be indexed are retried on the next pass.
+type SearchIndexService interface {
+	Run(ctx context.Context)
+}
+
+type searchIndexService struct {
+	repo       repository.SearchRepository
+	dataKeySvc organizationService.DataKeyService
+	log        logger.Logger
+}
+
+func NewSearchIndexService(
+	repo repository.SearchRepository,
+	dataKeySvc organizationService.DataKeyService,
+	log logger.Logger,
+) SearchIndexService {
+	return &searchIndexService{
+		repo:       repo,
+		dataKeySvc: dataKeySvc,
+		log:        log,
+	}
+}
+
+// Run updates the index at every interval until ctx is cancelled
+func (s *searchIndexService) Run(ctx context.Context) {
+	for {
+		s.update(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(searchIndexInterval):
+		}
+	}
+}
+
+// update makes one pass over the index
+func (s *searchIndexService) update(ctx context.Context) {
+	purged, err := s.repo.PurgeDisabled()
+	if err != nil {
+		return
+	}
+	if purged > 0 {
+		s.log.Info("Removed notes from the search index of organizations that disabled it", zap.Int64("notes", purged))
+	}
+
+	var indexed, failures int64
+	after := uuid.Nil
+	for {
+		records, err := s.repo.ListUnindexed(after, searchIndexBatchSize)
+		if err != nil || len(records) == 0 {
+			break
+		}
+
+		for _, record := range records {
+			if err := s.index(ctx, record); err != nil {
+				s.log.Debug("Failed to index clinical note", zap.Error(err), zap.String("note_id", record.ID.String()))
+				failures++
+				continue
+			}
+			indexed++
+		}
+		after = records[len(records)-1].ID
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(searchIndexPause):
+		}
+	}
+
+	fields := []zap.Field{zap.Int64("notes", indexed), zap.Int64("failures", failures)}
+	if failures > 0 {
+		s.log.Warn("Note search indexing finished with failures", fields...)
+		return
+	}
+	if indexed > 0 {
+		s.log.Info("Note search indexing finished", fields...)
+	}
+}
+
+// index decrypts a note and writes its search index entries
+func (s *searchIndexService) index(ctx context.Context, record entity.EncryptedRecord) error {
+	plaintext, err := s.dataKeySvc.Decrypt(ctx, record.OrganizationID, record.Encrypted, record.KeyID)
+	if err != nil {
+		return err
+	}
+
+	var content clinicalNoteContent
+	if err := json.Unmarshal(plaintext, &content); err != nil {
+		return err
+	}
+
+	tokens, err := buildSearchTokens(ctx, s.dataKeySvc, record.OrganizationID, content)
+	if err != nil {
+		return err
+	}
+	return s.repo.Index(record.ID, record.OrganizationID, tokens)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,7 +12,10 @@ import (
 	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/dto"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/entity"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/repository"
-	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
+	organizationRepo "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	organizationService "github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	patientEntity "github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	patientRepo "github.com/sahabatharianmu/OpenMind/internal/modules/patient/repository"
 	"github.com/sahabatharianmu/OpenMind/pkg/logger"
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
 	"go.uber.org/zap"
@@ -21,27 +25,27 @@ type ClinicalNoteService interface {
 	Create(
 		ctx context.Context,
 		req dto.CreateClinicalNoteRequest,
-		organizationID uuid.UUID,
+		access patientEntity.Access,
 	) (*dto.ClinicalNoteResponse, error)
 	Update(
 		ctx context.Context,
 		id uuid.UUID,
-		organizationID uuid.UUID,
+		access patientEntity.Access,
 		req dto.UpdateClinicalNoteRequest,
 	) (*dto.ClinicalNoteResponse, error)
-	Delete(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) error
-	Get(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (*dto.ClinicalNoteResponse, error)
-	List(ctx context.Context, organizationID uuid.UUID, page, pageSize int) ([]dto.ClinicalNoteResponse, int64, error)
+	Delete(ctx context.Context, id uuid.UUID, access patientEntity.Access) error
+	Get(ctx context.Context, id uuid.UUID, access patientEntity.Access) (*dto.ClinicalNoteResponse, error)
+	List(ctx context.Context, access patientEntity.Access, page, pageSize int) ([]dto.ClinicalNoteResponse, int64, error)
 	AddAddendum(
 		ctx context.Context,
 		noteID uuid.UUID,
-		organizationID uuid.UUID,
+		access patientEntity.Access,
 		req dto.AddAddendumRequest,
 	) (*dto.AddendumResponse, error)
 	UploadAttachment(
 		ctx context.Context,
 		noteID uuid.UUID,
-		organizationID uuid.UUID,
+		access patientEntity.Access,
 		fileName string,
 		contentType string,
 		data []byte,
@@ -49,26 +53,85 @@ type ClinicalNoteService interface {
 	DownloadAttachment(
 		ctx context.Context,
 		attachmentID uuid.UUID,
-		organizationID uuid.UUID,
+		access patientEntity.Access,
 	) (string, []byte, string, error)
-	GetOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
+	Search(ctx context.Context, access patientEntity.Access, query string, limit int) ([]dto.NoteSearchResult, error)
+	VerifySignature(
+		ctx context.Context,
+		id uuid.UUID,
+		access patientEntity.Access,
+	) (*dto.NoteSignatureVerificationResponse, error)
+	Submit(ctx context.Context, id uuid.UUID, access patientEntity.Access) (*dto.ClinicalNoteResponse, error)
+	Return(
+		ctx context.Context,
+		id uuid.UUID,
+		access patientEntity.Access,
+		req dto.ReviewNoteRequest,
+	) (*dto.ClinicalNoteResponse, error)
+	CoSign(
+		ctx context.Context,
+		id uuid.UUID,
+		access patientEntity.Access,
+		req dto.ReviewNoteRequest,
+	) (*dto.ClinicalNoteResponse, error)
+	ReviewQueue(
+		ctx context.Context,
+		access patientEntity.Access,
+		page, pageSize int,
+	) ([]dto.ClinicalNoteResponse, int64, error)
+	Autosave(
+		ctx context.Context,
+		id uuid.UUID,
+		access patientEntity.Access,
+		req dto.AutosaveClinicalNoteRequest,
+	) (*dto.AutosaveResponse, bool, error)
+	ListRevisions(
+		ctx context.Context,
+		id uuid.UUID,
+		access patientEntity.Access,
+		page, pageSize int,
+	) ([]dto.RevisionSummary, int64, error)
+	GetRevision(
+		ctx context.Context,
+		id uuid.UUID,
+		access patientEntity.Access,
+		number int,
+	) (*dto.RevisionResponse, error)
+	DiffRevisions(
+		ctx context.Context,
+		id uuid.UUID,
+		access patientEntity.Access,
+		from, to int,
+	) (*dto.RevisionDiffResponse, error)
 }
 
 type clinicalNoteService struct {
-	repo       repository.ClinicalNoteRepository
-	encryptSvc *crypto.EncryptionService
-	log        logger.Logger
+	repo            repository.ClinicalNoteRepository
+	searchRepo      repository.SearchRepository
+	careTeamRepo    patientRepo.CareTeamRepository
+	supervisionRepo organizationRepo.SupervisionRepository
+	dataKeySvc      organizationService.DataKeyService
+	signingSvc      SigningService
+	log             logger.Logger
 }
 
 func NewClinicalNoteService(
 	repo repository.ClinicalNoteRepository,
-	encryptSvc *crypto.EncryptionService,
+	searchRepo repository.SearchRepository,
+	careTeamRepo patientRepo.CareTeamRepository,
+	supervisionRepo organizationRepo.SupervisionRepository,
+	dataKeySvc organizationService.DataKeyService,
+	signingSvc SigningService,
 	log logger.Logger,
 ) ClinicalNoteService {
 	return &clinicalNoteService{
-		repo:       repo,
-		encryptSvc: encryptSvc,
-		log:        log,
+		repo:            repo,
+		searchRepo:      searchRepo,
+		careTeamRepo:    careTeamRepo,
+		supervisionRepo: supervisionRepo,
+		dataKeySvc:      dataKeySvc,
+		signingSvc:      signingSvc,
+		log:             log,
 	}
 }
 
@@ -82,19 +145,32 @@ type clinicalNoteContent struct {
 func (s *clinicalNoteService) Create(
 	ctx context.Context,
 	req dto.CreateClinicalNoteRequest,
-	organizationID uuid.UUID,
+	access patientEntity.Access,
 ) (*dto.ClinicalNoteResponse, error) {
+	allowed, err := s.careTeamRepo.CanAccess(access, req.PatientID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if !allowed {
+		return nil, response.NewNotFound("Patient not found")
+	}
+
+	status := entity.NoteStatusDraft
 	var signedAt *time.Time
 	if req.IsSigned {
+		if err := s.requireDirectSigning(access.OrganizationID, access.UserID); err != nil {
+			return nil, err
+		}
+		status = entity.NoteStatusSigned
 		now := time.Now()
 		signedAt = &now
 	}
 
 	note := &entity.ClinicalNote{
 		ID:             uuid.New(),
-		OrganizationID: organizationID,
+		OrganizationID: access.OrganizationID,
 		PatientID:      req.PatientID,
-		ClinicianID:    req.ClinicianID,
+		ClinicianID:    access.UserID,
 		AppointmentID:  req.AppointmentID,
 		NoteType:       req.NoteType,
 		Subjective:     req.Subjective,
@@ -103,9 +179,22 @@ func (s *clinicalNoteService) Create(
 		Plan:           req.Plan,
 		IsSigned:       req.IsSigned,
 		SignedAt:       signedAt,
+		Status:         status,
+	}
+
+	if note.IsSigned {
+		if err := s.signingSvc.SignNote(ctx, note, access.UserID); err != nil {
+			return nil, fmt.Errorf("failed to sign note: %w", err)
+		}
+	} else {
+		revision, err := s.newRevision(ctx, note.OrganizationID, noteRevisionContent(note), access.UserID, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt revision: %w", err)
+		}
+		note.NewRevisions = []entity.Revision{*revision}
 	}
 
-	if err := s.encryptNote(note); err != nil {
+	if err := s.encryptNote(ctx, note); err != nil {
 		return nil, fmt.Errorf("failed to encrypt note: %w", err)
 	}
 
@@ -119,38 +208,29 @@ func (s *clinicalNoteService) Create(
 func (s *clinicalNoteService) Update(
 	ctx context.Context,
 	id uuid.UUID,
-	organizationID uuid.UUID,
+	access patientEntity.Access,
 	req dto.UpdateClinicalNoteRequest,
 ) (*dto.ClinicalNoteResponse, error) {
-	note, err := s.repo.FindByID(id)
+	note, err := s.find(id, access)
 	if err != nil {
 		return nil, err
 	}
 
-	if note.OrganizationID != organizationID {
-		return nil, response.ErrNotFound
-	}
-
 	// Note Locking: Once "Signed", a note becomes immutable.
 	if note.IsSigned {
 		return nil, response.NewForbidden("Cannot update a signed clinical note")
 	}
-
-	if req.NoteType != "" {
-		note.NoteType = req.NoteType
-	}
-	if req.Subjective != nil {
-		note.Subjective = req.Subjective
-	}
-	if req.Objective != nil {
-		note.Objective = req.Objective
+	if note.Status == entity.NoteStatusPendingReview {
+		return nil, response.NewForbidden("Cannot update a clinical note awaiting review")
 	}
-	if req.Assessment != nil {
-		note.Assessment = req.Assessment
-	}
-	if req.Plan != nil {
-		note.Plan = req.Plan
+
+	// Fields left out of the request keep their saved content
+	if err := s.decryptNote(ctx, note); err != nil {
+		return nil, fmt.Errorf("failed to decrypt note: %w", err)
 	}
+	before := noteRevisionContent(note)
+
+	applyNoteChanges(note, req.NoteType, req.Subjective, req.Objective, req.Assessment, req.Plan)
 	if req.IsSigned != nil {
 		note.IsSigned = *req.IsSigned
 		if *req.IsSigned && note.SignedAt == nil {
@@ -161,30 +241,56 @@ func (s *clinicalNoteService) Update(
 		}
 	}
 
-	if err := s.encryptNote(note); err != nil {
+	if note.IsSigned {
+		if note.ClinicianID != access.UserID {
+			return nil, response.NewForbidden("Only the note's clinician can sign it")
+		}
+		if err := s.requireDirectSigning(access.OrganizationID, access.UserID); err != nil {
+			return nil, err
+		}
+		note.Status = entity.NoteStatusSigned
+		if err := s.signingSvc.SignNote(ctx, note, access.UserID); err != nil {
+			return nil, fmt.Errorf("failed to sign note: %w", err)
+		}
+	}
+
+	if _, _, err := s.recordRevision(ctx, note, before, access.UserID, false); err != nil {
+		return nil, err
+	}
+
+	if err := s.encryptNote(ctx, note); err != nil {
 		return nil, fmt.Errorf("failed to encrypt note: %w", err)
 	}
 
-	if err := s.repo.Update(note); err != nil {
+	if err := s.save(note); err != nil {
 		return nil, err
 	}
 
 	return s.mapEntityToResponse(note), nil
 }
 
-func (s *clinicalNoteService) Delete(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) error {
-	note, err := s.repo.FindByID(id)
-	if err != nil {
-		return err
+// save stores changes to an existing note, unless it was signed or submitted
+// for review while the changes were being made
+func (s *clinicalNoteService) save(note *entity.ClinicalNote) error {
+	err := s.repo.Update(note)
+	if errors.Is(err, repository.ErrNoteLocked) {
+		return response.NewConflict("Clinical note was signed or submitted for review in the meantime")
 	}
+	return err
+}
 
-	if note.OrganizationID != organizationID {
-		return response.ErrNotFound
+func (s *clinicalNoteService) Delete(ctx context.Context, id uuid.UUID, access patientEntity.Access) error {
+	note, err := s.find(id, access)
+	if err != nil {
+		return err
 	}
 
 	if note.IsSigned {
 		return response.NewForbidden("Cannot delete a signed clinical note")
 	}
+	if note.Status == entity.NoteStatusPendingReview {
+		return response.NewForbidden("Cannot delete a clinical note awaiting review")
+	}
 
 	return s.repo.Delete(id)
 }
@@ -192,18 +298,14 @@ func (s *clinicalNoteService) Delete(ctx context.Context, id uuid.UUID, organiza
 func (s *clinicalNoteService) Get(
 	ctx context.Context,
 	id uuid.UUID,
-	organizationID uuid.UUID,
+	access patientEntity.Access,
 ) (*dto.ClinicalNoteResponse, error) {
-	note, err := s.repo.FindByID(id)
+	note, err := s.find(id, access)
 	if err != nil {
 		return nil, err
 	}
 
-	if note.OrganizationID != organizationID {
-		return nil, response.ErrNotFound
-	}
-
-	if err := s.decryptNote(note); err != nil {
+	if err := s.decryptNote(ctx, note); err != nil {
 		return nil, fmt.Errorf("failed to decrypt note: %w", err)
 	}
 
@@ -212,18 +314,23 @@ func (s *clinicalNoteService) Get(
 
 func (s *clinicalNoteService) List(
 	ctx context.Context,
-	organizationID uuid.UUID,
+	access patientEntity.Access,
 	page, pageSize int,
 ) ([]dto.ClinicalNoteResponse, int64, error) {
 	offset := (page - 1) * pageSize
-	notes, total, err := s.repo.List(organizationID, pageSize, offset)
+	notes, total, err := s.repo.List(
+		access.OrganizationID,
+		pageSize,
+		offset,
+		patientRepo.CareTeamScope(access, "patient_id"),
+	)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	var responses []dto.ClinicalNoteResponse
 	for i := range notes {
-		if err := s.decryptNote(&notes[i]); err != nil {
+		if err := s.decryptNote(ctx, &notes[i]); err != nil {
 			s.log.Error("Failed to decrypt note", zap.String("note_id", notes[i].ID.String()))
 		}
 		responses = append(responses, *s.mapEntityToResponse(&notes[i]))
@@ -235,18 +342,14 @@ func (s *clinicalNoteService) List(
 func (s *clinicalNoteService) AddAddendum(
 	ctx context.Context,
 	noteID uuid.UUID,
-	organizationID uuid.UUID,
+	access patientEntity.Access,
 	req dto.AddAddendumRequest,
 ) (*dto.AddendumResponse, error) {
-	note, err := s.repo.FindByID(noteID)
+	note, err := s.find(noteID, access)
 	if err != nil {
 		return nil, err
 	}
 
-	if note.OrganizationID != organizationID {
-		return nil, response.ErrNotFound
-	}
-
 	if !note.IsSigned {
 		return nil, response.NewBadRequest("Cannot add addendum to an unsigned note")
 	}
@@ -258,7 +361,11 @@ func (s *clinicalNoteService) AddAddendum(
 		Content:     req.Content,
 	}
 
-	if err := s.encryptAddendum(addendum); err != nil {
+	if err := s.signingSvc.SignAddendum(ctx, note.OrganizationID, addendum, access.UserID); err != nil {
+		return nil, fmt.Errorf("failed to sign addendum: %w", err)
+	}
+
+	if err := s.encryptAddendum(ctx, note.OrganizationID, addendum); err != nil {
 		return nil, fmt.Errorf("failed to encrypt addendum: %w", err)
 	}
 
@@ -272,31 +379,33 @@ func (s *clinicalNoteService) AddAddendum(
 func (s *clinicalNoteService) UploadAttachment(
 	ctx context.Context,
 	noteID uuid.UUID,
-	organizationID uuid.UUID,
+	access patientEntity.Access,
 	fileName string,
 	contentType string,
 	data []byte,
 ) (*dto.AttachmentResponse, error) {
-	note, err := s.repo.FindByID(noteID)
+	note, err := s.find(noteID, access)
 	if err != nil {
 		return nil, err
 	}
 
-	if note.OrganizationID != organizationID {
-		return nil, response.ErrNotFound
-	}
-
 	if note.IsSigned {
 		return nil, response.NewForbidden("Cannot add attachment to a signed note")
 	}
+	if note.Status == entity.NoteStatusPendingReview {
+		return nil, response.NewForbidden("Cannot add attachment to a note awaiting review")
+	}
 
 	// Encrypt the file content
-	encryptedBase64, err := s.encryptSvc.Encrypt(base64.StdEncoding.EncodeToString(data))
+	encryptedBytes, keyID, err := s.dataKeySvc.Encrypt(
+		ctx,
+		note.OrganizationID,
+		[]byte(base64.StdEncoding.EncodeToString(data)),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt file: %w", err)
 	}
 
-	encryptedBytes, _ := base64.StdEncoding.DecodeString(encryptedBase64)
 	const nonceSize = 12
 
 	attachment := &entity.Attachment{
@@ -307,6 +416,7 @@ func (s *clinicalNoteService) UploadAttachment(
 		Size:          int64(len(data)),
 		DataEncrypted: encryptedBytes,
 		Nonce:         encryptedBytes[:nonceSize],
+		KeyID:         keyID,
 	}
 
 	if err := s.repo.AddAttachment(attachment); err != nil {
@@ -319,31 +429,31 @@ func (s *clinicalNoteService) UploadAttachment(
 func (s *clinicalNoteService) DownloadAttachment(
 	ctx context.Context,
 	attachmentID uuid.UUID,
-	organizationID uuid.UUID,
+	access patientEntity.Access,
 ) (string, []byte, string, error) {
 	attachment, err := s.repo.GetAttachmentByID(attachmentID)
 	if err != nil {
 		return "", nil, "", err
 	}
 
-	// Verify organization via the note
-	note, err := s.repo.FindByID(attachment.NoteID)
+	// Verify organization and care team via the note
+	note, err := s.find(attachment.NoteID, access)
 	if err != nil {
 		return "", nil, "", err
 	}
 
-	if note.OrganizationID != organizationID {
-		return "", nil, "", response.ErrNotFound
-	}
-
 	// Decrypt the file content
-	encryptedBase64 := base64.StdEncoding.EncodeToString(attachment.DataEncrypted)
-	decryptedBase64, err := s.encryptSvc.Decrypt(encryptedBase64)
+	decryptedBase64, err := s.dataKeySvc.Decrypt(
+		ctx,
+		note.OrganizationID,
+		attachment.DataEncrypted,
+		attachment.KeyID,
+	)
 	if err != nil {
 		return "", nil, "", fmt.Errorf("failed to decrypt file: %w", err)
 	}
 
-	decryptedBytes, err := base64.StdEncoding.DecodeString(decryptedBase64)
+	decryptedBytes, err := base64.StdEncoding.DecodeString(string(decryptedBase64))
 	if err != nil {
 		return "", nil, "", fmt.Errorf("failed to decode decrypted file: %w", err)
 	}
@@ -351,17 +461,56 @@ func (s *clinicalNoteService) DownloadAttachment(
 	return attachment.FileName, decryptedBytes, attachment.ContentType, nil
 }
 
-func (s *clinicalNoteService) GetOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
-	return s.repo.GetOrganizationID(userID)
+// VerifySignature checks that the note and each of its addendums are
+// unchanged since they were signed
+func (s *clinicalNoteService) VerifySignature(
+	ctx context.Context,
+	id uuid.UUID,
+	access patientEntity.Access,
+) (*dto.NoteSignatureVerificationResponse, error) {
+	note, err := s.find(id, access)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.decryptNote(ctx, note); err != nil {
+		return nil, fmt.Errorf("failed to decrypt note: %w", err)
+	}
+
+	return s.signingSvc.VerifyNote(ctx, note)
 }
 
-func (s *clinicalNoteService) encryptAddendum(a *entity.Addendum) error {
-	encryptedBase64, err := s.encryptSvc.Encrypt(a.Content)
+// find loads a note of the organization whose patient the member may access.
+// Supervisors may also access the notes of the clinicians they supervise.
+func (s *clinicalNoteService) find(id uuid.UUID, access patientEntity.Access) (*entity.ClinicalNote, error) {
+	note, err := s.repo.FindByID(id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if note.OrganizationID != access.OrganizationID {
+		return nil, response.ErrNotFound
+	}
+
+	allowed, err := s.careTeamRepo.CanAccess(access, note.PatientID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if !allowed {
+		allowed, err = s.supervisionRepo.IsSupervisor(access.OrganizationID, access.UserID, note.ClinicianID)
+		if err != nil {
+			return nil, response.ErrInternalServerError
+		}
 	}
+	if !allowed {
+		return nil, response.ErrNotFound
+	}
+
+	return note, nil
+}
 
-	encryptedBytes, err := base64.StdEncoding.DecodeString(encryptedBase64)
+func (s *clinicalNoteService) encryptAddendum(ctx context.Context, orgID uuid.UUID, a *entity.Addendum) error {
+	encryptedBytes, keyID, err := s.dataKeySvc.Encrypt(ctx, orgID, []byte(a.Content))
 	if err != nil {
 		return err
 	}
@@ -373,22 +522,22 @@ func (s *clinicalNoteService) encryptAddendum(a *entity.Addendum) error {
 
 	a.ContentEncrypted = encryptedBytes
 	a.Nonce = encryptedBytes[:nonceSize]
+	a.KeyID = keyID
 
 	return nil
 }
 
-func (s *clinicalNoteService) decryptAddendum(a *entity.Addendum) error {
+func (s *clinicalNoteService) decryptAddendum(ctx context.Context, orgID uuid.UUID, a *entity.Addendum) error {
 	if len(a.ContentEncrypted) == 0 {
 		return nil
 	}
 
-	encryptedBase64 := base64.StdEncoding.EncodeToString(a.ContentEncrypted)
-	decryptedContent, err := s.encryptSvc.Decrypt(encryptedBase64)
+	decryptedContent, err := s.dataKeySvc.Decrypt(ctx, orgID, a.ContentEncrypted, a.KeyID)
 	if err != nil {
 		return err
 	}
 
-	a.Content = decryptedContent
+	a.Content = string(decryptedContent)
 	return nil
 }
 
@@ -401,7 +550,7 @@ func (s *clinicalNoteService) mapAddendumEntityToResponse(a *entity.Addendum) *d
 	}
 }
 
-func (s *clinicalNoteService) encryptNote(n *entity.ClinicalNote) error {
+func (s *clinicalNoteService) encryptNote(ctx context.Context, n *entity.ClinicalNote) error {
 	content := clinicalNoteContent{
 		Subjective: n.Subjective,
 		Objective:  n.Objective,
@@ -414,12 +563,7 @@ func (s *clinicalNoteService) encryptNote(n *entity.ClinicalNote) error {
 		return err
 	}
 
-	encryptedBase64, err := s.encryptSvc.Encrypt(string(jsonData))
-	if err != nil {
-		return err
-	}
-
-	encryptedBytes, err := base64.StdEncoding.DecodeString(encryptedBase64)
+	encryptedBytes, keyID, err := s.dataKeySvc.Encrypt(ctx, n.OrganizationID, jsonData)
 	if err != nil {
 		return err
 	}
@@ -432,21 +576,34 @@ func (s *clinicalNoteService) encryptNote(n *entity.ClinicalNote) error {
 
 	n.ContentEncrypted = encryptedBytes
 	n.Nonce = encryptedBytes[:nonceSize]
-	n.KeyID = "v1"
+	n.KeyID = keyID
+
+	// The search index is built from the same content, while it is still in
+	// plaintext, and saved together with the note
+	enabled, err := s.searchRepo.SearchEnabled(n.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		tokens, err := buildSearchTokens(ctx, s.dataKeySvc, n.OrganizationID, content)
+		if err != nil {
+			return err
+		}
+		n.SearchTokens = tokens
+	}
 
 	return nil
 }
 
-func (s *clinicalNoteService) decryptNote(n *entity.ClinicalNote) error {
+func (s *clinicalNoteService) decryptNote(ctx context.Context, n *entity.ClinicalNote) error {
 	if len(n.ContentEncrypted) > 0 {
-		encryptedBase64 := base64.StdEncoding.EncodeToString(n.ContentEncrypted)
-		decryptedJSON, err := s.encryptSvc.Decrypt(encryptedBase64)
+		decryptedJSON, err := s.dataKeySvc.Decrypt(ctx, n.OrganizationID, n.ContentEncrypted, n.KeyID)
 		if err != nil {
 			return err
 		}
 
 		var content clinicalNoteContent
-		if err := json.Unmarshal([]byte(decryptedJSON), &content); err != nil {
+		if err := json.Unmarshal(decryptedJSON, &content); err != nil {
 			return err
 		}
 
@@ -457,11 +614,17 @@ func (s *clinicalNoteService) decryptNote(n *entity.ClinicalNote) error {
 	}
 
 	for i := range n.Addendums {
-		if err := s.decryptAddendum(&n.Addendums[i]); err != nil {
+		if err := s.decryptAddendum(ctx, n.OrganizationID, &n.Addendums[i]); err != nil {
 			s.log.Error("Failed to decrypt addendum", zap.String("addendum_id", n.Addendums[i].ID.String()))
 		}
 	}
 
+	for i := range n.Reviews {
+		if err := s.decryptReview(ctx, n.OrganizationID, &n.Reviews[i]); err != nil {
+			s.log.Error("Failed to decrypt review", zap.String("review_id", n.Reviews[i].ID.String()))
+		}
+	}
+
 	return nil
 }
 
@@ -486,6 +649,17 @@ func (s *clinicalNoteService) mapEntityToResponse(n *entity.ClinicalNote) *dto.C
 		attachments = append(attachments, *s.mapAttachmentEntityToResponse(&a))
 	}
 
+	var reviews []dto.ReviewResponse
+	for _, r := range n.Reviews {
+		reviews = append(reviews, dto.ReviewResponse{
+			ID:         r.ID,
+			ReviewerID: r.ReviewerID,
+			Action:     r.Action,
+			Comment:    r.Comment,
+			CreatedAt:  r.CreatedAt,
+		})
+	}
+
 	return &dto.ClinicalNoteResponse{
 		ID:             n.ID,
 		OrganizationID: n.OrganizationID,
@@ -499,8 +673,13 @@ func (s *clinicalNoteService) mapEntityToResponse(n *entity.ClinicalNote) *dto.C
 		Plan:           n.Plan,
 		IsSigned:       n.IsSigned,
 		SignedAt:       n.SignedAt,
+		Status:         n.Status,
+		SubmittedAt:    n.SubmittedAt,
+		CoSignedBy:     n.CoSignedBy,
+		CoSignedAt:     n.CoSignedAt,
 		Addendums:      addendums,
 		Attachments:    attachments,
+		Reviews:        reviews,
 		CreatedAt:      n.CreatedAt,
 		UpdatedAt:      n.UpdatedAt,
 	}
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/repository"
+	organizationService "github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// signatureVersion identifies the layout of signed payloads, so that it can
+// change without invalidating earlier signatures
+const signatureVersion = "openmind-signature-v1"
+
+// SigningService electronically signs notes and addendums. A signature is an
+// Ed25519 signature, with the signer's own key, of the SHA-256 of a canonical
+// payload of the decrypted content and its metadata, so that any later change
+// to either is detected on verification.
+type SigningService interface {
+	SignNote(ctx context.Context, note *entity.ClinicalNote, signerID uuid.UUID) error
+	SignAddendum(ctx context.Context, orgID uuid.UUID, addendum *entity.Addendum, signerID uuid.UUID) error
+	CoSignNote(ctx context.Context, note *entity.ClinicalNote, supervisorID uuid.UUID) error
+	VerifyNote(ctx context.Context, note *entity.ClinicalNote) (*dto.NoteSignatureVerificationResponse, error)
+}
+
+type signingService struct {
+	repo       repository.SigningKeyRepository
+	dataKeySvc organizationService.DataKeyService
+	log        logger.Logger
+}
+
+func NewSigningService(
+	repo repository.SigningKeyRepository,
+	dataKeySvc organizationService.DataKeyService,
+	log logger.Logger,
+) SigningService {
+	return &signingService{
+		repo:       repo,
+		dataKeySvc: dataKeySvc,
+		log:        log,
+	}
+}
+
+// notePayload is what a note signature covers. Fields are never reordered or
+// removed, since that would change the hash of every signed note.
+type notePayload struct {
+	Version        string          `json:"version"`
+	NoteID         uuid.UUID       `json:"note_id"`
+	OrganizationID uuid.UUID       `json:"organization_id"`
+	PatientID      uuid.UUID       `json:"patient_id"`
+	ClinicianID    uuid.UUID       `json:"clinician_id"`
+	AppointmentID  *uuid.UUID      `json:"appointment_id"`
+	NoteType       string          `json:"note_type"`
+	ICD10Code      string          `json:"icd10_code"`
+	Subjective     *string         `json:"subjective"`
+	Objective      *string         `json:"objective"`
+	Assessment     *string         `json:"assessment"`
+	Plan           *string         `json:"plan"`
+	Attachments    []attachmentRef `json:"attachments"`
+	SignedAt       string          `json:"signed_at"`
+	SignedBy       uuid.UUID       `json:"signed_by"`
+}
+
+// attachmentRef covers an attachment by the hash of its decrypted data
+type attachmentRef struct {
+	ID          uuid.UUID `json:"id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+}
+
+// addendumPayload is what an addendum signature covers
+type addendumPayload struct {
+	Version     string    `json:"version"`
+	AddendumID  uuid.UUID `json:"addendum_id"`
+	NoteID      uuid.UUID `json:"note_id"`
+	ClinicianID uuid.UUID `json:"clinician_id"`
+	Content     string    `json:"content"`
+	SignedAt    string    `json:"signed_at"`
+	SignedBy    uuid.UUID `json:"signed_by"`
+}
+
+// coSignaturePayload is what a supervisor's co-signature covers: the note as
+// signed by its clinician, through the hash of its signed payload
+type coSignaturePayload struct {
+	Version    string    `json:"version"`
+	NoteID     uuid.UUID `json:"note_id"`
+	NoteDigest string    `json:"note_digest"`
+	CoSignedAt string    `json:"co_signed_at"`
+	CoSignedBy uuid.UUID `json:"co_signed_by"`
+}
+
+// signatureTime rounds a signing time to what the database keeps, so that
+// the payload hashed at signing time is the one rebuilt on verification
+func signatureTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+// SignNote signs a decrypted note that is being signed by signerID
+func (s *signingService) SignNote(ctx context.Context, note *entity.ClinicalNote, signerID uuid.UUID) error {
+	signedAt := signatureTime(time.Now())
+	if note.SignedAt != nil {
+		signedAt = signatureTime(*note.SignedAt)
+	}
+	note.SignedAt = &signedAt
+	note.SignedBy = &signerID
+
+	digest, err := s.noteDigest(ctx, note)
+	if err != nil {
+		return err
+	}
+
+	key, privateKey, err := s.activeKey(ctx, note.OrganizationID, signerID)
+	if err != nil {
+		return err
+	}
+
+	note.Signature = ed25519.Sign(privateKey, digest)
+	note.SigningKeyFingerprint = &key.Fingerprint
+	return nil
+}
+
+// SignAddendum signs a new addendum written by signerID
+func (s *signingService) SignAddendum(
+	ctx context.Context,
+	orgID uuid.UUID,
+	addendum *entity.Addendum,
+	signerID uuid.UUID,
+) error {
+	addendum.SignedAt = signatureTime(time.Now())
+	addendum.SignedBy = &signerID
+
+	digest, err := addendumDigest(addendum)
+	if err != nil {
+		return err
+	}
+
+	key, privateKey, err := s.activeKey(ctx, orgID, signerID)
+	if err != nil {
+		return err
+	}
+
+	addendum.Signature = ed25519.Sign(privateKey, digest)
+	addendum.SigningKeyFingerprint = &key.Fingerprint
+	return nil
+}
+
+// CoSignNote countersigns a decrypted note its clinician signed, on behalf of
+// their supervisor
+func (s *signingService) CoSignNote(ctx context.Context, note *entity.ClinicalNote, supervisorID uuid.UUID) error {
+	coSignedAt := signatureTime(time.Now())
+	note.CoSignedAt = &coSignedAt
+	note.CoSignedBy = &supervisorID
+
+	digest, err := s.coSignatureDigest(ctx, note)
+	if err != nil {
+		return err
+	}
+
+	key, privateKey, err := s.activeKey(ctx, note.OrganizationID, supervisorID)
+	if err != nil {
+		return err
+	}
+
+	note.CoSignature = ed25519.Sign(privateKey, digest)
+	note.CoSigningKeyFingerprint = &key.Fingerprint
+	return nil
+}
+
+// VerifyNote checks the signatures of a decrypted note and of each of its
+// addendums against their content as it is now
+func (s *signingService) VerifyNote(
+	ctx context.Context,
+	note *entity.ClinicalNote,
+) (*dto.NoteSignatureVerificationResponse, error) {
+	resp := &dto.NoteSignatureVerificationResponse{NoteID: note.ID}
+
+	var err error
+	switch {
+	case !note.IsSigned && len(note.Signature) == 0:
+		resp.Note = dto.SignatureVerification{
+			Status: dto.SignatureUnsigned,
+			Reason: "The note has not been signed",
+		}
+	case len(note.Signature) == 0:
+		resp.Note = missingSignature(note.SignedBy, note.SignedAt)
+	default:
+		resp.Note, err = s.verify(
+			note.OrganizationID,
+			note.SignedBy,
+			note.SignedAt,
+			note.Signature,
+			note.SigningKeyFingerprint,
+			func() ([]byte, error) { return s.noteDigest(ctx, note) },
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if note.Status == entity.NoteStatusCoSigned || len(note.CoSignature) > 0 {
+		coSignature, err := s.verify(
+			note.OrganizationID,
+			note.CoSignedBy,
+			note.CoSignedAt,
+			note.CoSignature,
+			note.CoSigningKeyFingerprint,
+			func() ([]byte, error) { return s.coSignatureDigest(ctx, note) },
+		)
+		if err != nil {
+			return nil, err
+		}
+		resp.CoSignature = &coSignature
+	}
+
+	resp.Addendums = make([]dto.AddendumSignatureVerification, 0, len(note.Addendums))
+	for i := range note.Addendums {
+		a := &note.Addendums[i]
+		signedAt := a.SignedAt
+		verification := missingSignature(a.SignedBy, &signedAt)
+		if len(a.Signature) > 0 {
+			verification, err = s.verify(
+				note.OrganizationID,
+				a.SignedBy,
+				&signedAt,
+				a.Signature,
+				a.SigningKeyFingerprint,
+				func() ([]byte, error) { return addendumDigest(a) },
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resp.Addendums = append(resp.Addendums, dto.AddendumSignatureVerification{
+			AddendumID:            a.ID,
+			SignatureVerification: verification,
+		})
+	}
+
+	resp.Valid = resp.Note.Status == dto.SignatureValid
+	if resp.CoSignature != nil && resp.CoSignature.Status != dto.SignatureValid {
+		resp.Valid = false
+	}
+	for _, a := range resp.Addendums {
+		if a.Status != dto.SignatureValid {
+			resp.Valid = false
+		}
+	}
+	return resp, nil
+}
+
+// verify checks one signature of a record of the organization. digest
+// rebuilds the hash of the signed payload from the record as it is now.
+func (s *signingService) verify(
+	orgID uuid.UUID,
+	signedBy *uuid.UUID,
+	signedAt *time.Time,
+	signature []byte,
+	fingerprint *string,
+	digest func() ([]byte, error),
+) (dto.SignatureVerification, error) {
+	result := dto.SignatureVerification{
+		Status:         dto.SignatureInvalid,
+		SignedBy:       signedBy,
+		SignedAt:       signedAt,
+		KeyFingerprint: fingerprint,
+	}
+
+	if fingerprint == nil || signedBy == nil || signedAt == nil {
+		result.Reason = "The signature has no signer, signing time or signing key"
+		return result, nil
+	}
+
+	key, err := s.repo.FindByFingerprint(*fingerprint)
+	if err != nil {
+		result.Reason = "The signing key was not found"
+		return result, nil
+	}
+	result.PublicKey = base64.StdEncoding.EncodeToString(key.PublicKey)
+
+	if key.OrganizationID != orgID || key.UserID != *signedBy {
+		result.Reason = "The signing key does not belong to the signer"
+		return result, nil
+	}
+
+	sum, err := digest()
+	if err != nil {
+		return result, err
+	}
+	result.Digest = hex.EncodeToString(sum)
+
+	if len(key.PublicKey) != ed25519.PublicKeySize || !ed25519.Verify(key.PublicKey, sum, signature) {
+		result.Reason = "The content or its metadata changed after signing"
+		return result, nil
+	}
+
+	result.Status = dto.SignatureValid
+	return result, nil
+}
+
+// missingSignature is the verification of a record signed before electronic
+// signatures were recorded
+func missingSignature(signedBy *uuid.UUID, signedAt *time.Time) dto.SignatureVerification {
+	return dto.SignatureVerification{
+		Status:   dto.SignatureMissing,
+		Reason:   "Signed before electronic signatures were recorded",
+		SignedBy: signedBy,
+		SignedAt: signedAt,
+	}
+}
+
+// noteDigest hashes the signed payload of a decrypted note
+func (s *signingService) noteDigest(ctx context.Context, note *entity.ClinicalNote) ([]byte, error) {
+	if note.SignedAt == nil || note.SignedBy == nil {
+		return nil, errors.New("note has no signing time or signer")
+	}
+
+	attachments := make([]attachmentRef, 0, len(note.Attachments))
+	for _, a := range note.Attachments {
+		data, err := s.dataKeySvc.Decrypt(ctx, note.OrganizationID, a.DataEncrypted, a.KeyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt attachment %s: %w", a.ID, err)
+		}
+		sum := sha256.Sum256(data)
+		attachments = append(attachments, attachmentRef{
+			ID:          a.ID,
+			FileName:    a.FileName,
+			ContentType: a.ContentType,
+			Size:        a.Size,
+			SHA256:      hex.EncodeToString(sum[:]),
+		})
+	}
+	sort.Slice(attachments, func(i, j int) bool {
+		return attachments[i].ID.String() < attachments[j].ID.String()
+	})
+
+	return digestOf(notePayload{
+		Version:        signatureVersion,
+		NoteID:         note.ID,
+		OrganizationID: note.OrganizationID,
+		PatientID:      note.PatientID,
+		ClinicianID:    note.ClinicianID,
+		AppointmentID:  note.AppointmentID,
+		NoteType:       note.NoteType,
+		ICD10Code:      note.ICD10Code,
+		Subjective:     note.Subjective,
+		Objective:      note.Objective,
+		Assessment:     note.Assessment,
+		Plan:           note.Plan,
+		Attachments:    attachments,
+		SignedAt:       signatureTime(*note.SignedAt).Format(time.RFC3339Nano),
+		SignedBy:       *note.SignedBy,
+	})
+}
+
+// coSignatureDigest hashes the co-signed payload of a decrypted note
+func (s *signingService) coSignatureDigest(ctx context.Context, note *entity.ClinicalNote) ([]byte, error) {
+	if note.CoSignedAt == nil || note.CoSignedBy == nil {
+		return nil, errors.New("note has no co-signing time or co-signer")
+	}
+
+	noteDigest, err := s.noteDigest(ctx, note)
+	if err != nil {
+		return nil, err
+	}
+
+	return digestOf(coSignaturePayload{
+		Version:    signatureVersion,
+		NoteID:     note.ID,
+		NoteDigest: hex.EncodeToString(noteDigest),
+		CoSignedAt: signatureTime(*note.CoSignedAt).Format(time.RFC3339Nano),
+		CoSignedBy: *note.CoSignedBy,
+	})
+}
+
+// addendumDigest hashes the signed payload of a decrypted addendum
+func addendumDigest(addendum *entity.Addendum) ([]byte, error) {
+	if addendum.SignedBy == nil {
+		return nil, errors.New("addendum has no signer")
+	}
+	return digestOf(addendumPayload{
+		Version:     signatureVersion,
+		AddendumID:  addendum.ID,
+		NoteID:      addendum.NoteID,
+		ClinicianID: addendum.ClinicianID,
+		Content:     addendum.Content,
+		SignedAt:    signatureTime(addendum.SignedAt).Format(time.RFC3339Nano),
+		SignedBy:    *addendum.SignedBy,
+	})
+}
+
+// digestOf returns the SHA-256 of the JSON encoding of a payload. Payloads
+// are structs, whose fields encode in a fixed order, so the encoding is
+// canonical.
+func digestOf(payload interface{}) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+// activeKey returns the signer's key in use in the organization with its
+// private key, creating a key if the signer has none
+func (s *signingService) activeKey(
+	ctx context.Context,
+	orgID, userID uuid.UUID,
+) (*entity.SigningKey, ed25519.PrivateKey, error) {
+	key, err := s.repo.FindActive(orgID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		key, err = s.createKey(ctx, orgID, userID)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privateKey, err := s.dataKeySvc.Decrypt(ctx, orgID, key.PrivateKeyEncrypted, key.KeyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt signing key: %w", err)
+	}
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, nil, errors.New("invalid signing key")
+	}
+	return key, ed25519.PrivateKey(privateKey), nil
+}
+
+// createKey generates and stores a new signing key for the clinician. If
+// another request created one at the same time, that one is used instead.
+func (s *signingService) createKey(ctx context.Context, orgID, userID uuid.UUID) (*entity.SigningKey, error) {
+	publicKey, privateKey, err := crypto.GenerateSigningKey()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, keyID, err := s.dataKeySvc.Encrypt(ctx, orgID, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &entity.SigningKey{
+		ID:                  uuid.New(),
+		OrganizationID:      orgID,
+		UserID:              userID,
+		PublicKey:           publicKey,
+		PrivateKeyEncrypted: encrypted,
+		KeyID:               keyID,
+		Fingerprint:         crypto.SigningKeyFingerprint(publicKey),
+	}
+	if err := s.repo.Create(key); err != nil {
+		return s.repo.FindActive(orgID, userID)
+	}
+
+	s.log.Info(
+		"Clinician signing key created",
+		zap.String("org_id", orgID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("fingerprint", key.Fingerprint),
+	)
+	return key, nil
+}
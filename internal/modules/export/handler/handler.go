@@ -22,15 +22,15 @@ func NewExportHandler(svc service.ExportService) *ExportHandler {
 }
 
 func (h *ExportHandler) ExportData(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	orgIDVal, exists := c.Get("organizationID")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
+	orgID := orgIDVal.(uuid.UUID)
 
 	// Get all data files
-	files, err := h.svc.ExportAllData(userID)
+	files, err := h.svc.ExportAllData(orgID)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -58,7 +58,7 @@ func (h *ExportHandler) ExportData(_ context.Context, c *app.RequestContext) {
 	c.Response.Header.Set("Content-Type", "application/zip")
 	c.Response.Header.Set(
 		"Content-Disposition",
-		fmt.Sprintf("attachment; filename=openmind-export-%s.zip", userID.String()[:8]),
+		fmt.Sprintf("attachment; filename=openmind-export-%s.zip", orgID.String()[:8]),
 	)
 	c.Response.SetStatusCode(consts.StatusOK)
 
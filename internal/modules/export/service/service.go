@@ -11,6 +11,7 @@ import (
 	clinicalNoteService "github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/service"
 	invoiceRepo "github.com/sahabatharianmu/OpenMind/internal/modules/invoice/repository"
 	organizationRepo "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	patientEntity "github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
 	patientRepo "github.com/sahabatharianmu/OpenMind/internal/modules/patient/repository"
 	"github.com/sahabatharianmu/OpenMind/pkg/logger"
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
@@ -18,7 +19,7 @@ import (
 )
 
 type ExportService interface {
-	ExportAllData(userID uuid.UUID) (map[string][]byte, error)
+	ExportAllData(orgID uuid.UUID) (map[string][]byte, error)
 }
 
 type exportService struct {
@@ -51,9 +52,8 @@ func NewExportService(
 	}
 }
 
-func (s *exportService) ExportAllData(userID uuid.UUID) (map[string][]byte, error) {
-	// Get user's organization
-	org, err := s.orgRepo.GetByUserID(userID)
+func (s *exportService) ExportAllData(orgID uuid.UUID) (map[string][]byte, error) {
+	org, err := s.orgRepo.GetByID(orgID)
 	if err != nil {
 		s.log.Error("ExportAllData: failed to get organization", zap.Error(err))
 		return nil, response.ErrNotFound
@@ -80,7 +80,9 @@ func (s *exportService) ExportAllData(userID uuid.UUID) (map[string][]byte, erro
 	}
 
 	// Export clinical notes
-	notes, _, err := s.clinicalNoteSvc.List(context.Background(), org.ID, 1, 10000)
+	// The export covers every patient, regardless of the exporting member's care teams
+	access := patientEntity.Access{OrganizationID: org.ID, AllPatients: true}
+	notes, _, err := s.clinicalNoteSvc.List(context.Background(), access, 1, 10000)
 	if err != nil {
 		s.log.Error("Failed to fetch clinical notes for export", zap.Error(err))
 	} else {
@@ -90,7 +92,7 @@ func (s *exportService) ExportAllData(userID uuid.UUID) (map[string][]byte, erro
 		// Also export raw attachment files (decrypted)
 		for _, note := range notes {
 			for _, att := range note.Attachments {
-				_, data, _, err := s.clinicalNoteSvc.DownloadAttachment(context.Background(), att.ID, org.ID)
+				_, data, _, err := s.clinicalNoteSvc.DownloadAttachment(context.Background(), att.ID, access)
 				if err == nil {
 					files[fmt.Sprintf("attachments/%s_%s", att.ID.String()[:8], att.FileName)] = data
 				}
@@ -7,9 +7,9 @@ import (
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
-	"github.com/google/uuid"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/import/dto"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/import/service"
+	patientEntity "github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
 	"github.com/xuri/excelize/v2"
 )
@@ -23,19 +23,12 @@ func NewImportHandler(svc service.ImportService) *ImportHandler {
 }
 
 func (h *ImportHandler) PreviewImport(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	// Get organization ID from user
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(patientEntity.Access)
 
 	var req dto.ImportPreviewRequest
 	if err := c.BindAndValidate(&req); err != nil {
@@ -43,7 +36,7 @@ func (h *ImportHandler) PreviewImport(_ context.Context, c *app.RequestContext)
 		return
 	}
 
-	result, err := h.svc.PreviewImport(context.Background(), req, orgID, userID)
+	result, err := h.svc.PreviewImport(context.Background(), req, access)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -53,19 +46,12 @@ func (h *ImportHandler) PreviewImport(_ context.Context, c *app.RequestContext)
 }
 
 func (h *ImportHandler) ExecuteImport(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	// Get organization ID from user
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(patientEntity.Access)
 
 	var req dto.ImportExecuteRequest
 	if err := c.BindAndValidate(&req); err != nil {
@@ -73,7 +59,7 @@ func (h *ImportHandler) ExecuteImport(_ context.Context, c *app.RequestContext)
 		return
 	}
 
-	result, err := h.svc.ExecuteImport(context.Background(), req, orgID, userID)
+	result, err := h.svc.ExecuteImport(context.Background(), req, access)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -17,10 +17,10 @@ import (
 	clinicalNoteRepository "github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/repository"
 	clinicalNoteService "github.com/sahabatharianmu/OpenMind/internal/modules/clinical_note/service"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/import/dto"
+	organizationService "github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
 	patientEntity "github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
 	patientRepository "github.com/sahabatharianmu/OpenMind/internal/modules/patient/repository"
 	patientService "github.com/sahabatharianmu/OpenMind/internal/modules/patient/service"
-	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
 	"github.com/sahabatharianmu/OpenMind/pkg/logger"
 	"github.com/xuri/excelize/v2"
 	"gorm.io/gorm"
@@ -30,43 +30,43 @@ type ImportService interface {
 	PreviewImport(
 		ctx context.Context,
 		req dto.ImportPreviewRequest,
-		organizationID uuid.UUID,
-		userID uuid.UUID,
+		access patientEntity.Access,
 	) (*dto.ImportPreviewResponse, error)
 	ExecuteImport(
 		ctx context.Context,
 		req dto.ImportExecuteRequest,
-		organizationID uuid.UUID,
-		userID uuid.UUID,
+		access patientEntity.Access,
 	) (*dto.ImportExecuteResponse, error)
-	GetOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
 }
 
 type importService struct {
 	patientRepo      patientRepository.PatientRepository
+	careTeamRepo     patientRepository.CareTeamRepository
 	clinicalNoteRepo clinicalNoteRepository.ClinicalNoteRepository
 	patientSvc       patientService.PatientService
 	clinicalNoteSvc  clinicalNoteService.ClinicalNoteService
-	encryptSvc       *crypto.EncryptionService
+	dataKeySvc       organizationService.DataKeyService
 	db               *gorm.DB
 	log              logger.Logger
 }
 
 func NewImportService(
 	patientRepo patientRepository.PatientRepository,
+	careTeamRepo patientRepository.CareTeamRepository,
 	clinicalNoteRepo clinicalNoteRepository.ClinicalNoteRepository,
 	patientSvc patientService.PatientService,
 	clinicalNoteSvc clinicalNoteService.ClinicalNoteService,
-	encryptSvc *crypto.EncryptionService,
+	dataKeySvc organizationService.DataKeyService,
 	db *gorm.DB,
 	log logger.Logger,
 ) ImportService {
 	return &importService{
 		patientRepo:      patientRepo,
+		careTeamRepo:     careTeamRepo,
 		clinicalNoteRepo: clinicalNoteRepo,
 		patientSvc:       patientSvc,
 		clinicalNoteSvc:  clinicalNoteSvc,
-		encryptSvc:       encryptSvc,
+		dataKeySvc:       dataKeySvc,
 		db:               db,
 		log:              log,
 	}
@@ -75,8 +75,7 @@ func NewImportService(
 func (s *importService) PreviewImport(
 	ctx context.Context,
 	req dto.ImportPreviewRequest,
-	organizationID uuid.UUID,
-	userID uuid.UUID,
+	access patientEntity.Access,
 ) (*dto.ImportPreviewResponse, error) {
 	fileData, err := base64.StdEncoding.DecodeString(req.FileData)
 	if err != nil {
@@ -87,7 +86,7 @@ func (s *importService) PreviewImport(
 	case "patients":
 		return s.previewPatientsImport(fileData, req.FileName)
 	case "notes":
-		return s.previewNotesImport(fileData, req.FileName)
+		return s.previewNotesImport(fileData, req.FileName, access)
 	default:
 		return nil, fmt.Errorf("unsupported import type: %s", req.Type)
 	}
@@ -96,8 +95,7 @@ func (s *importService) PreviewImport(
 func (s *importService) ExecuteImport(
 	ctx context.Context,
 	req dto.ImportExecuteRequest,
-	organizationID uuid.UUID,
-	userID uuid.UUID,
+	access patientEntity.Access,
 ) (*dto.ImportExecuteResponse, error) {
 	fileData, err := base64.StdEncoding.DecodeString(req.FileData)
 	if err != nil {
@@ -106,9 +104,9 @@ func (s *importService) ExecuteImport(
 
 	switch req.Type {
 	case "patients":
-		return s.executePatientsImport(fileData, req.FileName, organizationID, userID)
+		return s.executePatientsImport(fileData, req.FileName, access.OrganizationID, access.UserID)
 	case "notes":
-		return s.executeNotesImport(fileData, req.FileName, organizationID, userID)
+		return s.executeNotesImport(fileData, req.FileName, access)
 	default:
 		return nil, fmt.Errorf("unsupported import type: %s", req.Type)
 	}
@@ -438,6 +436,14 @@ func (s *importService) executePatientsImport(
 				continue
 			}
 
+			if err := s.patientRepo.EncryptDemographics(patient); err != nil {
+				errors = append(errors, dto.RowError{
+					Row:     rowNum,
+					Message: fmt.Sprintf("Failed to encrypt patient: %v", err),
+				})
+				continue
+			}
+
 			if err := tx.Create(patient).Error; err != nil {
 				errors = append(errors, dto.RowError{
 					Row:     rowNum,
@@ -530,6 +536,15 @@ func (s *importService) validateAndCreatePatient(
 }
 
 // stringPtr returns a pointer to the string if it's not empty, otherwise nil
+// errImportSigned rejects notes marked as signed: a signature has to come
+// from the signing clinician, not from an import file
+const errImportSigned = "Notes cannot be imported as signed; sign them after the import"
+
+func isTruthy(value string) bool {
+	value = strings.ToLower(value)
+	return value == "true" || value == "1" || value == "yes"
+}
+
 func stringPtr(s string) *string {
 	if s == "" {
 		return nil
@@ -541,6 +556,7 @@ func stringPtr(s string) *string {
 func (s *importService) previewNotesImport(
 	fileData []byte,
 	fileName string,
+	access patientEntity.Access,
 ) (*dto.ImportPreviewResponse, error) {
 	records, err := parseFile(fileData, fileName)
 	if err != nil {
@@ -575,7 +591,7 @@ func (s *importService) previewNotesImport(
 		rowMap := buildRowMap(records[i], headerMap)
 		rowNum := i + 1
 
-		rowErrors, rowWarnings, isValid := s.validateNoteRow(rowMap, rowNum)
+		rowErrors, rowWarnings, isValid := s.validateNoteRow(rowMap, rowNum, access)
 		errors = append(errors, rowErrors...)
 		warnings = append(warnings, rowWarnings...)
 
@@ -598,7 +614,11 @@ func (s *importService) previewNotesImport(
 }
 
 // validateNoteRow validates a clinical note row and returns errors, warnings, and validity status
-func (s *importService) validateNoteRow(rowMap map[string]interface{}, rowNum int) ([]dto.RowError, []dto.RowWarning, bool) {
+func (s *importService) validateNoteRow(
+	rowMap map[string]interface{},
+	rowNum int,
+	access patientEntity.Access,
+) ([]dto.RowError, []dto.RowWarning, bool) {
 	var errors []dto.RowError
 	var warnings []dto.RowWarning
 	rowValid := true
@@ -621,15 +641,13 @@ func (s *importService) validateNoteRow(rowMap map[string]interface{}, rowNum in
 			Message: "Invalid UUID format",
 		})
 		rowValid = false
-	} else {
-		_, err := s.patientRepo.FindByID(uuid.MustParse(patientIDStr))
-		if err != nil {
-			warnings = append(warnings, dto.RowWarning{
-				Row:     rowNum,
-				Field:   "patient_id",
-				Message: "Patient not found in database",
-			})
-		}
+	} else if !s.canImportFor(access, uuid.MustParse(patientIDStr)) {
+		errors = append(errors, dto.RowError{
+			Row:     rowNum,
+			Field:   "patient_id",
+			Message: "Patient not found",
+		})
+		rowValid = false
 	}
 
 	// Validate note_type
@@ -642,14 +660,22 @@ func (s *importService) validateNoteRow(rowMap map[string]interface{}, rowNum in
 		rowValid = false
 	}
 
+	if isTruthy(getStringValue(rowMap, "is_signed")) {
+		errors = append(errors, dto.RowError{
+			Row:     rowNum,
+			Field:   "is_signed",
+			Message: errImportSigned,
+		})
+		rowValid = false
+	}
+
 	return errors, warnings, rowValid
 }
 
 func (s *importService) executeNotesImport(
 	fileData []byte,
 	fileName string,
-	organizationID uuid.UUID,
-	userID uuid.UUID,
+	access patientEntity.Access,
 ) (*dto.ImportExecuteResponse, error) {
 	records, err := parseFile(fileData, fileName)
 	if err != nil {
@@ -671,7 +697,7 @@ func (s *importService) executeNotesImport(
 	successCount := 0
 
 	err = s.db.Transaction(func(tx *gorm.DB) error {
-		return s.processNoteRows(records, headerMap, organizationID, userID, tx, &errors, &importedIDs, &successCount)
+		return s.processNoteRows(records, headerMap, access, tx, &errors, &importedIDs, &successCount)
 	})
 
 	if err != nil {
@@ -691,8 +717,7 @@ func (s *importService) executeNotesImport(
 func (s *importService) processNoteRows(
 	records [][]string,
 	headerMap map[string]int,
-	organizationID uuid.UUID,
-	userID uuid.UUID,
+	access patientEntity.Access,
 	tx *gorm.DB,
 	errors *[]dto.RowError,
 	importedIDs *[]uuid.UUID,
@@ -702,7 +727,7 @@ func (s *importService) processNoteRows(
 		rowMap := buildRowMap(records[i], headerMap)
 		rowNum := i + 1
 
-		clinicalNote, rowErr := s.validateAndCreateNote(rowMap, rowNum, organizationID, userID)
+		clinicalNote, rowErr := s.validateAndCreateNote(rowMap, rowNum, access)
 		if rowErr != nil {
 			*errors = append(*errors, *rowErr)
 			continue
@@ -732,12 +757,13 @@ func (s *importService) processNoteRows(
 	return nil
 }
 
-// validateAndCreateNote validates note data and creates the clinical note entity
+// validateAndCreateNote validates note data and creates the clinical note
+// entity. Notes are always imported as drafts for the importer, who signs
+// them afterwards through the usual signing flow.
 func (s *importService) validateAndCreateNote(
 	rowMap map[string]interface{},
 	rowNum int,
-	organizationID uuid.UUID,
-	userID uuid.UUID,
+	access patientEntity.Access,
 ) (*clinicalNoteEntity.ClinicalNote, *dto.RowError) {
 	patientIDStr := getStringValue(rowMap, "patient_id")
 	noteType := getStringValue(rowMap, "note_type")
@@ -752,9 +778,8 @@ func (s *importService) validateAndCreateNote(
 		}
 	}
 
-	// Verify patient exists
-	_, err = s.patientRepo.FindByID(patientID)
-	if err != nil {
+	// Verify the patient is in the organization and accessible to the importer
+	if !s.canImportFor(access, patientID) {
 		return nil, &dto.RowError{
 			Row:     rowNum,
 			Field:   "patient_id",
@@ -781,14 +806,12 @@ func (s *importService) validateAndCreateNote(
 		}
 	}
 
-	// Parse is_signed
-	isSignedStr := strings.ToLower(getStringValue(rowMap, "is_signed"))
-	isSigned := isSignedStr == "true" || isSignedStr == "1" || isSignedStr == "yes"
-
-	var signedAt *time.Time
-	if isSigned {
-		now := time.Now()
-		signedAt = &now
+	if isTruthy(getStringValue(rowMap, "is_signed")) {
+		return nil, &dto.RowError{
+			Row:     rowNum,
+			Field:   "is_signed",
+			Message: errImportSigned,
+		}
 	}
 
 	// Create clinical note entity
@@ -799,9 +822,9 @@ func (s *importService) validateAndCreateNote(
 
 	clinicalNote := &clinicalNoteEntity.ClinicalNote{
 		ID:             uuid.New(),
-		OrganizationID: organizationID,
+		OrganizationID: access.OrganizationID,
 		PatientID:      patientID,
-		ClinicianID:    userID,
+		ClinicianID:    access.UserID,
 		AppointmentID:  appointmentID,
 		NoteType:       noteType,
 		ICD10Code:      getStringValue(rowMap, "icd10_code"),
@@ -809,13 +832,24 @@ func (s *importService) validateAndCreateNote(
 		Objective:      &objective,
 		Assessment:     &assessment,
 		Plan:           &plan,
-		IsSigned:       isSigned,
-		SignedAt:       signedAt,
+		Status:         clinicalNoteEntity.NoteStatusDraft,
 	}
 
 	return clinicalNote, nil
 }
 
+// canImportFor reports whether the importer may add notes for the patient:
+// the patient must belong to the importer's organization and be on their
+// care team unless they can access all patients
+func (s *importService) canImportFor(access patientEntity.Access, patientID uuid.UUID) bool {
+	patient, err := s.patientRepo.FindByID(patientID)
+	if err != nil || patient.OrganizationID != access.OrganizationID {
+		return false
+	}
+	allowed, err := s.careTeamRepo.CanAccess(access, patientID)
+	return err == nil && allowed
+}
+
 // encryptNoteContent encrypts the clinical note content and sets encrypted fields
 func (s *importService) encryptNoteContent(
 	clinicalNote *clinicalNoteEntity.ClinicalNote,
@@ -838,7 +872,7 @@ func (s *importService) encryptNoteContent(
 		return err
 	}
 
-	encryptedBase64, err := s.encryptSvc.Encrypt(string(jsonData))
+	encryptedBytes, keyID, err := s.dataKeySvc.Encrypt(context.Background(), clinicalNote.OrganizationID, jsonData)
 	if err != nil {
 		*errors = append(*errors, dto.RowError{
 			Row:     rowNum,
@@ -847,15 +881,6 @@ func (s *importService) encryptNoteContent(
 		return err
 	}
 
-	encryptedBytes, err := base64.StdEncoding.DecodeString(encryptedBase64)
-	if err != nil {
-		*errors = append(*errors, dto.RowError{
-			Row:     rowNum,
-			Message: fmt.Sprintf("Failed to decode encrypted data: %v", err),
-		})
-		return err
-	}
-
 	const nonceSize = 12
 	if len(encryptedBytes) < nonceSize {
 		*errors = append(*errors, dto.RowError{
@@ -867,7 +892,7 @@ func (s *importService) encryptNoteContent(
 
 	clinicalNote.ContentEncrypted = encryptedBytes
 	clinicalNote.Nonce = encryptedBytes[:nonceSize]
-	clinicalNote.KeyID = "v1"
+	clinicalNote.KeyID = keyID
 
 	return nil
 }
@@ -887,10 +912,6 @@ func parseOptionalUUID(uuidStr string, errOut *error) *uuid.UUID {
 	return &parsed
 }
 
-func (s *importService) GetOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
-	return s.patientSvc.GetOrganizationID(ctx, userID)
-}
-
 func getStringValue(m map[string]interface{}, key string) string {
 	val, ok := m[key]
 	if !ok {
@@ -22,18 +22,12 @@ func NewInvoiceHandler(svc service.InvoiceService) *InvoiceHandler {
 }
 
 func (h *InvoiceHandler) Create(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	orgIDVal, exists := c.Get("organizationID")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	orgID := orgIDVal.(uuid.UUID)
 
 	var req dto.CreateInvoiceRequest
 	if err := c.BindAndValidate(&req); err != nil {
@@ -51,18 +45,12 @@ func (h *InvoiceHandler) Create(_ context.Context, c *app.RequestContext) {
 }
 
 func (h *InvoiceHandler) List(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	orgIDVal, exists := c.Get("organizationID")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	orgID := orgIDVal.(uuid.UUID)
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
@@ -88,18 +76,12 @@ func (h *InvoiceHandler) List(_ context.Context, c *app.RequestContext) {
 }
 
 func (h *InvoiceHandler) Get(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	orgIDVal, exists := c.Get("organizationID")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	orgID := orgIDVal.(uuid.UUID)
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -118,18 +100,12 @@ func (h *InvoiceHandler) Get(_ context.Context, c *app.RequestContext) {
 }
 
 func (h *InvoiceHandler) Update(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	orgIDVal, exists := c.Get("organizationID")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	orgID := orgIDVal.(uuid.UUID)
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -154,18 +130,12 @@ func (h *InvoiceHandler) Update(_ context.Context, c *app.RequestContext) {
 }
 
 func (h *InvoiceHandler) Delete(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	orgIDVal, exists := c.Get("organizationID")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	orgID := orgIDVal.(uuid.UUID)
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -183,18 +153,12 @@ func (h *InvoiceHandler) Delete(_ context.Context, c *app.RequestContext) {
 }
 
 func (h *InvoiceHandler) DownloadSuperbill(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	orgIDVal, exists := c.Get("organizationID")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	orgID := orgIDVal.(uuid.UUID)
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
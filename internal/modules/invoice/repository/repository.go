@@ -16,7 +16,6 @@ type InvoiceRepository interface {
 	Delete(id uuid.UUID) error
 	FindByID(id uuid.UUID) (*entity.Invoice, error)
 	List(organizationID uuid.UUID, limit, offset int) ([]entity.Invoice, int64, error)
-	GetOrganizationID(userID uuid.UUID) (uuid.UUID, error)
 }
 
 type invoiceRepository struct {
@@ -84,15 +83,3 @@ func (r *invoiceRepository) List(organizationID uuid.UUID, limit, offset int) ([
 
 	return invoices, total, nil
 }
-
-func (r *invoiceRepository) GetOrganizationID(userID uuid.UUID) (uuid.UUID, error) {
-	var orgIDStr string
-	if err := r.db.Table("organization_members").Select("organization_id").Where("user_id = ?", userID).Limit(1).Scan(&orgIDStr).Error; err != nil {
-		r.log.Error("Failed to get organization ID", zap.Error(err), zap.String("user_id", userID.String()))
-		return uuid.Nil, err
-	}
-	if orgIDStr == "" {
-		return uuid.Nil, errors.New("organization not found for user")
-	}
-	return uuid.Parse(orgIDStr)
-}
@@ -28,7 +28,6 @@ type InvoiceService interface {
 	Get(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (*dto.InvoiceResponse, error)
 	List(ctx context.Context, organizationID uuid.UUID, page, pageSize int) ([]dto.InvoiceResponse, int64, error)
 	GenerateSuperbill(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) ([]byte, error)
-	GetOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
 }
 
 type invoiceService struct {
@@ -204,10 +203,6 @@ func (s *invoiceService) List(
 	return responses, total, nil
 }
 
-func (s *invoiceService) GetOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
-	return s.repo.GetOrganizationID(userID)
-}
-
 func (s *invoiceService) parseTime(dateStr string) (time.Time, error) {
 	if t, err := time.Parse(time.RFC3339, dateStr); err == nil {
 		return t, nil
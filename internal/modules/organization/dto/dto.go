@@ -7,23 +7,191 @@ import (
 )
 
 type OrganizationResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Type        string    `json:"type"`
-	TaxID       string    `json:"tax_id"`
-	NPI         string    `json:"npi"`
-	Address     string    `json:"address"`
-	Currency    string    `json:"currency"`
-	Locale      string    `json:"locale"`
-	MemberCount int       `json:"member_count"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID                 uuid.UUID `json:"id"`
+	Name               string    `json:"name"`
+	Type               string    `json:"type"`
+	TaxID              string    `json:"tax_id"`
+	NPI                string    `json:"npi"`
+	Address            string    `json:"address"`
+	Currency           string    `json:"currency"`
+	Locale             string    `json:"locale"`
+	RequireMFA         bool      `json:"require_mfa"`
+	PasswordMaxAgeDays int       `json:"password_max_age_days"`
+	IPAllowlist        []string  `json:"ip_allowlist"`
+	NoteSearchEnabled  bool      `json:"note_search_enabled"`
+	MemberCount        int       `json:"member_count"`
+	CreatedAt          time.Time `json:"created_at"`
 }
 
 type UpdateOrganizationRequest struct {
-	Name     string `json:"name" binding:"required,min=2"`
-	TaxID    string `json:"tax_id"`
-	NPI      string `json:"npi"`
-	Address  string `json:"address"`
-	Currency string `json:"currency"`
-	Locale   string `json:"locale"`
+	Name               string    `json:"name" binding:"required,min=2"`
+	TaxID              string    `json:"tax_id"`
+	NPI                string    `json:"npi"`
+	Address            string    `json:"address"`
+	Currency           string    `json:"currency"`
+	Locale             string    `json:"locale"`
+	RequireMFA         *bool     `json:"require_mfa"`
+	PasswordMaxAgeDays *int      `json:"password_max_age_days"` // 0 turns password expiry off
+	IPAllowlist        *[]string `json:"ip_allowlist"`          // CIDR ranges or IPs; empty allows any IP
+	NoteSearchEnabled  *bool     `json:"note_search_enabled"`
+}
+
+type MemberResponse struct {
+	UserID        uuid.UUID  `json:"user_id"`
+	Email         string     `json:"email"`
+	FullName      string     `json:"full_name"`
+	Role          string     `json:"role"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	JoinedAt      time.Time  `json:"joined_at"`
+}
+
+type MembershipResponse struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Name           string    `json:"name"`
+	Role           string    `json:"role"`
+	Current        bool      `json:"current"`
+	JoinedAt       time.Time `json:"joined_at"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role"  binding:"required"`
+}
+
+type InvitationResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	InvitedBy uuid.UUID `json:"invited_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InvitationPreviewResponse struct {
+	OrganizationName string    `json:"organization_name"`
+	Email            string    `json:"email"`
+	Role             string    `json:"role"`
+	ExistingAccount  bool      `json:"existing_account"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+type PermissionResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	BuiltIn     bool     `json:"built_in"`
+	Customized  bool     `json:"customized"`
+}
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name"        binding:"required,min=2,max=50"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+type UpdateRoleRequest struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+type MyPermissionsResponse struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name        string     `json:"name"        binding:"required,min=2,max=100"`
+	Permissions []string   `json:"permissions" binding:"required"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+type APIKeyResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	KeyPrefix   string     `json:"key_prefix"`
+	Permissions []string   `json:"permissions"`
+	CreatedBy   uuid.UUID  `json:"created_by"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	Active      bool       `json:"active"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse carries the plaintext key. It is only ever shown once.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type CreateSupervisionRequest struct {
+	SupervisorID uuid.UUID `json:"supervisor_id" binding:"required"`
+	SuperviseeID uuid.UUID `json:"supervisee_id" binding:"required"`
+}
+
+type SupervisionResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	SupervisorID   uuid.UUID  `json:"supervisor_id"`
+	SupervisorName string     `json:"supervisor_name"`
+	SuperviseeID   uuid.UUID  `json:"supervisee_id"`
+	SuperviseeName string     `json:"supervisee_name"`
+	CreatedBy      *uuid.UUID `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// UpdateSSOConfigRequest creates or replaces the organization's SSO settings.
+// An empty client secret keeps the stored one.
+type UpdateSSOConfigRequest struct {
+	Enabled        *bool             `json:"enabled"`
+	Issuer         string            `json:"issuer"          binding:"required,url"`
+	ClientID       string            `json:"client_id"       binding:"required"`
+	ClientSecret   string            `json:"client_secret"`
+	AllowedDomains []string          `json:"allowed_domains" binding:"required"`
+	GroupsClaim    string            `json:"groups_claim"`
+	GroupRoles     map[string]string `json:"group_roles"`
+	DefaultRole    string            `json:"default_role"`
+}
+
+type SSOConfigResponse struct {
+	Enabled         bool              `json:"enabled"`
+	Issuer          string            `json:"issuer"`
+	ClientID        string            `json:"client_id"`
+	ClientSecretSet bool              `json:"client_secret_set"`
+	AllowedDomains  []string          `json:"allowed_domains"`
+	Domains         []SSODomain       `json:"domains"`
+	GroupsClaim     string            `json:"groups_claim"`
+	GroupRoles      map[string]string `json:"group_roles"`
+	DefaultRole     string            `json:"default_role"`
+	RedirectURI     string            `json:"redirect_uri"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// SSODomain is the verification state of an allowed domain. Sign-in from the
+// domain is accepted once a TXT record named VerificationRecord with the
+// content VerificationValue has been published and verified.
+type SSODomain struct {
+	Domain             string     `json:"domain"`
+	Verified           bool       `json:"verified"`
+	VerifiedAt         *time.Time `json:"verified_at,omitempty"`
+	VerificationRecord string     `json:"verification_record"`
+	VerificationValue  string     `json:"verification_value"`
+}
+
+type BreachedPasswordListResponse struct {
+	Count int64 `json:"count"`
+}
+
+// DestroyDataKeyRequest confirms crypto-shredding by repeating the
+// organization's name
+type DestroyDataKeyRequest struct {
+	Confirmation string `json:"confirmation" binding:"required"`
 }
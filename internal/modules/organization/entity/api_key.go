@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// APIKeyPrefix starts every API key so leaked keys are easy to recognise
+const APIKeyPrefix = "om_"
+
+// APIKey lets an external system call the API on behalf of an organization.
+// Only the hash of the key is stored. A key never grants more than its
+// permissions, nor more than its creator's current role.
+type APIKey struct {
+	ID             uuid.UUID                   `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID                   `gorm:"type:uuid;not null"                               json:"organization_id"`
+	Name           string                      `gorm:"type:varchar(100);not null"                       json:"name"`
+	KeyPrefix      string                      `gorm:"type:varchar(16);not null"                        json:"key_prefix"`
+	KeyHash        string                      `gorm:"type:varchar(64);not null;uniqueIndex"            json:"-"`
+	Permissions    datatypes.JSONSlice[string] `gorm:"type:jsonb;not null"                              json:"permissions"`
+	CreatedBy      uuid.UUID                   `gorm:"type:uuid;not null"                               json:"created_by"`
+	ExpiresAt      *time.Time                  `                                                        json:"expires_at"`
+	LastUsedAt     *time.Time                  `                                                        json:"last_used_at"`
+	RevokedAt      *time.Time                  `                                                        json:"revoked_at"`
+	CreatedAt      time.Time                   `                                                        json:"created_at"`
+}
+
+func (APIKey) TableName() string {
+	return "organization_api_keys"
+}
+
+// IsActive reports whether the key can still authenticate requests
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// HasPermission reports whether the key was granted the permission
+func (k *APIKey) HasPermission(permission string) bool {
+	for _, p := range k.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "github.com/google/uuid"
+
+// BreachedPasswordHash is the SHA-1 of a breached password uploaded by an
+// organization admin. Members of the organization cannot choose it.
+type BreachedPasswordHash struct {
+	OrganizationID uuid.UUID `gorm:"type:uuid;primaryKey"      json:"organization_id"`
+	Hash           string    `gorm:"type:char(40);primaryKey" json:"hash"`
+}
+
+func (BreachedPasswordHash) TableName() string {
+	return "breached_password_hashes"
+}
@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataKeyIDPrefix marks the key ID of data encrypted with an organization
+// data key, as opposed to a key of the shared keyring
+const DataKeyIDPrefix = "org:"
+
+// DataKey is an organization's data encryption key, wrapped by the master key
+// identified by MasterKeyID. A destroyed key has no WrappedKey left.
+type DataKey struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null"                               json:"organization_id"`
+	WrappedKey     []byte     `gorm:"type:bytea"                                       json:"-"`
+	MasterKeyID    string     `gorm:"type:varchar(255);not null"                       json:"master_key_id"`
+	CreatedAt      time.Time  `                                                        json:"created_at"`
+	DestroyedAt    *time.Time `                                                        json:"destroyed_at"`
+	DestroyedBy    *uuid.UUID `gorm:"type:uuid"                                        json:"destroyed_by"`
+}
+
+func (DataKey) TableName() string {
+	return "organization_data_keys"
+}
+
+// KeyID is the key ID recorded with data encrypted with this key
+func (k *DataKey) KeyID() string {
+	return DataKeyIDPrefix + k.ID.String()
+}
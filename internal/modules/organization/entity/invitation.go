@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation lets an admin bring a new staff member into an existing
+// organization. Only the hash of the emailed token is stored.
+type Invitation struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null"                               json:"organization_id"`
+	Email          string     `gorm:"type:varchar(255);not null"                       json:"email"`
+	Role           string     `gorm:"type:varchar(50);not null"                        json:"role"`
+	TokenHash      string     `gorm:"type:varchar(64);not null;uniqueIndex"            json:"-"`
+	InvitedBy      uuid.UUID  `gorm:"type:uuid;not null"                               json:"invited_by"`
+	ExpiresAt      time.Time  `gorm:"not null"                                         json:"expires_at"`
+	AcceptedAt     *time.Time `                                                        json:"accepted_at"`
+	RevokedAt      *time.Time `                                                        json:"revoked_at"`
+	CreatedAt      time.Time  `                                                        json:"created_at"`
+}
+
+func (Invitation) TableName() string {
+	return "organization_invitations"
+}
+
+// IsPending reports whether the invitation can still be accepted
+func (i *Invitation) IsPending(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
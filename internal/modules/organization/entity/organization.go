@@ -4,26 +4,59 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
+// Organization member roles
+const (
+	RoleOwner     = "owner"
+	RoleAdmin     = "admin"
+	RoleClinician = "clinician"
+	RoleMember    = "member"
+)
+
 type Organization struct {
-	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	Name      string         `gorm:"type:varchar(255);not null"                       json:"name"`
-	Type      string         `gorm:"type:varchar(50);not null"                        json:"type"`
-	TaxID     string         `gorm:"type:varchar(50)"                                 json:"tax_id"`
-	NPI       string         `gorm:"type:varchar(50)"                                 json:"npi"`
-	Address   string         `gorm:"type:text"                                        json:"address"`
-	Currency  string         `gorm:"type:varchar(10);not null;default:'USD'"          json:"currency"`
-	Locale    string         `gorm:"type:varchar(10);not null;default:'en-US'"        json:"locale"`
-	CreatedAt time.Time      `                                                        json:"created_at"`
-	UpdatedAt time.Time      `                                                        json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index"                                            json:"deleted_at,omitempty"`
+	ID                 uuid.UUID                   `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name               string                      `gorm:"type:varchar(255);not null"                       json:"name"`
+	Type               string                      `gorm:"type:varchar(50);not null"                        json:"type"`
+	TaxID              string                      `gorm:"type:varchar(50)"                                 json:"tax_id"`
+	NPI                string                      `gorm:"type:varchar(50)"                                 json:"npi"`
+	Address            string                      `gorm:"type:text"                                        json:"address"`
+	Currency           string                      `gorm:"type:varchar(10);not null;default:'USD'"          json:"currency"`
+	Locale             string                      `gorm:"type:varchar(10);not null;default:'en-US'"        json:"locale"`
+	RequireMFA         bool                        `gorm:"not null;default:false"                           json:"require_mfa"`
+	PasswordMaxAgeDays int                         `gorm:"not null;default:0"                               json:"password_max_age_days"`
+	IPAllowlist        datatypes.JSONSlice[string] `gorm:"type:jsonb;not null;default:'[]'"                 json:"ip_allowlist"`
+	NoteSearchEnabled  bool                        `gorm:"not null;default:false"                           json:"note_search_enabled"`
+	DataShreddedAt     *time.Time                  `                                                        json:"data_shredded_at,omitempty"`
+	CreatedAt          time.Time                   `                                                        json:"created_at"`
+	UpdatedAt          time.Time                   `                                                        json:"updated_at"`
+	DeletedAt          gorm.DeletedAt              `gorm:"index"                                            json:"deleted_at,omitempty"`
 }
 
 type OrganizationMember struct {
-	OrganizationID uuid.UUID `gorm:"type:uuid;not null"        json:"organization_id"`
-	UserID         uuid.UUID `gorm:"type:uuid;not null"        json:"user_id"`
-	Role           string    `gorm:"type:varchar(50);not null" json:"role"` // owner, admin, member
-	CreatedAt      time.Time `                                 json:"created_at"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null"        json:"organization_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null"        json:"user_id"`
+	Role           string     `gorm:"type:varchar(50);not null" json:"role"` // owner, admin, clinician, member
+	DeactivatedAt  *time.Time `                                 json:"deactivated_at"`
+	CreatedAt      time.Time  `                                 json:"created_at"`
+}
+
+// MemberDetail is an organization member joined with their user account
+type MemberDetail struct {
+	UserID        uuid.UUID  `json:"user_id"`
+	Email         string     `json:"email"`
+	FullName      string     `json:"full_name"`
+	Role          string     `json:"role"`
+	DeactivatedAt *time.Time `json:"deactivated_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Membership is one of the organizations a user belongs to
+type Membership struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Name           string    `json:"name"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
 }
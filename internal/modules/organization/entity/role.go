@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Permissions checked by routes and services. Roles grant a set of these.
+const (
+	PermPatientsRead       = "patients:read"
+	PermPatientsWrite      = "patients:write"
+	PermPatientsDelete     = "patients:delete"
+	PermPatientsAll        = "patients:all"
+	PermCareTeamManage     = "care_team:manage"
+	PermBreakGlassReview   = "break_glass:review"
+	PermAppointmentsRead   = "appointments:read"
+	PermAppointmentsWrite  = "appointments:write"
+	PermNotesRead          = "notes:read"
+	PermNotesWrite         = "notes:write"
+	PermInvoicesRead       = "invoices:read"
+	PermInvoicesWrite      = "invoices:write"
+	PermAuditRead          = "audit:read"
+	PermDataExport         = "data:export"
+	PermDataImport         = "data:import"
+	PermMembersRead        = "members:read"
+	PermMembersManage      = "members:manage"
+	PermUsersImpersonate   = "users:impersonate"
+	PermOrganizationManage = "organization:manage"
+)
+
+// PermissionInfo describes a permission for role editors
+type PermissionInfo struct {
+	Name        string
+	Description string
+}
+
+// Permissions lists every permission a role can be granted
+var Permissions = []PermissionInfo{
+	{PermPatientsRead, "View patient records"},
+	{PermPatientsWrite, "Create and update patient records"},
+	{PermPatientsDelete, "Delete patient records"},
+	{PermPatientsAll, "Access every patient, not only those on the member's care teams"},
+	{PermCareTeamManage, "Assign primary clinicians and grant or revoke care team access"},
+	{PermBreakGlassReview, "Review break-the-glass emergency access"},
+	{PermAppointmentsRead, "View appointments"},
+	{PermAppointmentsWrite, "Schedule, update and cancel appointments"},
+	{PermNotesRead, "Read clinical notes and attachments"},
+	{PermNotesWrite, "Write clinical notes, addendums and attachments"},
+	{PermInvoicesRead, "View invoices and superbills"},
+	{PermInvoicesWrite, "Create, update and delete invoices"},
+	{PermAuditRead, "Read the audit log"},
+	{PermDataExport, "Export all organization data"},
+	{PermDataImport, "Import patients and appointments"},
+	{PermMembersRead, "View the organization's members and their roles"},
+	{PermMembersManage, "Invite, remove and change roles of members"},
+	{PermUsersImpersonate, "Use the application as another member to troubleshoot their access"},
+	{PermOrganizationManage, "Change organization settings and roles"},
+}
+
+// IsPermission reports whether name is a known permission
+func IsPermission(name string) bool {
+	for _, p := range Permissions {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AllPermissions returns the names of every permission
+func AllPermissions() []string {
+	names := make([]string, 0, len(Permissions))
+	for _, p := range Permissions {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// DefaultRolePermissions are the permissions of the built-in roles until an
+// organization overrides them. The owner always holds every permission.
+var DefaultRolePermissions = map[string][]string{
+	RoleAdmin: AllPermissions(),
+	RoleClinician: {
+		PermPatientsRead,
+		PermPatientsWrite,
+		PermAppointmentsRead,
+		PermAppointmentsWrite,
+		PermNotesRead,
+		PermNotesWrite,
+		PermInvoicesRead,
+		PermMembersRead,
+	},
+	RoleMember: {
+		PermPatientsRead,
+		PermAppointmentsRead,
+		PermInvoicesRead,
+		PermMembersRead,
+	},
+}
+
+// Role is an organization's definition of a member role: either an override
+// of a built-in role or a custom role such as front desk or billing
+type Role struct {
+	ID             uuid.UUID                   `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID                   `gorm:"type:uuid;not null"                               json:"organization_id"`
+	Name           string                      `gorm:"type:varchar(50);not null"                        json:"name"`
+	Description    string                      `gorm:"type:text;not null;default:''"                    json:"description"`
+	Permissions    datatypes.JSONSlice[string] `gorm:"type:jsonb;not null"                              json:"permissions"`
+	CreatedAt      time.Time                   `                                                        json:"created_at"`
+	UpdatedAt      time.Time                   `                                                        json:"updated_at"`
+}
+
+func (Role) TableName() string {
+	return "organization_roles"
+}
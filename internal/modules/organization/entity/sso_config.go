@@ -0,0 +1,154 @@
+package entity
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// SSOConfig lets an organization's staff sign in through its own OpenID
+// Connect identity provider. The client secret is stored encrypted with the
+// organization's data key, as encoded by EncodeClientSecret.
+type SSOConfig struct {
+	ID             uuid.UUID                             `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID                             `gorm:"type:uuid;not null;uniqueIndex"                   json:"organization_id"`
+	Enabled        bool                                  `gorm:"not null;default:true"                            json:"enabled"`
+	Issuer         string                                `gorm:"type:varchar(500);not null"                       json:"issuer"`
+	ClientID       string                                `gorm:"type:varchar(255);not null"                       json:"client_id"`
+	ClientSecret   string                                `gorm:"type:text;not null"                               json:"-"`
+	AllowedDomains datatypes.JSONSlice[string]           `gorm:"type:jsonb;not null"                              json:"allowed_domains"`
+	GroupsClaim    string                                `gorm:"type:varchar(100);not null;default:'groups'"      json:"groups_claim"`
+	GroupRoles     datatypes.JSONType[map[string]string] `gorm:"type:jsonb;not null"                              json:"group_roles"`
+	DefaultRole    string                                `gorm:"type:varchar(50);not null;default:'member'"       json:"default_role"`
+	CreatedAt      time.Time                             `                                                        json:"created_at"`
+	UpdatedAt      time.Time                             `                                                        json:"updated_at"`
+
+	Domains []SSODomain `gorm:"foreignKey:OrganizationID;references:OrganizationID" json:"-"`
+}
+
+func (SSOConfig) TableName() string {
+	return "organization_sso_configs"
+}
+
+// EncodeClientSecret formats an encrypted client secret for storage, prefixed
+// with the ID of the key it is encrypted with
+func EncodeClientSecret(ciphertext []byte, keyID string) string {
+	return keyID + ":" + base64.StdEncoding.EncodeToString(ciphertext)
+}
+
+// DecodeClientSecret splits a stored client secret into its ciphertext and
+// key ID. Data key IDs contain a colon themselves, so the ciphertext follows
+// the last one. Secrets without a key ID predate key versioning and return
+// an empty one.
+func DecodeClientSecret(stored string) ([]byte, string, error) {
+	keyID, encoded := "", stored
+	if i := strings.LastIndex(stored, ":"); i >= 0 {
+		keyID, encoded = stored[:i], stored[i+1:]
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode client secret: %w", err)
+	}
+	return ciphertext, keyID, nil
+}
+
+// SSODomainVerificationPrefix names the DNS TXT record that proves
+// ownership of a domain, at _openmind-verification.<domain>
+const SSODomainVerificationPrefix = "_openmind-verification."
+
+// SSODomain is an email domain an organization claims for single sign-on.
+// The claim takes effect once the domain is verified.
+type SSODomain struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrganizationID    uuid.UUID  `gorm:"type:uuid;not null;index"                         json:"organization_id"`
+	Domain            string     `gorm:"type:varchar(255);not null"                       json:"domain"`
+	VerificationToken string     `gorm:"type:varchar(64);not null"                        json:"-"`
+	VerifiedAt        *time.Time `                                                        json:"verified_at"`
+	CreatedAt         time.Time  `                                                        json:"created_at"`
+}
+
+func (SSODomain) TableName() string {
+	return "organization_sso_domains"
+}
+
+// VerificationRecord is the DNS TXT record name that proves ownership
+func (d *SSODomain) VerificationRecord() string {
+	return SSODomainVerificationPrefix + d.Domain
+}
+
+// VerificationValue is the expected content of the verification record
+func (d *SSODomain) VerificationValue() string {
+	return "openmind-verification=" + d.VerificationToken
+}
+
+// AllowsEmail reports whether the email's domain may sign in through SSO.
+// Only allowed domains whose ownership has been verified qualify.
+func (c *SSOConfig) AllowsEmail(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, d := range c.AllowedDomains {
+		if d == domain {
+			return c.IsVerified(domain)
+		}
+	}
+	return false
+}
+
+// IsVerified reports whether the organization has proven it owns the domain
+func (c *SSOConfig) IsVerified(domain string) bool {
+	for _, d := range c.Domains {
+		if d.Domain == domain && d.VerifiedAt != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleForGroups maps identity provider groups to a member role. When groups
+// map to several roles the most privileged built-in role wins, then custom
+// roles in name order; without a mapped group the default role applies.
+func (c *SSOConfig) RoleForGroups(groups []string) string {
+	mapping := c.GroupRoles.Data()
+	roles := make([]string, 0, len(groups))
+	for _, g := range groups {
+		if role, ok := mapping[g]; ok {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		return c.DefaultRole
+	}
+
+	sort.Slice(roles, func(i, j int) bool {
+		ri, rj := roleRank(roles[i]), roleRank(roles[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return roles[i] < roles[j]
+	})
+	return roles[0]
+}
+
+// ssoRoleRank orders built-in roles for RoleForGroups. Custom roles rank
+// between clinician and member.
+var ssoRoleRank = map[string]int{
+	RoleAdmin:     0,
+	RoleClinician: 1,
+	RoleMember:    3,
+}
+
+func roleRank(role string) int {
+	if rank, ok := ssoRoleRank[role]; ok {
+		return rank
+	}
+	return 2
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Supervision makes a licensed clinician responsible for reviewing and
+// co-signing the notes of a trainee, such as an associate or intern. A
+// supervision is active until it is ended; ended ones are kept as a record of
+// who supervised whom.
+type Supervision struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null"                               json:"organization_id"`
+	SupervisorID   uuid.UUID  `gorm:"type:uuid;not null"                               json:"supervisor_id"`
+	SuperviseeID   uuid.UUID  `gorm:"type:uuid;not null"                               json:"supervisee_id"`
+	CreatedBy      *uuid.UUID `gorm:"type:uuid"                                        json:"created_by"`
+	CreatedAt      time.Time  `                                                        json:"created_at"`
+	EndedBy        *uuid.UUID `gorm:"type:uuid"                                        json:"ended_by"`
+	EndedAt        *time.Time `                                                        json:"ended_at"`
+}
+
+func (Supervision) TableName() string {
+	return "clinical_supervisions"
+}
+
+// SupervisionDetail is an active supervision joined with the names of both
+// members
+type SupervisionDetail struct {
+	ID             uuid.UUID  `json:"id"`
+	SupervisorID   uuid.UUID  `json:"supervisor_id"`
+	SupervisorName string     `json:"supervisor_name"`
+	SuperviseeID   uuid.UUID  `json:"supervisee_id"`
+	SuperviseeName string     `json:"supervisee_name"`
+	CreatedBy      *uuid.UUID `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type APIKeyHandler struct {
+	svc service.APIKeyService
+}
+
+func NewAPIKeyHandler(svc service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{svc: svc}
+}
+
+func (h *APIKeyHandler) Create(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.Create(orgID, userID, req)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	response.Created(c, resp, "API key created successfully. Store it now, it will not be shown again")
+}
+
+func (h *APIKeyHandler) List(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	resp, err := h.svc.List(orgID)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("API keys retrieved successfully", resp))
+}
+
+func (h *APIKeyHandler) Revoke(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid API key ID", nil)
+		return
+	}
+
+	if err := h.svc.Revoke(orgID, userID, keyID); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("API key revoked successfully", nil))
+}
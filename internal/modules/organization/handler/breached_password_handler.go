@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type BreachedPasswordHandler struct {
+	svc service.BreachedPasswordService
+}
+
+func NewBreachedPasswordHandler(svc service.BreachedPasswordService) *BreachedPasswordHandler {
+	return &BreachedPasswordHandler{svc: svc}
+}
+
+func (h *BreachedPasswordHandler) Get(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	resp, err := h.svc.Get(orgID)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Breached password list retrieved successfully", resp))
+}
+
+// Upload takes a text file of SHA-1 hashes in the "file" form field
+func (h *BreachedPasswordHandler) Upload(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "No file uploaded", nil)
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		response.InternalServerError(c, "Failed to open file")
+		return
+	}
+	defer f.Close()
+
+	resp, err := h.svc.Upload(orgID, f)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Breached password list uploaded successfully", resp))
+}
+
+func (h *BreachedPasswordHandler) Clear(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	if err := h.svc.Clear(orgID); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Breached password list cleared successfully", nil))
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type DataKeyHandler struct {
+	svc service.DataKeyService
+}
+
+func NewDataKeyHandler(svc service.DataKeyService) *DataKeyHandler {
+	return &DataKeyHandler{svc: svc}
+}
+
+// Destroy crypto-shreds the organization's encrypted data
+func (h *DataKeyHandler) Destroy(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	roleVal, exists := c.Get("role")
+	if !exists {
+		response.Unauthorized(c, "User role not found")
+		return
+	}
+	role := roleVal.(string)
+
+	var req dto.DestroyDataKeyRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	err := h.svc.Destroy(
+		context.Background(),
+		orgID,
+		userID,
+		role,
+		req.Confirmation,
+		c.ClientIP(),
+		string(c.UserAgent()),
+	)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(
+		consts.StatusOK,
+		response.Success("Organization data key destroyed; its encrypted data is no longer readable", nil),
+	)
+}
@@ -19,7 +19,7 @@ func NewOrganizationHandler(svc service.OrganizationService) *OrganizationHandle
 	return &OrganizationHandler{svc: svc}
 }
 
-func (h *OrganizationHandler) GetMyOrganization(_ context.Context, c *app.RequestContext) {
+func (h *OrganizationHandler) ListOrganizations(_ context.Context, c *app.RequestContext) {
 	userIDVal, exists := c.Get("userID")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
@@ -27,7 +27,31 @@ func (h *OrganizationHandler) GetMyOrganization(_ context.Context, c *app.Reques
 	}
 	userID := userIDVal.(uuid.UUID)
 
-	resp, err := h.svc.GetMyOrganization(userID)
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	resp, err := h.svc.ListOrganizations(userID, orgID)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Organizations retrieved successfully", resp))
+}
+
+func (h *OrganizationHandler) GetMyOrganization(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	resp, err := h.svc.GetMyOrganization(orgID)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -37,12 +61,12 @@ func (h *OrganizationHandler) GetMyOrganization(_ context.Context, c *app.Reques
 }
 
 func (h *OrganizationHandler) UpdateOrganization(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	orgIDVal, exists := c.Get("organizationID")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
+	orgID := orgIDVal.(uuid.UUID)
 
 	var req dto.UpdateOrganizationRequest
 	if err := c.BindAndValidate(&req); err != nil {
@@ -50,7 +74,7 @@ func (h *OrganizationHandler) UpdateOrganization(_ context.Context, c *app.Reque
 		return
 	}
 
-	resp, err := h.svc.UpdateOrganization(userID, req)
+	resp, err := h.svc.UpdateOrganization(orgID, req)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -58,3 +82,85 @@ func (h *OrganizationHandler) UpdateOrganization(_ context.Context, c *app.Reque
 
 	c.JSON(consts.StatusOK, response.Success("Organization updated successfully", resp))
 }
+
+func (h *OrganizationHandler) ListMembers(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	resp, err := h.svc.ListMembers(orgID)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Members retrieved successfully", resp))
+}
+
+func (h *OrganizationHandler) UpdateMemberRole(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	memberID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid member ID", nil)
+		return
+	}
+
+	var req dto.UpdateMemberRoleRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.UpdateMemberRole(orgID, userID, memberID, req.Role)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Member role updated successfully", resp))
+}
+
+func (h *OrganizationHandler) RemoveMember(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	memberID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid member ID", nil)
+		return
+	}
+
+	if err := h.svc.RemoveMember(orgID, userID, memberID); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Member removed successfully", nil))
+}
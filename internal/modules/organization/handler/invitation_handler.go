@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type InvitationHandler struct {
+	svc service.InvitationService
+}
+
+func NewInvitationHandler(svc service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{svc: svc}
+}
+
+func (h *InvitationHandler) Create(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	var req dto.CreateInvitationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.Create(orgID, userID, req)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	response.Created(c, resp, "Invitation sent successfully")
+}
+
+func (h *InvitationHandler) List(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	resp, err := h.svc.List(orgID, userID)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Invitations retrieved successfully", resp))
+}
+
+func (h *InvitationHandler) Revoke(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	invitationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid invitation ID", nil)
+		return
+	}
+
+	if err := h.svc.Revoke(orgID, userID, invitationID); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Invitation revoked successfully", nil))
+}
+
+func (h *InvitationHandler) Preview(_ context.Context, c *app.RequestContext) {
+	resp, err := h.svc.Preview(c.Param("token"))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Invitation retrieved successfully", resp))
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type RoleHandler struct {
+	svc service.RoleService
+}
+
+func NewRoleHandler(svc service.RoleService) *RoleHandler {
+	return &RoleHandler{svc: svc}
+}
+
+func (h *RoleHandler) ListPermissions(_ context.Context, c *app.RequestContext) {
+	c.JSON(consts.StatusOK, response.Success("Permissions retrieved successfully", h.svc.ListPermissions()))
+}
+
+func (h *RoleHandler) MyPermissions(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	roleVal, exists := c.Get("role")
+	if !exists {
+		response.Unauthorized(c, "User role not found")
+		return
+	}
+	role := roleVal.(string)
+
+	permissions, err := h.svc.Permissions(orgID, role)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Permissions retrieved successfully", dto.MyPermissionsResponse{
+		Role:        role,
+		Permissions: permissions,
+	}))
+}
+
+func (h *RoleHandler) List(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	resp, err := h.svc.ListRoles(orgID)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Roles retrieved successfully", resp))
+}
+
+func (h *RoleHandler) Create(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	roleVal, exists := c.Get("role")
+	if !exists {
+		response.Unauthorized(c, "User role not found")
+		return
+	}
+	role := roleVal.(string)
+
+	var req dto.CreateRoleRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.CreateRole(orgID, role, req)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	response.Created(c, resp, "Role created successfully")
+}
+
+func (h *RoleHandler) Update(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	roleVal, exists := c.Get("role")
+	if !exists {
+		response.Unauthorized(c, "User role not found")
+		return
+	}
+	role := roleVal.(string)
+
+	var req dto.UpdateRoleRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.UpdateRole(orgID, role, c.Param("name"), req)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Role updated successfully", resp))
+}
+
+func (h *RoleHandler) Delete(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	roleVal, exists := c.Get("role")
+	if !exists {
+		response.Unauthorized(c, "User role not found")
+		return
+	}
+	role := roleVal.(string)
+
+	if err := h.svc.DeleteRole(orgID, role, c.Param("name")); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Role deleted successfully", nil))
+}
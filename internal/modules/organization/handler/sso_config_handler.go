@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type SSOConfigHandler struct {
+	svc service.SSOConfigService
+}
+
+func NewSSOConfigHandler(svc service.SSOConfigService) *SSOConfigHandler {
+	return &SSOConfigHandler{svc: svc}
+}
+
+func (h *SSOConfigHandler) Get(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	resp, err := h.svc.Get(orgID)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("SSO configuration retrieved successfully", resp))
+}
+
+func (h *SSOConfigHandler) Update(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	roleVal, exists := c.Get("role")
+	if !exists {
+		response.Unauthorized(c, "User role not found")
+		return
+	}
+	role := roleVal.(string)
+
+	var req dto.UpdateSSOConfigRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.Update(orgID, role, req)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("SSO configuration updated successfully", resp))
+}
+
+func (h *SSOConfigHandler) Delete(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	if err := h.svc.Delete(orgID); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("SSO configuration removed successfully", nil))
+}
+
+func (h *SSOConfigHandler) VerifyDomain(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	resp, err := h.svc.VerifyDomain(orgID, c.Param("domain"))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("SSO domain verified successfully", resp))
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(key *entity.APIKey) error
+	FindByID(id uuid.UUID) (*entity.APIKey, error)
+	FindByKeyHash(keyHash string) (*entity.APIKey, error)
+	ListByOrganization(orgID uuid.UUID) ([]entity.APIKey, error)
+	Revoke(id uuid.UUID) error
+	TouchLastUsed(id uuid.UUID, usedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewAPIKeyRepository(db *gorm.DB, log logger.Logger) APIKeyRepository {
+	return &apiKeyRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *apiKeyRepository) Create(key *entity.APIKey) error {
+	if err := r.db.Create(key).Error; err != nil {
+		r.log.Error("Failed to create API key", zap.Error(err), zap.String("org_id", key.OrganizationID.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) FindByID(id uuid.UUID) (*entity.APIKey, error) {
+	var key entity.APIKey
+	if err := r.db.First(&key, "id = ?", id).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find API key", zap.Error(err), zap.String("id", id.String()))
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) FindByKeyHash(keyHash string) (*entity.APIKey, error) {
+	var key entity.APIKey
+	if err := r.db.First(&key, "key_hash = ?", keyHash).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find API key by hash", zap.Error(err))
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListByOrganization returns every key of the organization, revoked and
+// expired ones included, newest first
+func (r *apiKeyRepository) ListByOrganization(orgID uuid.UUID) ([]entity.APIKey, error) {
+	var keys []entity.APIKey
+	err := r.db.Where("organization_id = ?", orgID).Order("created_at DESC").Find(&keys).Error
+	if err != nil {
+		r.log.Error("Failed to list API keys", zap.Error(err), zap.String("org_id", orgID.String()))
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Revoke(id uuid.UUID) error {
+	err := r.db.Model(&entity.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		r.log.Error("Failed to revoke API key", zap.Error(err), zap.String("id", id.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(id uuid.UUID, usedAt time.Time) error {
+	err := r.db.Model(&entity.APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+	if err != nil {
+		r.log.Error("Failed to update API key last use", zap.Error(err), zap.String("id", id.String()))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const breachedHashBatchSize = 1000
+
+type BreachedPasswordRepository interface {
+	Replace(orgID uuid.UUID, hashes []string) error
+	Count(orgID uuid.UUID) (int64, error)
+	Clear(orgID uuid.UUID) error
+	Exists(hash string, userID, orgID uuid.UUID) (bool, error)
+}
+
+type breachedPasswordRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewBreachedPasswordRepository(db *gorm.DB, log logger.Logger) BreachedPasswordRepository {
+	return &breachedPasswordRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Replace swaps the organization's list for hashes in one transaction
+func (r *breachedPasswordRepository) Replace(orgID uuid.UUID, hashes []string) error {
+	rows := make([]entity.BreachedPasswordHash, len(hashes))
+	for i, hash := range hashes {
+		rows[i] = entity.BreachedPasswordHash{OrganizationID: orgID, Hash: hash}
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_id = ?", orgID).Delete(&entity.BreachedPasswordHash{}).Error; err != nil {
+			r.log.Error("Failed to clear breached password hashes", zap.Error(err))
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(rows, breachedHashBatchSize).Error; err != nil {
+			r.log.Error("Failed to store breached password hashes", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *breachedPasswordRepository) Count(orgID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.BreachedPasswordHash{}).Where("organization_id = ?", orgID).Count(&count).Error
+	if err != nil {
+		r.log.Error("Failed to count breached password hashes", zap.Error(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *breachedPasswordRepository) Clear(orgID uuid.UUID) error {
+	if err := r.db.Where("organization_id = ?", orgID).Delete(&entity.BreachedPasswordHash{}).Error; err != nil {
+		r.log.Error("Failed to clear breached password hashes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Exists reports whether hash is on the list of orgID or of any organization
+// userID belongs to. Either ID may be uuid.Nil.
+func (r *breachedPasswordRepository) Exists(hash string, userID, orgID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.BreachedPasswordHash{}).
+		Where("hash = ?", hash).
+		Where(
+			r.db.Where("organization_id = ?", orgID).
+				Or("organization_id IN (?)",
+					r.db.Table("organization_members").Select("organization_id").Where("user_id = ?", userID)),
+		).
+		Count(&count).Error
+	if err != nil {
+		r.log.Error("Failed to check breached password hashes", zap.Error(err))
+		return false, err
+	}
+	return count > 0, nil
+}
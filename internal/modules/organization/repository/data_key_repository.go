@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrDataShredded is returned when creating a data key for an organization
+// whose data key has been destroyed
+var ErrDataShredded = errors.New("organization data has been shredded")
+
+type DataKeyRepository interface {
+	FindActive(orgID uuid.UUID) (*entity.DataKey, error)
+	FindByID(id uuid.UUID) (*entity.DataKey, error)
+	Create(key *entity.DataKey) error
+	Destroy(orgID, actorID uuid.UUID, at time.Time) (bool, error)
+	CountSharedKeyRecords(orgID uuid.UUID) (int64, error)
+	ListWrappedWithOther(masterKeyID string, after uuid.UUID, limit int) ([]entity.DataKey, error)
+	Rewrap(id uuid.UUID, oldMasterKeyID string, wrapped []byte, masterKeyID string) (bool, error)
+}
+
+type dataKeyRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewDataKeyRepository(db *gorm.DB, log logger.Logger) DataKeyRepository {
+	return &dataKeyRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *dataKeyRepository) FindActive(orgID uuid.UUID) (*entity.DataKey, error) {
+	var key entity.DataKey
+	if err := r.db.First(&key, "organization_id = ? AND destroyed_at IS NULL", orgID).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find organization data key", zap.Error(err), zap.String("org_id", orgID.String()))
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *dataKeyRepository) FindByID(id uuid.UUID) (*entity.DataKey, error) {
+	var key entity.DataKey
+	if err := r.db.First(&key, "id = ?", id).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find data key", zap.Error(err), zap.String("id", id.String()))
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Create fails when the organization already has a key in use, and with
+// ErrDataShredded once its data key has been destroyed. The organization row
+// is locked so a concurrent Destroy cannot slip in between.
+func (r *dataKeyRepository) Create(key *entity.DataKey) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var org entity.Organization
+		err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "data_shredded_at").
+			First(&org, "id = ?", key.OrganizationID).Error
+		if err != nil {
+			return err
+		}
+		if org.DataShreddedAt != nil {
+			return ErrDataShredded
+		}
+		return tx.Create(key).Error
+	})
+	if err != nil && !errors.Is(err, ErrDataShredded) {
+		r.log.Error(
+			"Failed to create organization data key",
+			zap.Error(err),
+			zap.String("org_id", key.OrganizationID.String()),
+		)
+	}
+	return err
+}
+
+// Destroy erases the wrapped key the organization has in use and marks the
+// organization as shredded, so that no new key is created for it. It reports
+// whether there was a key.
+func (r *dataKeyRepository) Destroy(orgID, actorID uuid.UUID, at time.Time) (bool, error) {
+	destroyed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&entity.Organization{}).
+			Where("id = ?", orgID).
+			UpdateColumn("data_shredded_at", at).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.DataKey{}).
+			Where("organization_id = ? AND destroyed_at IS NULL", orgID).
+			Updates(map[string]interface{}{
+				"wrapped_key":  nil,
+				"destroyed_at": at,
+				"destroyed_by": actorID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		destroyed = result.RowsAffected > 0
+		if !destroyed {
+			// Nothing to shred; leave the organization as it was
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		r.log.Error("Failed to destroy organization data key", zap.Error(err), zap.String("org_id", orgID.String()))
+		return false, err
+	}
+	return destroyed, nil
+}
+
+// CountSharedKeyRecords counts the organization's patients, clinical records
+// and SSO client secret that are not encrypted with a data key, and so would
+// survive the data key being destroyed
+func (r *dataKeyRepository) CountSharedKeyRecords(orgID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM patients
+				WHERE organization_id = @org AND (key_id IS NULL OR key_id NOT LIKE @prefix))
+			+ (SELECT COUNT(*) FROM clinical_notes
+				WHERE organization_id = @org AND content_encrypted IS NOT NULL AND key_id NOT LIKE @prefix)
+			+ (SELECT COUNT(*) FROM clinical_note_addendums a JOIN clinical_notes n ON n.id = a.note_id
+				WHERE n.organization_id = @org AND a.content_encrypted IS NOT NULL AND a.key_id NOT LIKE @prefix)
+			+ (SELECT COUNT(*) FROM clinical_note_attachments a JOIN clinical_notes n ON n.id = a.note_id
+				WHERE n.organization_id = @org AND a.key_id NOT LIKE @prefix)
+			+ (SELECT COUNT(*) FROM organization_sso_configs
+				WHERE organization_id = @org AND client_secret NOT LIKE @prefix)`,
+		map[string]interface{}{"org": orgID, "prefix": entity.DataKeyIDPrefix + "%"},
+	).Scan(&count).Error
+	if err != nil {
+		r.log.Error("Failed to count records under shared keys", zap.Error(err), zap.String("org_id", orgID.String()))
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListWrappedWithOther returns data keys in use that are wrapped with a master
+// key other than the given one, ordered by ID and starting after the given one
+func (r *dataKeyRepository) ListWrappedWithOther(
+	masterKeyID string,
+	after uuid.UUID,
+	limit int,
+) ([]entity.DataKey, error) {
+	var keys []entity.DataKey
+	err := r.db.
+		Where("destroyed_at IS NULL AND master_key_id <> ? AND id > ?", masterKeyID, after).
+		Order("id").
+		Limit(limit).
+		Find(&keys).Error
+	if err != nil {
+		r.log.Error("Failed to list data keys to re-wrap", zap.Error(err))
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Rewrap replaces the wrapped form of a data key, provided it is still in use
+// and wrapped with oldMasterKeyID. It reports whether the key was updated.
+func (r *dataKeyRepository) Rewrap(
+	id uuid.UUID,
+	oldMasterKeyID string,
+	wrapped []byte,
+	masterKeyID string,
+) (bool, error) {
+	result := r.db.Model(&entity.DataKey{}).
+		Where("id = ? AND master_key_id = ? AND destroyed_at IS NULL", id, oldMasterKeyID).
+		Updates(map[string]interface{}{
+			"wrapped_key":   wrapped,
+			"master_key_id": masterKeyID,
+		})
+	if result.Error != nil {
+		r.log.Error("Failed to re-wrap data key", zap.Error(result.Error), zap.String("id", id.String()))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type InvitationRepository interface {
+	Create(invitation *entity.Invitation) error
+	FindByID(id uuid.UUID) (*entity.Invitation, error)
+	FindByTokenHash(tokenHash string) (*entity.Invitation, error)
+	FindPendingByEmail(orgID uuid.UUID, email string) (*entity.Invitation, error)
+	ListPending(orgID uuid.UUID) ([]entity.Invitation, error)
+	Revoke(id uuid.UUID) error
+}
+
+type invitationRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewInvitationRepository(db *gorm.DB, log logger.Logger) InvitationRepository {
+	return &invitationRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *invitationRepository) Create(invitation *entity.Invitation) error {
+	if err := r.db.Create(invitation).Error; err != nil {
+		r.log.Error("Failed to create invitation", zap.Error(err), zap.String("email", invitation.Email))
+		return err
+	}
+	return nil
+}
+
+func (r *invitationRepository) FindByID(id uuid.UUID) (*entity.Invitation, error) {
+	var invitation entity.Invitation
+	if err := r.db.First(&invitation, "id = ?", id).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find invitation", zap.Error(err), zap.String("id", id.String()))
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindByTokenHash(tokenHash string) (*entity.Invitation, error) {
+	var invitation entity.Invitation
+	if err := r.db.First(&invitation, "token_hash = ?", tokenHash).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find invitation by token", zap.Error(err))
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindPendingByEmail(orgID uuid.UUID, email string) (*entity.Invitation, error) {
+	var invitation entity.Invitation
+	err := r.db.Where(
+		"organization_id = ? AND LOWER(email) = LOWER(?) AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?",
+		orgID, email, time.Now(),
+	).First(&invitation).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find pending invitation", zap.Error(err), zap.String("email", email))
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) ListPending(orgID uuid.UUID) ([]entity.Invitation, error) {
+	var invitations []entity.Invitation
+	err := r.db.Where(
+		"organization_id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?",
+		orgID, time.Now(),
+	).Order("created_at DESC").Find(&invitations).Error
+	if err != nil {
+		r.log.Error("Failed to list invitations", zap.Error(err), zap.String("org_id", orgID.String()))
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *invitationRepository) Revoke(id uuid.UUID) error {
+	err := r.db.Model(&entity.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		r.log.Error("Failed to revoke invitation", zap.Error(err), zap.String("id", id.String()))
+		return err
+	}
+	return nil
+}
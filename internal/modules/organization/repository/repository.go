@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
 	"github.com/sahabatharianmu/OpenMind/pkg/logger"
@@ -10,9 +13,18 @@ import (
 
 type OrganizationRepository interface {
 	GetByID(id uuid.UUID) (*entity.Organization, error)
-	GetByUserID(userID uuid.UUID) (*entity.Organization, error)
 	GetMemberCount(orgID uuid.UUID) (int64, error)
+	IsMember(orgID, userID uuid.UUID) (bool, error)
 	Update(org *entity.Organization) error
+	ListMembers(orgID uuid.UUID) ([]entity.MemberDetail, error)
+	GetMemberDetail(orgID, userID uuid.UUID) (*entity.MemberDetail, error)
+	GetMember(orgID, userID uuid.UUID) (*entity.OrganizationMember, error)
+	GetMembershipByUserID(userID uuid.UUID) (*entity.OrganizationMember, error)
+	ListMemberships(userID uuid.UUID) ([]entity.Membership, error)
+	CountMemberships(userID uuid.UUID) (int64, error)
+	UpdateMemberRole(orgID, userID uuid.UUID, role string) error
+	SetMemberDeactivated(orgID, userID uuid.UUID, deactivatedAt *time.Time) error
+	RemoveMember(orgID, userID uuid.UUID) error
 }
 
 type organizationRepository struct {
@@ -36,38 +48,175 @@ func (r *organizationRepository) GetByID(id uuid.UUID) (*entity.Organization, er
 	return &org, nil
 }
 
-func (r *organizationRepository) GetByUserID(userID uuid.UUID) (*entity.Organization, error) {
-	var org entity.Organization
-	err := r.db.Table("organizations").
-		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
-		Where("organization_members.user_id = ?", userID).
-		First(&org).Error
+func (r *organizationRepository) GetMemberCount(orgID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.OrganizationMember{}).
+		Where("organization_id = ?", orgID).
+		Count(&count).Error
 
 	if err != nil {
-		r.log.Error("Failed to get organization by user ID", zap.Error(err), zap.String("user_id", userID.String()))
+		r.log.Error("Failed to count organization members", zap.Error(err), zap.String("org_id", orgID.String()))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *organizationRepository) IsMember(orgID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Count(&count).Error
+
+	if err != nil {
+		r.log.Error("Failed to check organization membership", zap.Error(err), zap.String("org_id", orgID.String()))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Update saves the organization's settings. The shredded mark is only set by
+// destroying the data key and is never written back from a stale copy.
+func (r *organizationRepository) Update(org *entity.Organization) error {
+	if err := r.db.Omit("data_shredded_at").Save(org).Error; err != nil {
+		r.log.Error("Failed to update organization", zap.Error(err), zap.String("id", org.ID.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *organizationRepository) ListMembers(orgID uuid.UUID) ([]entity.MemberDetail, error) {
+	var members []entity.MemberDetail
+	err := r.memberDetails().
+		Where("organization_members.organization_id = ?", orgID).
+		Order("organization_members.created_at ASC").
+		Scan(&members).Error
+
+	if err != nil {
+		r.log.Error("Failed to list organization members", zap.Error(err), zap.String("org_id", orgID.String()))
 		return nil, err
 	}
 
-	return &org, nil
+	return members, nil
 }
 
-func (r *organizationRepository) GetMemberCount(orgID uuid.UUID) (int64, error) {
+func (r *organizationRepository) GetMemberDetail(orgID, userID uuid.UUID) (*entity.MemberDetail, error) {
+	var member entity.MemberDetail
+	err := r.memberDetails().
+		Where("organization_members.organization_id = ? AND organization_members.user_id = ?", orgID, userID).
+		Take(&member).Error
+
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get organization member", zap.Error(err), zap.String("user_id", userID.String()))
+		}
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (r *organizationRepository) memberDetails() *gorm.DB {
+	return r.db.Table("organization_members").
+		Select("organization_members.user_id, users.email, users.full_name, organization_members.role, " +
+			"organization_members.deactivated_at, organization_members.created_at").
+		Joins("JOIN users ON users.id = organization_members.user_id AND users.deleted_at IS NULL")
+}
+
+func (r *organizationRepository) GetMember(orgID, userID uuid.UUID) (*entity.OrganizationMember, error) {
+	var member entity.OrganizationMember
+	err := r.db.Where("organization_id = ? AND user_id = ?", orgID, userID).First(&member).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get organization member", zap.Error(err), zap.String("org_id", orgID.String()))
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+// GetMembershipByUserID returns the user's default membership, the oldest
+// active one, used when no organization has been selected
+func (r *organizationRepository) GetMembershipByUserID(userID uuid.UUID) (*entity.OrganizationMember, error) {
+	var member entity.OrganizationMember
+	err := r.db.Where("user_id = ? AND deactivated_at IS NULL", userID).Order("created_at ASC").First(&member).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get membership", zap.Error(err), zap.String("user_id", userID.String()))
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+// ListMemberships returns the organizations the user is an active member of
+func (r *organizationRepository) ListMemberships(userID uuid.UUID) ([]entity.Membership, error) {
+	var memberships []entity.Membership
+	err := r.db.Table("organization_members").
+		Select("organization_members.organization_id, organizations.name, organization_members.role, "+
+			"organization_members.created_at").
+		Joins("JOIN organizations ON organizations.id = organization_members.organization_id AND "+
+			"organizations.deleted_at IS NULL").
+		Where("organization_members.user_id = ? AND organization_members.deactivated_at IS NULL", userID).
+		Order("organization_members.created_at ASC").
+		Scan(&memberships).Error
+
+	if err != nil {
+		r.log.Error("Failed to list memberships", zap.Error(err), zap.String("user_id", userID.String()))
+		return nil, err
+	}
+
+	return memberships, nil
+}
+
+// CountMemberships counts the organizations the user belongs to, including
+// those that have deactivated them
+func (r *organizationRepository) CountMemberships(userID uuid.UUID) (int64, error) {
 	var count int64
 	err := r.db.Model(&entity.OrganizationMember{}).
-		Where("organization_id = ?", orgID).
+		Where("user_id = ?", userID).
 		Count(&count).Error
 
 	if err != nil {
-		r.log.Error("Failed to count organization members", zap.Error(err), zap.String("org_id", orgID.String()))
+		r.log.Error("Failed to count memberships", zap.Error(err), zap.String("user_id", userID.String()))
 		return 0, err
 	}
 
 	return count, nil
 }
 
-func (r *organizationRepository) Update(org *entity.Organization) error {
-	if err := r.db.Save(org).Error; err != nil {
-		r.log.Error("Failed to update organization", zap.Error(err), zap.String("id", org.ID.String()))
+func (r *organizationRepository) UpdateMemberRole(orgID, userID uuid.UUID, role string) error {
+	err := r.db.Model(&entity.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Update("role", role).Error
+
+	if err != nil {
+		r.log.Error("Failed to update member role", zap.Error(err), zap.String("user_id", userID.String()))
+		return err
+	}
+	return nil
+}
+
+// SetMemberDeactivated deactivates the membership at deactivatedAt, or
+// reactivates it when deactivatedAt is nil
+func (r *organizationRepository) SetMemberDeactivated(orgID, userID uuid.UUID, deactivatedAt *time.Time) error {
+	err := r.db.Model(&entity.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Update("deactivated_at", deactivatedAt).Error
+
+	if err != nil {
+		r.log.Error("Failed to update member status", zap.Error(err), zap.String("user_id", userID.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *organizationRepository) RemoveMember(orgID, userID uuid.UUID) error {
+	err := r.db.Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Delete(&entity.OrganizationMember{}).Error
+	if err != nil {
+		r.log.Error("Failed to remove organization member", zap.Error(err), zap.String("user_id", userID.String()))
 		return err
 	}
 	return nil
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	Create(role *entity.Role) error
+	Update(role *entity.Role) error
+	Delete(id uuid.UUID) error
+	FindByName(orgID uuid.UUID, name string) (*entity.Role, error)
+	ListByOrganization(orgID uuid.UUID) ([]entity.Role, error)
+	CountMembers(orgID uuid.UUID, name string) (int64, error)
+}
+
+type roleRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewRoleRepository(db *gorm.DB, log logger.Logger) RoleRepository {
+	return &roleRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *roleRepository) Create(role *entity.Role) error {
+	if err := r.db.Create(role).Error; err != nil {
+		r.log.Error("Failed to create role", zap.Error(err), zap.String("name", role.Name))
+		return err
+	}
+	return nil
+}
+
+func (r *roleRepository) Update(role *entity.Role) error {
+	if err := r.db.Save(role).Error; err != nil {
+		r.log.Error("Failed to update role", zap.Error(err), zap.String("name", role.Name))
+		return err
+	}
+	return nil
+}
+
+func (r *roleRepository) Delete(id uuid.UUID) error {
+	if err := r.db.Delete(&entity.Role{}, "id = ?", id).Error; err != nil {
+		r.log.Error("Failed to delete role", zap.Error(err), zap.String("id", id.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *roleRepository) FindByName(orgID uuid.UUID, name string) (*entity.Role, error) {
+	var role entity.Role
+	err := r.db.Where("organization_id = ? AND name = ?", orgID, name).First(&role).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find role", zap.Error(err), zap.String("name", name))
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) ListByOrganization(orgID uuid.UUID) ([]entity.Role, error) {
+	var roles []entity.Role
+	if err := r.db.Where("organization_id = ?", orgID).Order("name ASC").Find(&roles).Error; err != nil {
+		r.log.Error("Failed to list roles", zap.Error(err), zap.String("org_id", orgID.String()))
+		return nil, err
+	}
+	return roles, nil
+}
+
+// CountMembers returns how many members of the organization hold the role
+func (r *roleRepository) CountMembers(orgID uuid.UUID, name string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", orgID, name).
+		Count(&count).Error
+	if err != nil {
+		r.log.Error("Failed to count role members", zap.Error(err), zap.String("name", name))
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrDomainClaimed is returned when another organization has already
+// verified a domain
+var ErrDomainClaimed = errors.New("domain is verified by another organization")
+
+type SSOConfigRepository interface {
+	FindByOrganization(orgID uuid.UUID) (*entity.SSOConfig, error)
+	FindByDomain(domain string) (*entity.SSOConfig, error)
+	Save(config *entity.SSOConfig) error
+	Delete(orgID uuid.UUID) error
+	IsDomainVerified(domain string) (bool, error)
+	VerifyDomain(domain *entity.SSODomain) error
+}
+
+type ssoConfigRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewSSOConfigRepository(db *gorm.DB, log logger.Logger) SSOConfigRepository {
+	return &ssoConfigRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *ssoConfigRepository) FindByOrganization(orgID uuid.UUID) (*entity.SSOConfig, error) {
+	var config entity.SSOConfig
+	if err := r.db.Preload("Domains", orderByDomain).First(&config, "organization_id = ?", orgID).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find SSO config", zap.Error(err), zap.String("org_id", orgID.String()))
+		}
+		return nil, err
+	}
+	return &config, nil
+}
+
+// FindByDomain returns the configuration that allows sign-in for the email
+// domain, whether enabled or not. Only a verified domain is matched.
+func (r *ssoConfigRepository) FindByDomain(domain string) (*entity.SSOConfig, error) {
+	var config entity.SSOConfig
+	err := r.db.Preload("Domains", orderByDomain).
+		Where("allowed_domains @> jsonb_build_array(?::text)", domain).
+		Where("EXISTS (SELECT 1 FROM organization_sso_domains d "+
+			"WHERE d.organization_id = organization_sso_configs.organization_id "+
+			"AND d.domain = ? AND d.verified_at IS NOT NULL)", domain).
+		First(&config).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find SSO config by domain", zap.Error(err), zap.String("domain", domain))
+		}
+		return nil, err
+	}
+	return &config, nil
+}
+
+func orderByDomain(db *gorm.DB) *gorm.DB {
+	return db.Order("domain")
+}
+
+// Save stores the configuration with its domain claims. Claims on domains
+// the configuration no longer allows are released; existing claims keep
+// their token and verification. When the issuer changes, all claims start
+// over unverified and accounts linked at the old issuer are unlinked.
+func (r *ssoConfigRepository) Save(config *entity.SSOConfig) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := r.resetIfIssuerChanged(tx, config); err != nil {
+			return err
+		}
+
+		if err := tx.Omit(clause.Associations).Save(config).Error; err != nil {
+			r.log.Error(
+				"Failed to save SSO config",
+				zap.Error(err),
+				zap.String("org_id", config.OrganizationID.String()),
+			)
+			return err
+		}
+
+		err := tx.
+			Where("organization_id = ? AND domain NOT IN ?", config.OrganizationID, []string(config.AllowedDomains)).
+			Delete(&entity.SSODomain{}).Error
+		if err != nil {
+			r.log.Error(
+				"Failed to release SSO domains",
+				zap.Error(err),
+				zap.String("org_id", config.OrganizationID.String()),
+			)
+			return err
+		}
+
+		if len(config.Domains) == 0 {
+			return nil
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&config.Domains).Error; err != nil {
+			r.log.Error(
+				"Failed to claim SSO domains",
+				zap.Error(err),
+				zap.String("org_id", config.OrganizationID.String()),
+			)
+			return err
+		}
+		return nil
+	})
+}
+
+// resetIfIssuerChanged drops the domain claims and linked identities of a
+// stored configuration whose issuer differs from the one being saved
+func (r *ssoConfigRepository) resetIfIssuerChanged(tx *gorm.DB, config *entity.SSOConfig) error {
+	var stored entity.SSOConfig
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("issuer").
+		First(&stored, "organization_id = ?", config.OrganizationID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		r.log.Error("Failed to find SSO config", zap.Error(err), zap.String("org_id", config.OrganizationID.String()))
+		return err
+	}
+	if stored.Issuer == config.Issuer {
+		return nil
+	}
+
+	if err := tx.Where("organization_id = ?", config.OrganizationID).Delete(&entity.SSODomain{}).Error; err != nil {
+		r.log.Error(
+			"Failed to reset SSO domains",
+			zap.Error(err),
+			zap.String("org_id", config.OrganizationID.String()),
+		)
+		return err
+	}
+	err = tx.Exec(
+		"DELETE FROM user_identities WHERE organization_id = ?",
+		config.OrganizationID,
+	).Error
+	if err != nil {
+		r.log.Error(
+			"Failed to unlink SSO identities",
+			zap.Error(err),
+			zap.String("org_id", config.OrganizationID.String()),
+		)
+		return err
+	}
+	return nil
+}
+
+func (r *ssoConfigRepository) Delete(orgID uuid.UUID) error {
+	result := r.db.Where("organization_id = ?", orgID).Delete(&entity.SSOConfig{})
+	if result.Error != nil {
+		r.log.Error("Failed to delete SSO config", zap.Error(result.Error), zap.String("org_id", orgID.String()))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// IsDomainVerified reports whether any organization has verified the domain
+func (r *ssoConfigRepository) IsDomainVerified(domain string) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.SSODomain{}).
+		Where("domain = ? AND verified_at IS NOT NULL", domain).
+		Count(&count).Error
+	if err != nil {
+		r.log.Error("Failed to check SSO domain", zap.Error(err), zap.String("domain", domain))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// VerifyDomain marks the organization's claim on the domain as verified,
+// unless another organization verified it first
+func (r *ssoConfigRepository) VerifyDomain(domain *entity.SSODomain) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&entity.SSODomain{}).
+			Where("domain = ? AND organization_id <> ? AND verified_at IS NOT NULL",
+				domain.Domain, domain.OrganizationID).
+			Count(&count).Error
+		if err != nil {
+			r.log.Error("Failed to check SSO domain", zap.Error(err), zap.String("domain", domain.Domain))
+			return err
+		}
+		if count > 0 {
+			return ErrDomainClaimed
+		}
+
+		now := time.Now()
+		if err := tx.Model(domain).Update("verified_at", now).Error; err != nil {
+			r.log.Error("Failed to verify SSO domain", zap.Error(err), zap.String("domain", domain.Domain))
+			return err
+		}
+		domain.VerifiedAt = &now
+		return nil
+	})
+}
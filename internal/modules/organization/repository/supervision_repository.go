@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type SupervisionRepository interface {
+	Create(supervision *entity.Supervision) error
+	FindByID(id uuid.UUID) (*entity.Supervision, error)
+	ListActive(orgID uuid.UUID) ([]entity.SupervisionDetail, error)
+	End(id, endedBy uuid.UUID) error
+	IsSupervisor(orgID, supervisorID, superviseeID uuid.UUID) (bool, error)
+	IsSupervised(orgID, userID uuid.UUID) (bool, error)
+	ListSuperviseeIDs(orgID, supervisorID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type supervisionRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewSupervisionRepository(db *gorm.DB, log logger.Logger) SupervisionRepository {
+	return &supervisionRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *supervisionRepository) Create(supervision *entity.Supervision) error {
+	if err := r.db.Create(supervision).Error; err != nil {
+		r.log.Error(
+			"Failed to create supervision",
+			zap.Error(err),
+			zap.String("org_id", supervision.OrganizationID.String()),
+		)
+		return err
+	}
+	return nil
+}
+
+func (r *supervisionRepository) FindByID(id uuid.UUID) (*entity.Supervision, error) {
+	var supervision entity.Supervision
+	if err := r.db.First(&supervision, "id = ?", id).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find supervision", zap.Error(err), zap.String("id", id.String()))
+		}
+		return nil, err
+	}
+	return &supervision, nil
+}
+
+// ListActive returns the organization's active supervisions, grouped by
+// supervisor
+func (r *supervisionRepository) ListActive(orgID uuid.UUID) ([]entity.SupervisionDetail, error) {
+	var supervisions []entity.SupervisionDetail
+	err := r.db.Table("clinical_supervisions s").
+		Select("s.id, s.supervisor_id, supervisor.full_name AS supervisor_name, "+
+			"s.supervisee_id, supervisee.full_name AS supervisee_name, s.created_by, s.created_at").
+		Joins("JOIN users supervisor ON supervisor.id = s.supervisor_id").
+		Joins("JOIN users supervisee ON supervisee.id = s.supervisee_id").
+		Where("s.organization_id = ? AND s.ended_at IS NULL", orgID).
+		Order("supervisor.full_name, s.supervisor_id, supervisee.full_name").
+		Scan(&supervisions).Error
+	if err != nil {
+		r.log.Error("Failed to list supervisions", zap.Error(err), zap.String("org_id", orgID.String()))
+		return nil, err
+	}
+	return supervisions, nil
+}
+
+func (r *supervisionRepository) End(id, endedBy uuid.UUID) error {
+	err := r.db.Model(&entity.Supervision{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Updates(map[string]interface{}{"ended_by": endedBy, "ended_at": time.Now()}).Error
+	if err != nil {
+		r.log.Error("Failed to end supervision", zap.Error(err), zap.String("id", id.String()))
+		return err
+	}
+	return nil
+}
+
+// IsSupervisor reports whether supervisorID currently supervises superviseeID
+func (r *supervisionRepository) IsSupervisor(orgID, supervisorID, superviseeID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.Supervision{}).
+		Where("organization_id = ? AND supervisor_id = ? AND supervisee_id = ? AND ended_at IS NULL",
+			orgID, supervisorID, superviseeID).
+		Count(&count).Error
+	if err != nil {
+		r.log.Error("Failed to check supervision", zap.Error(err), zap.String("org_id", orgID.String()))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// IsSupervised reports whether the member currently has a supervisor, and so
+// needs their notes co-signed
+func (r *supervisionRepository) IsSupervised(orgID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.Supervision{}).
+		Where("organization_id = ? AND supervisee_id = ? AND ended_at IS NULL", orgID, userID).
+		Count(&count).Error
+	if err != nil {
+		r.log.Error("Failed to check supervision", zap.Error(err), zap.String("org_id", orgID.String()))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListSuperviseeIDs returns the members the supervisor currently supervises
+func (r *supervisionRepository) ListSuperviseeIDs(orgID, supervisorID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&entity.Supervision{}).
+		Where("organization_id = ? AND supervisor_id = ? AND ended_at IS NULL", orgID, supervisorID).
+		Pluck("supervisee_id", &ids).Error
+	if err != nil {
+		r.log.Error("Failed to list supervisees", zap.Error(err), zap.String("org_id", orgID.String()))
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// lastUsedResolution limits how often authenticating a key writes its
+// last-used timestamp, so busy integrations do not update the row per request
+const lastUsedResolution = time.Minute
+
+// APIKeyService manages the API keys external systems use instead of a user
+// session. Keys are shown once on creation and only their hash is stored.
+type APIKeyService interface {
+	Create(orgID, actorID uuid.UUID, req dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error)
+	List(orgID uuid.UUID) ([]dto.APIKeyResponse, error)
+	Revoke(orgID, actorID, keyID uuid.UUID) error
+	Authenticate(rawKey string) (*entity.APIKey, error)
+}
+
+type apiKeyService struct {
+	repo    repository.APIKeyRepository
+	orgRepo repository.OrganizationRepository
+	roleSvc RoleService
+	log     logger.Logger
+}
+
+func NewAPIKeyService(
+	repo repository.APIKeyRepository,
+	orgRepo repository.OrganizationRepository,
+	roleSvc RoleService,
+	log logger.Logger,
+) APIKeyService {
+	return &apiKeyService{
+		repo:    repo,
+		orgRepo: orgRepo,
+		roleSvc: roleSvc,
+		log:     log,
+	}
+}
+
+func (s *apiKeyService) Create(
+	orgID, actorID uuid.UUID,
+	req dto.CreateAPIKeyRequest,
+) (*dto.CreateAPIKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, response.NewBadRequest("Name is required")
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, response.NewBadRequest("Expiry must be in the future")
+	}
+
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if len(permissions) == 0 {
+		return nil, response.NewBadRequest("At least one permission is required")
+	}
+
+	// A key may not be used to escalate beyond its creator's own role
+	actor, err := s.orgRepo.GetMember(orgID, actorID)
+	if err != nil {
+		return nil, response.ErrNotFound
+	}
+	granted, err := s.roleSvc.Permissions(orgID, actor.Role)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range permissions {
+		if !containsString(granted, p) {
+			return nil, response.NewForbidden("You cannot grant a permission you do not have: " + p)
+		}
+	}
+
+	token, err := crypto.GenerateToken()
+	if err != nil {
+		s.log.Error("API key creation failed: token generation error", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+	rawKey := entity.APIKeyPrefix + token
+
+	key := &entity.APIKey{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		Name:           name,
+		KeyPrefix:      rawKey[:len(entity.APIKeyPrefix)+6],
+		KeyHash:        crypto.HashToken(rawKey),
+		Permissions:    permissions,
+		CreatedBy:      actorID,
+		ExpiresAt:      req.ExpiresAt,
+	}
+	if err := s.repo.Create(key); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"API key created",
+		zap.String("org_id", orgID.String()),
+		zap.String("key_id", key.ID.String()),
+		zap.String("created_by", actorID.String()),
+	)
+
+	return &dto.CreateAPIKeyResponse{
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            rawKey,
+	}, nil
+}
+
+func (s *apiKeyService) List(orgID uuid.UUID) ([]dto.APIKeyResponse, error) {
+	keys, err := s.repo.ListByOrganization(orgID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	resp := make([]dto.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		resp = append(resp, toAPIKeyResponse(&keys[i]))
+	}
+	return resp, nil
+}
+
+func (s *apiKeyService) Revoke(orgID, actorID, keyID uuid.UUID) error {
+	key, err := s.repo.FindByID(keyID)
+	if err != nil || key.OrganizationID != orgID {
+		return response.NewNotFound("API key not found")
+	}
+	if key.RevokedAt != nil {
+		return response.NewConflict("API key has already been revoked")
+	}
+
+	if err := s.repo.Revoke(key.ID); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"API key revoked",
+		zap.String("org_id", orgID.String()),
+		zap.String("key_id", key.ID.String()),
+		zap.String("revoked_by", actorID.String()),
+	)
+	return nil
+}
+
+// Authenticate resolves a plaintext key to an active API key and records
+// that it was used
+func (s *apiKeyService) Authenticate(rawKey string) (*entity.APIKey, error) {
+	if !strings.HasPrefix(rawKey, entity.APIKeyPrefix) {
+		return nil, response.NewUnauthorized("Invalid API key")
+	}
+
+	key, err := s.repo.FindByKeyHash(crypto.HashToken(rawKey))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NewUnauthorized("Invalid API key")
+		}
+		return nil, response.ErrInternalServerError
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) {
+		return nil, response.NewUnauthorized("API key has been revoked or has expired")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		// Failing to record the last use must not fail the request
+		if err := s.repo.TouchLastUsed(key.ID, now); err == nil {
+			key.LastUsedAt = &now
+		}
+	}
+
+	return key, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func toAPIKeyResponse(key *entity.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:          key.ID,
+		Name:        key.Name,
+		KeyPrefix:   key.KeyPrefix,
+		Permissions: key.Permissions,
+		CreatedBy:   key.CreatedBy,
+		ExpiresAt:   key.ExpiresAt,
+		LastUsedAt:  key.LastUsedAt,
+		RevokedAt:   key.RevokedAt,
+		Active:      key.IsActive(time.Now()),
+		CreatedAt:   key.CreatedAt,
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+)
+
+// BreachedPasswordService manages an organization's own list of breached
+// password hashes, checked alongside the bundled list whenever a member
+// sets a password
+type BreachedPasswordService interface {
+	Get(orgID uuid.UUID) (*dto.BreachedPasswordListResponse, error)
+	Upload(orgID uuid.UUID, list io.Reader) (*dto.BreachedPasswordListResponse, error)
+	Clear(orgID uuid.UUID) error
+}
+
+type breachedPasswordService struct {
+	repo repository.BreachedPasswordRepository
+	log  logger.Logger
+}
+
+func NewBreachedPasswordService(repo repository.BreachedPasswordRepository, log logger.Logger) BreachedPasswordService {
+	return &breachedPasswordService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (s *breachedPasswordService) Get(orgID uuid.UUID) (*dto.BreachedPasswordListResponse, error) {
+	count, err := s.repo.Count(orgID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	return &dto.BreachedPasswordListResponse{Count: count}, nil
+}
+
+// Upload replaces the organization's list with the SHA-1 hashes in list
+func (s *breachedPasswordService) Upload(
+	orgID uuid.UUID,
+	list io.Reader,
+) (*dto.BreachedPasswordListResponse, error) {
+	hashes, err := crypto.ParseBreachedHashes(list)
+	if err != nil {
+		return nil, response.NewBadRequest("Invalid hash list: " + err.Error())
+	}
+	if len(hashes) == 0 {
+		return nil, response.NewBadRequest("Hash list is empty")
+	}
+
+	if err := s.repo.Replace(orgID, hashes); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"Breached password list uploaded",
+		zap.String("org_id", orgID.String()),
+		zap.Int("count", len(hashes)),
+	)
+	return &dto.BreachedPasswordListResponse{Count: int64(len(hashes))}, nil
+}
+
+func (s *breachedPasswordService) Clear(orgID uuid.UUID) error {
+	if err := s.repo.Clear(orgID); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	s.log.Info("Breached password list cleared", zap.String("org_id", orgID.String()))
+	return nil
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	auditLogService "github.com/sahabatharianmu/OpenMind/internal/modules/audit_log/service"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AuditActionDataKeyDestroyed is recorded when an organization's data key is
+// destroyed
+const AuditActionDataKeyDestroyed = "organization_data_key_destroyed"
+
+// dataKeyCacheTTL bounds how long an unwrapped data key is kept in memory. It
+// is also how long other server instances may keep decrypting after a key is
+// destroyed.
+const dataKeyCacheTTL = 5 * time.Minute
+
+// ErrDataKeyDestroyed is returned when decrypting data whose organization data
+// key has been destroyed
+var ErrDataKeyDestroyed = errors.New("organization data key has been destroyed")
+
+// DataKeyService encrypts organization data with envelope encryption: each
+// organization has its own data key, created on first use and stored wrapped
+// by the key provider's master key. Data encrypted before organizations had
+// keys is still decrypted with the shared keyring. Destroying an
+// organization's data key crypto-shreds all of its data.
+type DataKeyService interface {
+	Encrypt(ctx context.Context, orgID uuid.UUID, plaintext []byte) ([]byte, string, error)
+	Decrypt(ctx context.Context, orgID uuid.UUID, ciphertext []byte, keyID string) ([]byte, error)
+	BlindIndex(ctx context.Context, orgID uuid.UUID, purpose, value string) (string, error)
+	Destroy(ctx context.Context, orgID, actorID uuid.UUID, role, confirmation, ipAddress, userAgent string) error
+}
+
+type cachedDataKey struct {
+	orgID     uuid.UUID
+	key       []byte
+	expiresAt time.Time
+}
+
+type dataKeyService struct {
+	repo        repository.DataKeyRepository
+	orgRepo     repository.OrganizationRepository
+	provider    crypto.KeyProvider
+	encryptSvc  *crypto.EncryptionService
+	auditLogSvc auditLogService.AuditLogService
+	log         logger.Logger
+
+	mu     sync.Mutex
+	keys   map[uuid.UUID]cachedDataKey
+	active map[uuid.UUID]uuid.UUID
+}
+
+func NewDataKeyService(
+	repo repository.DataKeyRepository,
+	orgRepo repository.OrganizationRepository,
+	provider crypto.KeyProvider,
+	encryptSvc *crypto.EncryptionService,
+	auditLogSvc auditLogService.AuditLogService,
+	log logger.Logger,
+) DataKeyService {
+	return &dataKeyService{
+		repo:        repo,
+		orgRepo:     orgRepo,
+		provider:    provider,
+		encryptSvc:  encryptSvc,
+		auditLogSvc: auditLogSvc,
+		log:         log,
+		keys:        make(map[uuid.UUID]cachedDataKey),
+		active:      make(map[uuid.UUID]uuid.UUID),
+	}
+}
+
+// Encrypt encrypts plaintext with the organization's data key and returns the
+// ciphertext along with the key ID to store with it
+func (s *dataKeyService) Encrypt(ctx context.Context, orgID uuid.UUID, plaintext []byte) ([]byte, string, error) {
+	id, key, err := s.activeKey(ctx, orgID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ciphertext, err := crypto.SealWithKey(key, plaintext)
+	if err != nil {
+		return nil, "", err
+	}
+	return ciphertext, entity.DataKeyIDPrefix + id.String(), nil
+}
+
+// Decrypt decrypts data of the organization with the key identified by keyID
+func (s *dataKeyService) Decrypt(
+	ctx context.Context,
+	orgID uuid.UUID,
+	ciphertext []byte,
+	keyID string,
+) ([]byte, error) {
+	if !strings.HasPrefix(keyID, entity.DataKeyIDPrefix) {
+		return s.encryptSvc.DecryptBytes(ciphertext, keyID)
+	}
+
+	id, err := uuid.Parse(strings.TrimPrefix(keyID, entity.DataKeyIDPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key ID %q", keyID)
+	}
+
+	key, err := s.key(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.OpenWithKey(key, ciphertext)
+}
+
+// BlindIndex returns a keyed hash of value for exact-match lookups of the
+// organization's encrypted data. It is keyed by the organization's data key,
+// so indexes differ between organizations and are useless once the key is
+// destroyed.
+func (s *dataKeyService) BlindIndex(ctx context.Context, orgID uuid.UUID, purpose, value string) (string, error) {
+	_, key, err := s.activeKey(ctx, orgID)
+	if err != nil {
+		return "", err
+	}
+	return crypto.BlindIndex(key, purpose, value), nil
+}
+
+// Destroy crypto-shreds the organization's data. Only the owner may do so, by
+// confirming the organization's name, and only once no data is left under the
+// shared keyring, since that data would otherwise remain readable.
+func (s *dataKeyService) Destroy(
+	ctx context.Context,
+	orgID, actorID uuid.UUID,
+	role, confirmation, ipAddress, userAgent string,
+) error {
+	if role != entity.RoleOwner {
+		return response.NewForbidden("Only the organization owner can destroy its data key")
+	}
+
+	org, err := s.orgRepo.GetByID(orgID)
+	if err != nil {
+		return response.ErrInternalServerError
+	}
+	if strings.TrimSpace(confirmation) != org.Name {
+		return response.NewBadRequest("Confirmation must match the organization name")
+	}
+
+	pending, err := s.repo.CountSharedKeyRecords(orgID)
+	if err != nil {
+		return response.ErrInternalServerError
+	}
+	if pending > 0 {
+		return response.NewConflict(
+			"Some of this organization's data is still being re-encrypted with its data key; try again later",
+		)
+	}
+
+	destroyed, err := s.repo.Destroy(orgID, actorID, time.Now())
+	if err != nil {
+		return response.ErrInternalServerError
+	}
+	if !destroyed {
+		return response.NewNotFound("This organization has no data key to destroy")
+	}
+
+	s.mu.Lock()
+	delete(s.active, orgID)
+	for id, cached := range s.keys {
+		if cached.orgID == orgID {
+			delete(s.keys, id)
+		}
+	}
+	s.mu.Unlock()
+
+	s.log.Warn(
+		"Organization data key destroyed",
+		zap.String("org_id", orgID.String()),
+		zap.String("user_id", actorID.String()),
+	)
+
+	if err := s.auditLogSvc.Log(
+		ctx,
+		AuditActionDataKeyDestroyed,
+		auditResourceOrganization,
+		&orgID,
+		actorID,
+		orgID,
+		map[string]interface{}{"organization_name": org.Name},
+		&ipAddress,
+		&userAgent,
+	); err != nil {
+		s.log.Error("Failed to write data key audit entry", zap.Error(err), zap.String("org_id", orgID.String()))
+	}
+	return nil
+}
+
+// activeKey returns the organization's data key in use, creating one if the
+// organization has none. An organization whose data key was destroyed gets
+// no new one, so it cannot go on storing data.
+func (s *dataKeyService) activeKey(ctx context.Context, orgID uuid.UUID) (uuid.UUID, []byte, error) {
+	s.mu.Lock()
+	if id, ok := s.active[orgID]; ok {
+		if cached, ok := s.keys[id]; ok && time.Now().Before(cached.expiresAt) {
+			s.mu.Unlock()
+			return id, cached.key, nil
+		}
+	}
+	s.mu.Unlock()
+
+	dataKey, err := s.repo.FindActive(orgID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		dataKey, err = s.create(ctx, orgID)
+	}
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	key, err := s.unwrap(ctx, dataKey)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	s.mu.Lock()
+	s.active[orgID] = dataKey.ID
+	s.mu.Unlock()
+	return dataKey.ID, key, nil
+}
+
+// create generates and stores a new data key for the organization. If another
+// request created one at the same time, that one is used instead.
+func (s *dataKeyService) create(ctx context.Context, orgID uuid.UUID) (*entity.DataKey, error) {
+	key, err := crypto.GenerateDataKey()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := s.provider.Wrap(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	dataKey := &entity.DataKey{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		WrappedKey:     wrapped,
+		MasterKeyID:    s.provider.KeyID(),
+	}
+	if err := s.repo.Create(dataKey); err != nil {
+		if errors.Is(err, repository.ErrDataShredded) {
+			return nil, ErrDataKeyDestroyed
+		}
+		return s.repo.FindActive(orgID)
+	}
+
+	s.log.Info(
+		"Organization data key created",
+		zap.String("org_id", orgID.String()),
+		zap.String("key_id", dataKey.KeyID()),
+		zap.String("master_key_id", dataKey.MasterKeyID),
+	)
+	return dataKey, nil
+}
+
+// key returns the unwrapped data key with the given ID, which must belong to
+// the organization
+func (s *dataKeyService) key(ctx context.Context, orgID, id uuid.UUID) ([]byte, error) {
+	s.mu.Lock()
+	cached, ok := s.keys[id]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		if cached.orgID != orgID {
+			return nil, errors.New("data key belongs to another organization")
+		}
+		return cached.key, nil
+	}
+
+	dataKey, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load data key: %w", err)
+	}
+	if dataKey.OrganizationID != orgID {
+		return nil, errors.New("data key belongs to another organization")
+	}
+	return s.unwrap(ctx, dataKey)
+}
+
+// unwrap recovers the data key from its wrapped form and caches it
+func (s *dataKeyService) unwrap(ctx context.Context, dataKey *entity.DataKey) ([]byte, error) {
+	if dataKey.DestroyedAt != nil || len(dataKey.WrappedKey) == 0 {
+		return nil, ErrDataKeyDestroyed
+	}
+
+	key, err := s.provider.Unwrap(ctx, dataKey.WrappedKey, dataKey.MasterKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	s.mu.Lock()
+	s.keys[dataKey.ID] = cachedDataKey{
+		orgID:     dataKey.OrganizationID,
+		key:       key,
+		expiresAt: time.Now().Add(dataKeyCacheTTL),
+	}
+	s.mu.Unlock()
+	return key, nil
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	userRepository "github.com/sahabatharianmu/OpenMind/internal/modules/user/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/mail"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+)
+
+const invitationTTL = 7 * 24 * time.Hour
+
+type InvitationService interface {
+	Create(orgID, actorID uuid.UUID, req dto.CreateInvitationRequest) (*dto.InvitationResponse, error)
+	List(orgID, actorID uuid.UUID) ([]dto.InvitationResponse, error)
+	Revoke(orgID, actorID, invitationID uuid.UUID) error
+	Preview(token string) (*dto.InvitationPreviewResponse, error)
+}
+
+type invitationService struct {
+	repo     repository.InvitationRepository
+	orgRepo  repository.OrganizationRepository
+	roleSvc  RoleService
+	userRepo userRepository.UserRepository
+	mailer   mail.Sender
+	baseURL  string
+	log      logger.Logger
+}
+
+func NewInvitationService(
+	repo repository.InvitationRepository,
+	orgRepo repository.OrganizationRepository,
+	roleSvc RoleService,
+	userRepo userRepository.UserRepository,
+	mailer mail.Sender,
+	baseURL string,
+	log logger.Logger,
+) InvitationService {
+	return &invitationService{
+		repo:     repo,
+		orgRepo:  orgRepo,
+		roleSvc:  roleSvc,
+		userRepo: userRepo,
+		mailer:   mailer,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		log:      log,
+	}
+}
+
+func (s *invitationService) Create(
+	orgID, actorID uuid.UUID,
+	req dto.CreateInvitationRequest,
+) (*dto.InvitationResponse, error) {
+	org, actor, err := s.managedOrganization(orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignable, err := s.roleSvc.IsAssignable(org.ID, req.Role)
+	if err != nil {
+		return nil, err
+	}
+	if !assignable {
+		return nil, response.NewBadRequest("Invalid role")
+	}
+	if err := requireCovers(s.roleSvc, org.ID, actor.Role, req.Role); err != nil {
+		return nil, err
+	}
+
+	// Existing users may be invited too; they join with their current account
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if existing, _ := s.userRepo.FindByEmail(email); existing != nil {
+		isMember, err := s.orgRepo.IsMember(org.ID, existing.ID)
+		if err != nil {
+			return nil, response.ErrInternalServerError
+		}
+		if isMember {
+			return nil, response.NewConflict("This user is already a member of the organization")
+		}
+	}
+	if pending, _ := s.repo.FindPendingByEmail(org.ID, email); pending != nil {
+		return nil, response.NewConflict("An invitation is already pending for this email")
+	}
+
+	token, err := crypto.GenerateToken()
+	if err != nil {
+		s.log.Error("Invitation failed: token generation error", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+
+	invitation := &entity.Invitation{
+		ID:             uuid.New(),
+		OrganizationID: org.ID,
+		Email:          email,
+		Role:           req.Role,
+		TokenHash:      crypto.HashToken(token),
+		InvitedBy:      actorID,
+		ExpiresAt:      time.Now().Add(invitationTTL),
+	}
+
+	if err := s.repo.Create(invitation); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	if err := s.sendInvitation(org, invitation, actorID, token); err != nil {
+		s.log.Error("Invitation failed: email delivery error", zap.Error(err), zap.String("email", email))
+		// An invitation nobody received cannot be accepted; withdraw it so
+		// the admin can simply retry
+		_ = s.repo.Revoke(invitation.ID)
+		return nil, response.NewInternalServerError("Failed to send invitation email")
+	}
+
+	s.log.Info(
+		"Invitation created",
+		zap.String("org_id", org.ID.String()),
+		zap.String("email", email),
+		zap.String("role", req.Role),
+	)
+
+	resp := toInvitationResponse(invitation)
+	return &resp, nil
+}
+
+func (s *invitationService) List(orgID, actorID uuid.UUID) ([]dto.InvitationResponse, error) {
+	org, _, err := s.managedOrganization(orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.repo.ListPending(org.ID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	resp := make([]dto.InvitationResponse, 0, len(invitations))
+	for i := range invitations {
+		resp = append(resp, toInvitationResponse(&invitations[i]))
+	}
+	return resp, nil
+}
+
+func (s *invitationService) Revoke(orgID, actorID, invitationID uuid.UUID) error {
+	org, _, err := s.managedOrganization(orgID, actorID)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := s.repo.FindByID(invitationID)
+	if err != nil || invitation.OrganizationID != org.ID {
+		return response.NewNotFound("Invitation not found")
+	}
+
+	if err := s.repo.Revoke(invitationID); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	s.log.Info("Invitation revoked", zap.String("org_id", org.ID.String()), zap.String("id", invitationID.String()))
+	return nil
+}
+
+// Preview lets the invitee see which organization they are joining before
+// choosing a password, or confirming their current one if they already
+// have an account
+func (s *invitationService) Preview(token string) (*dto.InvitationPreviewResponse, error) {
+	invitation, err := s.repo.FindByTokenHash(crypto.HashToken(token))
+	if err != nil || !invitation.IsPending(time.Now()) {
+		return nil, response.NewNotFound("Invitation is invalid or has expired")
+	}
+
+	org, err := s.orgRepo.GetByID(invitation.OrganizationID)
+	if err != nil {
+		return nil, response.ErrNotFound
+	}
+
+	existing, _ := s.userRepo.FindByEmail(invitation.Email)
+
+	return &dto.InvitationPreviewResponse{
+		OrganizationName: org.Name,
+		Email:            invitation.Email,
+		Role:             invitation.Role,
+		ExistingAccount:  existing != nil,
+		ExpiresAt:        invitation.ExpiresAt,
+	}, nil
+}
+
+// managedOrganization returns the organization and the actor's membership
+// when the actor may manage its invitations
+func (s *invitationService) managedOrganization(
+	orgID, actorID uuid.UUID,
+) (*entity.Organization, *entity.OrganizationMember, error) {
+	org, err := s.orgRepo.GetByID(orgID)
+	if err != nil {
+		return nil, nil, response.ErrNotFound
+	}
+
+	actor, err := s.orgRepo.GetMember(org.ID, actorID)
+	if err != nil {
+		return nil, nil, response.ErrNotFound
+	}
+	allowed, err := s.roleSvc.HasPermission(orgID, actor.Role, entity.PermMembersManage)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !allowed {
+		return nil, nil, response.NewForbidden("You do not have permission to manage invitations")
+	}
+
+	return org, actor, nil
+}
+
+func (s *invitationService) sendInvitation(
+	org *entity.Organization,
+	invitation *entity.Invitation,
+	actorID uuid.UUID,
+	token string,
+) error {
+	inviter := "A colleague"
+	if user, err := s.userRepo.GetByID(actorID); err == nil {
+		inviter = user.FullName
+	}
+
+	link := fmt.Sprintf("%s/auth?invite=%s", s.baseURL, url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"%s has invited you to join %s on OpenMind as %s.\r\n\r\n"+
+			"Accept the invitation here:\r\n%s\r\n\r\n"+
+			"If you already have an OpenMind account, confirm with your current password.\r\n\r\n"+
+			"This link expires on %s. If you were not expecting this invitation you can ignore this email.\r\n",
+		inviter,
+		org.Name,
+		invitation.Role,
+		link,
+		invitation.ExpiresAt.Format("January 2, 2006"),
+	)
+
+	return s.mailer.Send(mail.Message{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("You're invited to join %s", org.Name),
+		Body:    body,
+	})
+}
+
+func toInvitationResponse(invitation *entity.Invitation) dto.InvitationResponse {
+	return dto.InvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		InvitedBy: invitation.InvitedBy,
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	auditLogService "github.com/sahabatharianmu/OpenMind/internal/modules/audit_log/service"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"github.com/sahabatharianmu/OpenMind/pkg/security"
+	"go.uber.org/zap"
+)
+
+// Audit actions recorded by the organization IP allowlist
+const (
+	AuditActionIPAllowlistBypass = "ip_allowlist_bypass"
+	AuditActionIPAllowlistDenied = "ip_allowlist_denied"
+
+	auditResourceOrganization = "organization"
+)
+
+// ipAuditInterval limits how often the same member, IP and outcome is written
+// to the audit log, since the check runs on every request
+const ipAuditInterval = time.Hour
+
+// IPAccessService enforces an organization's IP allowlist. Members who can
+// manage the organization may still connect from elsewhere, so a wrong
+// allowlist cannot lock everyone out, but each such bypass is audited. API
+// keys never bypass the allowlist.
+type IPAccessService interface {
+	Check(orgID, userID uuid.UUID, role, ipAddress, userAgent string, apiKeyID uuid.UUID) error
+}
+
+type ipAccessService struct {
+	repo        repository.OrganizationRepository
+	roleSvc     RoleService
+	auditLogSvc auditLogService.AuditLogService
+	log         logger.Logger
+
+	mu        sync.Mutex
+	lastAudit map[string]time.Time
+}
+
+func NewIPAccessService(
+	repo repository.OrganizationRepository,
+	roleSvc RoleService,
+	auditLogSvc auditLogService.AuditLogService,
+	log logger.Logger,
+) IPAccessService {
+	return &ipAccessService{
+		repo:        repo,
+		roleSvc:     roleSvc,
+		auditLogSvc: auditLogSvc,
+		log:         log,
+		lastAudit:   make(map[string]time.Time),
+	}
+}
+
+func (s *ipAccessService) Check(
+	orgID, userID uuid.UUID,
+	role, ipAddress, userAgent string,
+	apiKeyID uuid.UUID,
+) error {
+	org, err := s.repo.GetByID(orgID)
+	if err != nil {
+		return response.ErrInternalServerError
+	}
+	if len(org.IPAllowlist) == 0 {
+		return nil
+	}
+
+	nets, err := security.ParseCIDRs(org.IPAllowlist)
+	if err != nil {
+		s.log.Error("Invalid IP allowlist stored for organization", zap.Error(err), zap.String("org_id", orgID.String()))
+		return response.ErrInternalServerError
+	}
+	if security.IPInRanges(nets, ipAddress) {
+		return nil
+	}
+
+	if apiKeyID == uuid.Nil {
+		canBypass, err := s.roleSvc.HasPermission(orgID, role, entity.PermOrganizationManage)
+		if err != nil {
+			return err
+		}
+		if canBypass {
+			s.audit(AuditActionIPAllowlistBypass, org.ID, userID, ipAddress, userAgent, apiKeyID)
+			return nil
+		}
+	}
+
+	s.log.Warn(
+		"Request blocked by organization IP allowlist",
+		zap.String("org_id", orgID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("ip", ipAddress),
+	)
+	s.audit(AuditActionIPAllowlistDenied, org.ID, userID, ipAddress, userAgent, apiKeyID)
+	return response.NewForbidden("Access to this organization is not allowed from your network")
+}
+
+// audit records the outcome unless the same one was recorded for the member
+// and IP within ipAuditInterval
+func (s *ipAccessService) audit(
+	action string,
+	orgID, userID uuid.UUID,
+	ipAddress, userAgent string,
+	apiKeyID uuid.UUID,
+) {
+	now := time.Now()
+	key := action + "|" + orgID.String() + "|" + userID.String() + "|" + apiKeyID.String() + "|" + ipAddress
+
+	s.mu.Lock()
+	if last, ok := s.lastAudit[key]; ok && now.Sub(last) < ipAuditInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastAudit[key] = now
+	for k, last := range s.lastAudit {
+		if now.Sub(last) >= ipAuditInterval {
+			delete(s.lastAudit, k)
+		}
+	}
+	s.mu.Unlock()
+
+	ctx := context.Background()
+	if apiKeyID != uuid.Nil {
+		ctx = auditLogService.WithAPIKey(ctx, apiKeyID)
+	}
+
+	err := s.auditLogSvc.Log(
+		ctx,
+		action,
+		auditResourceOrganization,
+		&orgID,
+		userID,
+		orgID,
+		map[string]interface{}{"ip_address": ipAddress},
+		&ipAddress,
+		&userAgent,
+	)
+	if err != nil {
+		s.log.Error("Failed to write IP allowlist audit entry", zap.Error(err), zap.String("action", action))
+	}
+}
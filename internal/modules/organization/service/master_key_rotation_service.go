@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	masterKeyRotationBatchSize = 100
+	// masterKeyRotationPause spaces out batches so re-wrapping does not
+	// starve regular traffic of database capacity
+	masterKeyRotationPause = 200 * time.Millisecond
+)
+
+// MasterKeyRotationService re-wraps organization data keys still wrapped with
+// a retired master key under the provider's current one. Each key records the
+// master key it is wrapped with, so an interrupted run simply resumes with
+// the keys left on the next start. Keys that fail, for instance because their
+// master key is no longer configured, are counted and skipped; they are
+// retried the next time the service runs. Once no key is left under a retired
+// master key, that key can be removed from the configuration.
+type MasterKeyRotationService interface {
+	Run(ctx context.Context)
+}
+
+type masterKeyRotationService struct {
+	repo     repository.DataKeyRepository
+	provider crypto.KeyProvider
+	log      logger.Logger
+}
+
+func NewMasterKeyRotationService(
+	repo repository.DataKeyRepository,
+	provider crypto.KeyProvider,
+	log logger.Logger,
+) MasterKeyRotationService {
+	return &masterKeyRotationService{
+		repo:     repo,
+		provider: provider,
+		log:      log,
+	}
+}
+
+// Run re-wraps until no data key is left under another master key or ctx is
+// cancelled
+func (s *masterKeyRotationService) Run(ctx context.Context) {
+	target := s.provider.KeyID()
+	var rewrapped, failures int64
+
+	after := uuid.Nil
+	for {
+		keys, err := s.repo.ListWrappedWithOther(target, after, masterKeyRotationBatchSize)
+		if err != nil {
+			return
+		}
+		if len(keys) == 0 {
+			break
+		}
+
+		for i := range keys {
+			key := &keys[i]
+			ok, err := s.rewrap(ctx, key.ID, key.WrappedKey, key.MasterKeyID)
+			if err != nil {
+				s.log.Warn(
+					"Failed to re-wrap data key",
+					zap.Error(err),
+					zap.String("key_id", key.KeyID()),
+					zap.String("master_key_id", key.MasterKeyID),
+				)
+				failures++
+				continue
+			}
+			if ok {
+				rewrapped++
+			}
+		}
+		after = keys[len(keys)-1].ID
+
+		select {
+		case <-ctx.Done():
+			s.log.Warn("Master key rotation paused; it resumes on next start", zap.Error(ctx.Err()))
+			return
+		case <-time.After(masterKeyRotationPause):
+		}
+	}
+
+	if rewrapped == 0 && failures == 0 {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("master_key_id", target),
+		zap.Int64("rewrapped", rewrapped),
+		zap.Int64("failures", failures),
+	}
+	if failures > 0 {
+		s.log.Warn("Master key rotation finished with failures", fields...)
+		return
+	}
+	s.log.Info("Master key rotation finished", fields...)
+}
+
+// rewrap unwraps a data key with the master key it was wrapped with and
+// stores it wrapped with the current one. The data key itself is unchanged,
+// so nothing encrypted with it needs to be touched.
+func (s *masterKeyRotationService) rewrap(
+	ctx context.Context,
+	id uuid.UUID,
+	wrapped []byte,
+	masterKeyID string,
+) (bool, error) {
+	key, err := s.provider.Unwrap(ctx, wrapped, masterKeyID)
+	if err != nil {
+		return false, err
+	}
+
+	rewrapped, err := s.provider.Wrap(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return s.repo.Rewrap(id, masterKeyID, rewrapped, s.provider.KeyID())
+}
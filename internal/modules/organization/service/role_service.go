@@ -0,0 +1,397 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+// ErrMemberNotCovered is returned when an actor tries to change a member
+// whose role grants permissions the actor's own role does not
+var ErrMemberNotCovered = response.NewForbidden("You cannot manage a member with permissions you do not have")
+
+// RoleService maps organization roles to permissions. Built-in roles fall
+// back to entity.DefaultRolePermissions until an organization overrides them.
+type RoleService interface {
+	ListPermissions() []dto.PermissionResponse
+	ListRoles(orgID uuid.UUID) ([]dto.RoleResponse, error)
+	CreateRole(orgID uuid.UUID, actorRole string, req dto.CreateRoleRequest) (*dto.RoleResponse, error)
+	UpdateRole(orgID uuid.UUID, actorRole, name string, req dto.UpdateRoleRequest) (*dto.RoleResponse, error)
+	DeleteRole(orgID uuid.UUID, actorRole, name string) error
+	Permissions(orgID uuid.UUID, role string) ([]string, error)
+	HasPermission(orgID uuid.UUID, role, permission string) (bool, error)
+	Covers(orgID uuid.UUID, actorRole, role string) (bool, error)
+	IsAssignable(orgID uuid.UUID, role string) (bool, error)
+}
+
+type roleService struct {
+	repo repository.RoleRepository
+	log  logger.Logger
+}
+
+func NewRoleService(repo repository.RoleRepository, log logger.Logger) RoleService {
+	return &roleService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (s *roleService) ListPermissions() []dto.PermissionResponse {
+	resp := make([]dto.PermissionResponse, 0, len(entity.Permissions))
+	for _, p := range entity.Permissions {
+		resp = append(resp, dto.PermissionResponse{Name: p.Name, Description: p.Description})
+	}
+	return resp
+}
+
+func (s *roleService) ListRoles(orgID uuid.UUID) ([]dto.RoleResponse, error) {
+	custom, err := s.repo.ListByOrganization(orgID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	overrides := make(map[string]*entity.Role, len(custom))
+	for i := range custom {
+		overrides[custom[i].Name] = &custom[i]
+	}
+
+	resp := []dto.RoleResponse{{
+		Name:        entity.RoleOwner,
+		Description: "Organization owner",
+		Permissions: entity.AllPermissions(),
+		BuiltIn:     true,
+	}}
+
+	for _, name := range []string{entity.RoleAdmin, entity.RoleClinician, entity.RoleMember} {
+		if role, ok := overrides[name]; ok {
+			resp = append(resp, toRoleResponse(role, true))
+			delete(overrides, name)
+			continue
+		}
+		resp = append(resp, dto.RoleResponse{
+			Name:        name,
+			Permissions: entity.DefaultRolePermissions[name],
+			BuiltIn:     true,
+		})
+	}
+
+	for i := range custom {
+		if _, ok := overrides[custom[i].Name]; ok {
+			resp = append(resp, toRoleResponse(&custom[i], false))
+		}
+	}
+
+	return resp, nil
+}
+
+// CreateRole adds a custom role. The actor can only grant permissions their
+// own role holds.
+func (s *roleService) CreateRole(
+	orgID uuid.UUID,
+	actorRole string,
+	req dto.CreateRoleRequest,
+) (*dto.RoleResponse, error) {
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if !roleNamePattern.MatchString(name) {
+		return nil, response.NewBadRequest(
+			"Role name must start with a letter and contain only lowercase letters, digits and underscores",
+		)
+	}
+	if isBuiltInRole(name) {
+		return nil, response.NewConflict("A built-in role with this name already exists")
+	}
+
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireHeld(orgID, actorRole, permissions); err != nil {
+		return nil, err
+	}
+
+	if existing, _ := s.repo.FindByName(orgID, name); existing != nil {
+		return nil, response.NewConflict("A role with this name already exists")
+	}
+
+	role := &entity.Role{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		Name:           name,
+		Description:    strings.TrimSpace(req.Description),
+		Permissions:    datatypes.NewJSONSlice(permissions),
+	}
+	if err := s.repo.Create(role); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info("Role created", zap.String("org_id", orgID.String()), zap.String("role", name))
+	resp := toRoleResponse(role, false)
+	return &resp, nil
+}
+
+// UpdateRole replaces a role's permissions. Updating a built-in role stores
+// an organization-specific override of its defaults. Like member changes,
+// the actor can only change roles their own role covers, to permissions
+// they hold, and never their own role.
+func (s *roleService) UpdateRole(
+	orgID uuid.UUID,
+	actorRole, name string,
+	req dto.UpdateRoleRequest,
+) (*dto.RoleResponse, error) {
+	if err := s.requireChangeable(orgID, actorRole, name); err != nil {
+		return nil, err
+	}
+
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireHeld(orgID, actorRole, permissions); err != nil {
+		return nil, err
+	}
+
+	role, err := s.repo.FindByName(orgID, name)
+	isNew := false
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrInternalServerError
+		}
+		if !isBuiltInRole(name) {
+			return nil, response.NewNotFound("Role not found")
+		}
+		role = &entity.Role{
+			ID:             uuid.New(),
+			OrganizationID: orgID,
+			Name:           name,
+		}
+		isNew = true
+	}
+
+	role.Description = strings.TrimSpace(req.Description)
+	role.Permissions = datatypes.NewJSONSlice(permissions)
+
+	if isNew {
+		err = s.repo.Create(role)
+	} else {
+		err = s.repo.Update(role)
+	}
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"Role permissions updated",
+		zap.String("org_id", orgID.String()),
+		zap.String("role", name),
+		zap.Strings("permissions", permissions),
+	)
+	resp := toRoleResponse(role, isBuiltInRole(name))
+	return &resp, nil
+}
+
+// DeleteRole removes a custom role, or resets a built-in role to its
+// defaults. Custom roles still held by members cannot be deleted, and a
+// built-in role is only reset when the actor holds all of its defaults.
+func (s *roleService) DeleteRole(orgID uuid.UUID, actorRole, name string) error {
+	if err := s.requireChangeable(orgID, actorRole, name); err != nil {
+		return err
+	}
+	if isBuiltInRole(name) {
+		if err := s.requireHeld(orgID, actorRole, entity.DefaultRolePermissions[name]); err != nil {
+			return err
+		}
+	}
+
+	role, err := s.repo.FindByName(orgID, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if isBuiltInRole(name) {
+				return nil
+			}
+			return response.NewNotFound("Role not found")
+		}
+		return response.ErrInternalServerError
+	}
+
+	if !isBuiltInRole(name) {
+		count, err := s.repo.CountMembers(orgID, name)
+		if err != nil {
+			return response.ErrInternalServerError
+		}
+		if count > 0 {
+			return response.NewConflict("Reassign members holding this role before deleting it")
+		}
+	}
+
+	if err := s.repo.Delete(role.ID); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	s.log.Info("Role deleted", zap.String("org_id", orgID.String()), zap.String("role", name))
+	return nil
+}
+
+// Permissions resolves the permissions granted by a role in the organization
+func (s *roleService) Permissions(orgID uuid.UUID, role string) ([]string, error) {
+	if role == entity.RoleOwner {
+		return entity.AllPermissions(), nil
+	}
+
+	custom, err := s.repo.FindByName(orgID, role)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.DefaultRolePermissions[role], nil
+		}
+		return nil, response.ErrInternalServerError
+	}
+	return custom.Permissions, nil
+}
+
+func (s *roleService) HasPermission(orgID uuid.UUID, role, permission string) (bool, error) {
+	permissions, err := s.Permissions(orgID, role)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range permissions {
+		if p == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Covers reports whether the actor's role grants every permission of the
+// other role, so that the actor cannot give, or act on, more access than
+// they hold themselves
+func (s *roleService) Covers(orgID uuid.UUID, actorRole, role string) (bool, error) {
+	actorPerms, err := s.Permissions(orgID, actorRole)
+	if err != nil {
+		return false, err
+	}
+	rolePerms, err := s.Permissions(orgID, role)
+	if err != nil {
+		return false, err
+	}
+
+	granted := make(map[string]bool, len(actorPerms))
+	for _, p := range actorPerms {
+		granted[p] = true
+	}
+	for _, p := range rolePerms {
+		if !granted[p] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// requireCovers rejects giving a role that grants a permission the actor's
+// own role does not
+func requireCovers(roleSvc RoleService, orgID uuid.UUID, actorRole, role string) error {
+	covers, err := roleSvc.Covers(orgID, actorRole, role)
+	if err != nil {
+		return err
+	}
+	if !covers {
+		return response.NewForbidden("You cannot assign a role with permissions you do not have")
+	}
+	return nil
+}
+
+// requireChangeable rejects changes to the owner role, to the actor's own
+// role and to roles granting permissions the actor's role does not
+func (s *roleService) requireChangeable(orgID uuid.UUID, actorRole, name string) error {
+	if name == entity.RoleOwner {
+		return response.NewForbidden("The owner role cannot be changed")
+	}
+	if name == actorRole {
+		return response.NewForbidden("You cannot change your own role")
+	}
+
+	covers, err := s.Covers(orgID, actorRole, name)
+	if err != nil {
+		return err
+	}
+	if !covers {
+		return response.NewForbidden("You cannot change a role with permissions you do not have")
+	}
+	return nil
+}
+
+// requireHeld rejects granting permissions the actor's own role does not hold
+func (s *roleService) requireHeld(orgID uuid.UUID, actorRole string, permissions []string) error {
+	for _, p := range permissions {
+		held, err := s.HasPermission(orgID, actorRole, p)
+		if err != nil {
+			return err
+		}
+		if !held {
+			return response.NewForbidden("You cannot grant a permission you do not have: " + p)
+		}
+	}
+	return nil
+}
+
+// IsAssignable reports whether a role can be given through invitations or
+// role changes. Ownership is only set when an organization is created.
+func (s *roleService) IsAssignable(orgID uuid.UUID, role string) (bool, error) {
+	if role == entity.RoleOwner {
+		return false, nil
+	}
+	if isBuiltInRole(role) {
+		return true, nil
+	}
+
+	if _, err := s.repo.FindByName(orgID, role); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, response.ErrInternalServerError
+	}
+	return true, nil
+}
+
+func isBuiltInRole(name string) bool {
+	_, ok := entity.DefaultRolePermissions[name]
+	return ok || name == entity.RoleOwner
+}
+
+func normalizePermissions(permissions []string) ([]string, error) {
+	seen := make(map[string]bool, len(permissions))
+	result := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		p = strings.TrimSpace(p)
+		if !entity.IsPermission(p) {
+			return nil, response.NewBadRequest("Unknown permission: " + p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			result = append(result, p)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func toRoleResponse(role *entity.Role, builtIn bool) dto.RoleResponse {
+	return dto.RoleResponse{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+		BuiltIn:     builtIn,
+		Customized:  true,
+	}
+}
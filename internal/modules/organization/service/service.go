@@ -1,35 +1,61 @@
 package service
 
 import (
+	"errors"
+
 	"github.com/google/uuid"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	sessionService "github.com/sahabatharianmu/OpenMind/internal/modules/session/service"
 	"github.com/sahabatharianmu/OpenMind/pkg/logger"
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"github.com/sahabatharianmu/OpenMind/pkg/security"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// maxPasswordAgeDays caps the password expiry an organization can configure
+const maxPasswordAgeDays = 365
+
+// maxIPAllowlistEntries bounds the ranges checked on every request
+const maxIPAllowlistEntries = 100
+
 type OrganizationService interface {
-	GetMyOrganization(userID uuid.UUID) (*dto.OrganizationResponse, error)
-	UpdateOrganization(userID uuid.UUID, req dto.UpdateOrganizationRequest) (*dto.OrganizationResponse, error)
+	GetMyOrganization(orgID uuid.UUID) (*dto.OrganizationResponse, error)
+	UpdateOrganization(orgID uuid.UUID, req dto.UpdateOrganizationRequest) (*dto.OrganizationResponse, error)
+	ListMembers(orgID uuid.UUID) ([]dto.MemberResponse, error)
+	UpdateMemberRole(orgID, actorID, memberID uuid.UUID, role string) (*dto.MemberResponse, error)
+	RemoveMember(orgID, actorID, memberID uuid.UUID) error
+	ListOrganizations(userID, activeOrgID uuid.UUID) ([]dto.MembershipResponse, error)
+	GetMembership(userID, orgID uuid.UUID) (*entity.OrganizationMember, error)
 }
 
 type organizationService struct {
-	repo repository.OrganizationRepository
-	log  logger.Logger
+	repo       repository.OrganizationRepository
+	roleSvc    RoleService
+	sessionSvc sessionService.SessionService
+	log        logger.Logger
 }
 
-func NewOrganizationService(repo repository.OrganizationRepository, log logger.Logger) OrganizationService {
+func NewOrganizationService(
+	repo repository.OrganizationRepository,
+	roleSvc RoleService,
+	sessionSvc sessionService.SessionService,
+	log logger.Logger,
+) OrganizationService {
 	return &organizationService{
-		repo: repo,
-		log:  log,
+		repo:       repo,
+		roleSvc:    roleSvc,
+		sessionSvc: sessionSvc,
+		log:        log,
 	}
 }
 
-func (s *organizationService) GetMyOrganization(userID uuid.UUID) (*dto.OrganizationResponse, error) {
-	org, err := s.repo.GetByUserID(userID)
+func (s *organizationService) GetMyOrganization(orgID uuid.UUID) (*dto.OrganizationResponse, error) {
+	org, err := s.repo.GetByID(orgID)
 	if err != nil {
-		s.log.Error("GetMyOrganization failed", zap.Error(err), zap.String("user_id", userID.String()))
+		s.log.Error("GetMyOrganization failed", zap.Error(err), zap.String("org_id", orgID.String()))
 		return nil, response.ErrNotFound
 	}
 
@@ -40,24 +66,28 @@ func (s *organizationService) GetMyOrganization(userID uuid.UUID) (*dto.Organiza
 	}
 
 	return &dto.OrganizationResponse{
-		ID:          org.ID,
-		Name:        org.Name,
-		Type:        org.Type,
-		TaxID:       org.TaxID,
-		NPI:         org.NPI,
-		Address:     org.Address,
-		Currency:    org.Currency,
-		Locale:      org.Locale,
-		MemberCount: int(memberCount),
-		CreatedAt:   org.CreatedAt,
+		ID:                 org.ID,
+		Name:               org.Name,
+		Type:               org.Type,
+		TaxID:              org.TaxID,
+		NPI:                org.NPI,
+		Address:            org.Address,
+		Currency:           org.Currency,
+		Locale:             org.Locale,
+		RequireMFA:         org.RequireMFA,
+		PasswordMaxAgeDays: org.PasswordMaxAgeDays,
+		IPAllowlist:        org.IPAllowlist,
+		NoteSearchEnabled:  org.NoteSearchEnabled,
+		MemberCount:        int(memberCount),
+		CreatedAt:          org.CreatedAt,
 	}, nil
 }
 
 func (s *organizationService) UpdateOrganization(
-	userID uuid.UUID,
+	orgID uuid.UUID,
 	req dto.UpdateOrganizationRequest,
 ) (*dto.OrganizationResponse, error) {
-	org, err := s.repo.GetByUserID(userID)
+	org, err := s.repo.GetByID(orgID)
 	if err != nil {
 		s.log.Error("UpdateOrganization failed: org not found", zap.Error(err))
 		return nil, response.ErrNotFound
@@ -79,6 +109,25 @@ func (s *organizationService) UpdateOrganization(
 	if req.Locale != "" {
 		org.Locale = req.Locale
 	}
+	if req.RequireMFA != nil {
+		org.RequireMFA = *req.RequireMFA
+	}
+	if req.PasswordMaxAgeDays != nil {
+		if *req.PasswordMaxAgeDays < 0 || *req.PasswordMaxAgeDays > maxPasswordAgeDays {
+			return nil, response.NewBadRequest("Password max age must be between 0 and 365 days")
+		}
+		org.PasswordMaxAgeDays = *req.PasswordMaxAgeDays
+	}
+	if req.IPAllowlist != nil {
+		allowlist, err := normalizeIPAllowlist(*req.IPAllowlist)
+		if err != nil {
+			return nil, err
+		}
+		org.IPAllowlist = allowlist
+	}
+	if req.NoteSearchEnabled != nil {
+		org.NoteSearchEnabled = *req.NoteSearchEnabled
+	}
 
 	if err := s.repo.Update(org); err != nil {
 		s.log.Error("UpdateOrganization failed: update error", zap.Error(err))
@@ -90,15 +139,214 @@ func (s *organizationService) UpdateOrganization(
 	s.log.Info("Organization updated successfully", zap.String("org_id", org.ID.String()))
 
 	return &dto.OrganizationResponse{
-		ID:          org.ID,
-		Name:        org.Name,
-		Type:        org.Type,
-		TaxID:       org.TaxID,
-		NPI:         org.NPI,
-		Address:     org.Address,
-		Currency:    org.Currency,
-		Locale:      org.Locale,
-		MemberCount: int(memberCount),
-		CreatedAt:   org.CreatedAt,
+		ID:                 org.ID,
+		Name:               org.Name,
+		Type:               org.Type,
+		TaxID:              org.TaxID,
+		NPI:                org.NPI,
+		Address:            org.Address,
+		Currency:           org.Currency,
+		Locale:             org.Locale,
+		RequireMFA:         org.RequireMFA,
+		PasswordMaxAgeDays: org.PasswordMaxAgeDays,
+		IPAllowlist:        org.IPAllowlist,
+		NoteSearchEnabled:  org.NoteSearchEnabled,
+		MemberCount:        int(memberCount),
+		CreatedAt:          org.CreatedAt,
 	}, nil
 }
+
+func (s *organizationService) ListMembers(orgID uuid.UUID) ([]dto.MemberResponse, error) {
+	members, err := s.repo.ListMembers(orgID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	resp := make([]dto.MemberResponse, 0, len(members))
+	for _, m := range members {
+		resp = append(resp, toMemberResponse(m))
+	}
+	return resp, nil
+}
+
+func (s *organizationService) UpdateMemberRole(
+	orgID, actorID, memberID uuid.UUID,
+	role string,
+) (*dto.MemberResponse, error) {
+	assignable, err := s.roleSvc.IsAssignable(orgID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !assignable {
+		return nil, response.NewBadRequest("Invalid role")
+	}
+
+	actor, target, err := s.manageableMember(orgID, actorID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireCovers(s.roleSvc, orgID, actor.Role, role); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateMemberRole(orgID, memberID, role); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"Member role updated",
+		zap.String("org_id", orgID.String()),
+		zap.String("user_id", memberID.String()),
+		zap.String("from", target.Role),
+		zap.String("to", role),
+	)
+
+	member, err := s.repo.GetMemberDetail(orgID, memberID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	resp := toMemberResponse(*member)
+	return &resp, nil
+}
+
+func (s *organizationService) RemoveMember(orgID, actorID, memberID uuid.UUID) error {
+	if _, _, err := s.manageableMember(orgID, actorID, memberID); err != nil {
+		return err
+	}
+
+	if err := s.repo.RemoveMember(orgID, memberID); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	// Requests scoped to this organization are rejected from now on by the
+	// membership check; a user left without any organization is signed out
+	if _, err := s.repo.GetMembershipByUserID(memberID); errors.Is(err, gorm.ErrRecordNotFound) {
+		if _, err := s.sessionSvc.RevokeAll(memberID); err != nil {
+			s.log.Error("Failed to revoke sessions of removed member", zap.Error(err))
+		}
+	}
+
+	s.log.Info("Member removed", zap.String("org_id", orgID.String()), zap.String("user_id", memberID.String()))
+	return nil
+}
+
+// ListOrganizations returns every organization the user belongs to, marking
+// the one the current request is scoped to
+func (s *organizationService) ListOrganizations(userID, activeOrgID uuid.UUID) ([]dto.MembershipResponse, error) {
+	memberships, err := s.repo.ListMemberships(userID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	resp := make([]dto.MembershipResponse, 0, len(memberships))
+	for _, m := range memberships {
+		resp = append(resp, dto.MembershipResponse{
+			OrganizationID: m.OrganizationID,
+			Name:           m.Name,
+			Role:           m.Role,
+			Current:        m.OrganizationID == activeOrgID,
+			JoinedAt:       m.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+// GetMembership returns the membership that authorizes the user's requests
+// in the given organization. Without an organization the user's default
+// membership is used.
+func (s *organizationService) GetMembership(userID, orgID uuid.UUID) (*entity.OrganizationMember, error) {
+	if orgID == uuid.Nil {
+		member, err := s.repo.GetMembershipByUserID(userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, response.NewForbidden("You are not a member of any organization")
+			}
+			return nil, response.ErrInternalServerError
+		}
+		return member, nil
+	}
+
+	member, err := s.repo.GetMember(orgID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NewForbidden("You are not a member of this organization")
+		}
+		return nil, response.ErrInternalServerError
+	}
+	if member.DeactivatedAt != nil {
+		return nil, response.NewForbidden("Your account has been deactivated in this organization")
+	}
+	return member, nil
+}
+
+// manageableMember checks that the actor may change the target member of
+// the organization: only members allowed to manage members do, nobody
+// manages themselves, the owner cannot be changed, and the actor's role must
+// cover the target's permissions. It returns the actor and the target.
+func (s *organizationService) manageableMember(
+	orgID, actorID, memberID uuid.UUID,
+) (*entity.OrganizationMember, *entity.OrganizationMember, error) {
+	actor, err := s.repo.GetMember(orgID, actorID)
+	if err != nil {
+		return nil, nil, response.ErrNotFound
+	}
+	allowed, err := s.roleSvc.HasPermission(orgID, actor.Role, entity.PermMembersManage)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !allowed {
+		return nil, nil, response.NewForbidden("You do not have permission to manage members")
+	}
+
+	if actorID == memberID {
+		return nil, nil, response.NewForbidden("You cannot change your own membership")
+	}
+
+	target, err := s.repo.GetMember(orgID, memberID)
+	if err != nil {
+		return nil, nil, response.NewNotFound("Member not found")
+	}
+	if target.Role == entity.RoleOwner {
+		return nil, nil, response.NewForbidden("The organization owner cannot be changed")
+	}
+	covers, err := s.roleSvc.Covers(orgID, actor.Role, target.Role)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !covers {
+		return nil, nil, ErrMemberNotCovered
+	}
+
+	return actor, target, nil
+}
+
+func toMemberResponse(m entity.MemberDetail) dto.MemberResponse {
+	return dto.MemberResponse{
+		UserID:        m.UserID,
+		Email:         m.Email,
+		FullName:      m.FullName,
+		Role:          m.Role,
+		DeactivatedAt: m.DeactivatedAt,
+		JoinedAt:      m.CreatedAt,
+	}
+}
+
+// normalizeIPAllowlist validates allowlist entries and stores them in
+// canonical CIDR form
+func normalizeIPAllowlist(entries []string) ([]string, error) {
+	if len(entries) > maxIPAllowlistEntries {
+		return nil, response.NewBadRequest("IP allowlist cannot have more than 100 entries")
+	}
+
+	nets, err := security.ParseCIDRs(entries)
+	if err != nil {
+		return nil, response.NewBadRequest(err.Error())
+	}
+
+	allowlist := make([]string, 0, len(nets))
+	for _, ipNet := range nets {
+		allowlist = append(allowlist, ipNet.String())
+	}
+	return allowlist, nil
+}
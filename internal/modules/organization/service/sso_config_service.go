@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// SSOCallbackPath is the frontend route identity providers redirect back to
+const SSOCallbackPath = "/auth/sso/callback"
+
+const (
+	defaultGroupsClaim  = "groups"
+	domainLookupTimeout = 10 * time.Second
+)
+
+// SSOConfigService manages an organization's OpenID Connect settings
+type SSOConfigService interface {
+	Get(orgID uuid.UUID) (*dto.SSOConfigResponse, error)
+	Update(orgID uuid.UUID, actorRole string, req dto.UpdateSSOConfigRequest) (*dto.SSOConfigResponse, error)
+	Delete(orgID uuid.UUID) error
+	VerifyDomain(orgID uuid.UUID, domain string) (*dto.SSOConfigResponse, error)
+}
+
+type ssoConfigService struct {
+	repo        repository.SSOConfigRepository
+	roleSvc     RoleService
+	dataKeySvc  DataKeyService
+	redirectURI string
+	lookupTXT   func(ctx context.Context, name string) ([]string, error)
+	log         logger.Logger
+}
+
+func NewSSOConfigService(
+	repo repository.SSOConfigRepository,
+	roleSvc RoleService,
+	dataKeySvc DataKeyService,
+	baseURL string,
+	log logger.Logger,
+) SSOConfigService {
+	return &ssoConfigService{
+		repo:        repo,
+		roleSvc:     roleSvc,
+		dataKeySvc:  dataKeySvc,
+		redirectURI: strings.TrimRight(baseURL, "/") + SSOCallbackPath,
+		lookupTXT:   net.DefaultResolver.LookupTXT,
+		log:         log,
+	}
+}
+
+func (s *ssoConfigService) Get(orgID uuid.UUID) (*dto.SSOConfigResponse, error) {
+	config, err := s.repo.FindByOrganization(orgID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NewNotFound("Single sign-on is not configured")
+		}
+		return nil, response.ErrInternalServerError
+	}
+
+	resp := s.toResponse(config)
+	return &resp, nil
+}
+
+// Update saves the organization's identity provider settings. Roles given
+// through SSO must be covered by the actor's own role. Switching to another
+// issuer drops the domain verifications and linked accounts, which were only
+// trusted for the previous identity provider.
+func (s *ssoConfigService) Update(
+	orgID uuid.UUID,
+	actorRole string,
+	req dto.UpdateSSOConfigRequest,
+) (*dto.SSOConfigResponse, error) {
+	config, err := s.repo.FindByOrganization(orgID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrInternalServerError
+		}
+		config = &entity.SSOConfig{ID: uuid.New(), OrganizationID: orgID, Enabled: true}
+	}
+
+	issuer, err := parseIssuer(req.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	domains, err := s.normalizeDomains(orgID, req.AllowedDomains)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultRole := strings.TrimSpace(req.DefaultRole)
+	if defaultRole == "" {
+		defaultRole = entity.RoleMember
+	}
+	if err := s.requireAssignable(orgID, actorRole, defaultRole); err != nil {
+		return nil, err
+	}
+
+	groupRoles := make(map[string]string, len(req.GroupRoles))
+	for group, role := range req.GroupRoles {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			return nil, response.NewBadRequest("Group names cannot be empty")
+		}
+		if err := s.requireAssignable(orgID, actorRole, role); err != nil {
+			return nil, err
+		}
+		groupRoles[group] = role
+	}
+
+	if req.ClientSecret != "" {
+		encrypted, keyID, err := s.dataKeySvc.Encrypt(context.Background(), orgID, []byte(req.ClientSecret))
+		if err != nil {
+			s.log.Error("SSO config update failed: secret encryption error", zap.Error(err))
+			return nil, response.ErrInternalServerError
+		}
+		config.ClientSecret = entity.EncodeClientSecret(encrypted, keyID)
+	}
+	if config.ClientSecret == "" {
+		return nil, response.NewBadRequest("Client secret is required")
+	}
+
+	groupsClaim := strings.TrimSpace(req.GroupsClaim)
+	if groupsClaim == "" {
+		groupsClaim = defaultGroupsClaim
+	}
+
+	if req.Enabled != nil {
+		config.Enabled = *req.Enabled
+	}
+	issuerChanged := config.Issuer != "" && config.Issuer != issuer
+	if issuerChanged {
+		// Claims start again with new tokens; the repository unlinks the
+		// previous provider's accounts
+		config.Domains = nil
+	}
+	config.Issuer = issuer
+	config.ClientID = strings.TrimSpace(req.ClientID)
+	config.AllowedDomains = domains
+	if err := s.claimDomains(config); err != nil {
+		return nil, err
+	}
+	config.GroupsClaim = groupsClaim
+	config.GroupRoles = datatypes.NewJSONType(groupRoles)
+	config.DefaultRole = defaultRole
+
+	if err := s.repo.Save(config); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	if issuerChanged {
+		s.log.Warn(
+			"SSO issuer changed: domain verification and linked accounts reset",
+			zap.String("org_id", orgID.String()),
+			zap.String("issuer", issuer),
+		)
+	}
+	s.log.Info("SSO config updated", zap.String("org_id", orgID.String()), zap.String("issuer", issuer))
+
+	resp := s.toResponse(config)
+	return &resp, nil
+}
+
+func (s *ssoConfigService) Delete(orgID uuid.UUID) error {
+	if err := s.repo.Delete(orgID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.NewNotFound("Single sign-on is not configured")
+		}
+		return response.ErrInternalServerError
+	}
+
+	s.log.Info("SSO config removed", zap.String("org_id", orgID.String()))
+	return nil
+}
+
+// VerifyDomain checks the DNS TXT record proving the organization owns an
+// allowed domain. Sign-in from the domain is accepted once it is verified.
+func (s *ssoConfigService) VerifyDomain(orgID uuid.UUID, domain string) (*dto.SSOConfigResponse, error) {
+	config, err := s.repo.FindByOrganization(orgID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NewNotFound("Single sign-on is not configured")
+		}
+		return nil, response.ErrInternalServerError
+	}
+
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	var claim *entity.SSODomain
+	for i := range config.Domains {
+		if config.Domains[i].Domain == domain {
+			claim = &config.Domains[i]
+			break
+		}
+	}
+	if claim == nil {
+		return nil, response.NewNotFound("Domain is not allowed for single sign-on: " + domain)
+	}
+
+	if claim.VerifiedAt == nil {
+		if err := s.checkVerificationRecord(claim); err != nil {
+			return nil, err
+		}
+		if err := s.repo.VerifyDomain(claim); err != nil {
+			if errors.Is(err, repository.ErrDomainClaimed) {
+				return nil, response.NewConflict("Another organization already uses single sign-on for " + domain)
+			}
+			return nil, response.ErrInternalServerError
+		}
+		s.log.Info("SSO domain verified", zap.String("org_id", orgID.String()), zap.String("domain", domain))
+	}
+
+	resp := s.toResponse(config)
+	return &resp, nil
+}
+
+// checkVerificationRecord looks for the domain's verification token in its
+// DNS TXT record
+func (s *ssoConfigService) checkVerificationRecord(claim *entity.SSODomain) error {
+	ctx, cancel := context.WithTimeout(context.Background(), domainLookupTimeout)
+	defer cancel()
+
+	records, err := s.lookupTXT(ctx, claim.VerificationRecord())
+	if err != nil {
+		s.log.Warn("SSO domain verification failed: lookup error", zap.Error(err), zap.String("domain", claim.Domain))
+	}
+	for _, record := range records {
+		if strings.TrimSpace(record) == claim.VerificationValue() {
+			return nil
+		}
+	}
+	return response.NewBadRequest(
+		"Verification record not found. Publish a TXT record at " + claim.VerificationRecord() +
+			" containing " + claim.VerificationValue() + " and try again",
+	)
+}
+
+// claimDomains starts a claim, with a new verification token, for each
+// allowed domain the organization has not claimed yet
+func (s *ssoConfigService) claimDomains(config *entity.SSOConfig) error {
+	claimed := make(map[string]entity.SSODomain, len(config.Domains))
+	for _, d := range config.Domains {
+		claimed[d.Domain] = d
+	}
+
+	domains := make([]entity.SSODomain, 0, len(config.AllowedDomains))
+	for _, d := range config.AllowedDomains {
+		if claim, ok := claimed[d]; ok {
+			domains = append(domains, claim)
+			continue
+		}
+		token, err := crypto.GenerateToken()
+		if err != nil {
+			return response.ErrInternalServerError
+		}
+		domains = append(domains, entity.SSODomain{
+			ID:                uuid.New(),
+			OrganizationID:    config.OrganizationID,
+			Domain:            d,
+			VerificationToken: token,
+		})
+	}
+	config.Domains = domains
+	return nil
+}
+
+// normalizeDomains lowercases the domains and makes sure no other
+// organization has verified one, since sign-in is routed by email domain.
+// Unverified claims do not block others, as nobody has proven ownership.
+func (s *ssoConfigService) normalizeDomains(orgID uuid.UUID, domains []string) ([]string, error) {
+	seen := make(map[string]bool, len(domains))
+	result := make([]string, 0, len(domains))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@"))
+		if d == "" || !strings.Contains(d, ".") || strings.ContainsAny(d, "@/ ") {
+			return nil, response.NewBadRequest("Invalid email domain: " + d)
+		}
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+
+		existing, err := s.repo.FindByDomain(d)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrInternalServerError
+		}
+		if existing != nil && existing.OrganizationID != orgID {
+			return nil, response.NewConflict("Another organization already uses single sign-on for " + d)
+		}
+		result = append(result, d)
+	}
+
+	if len(result) == 0 {
+		return nil, response.NewBadRequest("At least one email domain is required")
+	}
+	return result, nil
+}
+
+func (s *ssoConfigService) requireAssignable(orgID uuid.UUID, actorRole, role string) error {
+	assignable, err := s.roleSvc.IsAssignable(orgID, role)
+	if err != nil {
+		return err
+	}
+	if !assignable {
+		return response.NewBadRequest("Invalid role: " + role)
+	}
+	return requireCovers(s.roleSvc, orgID, actorRole, role)
+}
+
+// parseIssuer requires HTTPS, except for local identity providers used in
+// development
+func parseIssuer(raw string) (string, error) {
+	issuer := strings.TrimRight(strings.TrimSpace(raw), "/")
+	u, err := url.Parse(issuer)
+	if err != nil || u.Host == "" {
+		return "", response.NewBadRequest("Invalid issuer URL")
+	}
+	local := u.Hostname() == "localhost" || u.Hostname() == "127.0.0.1"
+	if u.Scheme != "https" && !(u.Scheme == "http" && local) {
+		return "", response.NewBadRequest("Issuer must use HTTPS")
+	}
+	return issuer, nil
+}
+
+func (s *ssoConfigService) toResponse(config *entity.SSOConfig) dto.SSOConfigResponse {
+	return dto.SSOConfigResponse{
+		Enabled:         config.Enabled,
+		Issuer:          config.Issuer,
+		ClientID:        config.ClientID,
+		ClientSecretSet: config.ClientSecret != "",
+		AllowedDomains:  config.AllowedDomains,
+		Domains:         toSSODomains(config.Domains),
+		GroupsClaim:     config.GroupsClaim,
+		GroupRoles:      config.GroupRoles.Data(),
+		DefaultRole:     config.DefaultRole,
+		RedirectURI:     s.redirectURI,
+		UpdatedAt:       config.UpdatedAt,
+	}
+}
+
+func toSSODomains(domains []entity.SSODomain) []dto.SSODomain {
+	resp := make([]dto.SSODomain, 0, len(domains))
+	for i := range domains {
+		resp = append(resp, dto.SSODomain{
+			Domain:             domains[i].Domain,
+			Verified:           domains[i].VerifiedAt != nil,
+			VerifiedAt:         domains[i].VerifiedAt,
+			VerificationRecord: domains[i].VerificationRecord(),
+			VerificationValue:  domains[i].VerificationValue(),
+		})
+	}
+	return resp
+}
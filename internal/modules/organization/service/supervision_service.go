@@ -0,0 +1,163 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SupervisionService manages which licensed clinicians supervise which
+// trainees. While a member has a supervisor, their notes are submitted for
+// review and only signed once a supervisor co-signs them.
+type SupervisionService interface {
+	Create(orgID, actorID uuid.UUID, req dto.CreateSupervisionRequest) (*dto.SupervisionResponse, error)
+	List(orgID uuid.UUID) ([]dto.SupervisionResponse, error)
+	End(orgID, actorID, supervisionID uuid.UUID) error
+}
+
+type supervisionService struct {
+	repo    repository.SupervisionRepository
+	orgRepo repository.OrganizationRepository
+	log     logger.Logger
+}
+
+func NewSupervisionService(
+	repo repository.SupervisionRepository,
+	orgRepo repository.OrganizationRepository,
+	log logger.Logger,
+) SupervisionService {
+	return &supervisionService{
+		repo:    repo,
+		orgRepo: orgRepo,
+		log:     log,
+	}
+}
+
+func (s *supervisionService) Create(
+	orgID, actorID uuid.UUID,
+	req dto.CreateSupervisionRequest,
+) (*dto.SupervisionResponse, error) {
+	if req.SupervisorID == req.SuperviseeID {
+		return nil, response.NewBadRequest("A member cannot supervise themselves")
+	}
+
+	supervisor, err := s.member(orgID, req.SupervisorID, "Supervisor")
+	if err != nil {
+		return nil, err
+	}
+	supervisee, err := s.member(orgID, req.SuperviseeID, "Supervisee")
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.IsSupervisor(orgID, req.SupervisorID, req.SuperviseeID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if exists {
+		return nil, response.NewConflict("The supervisee already has this supervisor")
+	}
+
+	// Supervisors would otherwise co-sign each other's notes
+	reverse, err := s.repo.IsSupervisor(orgID, req.SuperviseeID, req.SupervisorID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if reverse {
+		return nil, response.NewConflict("The supervisee already supervises this supervisor")
+	}
+
+	supervision := &entity.Supervision{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		SupervisorID:   req.SupervisorID,
+		SuperviseeID:   req.SuperviseeID,
+		CreatedBy:      &actorID,
+	}
+	if err := s.repo.Create(supervision); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"Supervision created",
+		zap.String("org_id", orgID.String()),
+		zap.String("supervisor_id", supervision.SupervisorID.String()),
+		zap.String("supervisee_id", supervision.SuperviseeID.String()),
+		zap.String("created_by", actorID.String()),
+	)
+
+	return &dto.SupervisionResponse{
+		ID:             supervision.ID,
+		SupervisorID:   supervision.SupervisorID,
+		SupervisorName: supervisor.FullName,
+		SuperviseeID:   supervision.SuperviseeID,
+		SuperviseeName: supervisee.FullName,
+		CreatedBy:      supervision.CreatedBy,
+		CreatedAt:      supervision.CreatedAt,
+	}, nil
+}
+
+func (s *supervisionService) List(orgID uuid.UUID) ([]dto.SupervisionResponse, error) {
+	supervisions, err := s.repo.ListActive(orgID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	resp := make([]dto.SupervisionResponse, 0, len(supervisions))
+	for _, sv := range supervisions {
+		resp = append(resp, dto.SupervisionResponse{
+			ID:             sv.ID,
+			SupervisorID:   sv.SupervisorID,
+			SupervisorName: sv.SupervisorName,
+			SuperviseeID:   sv.SuperviseeID,
+			SuperviseeName: sv.SuperviseeName,
+			CreatedBy:      sv.CreatedBy,
+			CreatedAt:      sv.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+// End stops a supervision. Notes the supervisee already submitted stay
+// pending until any current supervisor of theirs co-signs or returns them.
+func (s *supervisionService) End(orgID, actorID, supervisionID uuid.UUID) error {
+	supervision, err := s.repo.FindByID(supervisionID)
+	if err != nil || supervision.OrganizationID != orgID {
+		return response.NewNotFound("Supervision not found")
+	}
+	if supervision.EndedAt != nil {
+		return response.NewConflict("Supervision has already ended")
+	}
+
+	if err := s.repo.End(supervision.ID, actorID); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"Supervision ended",
+		zap.String("org_id", orgID.String()),
+		zap.String("supervision_id", supervision.ID.String()),
+		zap.String("ended_by", actorID.String()),
+	)
+	return nil
+}
+
+// member returns a member of the organization, or a not found error naming
+// their part in the supervision
+func (s *supervisionService) member(orgID, userID uuid.UUID, part string) (*entity.MemberDetail, error) {
+	member, err := s.orgRepo.GetMemberDetail(orgID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NewNotFound(part + " is not a member of this organization")
+		}
+		return nil, response.ErrInternalServerError
+	}
+	return member, nil
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BreakGlassRequest struct {
+	Reason string `json:"reason" validate:"required,min=10,max=2000"`
+}
+
+// ReviewBreakGlassRequest closes a grant in the review queue. RevokeAccess
+// ends the emergency access immediately if it has not expired yet.
+type ReviewBreakGlassRequest struct {
+	Note         *string `json:"note"          validate:"omitempty,max=2000"`
+	RevokeAccess bool    `json:"revoke_access"`
+}
+
+type BreakGlassGrantResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	PatientID    uuid.UUID  `json:"patient_id"`
+	PatientName  string     `json:"patient_name"`
+	UserID       uuid.UUID  `json:"user_id"`
+	UserEmail    string     `json:"user_email"`
+	UserFullName string     `json:"user_full_name"`
+	Reason       string     `json:"reason"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	Active       bool       `json:"active"`
+	ReviewedBy   *uuid.UUID `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"`
+	ReviewNote   *string    `json:"review_note"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GrantCareTeamAccessRequest struct {
+	UserID uuid.UUID `json:"user_id" validate:"required"`
+}
+
+// SetPrimaryClinicianRequest assigns the primary clinician. A null user_id
+// leaves the patient without one.
+type SetPrimaryClinicianRequest struct {
+	UserID *uuid.UUID `json:"user_id"`
+}
+
+type CareTeamMemberResponse struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	Email     string     `json:"email"`
+	FullName  string     `json:"full_name"`
+	GrantedBy *uuid.UUID `json:"granted_by"`
+	GrantedAt time.Time  `json:"granted_at"`
+}
+
+type CareTeamResponse struct {
+	PatientID          uuid.UUID                `json:"patient_id"`
+	PrimaryClinicianID *uuid.UUID               `json:"primary_clinician_id"`
+	Members            []CareTeamMemberResponse `json:"members"`
+}
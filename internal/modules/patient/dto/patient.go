@@ -26,17 +26,28 @@ type UpdatePatientRequest struct {
 	Status      string  `json:"status"        validate:"omitempty,oneof=active inactive archived"`
 }
 
+// PatientLookupRequest finds patients by an exact email, phone number, or
+// names with date of birth. Exactly one of these must be given.
+type PatientLookupRequest struct {
+	Email       string
+	Phone       string
+	FirstName   string
+	LastName    string
+	DateOfBirth string
+}
+
 type PatientResponse struct {
-	ID             uuid.UUID `json:"id"`
-	OrganizationID uuid.UUID `json:"organization_id"`
-	FirstName      string    `json:"first_name"`
-	LastName       string    `json:"last_name"`
-	DateOfBirth    string    `json:"date_of_birth"`
-	Email          *string   `json:"email"`
-	Phone          *string   `json:"phone"`
-	Address        *string   `json:"address"`
-	Status         string    `json:"status"`
-	CreatedBy      uuid.UUID `json:"created_by"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID                 uuid.UUID  `json:"id"`
+	OrganizationID     uuid.UUID  `json:"organization_id"`
+	FirstName          string     `json:"first_name"`
+	LastName           string     `json:"last_name"`
+	DateOfBirth        string     `json:"date_of_birth"`
+	Email              *string    `json:"email"`
+	Phone              *string    `json:"phone"`
+	Address            *string    `json:"address"`
+	Status             string     `json:"status"`
+	PrimaryClinicianID *uuid.UUID `json:"primary_clinician_id"`
+	CreatedBy          uuid.UUID  `json:"created_by"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
 }
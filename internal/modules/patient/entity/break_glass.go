@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BreakGlassGrant is time-limited emergency access to a patient outside the
+// member's care teams. It is granted on a written reason and stays in the
+// admin review queue until reviewed.
+type BreakGlassGrant struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null"                               json:"organization_id"`
+	PatientID      uuid.UUID  `gorm:"type:uuid;not null"                               json:"patient_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null"                               json:"user_id"`
+	Reason         string     `gorm:"type:text;not null"                               json:"reason"`
+	ExpiresAt      time.Time  `gorm:"not null"                                         json:"expires_at"`
+	ReviewedBy     *uuid.UUID `gorm:"type:uuid"                                        json:"reviewed_by"`
+	ReviewedAt     *time.Time `                                                        json:"reviewed_at"`
+	ReviewNote     *string    `gorm:"type:text"                                        json:"review_note"`
+	CreatedAt      time.Time  `                                                        json:"created_at"`
+}
+
+func (BreakGlassGrant) TableName() string {
+	return "patient_break_glass_grants"
+}
+
+// IsActive reports whether the grant still gives access
+func (g *BreakGlassGrant) IsActive() bool {
+	return time.Now().Before(g.ExpiresAt)
+}
+
+// BreakGlassGrantDetail is a grant joined with the names of the member and
+// the patient, as shown in the review queue. The patient's names are
+// decrypted from PatientDemographicsEncrypted.
+type BreakGlassGrantDetail struct {
+	BreakGlassGrant              `gorm:"embedded"`
+	UserEmail                    string `json:"user_email"`
+	UserFullName                 string `json:"user_full_name"`
+	PatientFirstName             string `gorm:"-" json:"patient_first_name"`
+	PatientLastName              string `gorm:"-" json:"patient_last_name"`
+	PatientDemographicsEncrypted []byte `json:"-"`
+	PatientKeyID                 string `json:"-"`
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CareTeamMember is a member of staff explicitly granted access to a patient
+// in addition to the patient's primary clinician
+type CareTeamMember struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null"                               json:"organization_id"`
+	PatientID      uuid.UUID  `gorm:"type:uuid;not null"                               json:"patient_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null"                               json:"user_id"`
+	GrantedBy      *uuid.UUID `gorm:"type:uuid"                                        json:"granted_by"`
+	CreatedAt      time.Time  `                                                        json:"created_at"`
+}
+
+func (CareTeamMember) TableName() string {
+	return "patient_care_team_members"
+}
+
+// CareTeamMemberDetail is a care team member joined with their user account
+type CareTeamMemberDetail struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	Email     string     `json:"email"`
+	FullName  string     `json:"full_name"`
+	GrantedBy *uuid.UUID `json:"granted_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Access describes which patients of an organization a member may see.
+// Members without the patients:all permission are limited to the patients
+// whose care team they belong to.
+type Access struct {
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+	AllPatients    bool
+}
@@ -6,21 +6,44 @@ import (
 	"github.com/google/uuid"
 )
 
+// Patient is a person under the organization's care. The demographic fields
+// are stored encrypted in DemographicsEncrypted; the blind indexes are keyed
+// hashes that allow exact-match lookup by email, phone and name with date of
+// birth without decrypting.
 type Patient struct {
-	ID             uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
-	OrganizationID uuid.UUID `gorm:"type:uuid;not null"                              json:"organization_id"`
-	FirstName      string    `gorm:"not null"                                        json:"first_name"`
-	LastName       string    `gorm:"not null"                                        json:"last_name"`
-	DateOfBirth    time.Time `gorm:"type:date;not null"                              json:"date_of_birth"`
-	Email          *string   `gorm:""                                                json:"email"`
-	Phone          *string   `gorm:""                                                json:"phone"`
-	Address        *string   `gorm:""                                                json:"address"`
-	Status         string    `gorm:"not null;default:'active'"                       json:"status"`
-	CreatedBy      uuid.UUID `gorm:"type:uuid;not null"                              json:"created_by"`
-	CreatedAt      time.Time `gorm:"autoCreateTime"                                  json:"created_at"`
-	UpdatedAt      time.Time `gorm:"autoUpdateTime"                                  json:"updated_at"`
+	ID                    uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	OrganizationID        uuid.UUID  `gorm:"type:uuid;not null"                              json:"organization_id"`
+	FirstName             string     `gorm:"-"                                               json:"first_name"`
+	LastName              string     `gorm:"-"                                               json:"last_name"`
+	DateOfBirth           time.Time  `gorm:"-"                                               json:"date_of_birth"`
+	Email                 *string    `gorm:"-"                                               json:"email"`
+	Phone                 *string    `gorm:"-"                                               json:"phone"`
+	Address               *string    `gorm:"-"                                               json:"address"`
+	DemographicsEncrypted []byte     `gorm:"type:bytea"                                      json:"-"`
+	KeyID                 string     `gorm:"type:varchar(255)"                               json:"-"`
+	EmailIndex            *string    `gorm:"type:varchar(64)"                                json:"-"`
+	PhoneIndex            *string    `gorm:"type:varchar(64)"                                json:"-"`
+	NameDOBIndex          *string    `gorm:"column:name_dob_index;type:varchar(64)"          json:"-"`
+	Status                string     `gorm:"not null;default:'active'"                       json:"status"`
+	PrimaryClinicianID    *uuid.UUID `gorm:"type:uuid"                                       json:"primary_clinician_id"`
+	CreatedBy             uuid.UUID  `gorm:"type:uuid;not null"                              json:"created_by"`
+	CreatedAt             time.Time  `gorm:"autoCreateTime"                                  json:"created_at"`
+	UpdatedAt             time.Time  `gorm:"autoUpdateTime"                                  json:"updated_at"`
 }
 
 func (Patient) TableName() string {
 	return "patients"
 }
+
+// PlaintextPatient is a patient row written before demographics were
+// encrypted, read only to encrypt it
+type PlaintextPatient struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	FirstName      string
+	LastName       string
+	DateOfBirth    time.Time
+	Email          *string
+	Phone          *string
+	Address        *string
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type BreakGlassHandler struct {
+	svc service.BreakGlassService
+}
+
+func NewBreakGlassHandler(svc service.BreakGlassService) *BreakGlassHandler {
+	return &BreakGlassHandler{svc: svc}
+}
+
+func (h *BreakGlassHandler) Request(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(entity.Access)
+
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	var req dto.BreakGlassRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.Request(context.Background(), patientID, access, req, c.ClientIP(), string(c.UserAgent()))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	response.Created(c, resp, "Emergency access granted")
+}
+
+func (h *BreakGlassHandler) List(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	resp, total, err := h.svc.List(context.Background(), orgID, c.Query("status"), page, pageSize)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Emergency access grants retrieved successfully", map[string]interface{}{
+		"items":     resp,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
+
+func (h *BreakGlassHandler) Review(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid grant ID", nil)
+		return
+	}
+
+	var req dto.ReviewBreakGlassRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.Review(context.Background(), id, orgID, userID, req, c.ClientIP(), string(c.UserAgent()))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Emergency access reviewed successfully", resp))
+}
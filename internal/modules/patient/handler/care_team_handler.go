@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type CareTeamHandler struct {
+	svc service.CareTeamService
+}
+
+func NewCareTeamHandler(svc service.CareTeamService) *CareTeamHandler {
+	return &CareTeamHandler{svc: svc}
+}
+
+func (h *CareTeamHandler) Get(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(entity.Access)
+
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	resp, err := h.svc.Get(context.Background(), patientID, access)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Care team retrieved successfully", resp))
+}
+
+func (h *CareTeamHandler) Grant(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(entity.Access)
+
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	var req dto.GrantCareTeamAccessRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.Grant(context.Background(), patientID, req.UserID, access, c.ClientIP(), string(c.UserAgent()))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	response.Created(c, resp, "Care team access granted successfully")
+}
+
+func (h *CareTeamHandler) Revoke(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(entity.Access)
+
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", nil)
+		return
+	}
+
+	err = h.svc.Revoke(context.Background(), patientID, userID, access, c.ClientIP(), string(c.UserAgent()))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Care team access revoked successfully", nil))
+}
+
+func (h *CareTeamHandler) SetPrimaryClinician(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	access := accessVal.(entity.Access)
+
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid patient ID", nil)
+		return
+	}
+
+	var req dto.SetPrimaryClinicianRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.SetPrimaryClinician(
+		context.Background(),
+		patientID,
+		req.UserID,
+		access,
+		c.ClientIP(),
+		string(c.UserAgent()),
+	)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Primary clinician updated successfully", resp))
+}
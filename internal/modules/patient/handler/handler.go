@@ -8,6 +8,7 @@ import (
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
 	"github.com/google/uuid"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/service"
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
 )
@@ -21,18 +22,12 @@ func NewPatientHandler(svc service.PatientService) *PatientHandler {
 }
 
 func (h *PatientHandler) Create(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(entity.Access)
 
 	var req dto.CreatePatientRequest
 	if err := c.BindAndValidate(&req); err != nil {
@@ -40,7 +35,7 @@ func (h *PatientHandler) Create(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
-	resp, err := h.svc.Create(context.Background(), req, orgID, userID)
+	resp, err := h.svc.Create(context.Background(), req, access)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -50,18 +45,12 @@ func (h *PatientHandler) Create(_ context.Context, c *app.RequestContext) {
 }
 
 func (h *PatientHandler) List(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(entity.Access)
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
@@ -72,7 +61,7 @@ func (h *PatientHandler) List(_ context.Context, c *app.RequestContext) {
 		pageSize = 10
 	}
 
-	resp, total, err := h.svc.List(context.Background(), orgID, page, pageSize)
+	resp, total, err := h.svc.List(context.Background(), access, page, pageSize)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -86,19 +75,39 @@ func (h *PatientHandler) List(_ context.Context, c *app.RequestContext) {
 	}))
 }
 
-func (h *PatientHandler) Get(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+// Lookup finds patients by exact email, phone, or names with date of birth
+func (h *PatientHandler) Lookup(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
+	access := accessVal.(entity.Access)
+
+	req := dto.PatientLookupRequest{
+		Email:       c.Query("email"),
+		Phone:       c.Query("phone"),
+		FirstName:   c.Query("first_name"),
+		LastName:    c.Query("last_name"),
+		DateOfBirth: c.Query("date_of_birth"),
+	}
 
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
+	resp, err := h.svc.Lookup(context.Background(), access, req)
 	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Patients retrieved successfully", resp))
+}
+
+func (h *PatientHandler) Get(_ context.Context, c *app.RequestContext) {
+	accessVal, exists := c.Get("patientAccess")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
 		return
 	}
+	access := accessVal.(entity.Access)
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -107,7 +116,7 @@ func (h *PatientHandler) Get(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
-	resp, err := h.svc.Get(context.Background(), id, orgID)
+	resp, err := h.svc.Get(context.Background(), id, access)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -117,18 +126,12 @@ func (h *PatientHandler) Get(_ context.Context, c *app.RequestContext) {
 }
 
 func (h *PatientHandler) Update(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(entity.Access)
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -143,7 +146,7 @@ func (h *PatientHandler) Update(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
-	resp, err := h.svc.Update(context.Background(), id, orgID, req)
+	resp, err := h.svc.Update(context.Background(), id, access, req)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -153,18 +156,12 @@ func (h *PatientHandler) Update(_ context.Context, c *app.RequestContext) {
 }
 
 func (h *PatientHandler) Delete(_ context.Context, c *app.RequestContext) {
-	userIDVal, exists := c.Get("userID")
+	accessVal, exists := c.Get("patientAccess")
 	if !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
-
-	orgID, err := h.svc.GetOrganizationID(context.Background(), userID)
-	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve organization")
-		return
-	}
+	access := accessVal.(entity.Access)
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -173,7 +170,7 @@ func (h *PatientHandler) Delete(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
-	if err := h.svc.Delete(context.Background(), id, orgID); err != nil {
+	if err := h.svc.Delete(context.Background(), id, access); err != nil {
 		response.HandleError(c, err)
 		return
 	}
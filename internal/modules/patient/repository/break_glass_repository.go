@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type BreakGlassRepository interface {
+	Create(grant *entity.BreakGlassGrant) error
+	Update(grant *entity.BreakGlassGrant) error
+	FindByID(id uuid.UUID) (*entity.BreakGlassGrant, error)
+	FindDetail(id uuid.UUID) (*entity.BreakGlassGrantDetail, error)
+	List(organizationID uuid.UUID, reviewed *bool, limit, offset int) ([]entity.BreakGlassGrantDetail, int64, error)
+}
+
+type breakGlassRepository struct {
+	db     *gorm.DB
+	cipher DemographicsCipher
+	log    logger.Logger
+}
+
+func NewBreakGlassRepository(db *gorm.DB, cipher DemographicsCipher, log logger.Logger) BreakGlassRepository {
+	return &breakGlassRepository{
+		db:     db,
+		cipher: cipher,
+		log:    log,
+	}
+}
+
+func (r *breakGlassRepository) Create(grant *entity.BreakGlassGrant) error {
+	if err := r.db.Create(grant).Error; err != nil {
+		r.log.Error(
+			"Failed to create break-the-glass grant",
+			zap.Error(err),
+			zap.String("patient_id", grant.PatientID.String()),
+		)
+		return err
+	}
+	return nil
+}
+
+func (r *breakGlassRepository) Update(grant *entity.BreakGlassGrant) error {
+	if err := r.db.Save(grant).Error; err != nil {
+		r.log.Error("Failed to update break-the-glass grant", zap.Error(err), zap.String("id", grant.ID.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *breakGlassRepository) FindByID(id uuid.UUID) (*entity.BreakGlassGrant, error) {
+	var grant entity.BreakGlassGrant
+	if err := r.db.First(&grant, "id = ?", id).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find break-the-glass grant", zap.Error(err), zap.String("id", id.String()))
+		}
+		return nil, err
+	}
+	return &grant, nil
+}
+
+func (r *breakGlassRepository) FindDetail(id uuid.UUID) (*entity.BreakGlassGrantDetail, error) {
+	var grant entity.BreakGlassGrantDetail
+	err := r.details().Where("patient_break_glass_grants.id = ?", id).Take(&grant).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find break-the-glass grant", zap.Error(err), zap.String("id", id.String()))
+		}
+		return nil, err
+	}
+	r.openPatientName(&grant)
+	return &grant, nil
+}
+
+// List returns the organization's grants, newest first. A nil reviewed lists
+// every grant; otherwise only reviewed or pending ones.
+func (r *breakGlassRepository) List(
+	organizationID uuid.UUID,
+	reviewed *bool,
+	limit, offset int,
+) ([]entity.BreakGlassGrantDetail, int64, error) {
+	var grants []entity.BreakGlassGrantDetail
+	var total int64
+
+	filter := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("patient_break_glass_grants.organization_id = ?", organizationID)
+		if reviewed == nil {
+			return db
+		}
+		if *reviewed {
+			return db.Where("patient_break_glass_grants.reviewed_at IS NOT NULL")
+		}
+		return db.Where("patient_break_glass_grants.reviewed_at IS NULL")
+	}
+
+	if err := r.db.Model(&entity.BreakGlassGrant{}).Scopes(filter).Count(&total).Error; err != nil {
+		r.log.Error("Failed to count break-the-glass grants", zap.Error(err))
+		return nil, 0, err
+	}
+
+	err := r.details().
+		Scopes(filter).
+		Order("patient_break_glass_grants.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&grants).Error
+	if err != nil {
+		r.log.Error("Failed to list break-the-glass grants", zap.Error(err))
+		return nil, 0, err
+	}
+
+	for i := range grants {
+		r.openPatientName(&grants[i])
+	}
+	return grants, total, nil
+}
+
+func (r *breakGlassRepository) details() *gorm.DB {
+	return r.db.Table("patient_break_glass_grants").
+		Select("patient_break_glass_grants.*, users.email AS user_email, users.full_name AS user_full_name, " +
+			"patients.demographics_encrypted AS patient_demographics_encrypted, patients.key_id AS patient_key_id").
+		Joins("JOIN users ON users.id = patient_break_glass_grants.user_id").
+		Joins("JOIN patients ON patients.id = patient_break_glass_grants.patient_id")
+}
+
+// openPatientName decrypts the patient's names of a grant. A grant whose
+// patient cannot be decrypted is logged and shown without names.
+func (r *breakGlassRepository) openPatientName(grant *entity.BreakGlassGrantDetail) {
+	if len(grant.PatientDemographicsEncrypted) == 0 {
+		return
+	}
+
+	d, err := openDemographics(
+		context.Background(),
+		r.cipher,
+		grant.OrganizationID,
+		grant.PatientDemographicsEncrypted,
+		grant.PatientKeyID,
+	)
+	if err != nil {
+		r.log.Error("Failed to decrypt patient of break-the-glass grant", zap.Error(err), zap.String("id", grant.ID.String()))
+		return
+	}
+	grant.PatientFirstName = d.FirstName
+	grant.PatientLastName = d.LastName
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type CareTeamRepository interface {
+	AddMember(member *entity.CareTeamMember) error
+	RemoveMember(patientID, userID uuid.UUID) error
+	IsMember(patientID, userID uuid.UUID) (bool, error)
+	ListMembers(patientID uuid.UUID) ([]entity.CareTeamMemberDetail, error)
+	SetPrimaryClinician(patientID uuid.UUID, clinicianID *uuid.UUID) error
+	CanAccess(access entity.Access, patientID uuid.UUID) (bool, error)
+}
+
+type careTeamRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewCareTeamRepository(db *gorm.DB, log logger.Logger) CareTeamRepository {
+	return &careTeamRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// CareTeamScope limits a query to rows whose patient, identified by column,
+// is accessible to the member. It is a no-op for members with access to all
+// patients.
+func CareTeamScope(access entity.Access, column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if access.AllPatients {
+			return db
+		}
+		return db.Where(column+" IN (?)", careTeamPatients(db, access.UserID))
+	}
+}
+
+// careTeamPatients selects the IDs of every patient the user is primary
+// clinician for, has been granted access to, or holds unexpired
+// break-the-glass access to
+func careTeamPatients(db *gorm.DB, userID uuid.UUID) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Raw("SELECT id FROM patients WHERE primary_clinician_id = ? "+
+			"UNION SELECT patient_id FROM patient_care_team_members WHERE user_id = ? "+
+			"UNION SELECT patient_id FROM patient_break_glass_grants WHERE user_id = ? AND expires_at > ?",
+			userID, userID, userID, time.Now())
+}
+
+func (r *careTeamRepository) AddMember(member *entity.CareTeamMember) error {
+	if err := r.db.Create(member).Error; err != nil {
+		r.log.Error(
+			"Failed to add care team member",
+			zap.Error(err),
+			zap.String("patient_id", member.PatientID.String()),
+		)
+		return err
+	}
+	return nil
+}
+
+// RemoveMember returns gorm.ErrRecordNotFound when the user was not on the
+// patient's care team
+func (r *careTeamRepository) RemoveMember(patientID, userID uuid.UUID) error {
+	result := r.db.Where("patient_id = ? AND user_id = ?", patientID, userID).Delete(&entity.CareTeamMember{})
+	if result.Error != nil {
+		r.log.Error(
+			"Failed to remove care team member",
+			zap.Error(result.Error),
+			zap.String("patient_id", patientID.String()),
+		)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *careTeamRepository) IsMember(patientID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.CareTeamMember{}).
+		Where("patient_id = ? AND user_id = ?", patientID, userID).
+		Count(&count).Error
+	if err != nil {
+		r.log.Error(
+			"Failed to check care team membership",
+			zap.Error(err),
+			zap.String("patient_id", patientID.String()),
+		)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *careTeamRepository) ListMembers(patientID uuid.UUID) ([]entity.CareTeamMemberDetail, error) {
+	var members []entity.CareTeamMemberDetail
+	err := r.db.Table("patient_care_team_members").
+		Select("patient_care_team_members.user_id, users.email, users.full_name, "+
+			"patient_care_team_members.granted_by, patient_care_team_members.created_at").
+		Joins("JOIN users ON users.id = patient_care_team_members.user_id AND users.deleted_at IS NULL").
+		Where("patient_care_team_members.patient_id = ?", patientID).
+		Order("patient_care_team_members.created_at ASC").
+		Scan(&members).Error
+	if err != nil {
+		r.log.Error(
+			"Failed to list care team members",
+			zap.Error(err),
+			zap.String("patient_id", patientID.String()),
+		)
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *careTeamRepository) SetPrimaryClinician(patientID uuid.UUID, clinicianID *uuid.UUID) error {
+	err := r.db.Model(&entity.Patient{}).
+		Where("id = ?", patientID).
+		Update("primary_clinician_id", clinicianID).Error
+	if err != nil {
+		r.log.Error(
+			"Failed to set primary clinician",
+			zap.Error(err),
+			zap.String("patient_id", patientID.String()),
+		)
+		return err
+	}
+	return nil
+}
+
+// CanAccess reports whether the member may access the patient
+func (r *careTeamRepository) CanAccess(access entity.Access, patientID uuid.UUID) (bool, error) {
+	if access.AllPatients {
+		return true, nil
+	}
+
+	var count int64
+	err := r.db.Model(&entity.Patient{}).
+		Where("id = ?", patientID).
+		Scopes(CareTeamScope(access, "id")).
+		Count(&count).Error
+	if err != nil {
+		r.log.Error(
+			"Failed to check patient access",
+			zap.Error(err),
+			zap.String("patient_id", patientID.String()),
+		)
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+)
+
+// Blind index purposes, one per kind of lookup
+const (
+	blindIndexEmail   = "patient_email"
+	blindIndexPhone   = "patient_phone"
+	blindIndexNameDOB = "patient_name_dob"
+)
+
+const dateOfBirthLayout = "2006-01-02"
+
+// DemographicsCipher encrypts patient demographics and computes their blind
+// indexes with the organization's data key
+type DemographicsCipher interface {
+	Encrypt(ctx context.Context, orgID uuid.UUID, plaintext []byte) ([]byte, string, error)
+	Decrypt(ctx context.Context, orgID uuid.UUID, ciphertext []byte, keyID string) ([]byte, error)
+	BlindIndex(ctx context.Context, orgID uuid.UUID, purpose, value string) (string, error)
+}
+
+// demographics is the encrypted payload of a patient
+type demographics struct {
+	FirstName   string  `json:"first_name"`
+	LastName    string  `json:"last_name"`
+	DateOfBirth string  `json:"date_of_birth"`
+	Email       *string `json:"email,omitempty"`
+	Phone       *string `json:"phone,omitempty"`
+	Address     *string `json:"address,omitempty"`
+}
+
+// normalizeEmail makes emails that differ only in case or surrounding spaces
+// match
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// normalizePhone keeps only the digits, so that formatting does not matter
+func normalizePhone(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+// normalizeNameDOB joins the names, lowercased and with spaces collapsed, to
+// the date of birth
+func normalizeNameDOB(firstName, lastName string, dateOfBirth time.Time) string {
+	normalize := func(name string) string {
+		return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	}
+	return normalize(firstName) + "|" + normalize(lastName) + "|" + dateOfBirth.Format(dateOfBirthLayout)
+}
+
+// sealDemographics encrypts the patient's demographics and sets its blind
+// indexes
+func sealDemographics(ctx context.Context, cipher DemographicsCipher, p *entity.Patient) error {
+	payload, err := json.Marshal(demographics{
+		FirstName:   p.FirstName,
+		LastName:    p.LastName,
+		DateOfBirth: p.DateOfBirth.Format(dateOfBirthLayout),
+		Email:       p.Email,
+		Phone:       p.Phone,
+		Address:     p.Address,
+	})
+	if err != nil {
+		return err
+	}
+
+	encrypted, keyID, err := cipher.Encrypt(ctx, p.OrganizationID, payload)
+	if err != nil {
+		return err
+	}
+
+	nameDOB := normalizeNameDOB(p.FirstName, p.LastName, p.DateOfBirth)
+	nameDOBIndex, err := cipher.BlindIndex(ctx, p.OrganizationID, blindIndexNameDOB, nameDOB)
+	if err != nil {
+		return err
+	}
+	emailIndex, err := optionalBlindIndex(ctx, cipher, p.OrganizationID, blindIndexEmail, p.Email, normalizeEmail)
+	if err != nil {
+		return err
+	}
+	phoneIndex, err := optionalBlindIndex(ctx, cipher, p.OrganizationID, blindIndexPhone, p.Phone, normalizePhone)
+	if err != nil {
+		return err
+	}
+
+	p.DemographicsEncrypted = encrypted
+	p.KeyID = keyID
+	p.NameDOBIndex = &nameDOBIndex
+	p.EmailIndex = emailIndex
+	p.PhoneIndex = phoneIndex
+	return nil
+}
+
+// optionalBlindIndex returns nil for a missing or blank value, so that
+// patients without an email or phone never match each other
+func optionalBlindIndex(
+	ctx context.Context,
+	cipher DemographicsCipher,
+	orgID uuid.UUID,
+	purpose string,
+	value *string,
+	normalize func(string) string,
+) (*string, error) {
+	if value == nil || normalize(*value) == "" {
+		return nil, nil
+	}
+	index, err := cipher.BlindIndex(ctx, orgID, purpose, normalize(*value))
+	if err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+// openDemographics decrypts demographics encrypted by sealDemographics
+func openDemographics(
+	ctx context.Context,
+	cipher DemographicsCipher,
+	orgID uuid.UUID,
+	encrypted []byte,
+	keyID string,
+) (*demographics, error) {
+	payload, err := cipher.Decrypt(ctx, orgID, encrypted, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var d demographics
+	if err := json.Unmarshal(payload, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// openPatient fills the patient's demographic fields from their ciphertext
+func openPatient(ctx context.Context, cipher DemographicsCipher, p *entity.Patient) error {
+	if len(p.DemographicsEncrypted) == 0 {
+		return nil
+	}
+
+	d, err := openDemographics(ctx, cipher, p.OrganizationID, p.DemographicsEncrypted, p.KeyID)
+	if err != nil {
+		return err
+	}
+
+	dateOfBirth, err := time.Parse(dateOfBirthLayout, d.DateOfBirth)
+	if err != nil {
+		return err
+	}
+
+	p.FirstName = d.FirstName
+	p.LastName = d.LastName
+	p.DateOfBirth = dateOfBirth
+	p.Email = d.Email
+	p.Phone = d.Phone
+	p.Address = d.Address
+	return nil
+}
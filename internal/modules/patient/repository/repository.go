@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
@@ -10,28 +12,50 @@ import (
 	"gorm.io/gorm"
 )
 
+// PatientRepository stores patients with their demographics encrypted. The
+// demographic fields of the patients it returns are decrypted, and those of
+// the patients it is given are encrypted before being written.
 type PatientRepository interface {
 	Create(patient *entity.Patient) error
 	Update(patient *entity.Patient) error
 	Delete(id uuid.UUID) error
 	FindByID(id uuid.UUID) (*entity.Patient, error)
-	List(organizationID uuid.UUID, limit, offset int) ([]entity.Patient, int64, error)
-	GetOrganizationID(userID uuid.UUID) (uuid.UUID, error)
+	List(
+		organizationID uuid.UUID,
+		limit, offset int,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) ([]entity.Patient, int64, error)
+	FindByEmail(organizationID uuid.UUID, email string, scopes ...func(*gorm.DB) *gorm.DB) ([]entity.Patient, error)
+	FindByPhone(organizationID uuid.UUID, phone string, scopes ...func(*gorm.DB) *gorm.DB) ([]entity.Patient, error)
+	FindByNameAndDOB(
+		organizationID uuid.UUID,
+		firstName, lastName string,
+		dateOfBirth time.Time,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) ([]entity.Patient, error)
+	EncryptDemographics(patient *entity.Patient) error
+	ListPlaintext(after uuid.UUID, limit int) ([]entity.PlaintextPatient, error)
+	StoreEncrypted(patient *entity.Patient) (bool, error)
 }
 
 type patientRepository struct {
-	db  *gorm.DB
-	log logger.Logger
+	db     *gorm.DB
+	cipher DemographicsCipher
+	log    logger.Logger
 }
 
-func NewPatientRepository(db *gorm.DB, log logger.Logger) PatientRepository {
+func NewPatientRepository(db *gorm.DB, cipher DemographicsCipher, log logger.Logger) PatientRepository {
 	return &patientRepository{
-		db:  db,
-		log: log,
+		db:     db,
+		cipher: cipher,
+		log:    log,
 	}
 }
 
 func (r *patientRepository) Create(patient *entity.Patient) error {
+	if err := r.EncryptDemographics(patient); err != nil {
+		return err
+	}
 	if err := r.db.Create(patient).Error; err != nil {
 		r.log.Error("Failed to create patient", zap.Error(err))
 		return err
@@ -40,6 +64,9 @@ func (r *patientRepository) Create(patient *entity.Patient) error {
 }
 
 func (r *patientRepository) Update(patient *entity.Patient) error {
+	if err := r.EncryptDemographics(patient); err != nil {
+		return err
+	}
 	if err := r.db.Save(patient).Error; err != nil {
 		r.log.Error("Failed to update patient", zap.Error(err), zap.String("id", patient.ID.String()))
 		return err
@@ -63,14 +90,22 @@ func (r *patientRepository) FindByID(id uuid.UUID) (*entity.Patient, error) {
 		}
 		return nil, err
 	}
+	if err := openPatient(context.Background(), r.cipher, &patient); err != nil {
+		r.log.Error("Failed to decrypt patient", zap.Error(err), zap.String("id", id.String()))
+		return nil, err
+	}
 	return &patient, nil
 }
 
-func (r *patientRepository) List(organizationID uuid.UUID, limit, offset int) ([]entity.Patient, int64, error) {
+func (r *patientRepository) List(
+	organizationID uuid.UUID,
+	limit, offset int,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]entity.Patient, int64, error) {
 	var patients []entity.Patient
 	var total int64
 
-	query := r.db.Model(&entity.Patient{}).Where("organization_id = ?", organizationID)
+	query := r.db.Model(&entity.Patient{}).Where("organization_id = ?", organizationID).Scopes(scopes...)
 
 	if err := query.Count(&total).Error; err != nil {
 		r.log.Error("Failed to count patients", zap.Error(err))
@@ -82,17 +117,131 @@ func (r *patientRepository) List(organizationID uuid.UUID, limit, offset int) ([
 		return nil, 0, err
 	}
 
+	r.openAll(patients)
 	return patients, total, nil
 }
 
-func (r *patientRepository) GetOrganizationID(userID uuid.UUID) (uuid.UUID, error) {
-	var orgIDStr string
-	if err := r.db.Table("organization_members").Select("organization_id").Where("user_id = ?", userID).Limit(1).Scan(&orgIDStr).Error; err != nil {
-		r.log.Error("Failed to get organization ID", zap.Error(err), zap.String("user_id", userID.String()))
-		return uuid.Nil, err
+// FindByEmail returns the organization's patients with the given email,
+// ignoring case and surrounding spaces
+func (r *patientRepository) FindByEmail(
+	organizationID uuid.UUID,
+	email string,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]entity.Patient, error) {
+	return r.findByBlindIndex(organizationID, "email_index", blindIndexEmail, normalizeEmail(email), scopes...)
+}
+
+// FindByPhone returns the organization's patients with the given phone
+// number, ignoring everything but its digits
+func (r *patientRepository) FindByPhone(
+	organizationID uuid.UUID,
+	phone string,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]entity.Patient, error) {
+	return r.findByBlindIndex(organizationID, "phone_index", blindIndexPhone, normalizePhone(phone), scopes...)
+}
+
+// FindByNameAndDOB returns the organization's patients with the given names
+// and date of birth, ignoring case and extra spaces in the names
+func (r *patientRepository) FindByNameAndDOB(
+	organizationID uuid.UUID,
+	firstName, lastName string,
+	dateOfBirth time.Time,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]entity.Patient, error) {
+	value := normalizeNameDOB(firstName, lastName, dateOfBirth)
+	return r.findByBlindIndex(organizationID, "name_dob_index", blindIndexNameDOB, value, scopes...)
+}
+
+// EncryptDemographics encrypts the patient's demographics and sets its blind
+// indexes. Create and Update do so themselves; it is for callers that write
+// patients in their own transaction.
+func (r *patientRepository) EncryptDemographics(patient *entity.Patient) error {
+	if err := sealDemographics(context.Background(), r.cipher, patient); err != nil {
+		r.log.Error(
+			"Failed to encrypt patient demographics",
+			zap.Error(err),
+			zap.String("org_id", patient.OrganizationID.String()),
+		)
+		return err
 	}
-	if orgIDStr == "" {
-		return uuid.Nil, errors.New("organization not found for user")
+	return nil
+}
+
+// ListPlaintext returns patients whose demographics are not encrypted yet,
+// ordered by ID and starting after the given one
+func (r *patientRepository) ListPlaintext(after uuid.UUID, limit int) ([]entity.PlaintextPatient, error) {
+	var patients []entity.PlaintextPatient
+	err := r.db.Table("patients").
+		Select("id, organization_id, first_name, last_name, date_of_birth, email, phone, address").
+		Where("demographics_encrypted IS NULL AND id > ?", after).
+		Order("id").
+		Limit(limit).
+		Scan(&patients).Error
+	if err != nil {
+		r.log.Error("Failed to list patients with plaintext demographics", zap.Error(err))
+		return nil, err
+	}
+	return patients, nil
+}
+
+// StoreEncrypted writes the encrypted demographics and blind indexes of a
+// patient listed by ListPlaintext and empties its plaintext columns, provided
+// it has not been encrypted since. It reports whether the patient was updated.
+func (r *patientRepository) StoreEncrypted(patient *entity.Patient) (bool, error) {
+	result := r.db.Table("patients").
+		Where("id = ? AND demographics_encrypted IS NULL", patient.ID).
+		UpdateColumns(map[string]interface{}{
+			"demographics_encrypted": patient.DemographicsEncrypted,
+			"key_id":                 patient.KeyID,
+			"email_index":            patient.EmailIndex,
+			"phone_index":            patient.PhoneIndex,
+			"name_dob_index":         patient.NameDOBIndex,
+			"first_name":             nil,
+			"last_name":              nil,
+			"date_of_birth":          nil,
+			"email":                  nil,
+			"phone":                  nil,
+			"address":                nil,
+		})
+	if result.Error != nil {
+		r.log.Error("Failed to store encrypted patient", zap.Error(result.Error), zap.String("id", patient.ID.String()))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *patientRepository) findByBlindIndex(
+	organizationID uuid.UUID,
+	column, purpose, value string,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]entity.Patient, error) {
+	index, err := r.cipher.BlindIndex(context.Background(), organizationID, purpose, value)
+	if err != nil {
+		r.log.Error("Failed to compute patient blind index", zap.Error(err), zap.String("org_id", organizationID.String()))
+		return nil, err
+	}
+
+	var patients []entity.Patient
+	err = r.db.Where("organization_id = ? AND "+column+" = ?", organizationID, index).
+		Scopes(scopes...).
+		Order("created_at").
+		Find(&patients).Error
+	if err != nil {
+		r.log.Error("Failed to look up patients", zap.Error(err), zap.String("column", column))
+		return nil, err
+	}
+
+	r.openAll(patients)
+	return patients, nil
+}
+
+// openAll decrypts the demographics of each patient. A patient that cannot be
+// decrypted is logged and left with empty demographics.
+func (r *patientRepository) openAll(patients []entity.Patient) {
+	for i := range patients {
+		if err := openPatient(context.Background(), r.cipher, &patients[i]); err != nil {
+			r.log.Error("Failed to decrypt patient", zap.Error(err), zap.String("id", patients[i].ID.String()))
+		}
 	}
-	return uuid.Parse(orgIDStr)
 }
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	auditLogService "github.com/sahabatharianmu/OpenMind/internal/modules/audit_log/service"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const auditResourcePatient = "patient"
+
+// recordAudit writes a patient access event to the audit log. The change has
+// already been applied, so a failure to write the entry is logged rather
+// than returned.
+func recordAudit(
+	ctx context.Context,
+	auditLogSvc auditLogService.AuditLogService,
+	log logger.Logger,
+	action string,
+	patientID uuid.UUID,
+	access entity.Access,
+	details map[string]interface{},
+	ipAddress, userAgent string,
+) {
+	details["patient_id"] = patientID.String()
+	err := auditLogSvc.Log(
+		ctx,
+		action,
+		auditResourcePatient,
+		&patientID,
+		access.UserID,
+		access.OrganizationID,
+		details,
+		&ipAddress,
+		&userAgent,
+	)
+	if err != nil {
+		log.Error("Failed to write patient audit entry", zap.Error(err), zap.String("action", action))
+	}
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	auditLogService "github.com/sahabatharianmu/OpenMind/internal/modules/audit_log/service"
+	organizationRepo "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/mail"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Audit actions recorded for break-the-glass access
+const (
+	AuditActionBreakGlassAccess = "break_glass_access"
+	AuditActionBreakGlassReview = "break_glass_review"
+)
+
+// BreakGlassService grants members time-limited emergency access to a patient
+// outside their care teams. Every grant is audited and queued for admin review.
+type BreakGlassService interface {
+	Request(
+		ctx context.Context,
+		patientID uuid.UUID,
+		access entity.Access,
+		req dto.BreakGlassRequest,
+		ipAddress, userAgent string,
+	) (*dto.BreakGlassGrantResponse, error)
+	List(
+		ctx context.Context,
+		organizationID uuid.UUID,
+		status string,
+		page, pageSize int,
+	) ([]dto.BreakGlassGrantResponse, int64, error)
+	Review(
+		ctx context.Context,
+		id uuid.UUID,
+		organizationID, reviewerID uuid.UUID,
+		req dto.ReviewBreakGlassRequest,
+		ipAddress, userAgent string,
+	) (*dto.BreakGlassGrantResponse, error)
+}
+
+type breakGlassService struct {
+	patientRepo     repository.PatientRepository
+	careTeamRepo    repository.CareTeamRepository
+	breakGlassRepo  repository.BreakGlassRepository
+	orgRepo         organizationRepo.OrganizationRepository
+	auditLogSvc     auditLogService.AuditLogService
+	mailer          mail.Sender
+	duration        time.Duration
+	notifyClinician bool
+	baseURL         string
+	log             logger.Logger
+}
+
+func NewBreakGlassService(
+	patientRepo repository.PatientRepository,
+	careTeamRepo repository.CareTeamRepository,
+	breakGlassRepo repository.BreakGlassRepository,
+	orgRepo organizationRepo.OrganizationRepository,
+	auditLogSvc auditLogService.AuditLogService,
+	mailer mail.Sender,
+	duration time.Duration,
+	notifyClinician bool,
+	baseURL string,
+	log logger.Logger,
+) BreakGlassService {
+	return &breakGlassService{
+		patientRepo:     patientRepo,
+		careTeamRepo:    careTeamRepo,
+		breakGlassRepo:  breakGlassRepo,
+		orgRepo:         orgRepo,
+		auditLogSvc:     auditLogSvc,
+		mailer:          mailer,
+		duration:        duration,
+		notifyClinician: notifyClinician,
+		baseURL:         strings.TrimRight(baseURL, "/"),
+		log:             log,
+	}
+}
+
+func (s *breakGlassService) Request(
+	ctx context.Context,
+	patientID uuid.UUID,
+	access entity.Access,
+	req dto.BreakGlassRequest,
+	ipAddress, userAgent string,
+) (*dto.BreakGlassGrantResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, response.NewBadRequest("A reason is required for emergency access")
+	}
+
+	patient, err := s.patientRepo.FindByID(patientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NewNotFound("Patient not found")
+		}
+		return nil, response.ErrInternalServerError
+	}
+	if patient.OrganizationID != access.OrganizationID {
+		return nil, response.NewNotFound("Patient not found")
+	}
+
+	allowed, err := s.careTeamRepo.CanAccess(access, patient.ID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if allowed {
+		return nil, response.NewConflict("You already have access to this patient")
+	}
+
+	grant := &entity.BreakGlassGrant{
+		ID:             uuid.New(),
+		OrganizationID: access.OrganizationID,
+		PatientID:      patient.ID,
+		UserID:         access.UserID,
+		Reason:         reason,
+		ExpiresAt:      time.Now().Add(s.duration),
+	}
+	if err := s.breakGlassRepo.Create(grant); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	recordAudit(ctx, s.auditLogSvc, s.log, AuditActionBreakGlassAccess, patient.ID, access, map[string]interface{}{
+		"grant_id":   grant.ID.String(),
+		"reason":     reason,
+		"expires_at": grant.ExpiresAt,
+	}, ipAddress, userAgent)
+
+	s.log.Warn(
+		"Break-the-glass access granted",
+		zap.String("grant_id", grant.ID.String()),
+		zap.String("user_id", access.UserID.String()),
+		zap.String("patient_id", patient.ID.String()),
+	)
+
+	if s.notifyClinician {
+		s.notifyPrimaryClinician(patient, grant)
+	}
+
+	return s.detail(grant.ID)
+}
+
+// List returns the review queue. Status is "pending" (default), "reviewed"
+// or "all".
+func (s *breakGlassService) List(
+	ctx context.Context,
+	organizationID uuid.UUID,
+	status string,
+	page, pageSize int,
+) ([]dto.BreakGlassGrantResponse, int64, error) {
+	var reviewed *bool
+	switch status {
+	case "", "pending":
+		reviewed = new(bool)
+	case "reviewed":
+		reviewed = new(bool)
+		*reviewed = true
+	case "all":
+	default:
+		return nil, 0, response.NewBadRequest("Status must be one of pending, reviewed or all")
+	}
+
+	offset := (page - 1) * pageSize
+	grants, total, err := s.breakGlassRepo.List(organizationID, reviewed, pageSize, offset)
+	if err != nil {
+		return nil, 0, response.ErrInternalServerError
+	}
+
+	responses := make([]dto.BreakGlassGrantResponse, 0, len(grants))
+	for i := range grants {
+		responses = append(responses, toBreakGlassGrantResponse(&grants[i]))
+	}
+
+	return responses, total, nil
+}
+
+func (s *breakGlassService) Review(
+	ctx context.Context,
+	id uuid.UUID,
+	organizationID, reviewerID uuid.UUID,
+	req dto.ReviewBreakGlassRequest,
+	ipAddress, userAgent string,
+) (*dto.BreakGlassGrantResponse, error) {
+	grant, err := s.breakGlassRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NewNotFound("Emergency access grant not found")
+		}
+		return nil, response.ErrInternalServerError
+	}
+	if grant.OrganizationID != organizationID {
+		return nil, response.NewNotFound("Emergency access grant not found")
+	}
+	if grant.UserID == reviewerID {
+		return nil, response.NewForbidden("You cannot review your own emergency access")
+	}
+	if grant.ReviewedAt != nil {
+		return nil, response.NewConflict("Emergency access grant has already been reviewed")
+	}
+
+	now := time.Now()
+	revoked := req.RevokeAccess && grant.IsActive()
+	if revoked {
+		grant.ExpiresAt = now
+	}
+	grant.ReviewedBy = &reviewerID
+	grant.ReviewedAt = &now
+	grant.ReviewNote = req.Note
+
+	if err := s.breakGlassRepo.Update(grant); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	details := map[string]interface{}{
+		"grant_id":        grant.ID.String(),
+		"granted_user_id": grant.UserID.String(),
+		"access_revoked":  revoked,
+	}
+	if req.Note != nil {
+		details["note"] = *req.Note
+	}
+	reviewer := entity.Access{OrganizationID: organizationID, UserID: reviewerID}
+	recordAudit(
+		ctx,
+		s.auditLogSvc,
+		s.log,
+		AuditActionBreakGlassReview,
+		grant.PatientID,
+		reviewer,
+		details,
+		ipAddress,
+		userAgent,
+	)
+
+	return s.detail(grant.ID)
+}
+
+func (s *breakGlassService) detail(id uuid.UUID) (*dto.BreakGlassGrantResponse, error) {
+	grant, err := s.breakGlassRepo.FindDetail(id)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	resp := toBreakGlassGrantResponse(grant)
+	return &resp, nil
+}
+
+// notifyPrimaryClinician emails the patient's primary clinician about the
+// emergency access. The email only links to the patient and names the grant,
+// keeping patient details and the reason out of the mailbox. It is sent in
+// the background and delivery failures are logged; the grant stands.
+func (s *breakGlassService) notifyPrimaryClinician(patient *entity.Patient, grant *entity.BreakGlassGrant) {
+	if patient.PrimaryClinicianID == nil || *patient.PrimaryClinicianID == grant.UserID {
+		return
+	}
+
+	clinician, err := s.orgRepo.GetMemberDetail(patient.OrganizationID, *patient.PrimaryClinicianID)
+	if err != nil {
+		s.log.Warn("Primary clinician not found for break-the-glass notice", zap.String("grant_id", grant.ID.String()))
+		return
+	}
+
+	requester := "A colleague"
+	if member, err := s.orgRepo.GetMemberDetail(patient.OrganizationID, grant.UserID); err == nil {
+		requester = member.FullName
+	}
+
+	body := fmt.Sprintf(
+		"%s used emergency (break-the-glass) access to the records of one of your patients on OpenMind.\r\n\r\n"+
+			"Patient: %s/dashboard/patients/%s\r\n"+
+			"Emergency access reference: %s\r\n\r\n"+
+			"The access expires on %s. It has been logged with the reason given and will be reviewed "+
+			"by an administrator.\r\n",
+		requester,
+		s.baseURL,
+		patient.ID,
+		grant.ID,
+		grant.ExpiresAt.Format("January 2, 2006 at 15:04 MST"),
+	)
+
+	grantID, email := grant.ID, clinician.Email
+	go func() {
+		if err := s.mailer.Send(mail.Message{
+			To:      email,
+			Subject: "Emergency access to your patient's records",
+			Body:    body,
+		}); err != nil {
+			s.log.Error("Failed to send break-the-glass notice", zap.Error(err), zap.String("grant_id", grantID.String()))
+		}
+	}()
+}
+
+func toBreakGlassGrantResponse(g *entity.BreakGlassGrantDetail) dto.BreakGlassGrantResponse {
+	return dto.BreakGlassGrantResponse{
+		ID:           g.ID,
+		PatientID:    g.PatientID,
+		PatientName:  strings.TrimSpace(g.PatientFirstName + " " + g.PatientLastName),
+		UserID:       g.UserID,
+		UserEmail:    g.UserEmail,
+		UserFullName: g.UserFullName,
+		Reason:       g.Reason,
+		ExpiresAt:    g.ExpiresAt,
+		Active:       g.IsActive(),
+		ReviewedBy:   g.ReviewedBy,
+		ReviewedAt:   g.ReviewedAt,
+		ReviewNote:   g.ReviewNote,
+		CreatedAt:    g.CreatedAt,
+	}
+}
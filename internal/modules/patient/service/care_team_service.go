@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	auditLogService "github.com/sahabatharianmu/OpenMind/internal/modules/audit_log/service"
+	organizationRepo "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"gorm.io/gorm"
+)
+
+// Audit actions recorded for care team changes
+const (
+	AuditActionCareTeamGrant          = "care_team_grant"
+	AuditActionCareTeamRevoke         = "care_team_revoke"
+	AuditActionPrimaryClinicianChange = "primary_clinician_change"
+)
+
+// CareTeamService manages who may access a patient: the primary clinician
+// and the staff explicitly granted access. Every change is audited.
+type CareTeamService interface {
+	Get(ctx context.Context, patientID uuid.UUID, access entity.Access) (*dto.CareTeamResponse, error)
+	Grant(
+		ctx context.Context,
+		patientID, userID uuid.UUID,
+		access entity.Access,
+		ipAddress, userAgent string,
+	) (*dto.CareTeamResponse, error)
+	Revoke(ctx context.Context, patientID, userID uuid.UUID, access entity.Access, ipAddress, userAgent string) error
+	SetPrimaryClinician(
+		ctx context.Context,
+		patientID uuid.UUID,
+		clinicianID *uuid.UUID,
+		access entity.Access,
+		ipAddress, userAgent string,
+	) (*dto.CareTeamResponse, error)
+}
+
+type careTeamService struct {
+	patientRepo  repository.PatientRepository
+	careTeamRepo repository.CareTeamRepository
+	orgRepo      organizationRepo.OrganizationRepository
+	auditLogSvc  auditLogService.AuditLogService
+	log          logger.Logger
+}
+
+func NewCareTeamService(
+	patientRepo repository.PatientRepository,
+	careTeamRepo repository.CareTeamRepository,
+	orgRepo organizationRepo.OrganizationRepository,
+	auditLogSvc auditLogService.AuditLogService,
+	log logger.Logger,
+) CareTeamService {
+	return &careTeamService{
+		patientRepo:  patientRepo,
+		careTeamRepo: careTeamRepo,
+		orgRepo:      orgRepo,
+		auditLogSvc:  auditLogSvc,
+		log:          log,
+	}
+}
+
+func (s *careTeamService) Get(
+	ctx context.Context,
+	patientID uuid.UUID,
+	access entity.Access,
+) (*dto.CareTeamResponse, error) {
+	patient, err := s.patient(patientID, access)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.careTeam(patient)
+}
+
+func (s *careTeamService) Grant(
+	ctx context.Context,
+	patientID, userID uuid.UUID,
+	access entity.Access,
+	ipAddress, userAgent string,
+) (*dto.CareTeamResponse, error) {
+	patient, err := s.patient(patientID, access)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireMember(access.OrganizationID, userID); err != nil {
+		return nil, err
+	}
+
+	if patient.PrimaryClinicianID != nil && *patient.PrimaryClinicianID == userID {
+		return nil, response.NewConflict("User is already the patient's primary clinician")
+	}
+
+	onTeam, err := s.careTeamRepo.IsMember(patient.ID, userID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if onTeam {
+		return nil, response.NewConflict("User is already on the patient's care team")
+	}
+
+	member := &entity.CareTeamMember{
+		ID:             uuid.New(),
+		OrganizationID: access.OrganizationID,
+		PatientID:      patient.ID,
+		UserID:         userID,
+		GrantedBy:      &access.UserID,
+	}
+	if err := s.careTeamRepo.AddMember(member); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	recordAudit(ctx, s.auditLogSvc, s.log, AuditActionCareTeamGrant, patient.ID, access, map[string]interface{}{
+		"user_id": userID.String(),
+	}, ipAddress, userAgent)
+
+	return s.careTeam(patient)
+}
+
+func (s *careTeamService) Revoke(
+	ctx context.Context,
+	patientID, userID uuid.UUID,
+	access entity.Access,
+	ipAddress, userAgent string,
+) error {
+	patient, err := s.patient(patientID, access)
+	if err != nil {
+		return err
+	}
+
+	if err := s.careTeamRepo.RemoveMember(patient.ID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.NewNotFound("User is not on the patient's care team")
+		}
+		return response.ErrInternalServerError
+	}
+
+	recordAudit(ctx, s.auditLogSvc, s.log, AuditActionCareTeamRevoke, patient.ID, access, map[string]interface{}{
+		"user_id": userID.String(),
+	}, ipAddress, userAgent)
+
+	return nil
+}
+
+func (s *careTeamService) SetPrimaryClinician(
+	ctx context.Context,
+	patientID uuid.UUID,
+	clinicianID *uuid.UUID,
+	access entity.Access,
+	ipAddress, userAgent string,
+) (*dto.CareTeamResponse, error) {
+	patient, err := s.patient(patientID, access)
+	if err != nil {
+		return nil, err
+	}
+
+	if clinicianID != nil {
+		if err := s.requireMember(access.OrganizationID, *clinicianID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.careTeamRepo.SetPrimaryClinician(patient.ID, clinicianID); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	details := map[string]interface{}{
+		"previous_primary_clinician_id": uuidString(patient.PrimaryClinicianID),
+		"primary_clinician_id":          uuidString(clinicianID),
+	}
+	patient.PrimaryClinicianID = clinicianID
+
+	recordAudit(
+		ctx,
+		s.auditLogSvc,
+		s.log,
+		AuditActionPrimaryClinicianChange,
+		patient.ID,
+		access,
+		details,
+		ipAddress,
+		userAgent,
+	)
+
+	return s.careTeam(patient)
+}
+
+// patient loads a patient of the organization that the member may access
+func (s *careTeamService) patient(patientID uuid.UUID, access entity.Access) (*entity.Patient, error) {
+	patient, err := s.patientRepo.FindByID(patientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NewNotFound("Patient not found")
+		}
+		return nil, response.ErrInternalServerError
+	}
+
+	if patient.OrganizationID != access.OrganizationID {
+		return nil, response.NewNotFound("Patient not found")
+	}
+
+	allowed, err := s.careTeamRepo.CanAccess(access, patient.ID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if !allowed {
+		return nil, response.NewNotFound("Patient not found")
+	}
+
+	return patient, nil
+}
+
+func (s *careTeamService) requireMember(orgID, userID uuid.UUID) error {
+	isMember, err := s.orgRepo.IsMember(orgID, userID)
+	if err != nil {
+		return response.ErrInternalServerError
+	}
+	if !isMember {
+		return response.NewBadRequest("User is not a member of this organization")
+	}
+	return nil
+}
+
+func (s *careTeamService) careTeam(patient *entity.Patient) (*dto.CareTeamResponse, error) {
+	members, err := s.careTeamRepo.ListMembers(patient.ID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	resp := &dto.CareTeamResponse{
+		PatientID:          patient.ID,
+		PrimaryClinicianID: patient.PrimaryClinicianID,
+		Members:            make([]dto.CareTeamMemberResponse, 0, len(members)),
+	}
+	for _, m := range members {
+		resp.Members = append(resp.Members, dto.CareTeamMemberResponse{
+			UserID:    m.UserID,
+			Email:     m.Email,
+			FullName:  m.FullName,
+			GrantedBy: m.GrantedBy,
+			GrantedAt: m.CreatedAt,
+		})
+	}
+
+	return resp, nil
+}
+
+func uuidString(id *uuid.UUID) interface{} {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/patient/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const demographicsMigrationBatchSize = 100
+
+// DemographicsMigrationService encrypts the demographics of patients written
+// before demographics were encrypted, and empties their plaintext columns.
+// Every patient is updated on its own, so an interrupted run simply continues
+// with the patients left the next time it runs.
+type DemographicsMigrationService interface {
+	Run(ctx context.Context) error
+}
+
+type demographicsMigrationService struct {
+	repo repository.PatientRepository
+	log  logger.Logger
+}
+
+func NewDemographicsMigrationService(
+	repo repository.PatientRepository,
+	log logger.Logger,
+) DemographicsMigrationService {
+	return &demographicsMigrationService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// Run encrypts every patient still in plaintext. It must finish before
+// patients are served, since plaintext patients cannot be read or looked up.
+func (s *demographicsMigrationService) Run(ctx context.Context) error {
+	var encrypted int
+	after := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		patients, err := s.repo.ListPlaintext(after, demographicsMigrationBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(patients) == 0 {
+			break
+		}
+
+		for _, plaintext := range patients {
+			patient := &entity.Patient{
+				ID:             plaintext.ID,
+				OrganizationID: plaintext.OrganizationID,
+				FirstName:      plaintext.FirstName,
+				LastName:       plaintext.LastName,
+				DateOfBirth:    plaintext.DateOfBirth,
+				Email:          plaintext.Email,
+				Phone:          plaintext.Phone,
+				Address:        plaintext.Address,
+			}
+			if err := s.repo.EncryptDemographics(patient); err != nil {
+				return err
+			}
+
+			stored, err := s.repo.StoreEncrypted(patient)
+			if err != nil {
+				return err
+			}
+			if stored {
+				encrypted++
+			}
+		}
+		after = patients[len(patients)-1].ID
+	}
+
+	if encrypted > 0 {
+		s.log.Info("Encrypted patient demographics", zap.Int("patients", encrypted))
+	}
+	return nil
+}
@@ -12,40 +12,51 @@ import (
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
 )
 
+// PatientService manages patient records. Every method is scoped by an
+// entity.Access, so members limited to their care teams cannot see or change
+// other patients.
 type PatientService interface {
 	Create(
 		ctx context.Context,
 		req dto.CreatePatientRequest,
-		organizationID, createdBy uuid.UUID,
+		access entity.Access,
 	) (*dto.PatientResponse, error)
 	Update(
 		ctx context.Context,
 		id uuid.UUID,
-		organizationID uuid.UUID,
+		access entity.Access,
 		req dto.UpdatePatientRequest,
 	) (*dto.PatientResponse, error)
-	Delete(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) error
-	Get(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (*dto.PatientResponse, error)
-	List(ctx context.Context, organizationID uuid.UUID, page, pageSize int) ([]dto.PatientResponse, int64, error)
-	GetOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
+	Delete(ctx context.Context, id uuid.UUID, access entity.Access) error
+	Get(ctx context.Context, id uuid.UUID, access entity.Access) (*dto.PatientResponse, error)
+	List(ctx context.Context, access entity.Access, page, pageSize int) ([]dto.PatientResponse, int64, error)
+	Lookup(ctx context.Context, access entity.Access, req dto.PatientLookupRequest) ([]dto.PatientResponse, error)
 }
 
 type patientService struct {
-	repo repository.PatientRepository
-	log  logger.Logger
+	repo         repository.PatientRepository
+	careTeamRepo repository.CareTeamRepository
+	log          logger.Logger
 }
 
-func NewPatientService(repo repository.PatientRepository, log logger.Logger) PatientService {
+func NewPatientService(
+	repo repository.PatientRepository,
+	careTeamRepo repository.CareTeamRepository,
+	log logger.Logger,
+) PatientService {
 	return &patientService{
-		repo: repo,
-		log:  log,
+		repo:         repo,
+		careTeamRepo: careTeamRepo,
+		log:          log,
 	}
 }
 
+// Create adds a patient. A member limited to their care teams becomes the
+// new patient's primary clinician so they keep access to it.
 func (s *patientService) Create(
 	ctx context.Context,
 	req dto.CreatePatientRequest,
-	organizationID, createdBy uuid.UUID,
+	access entity.Access,
 ) (*dto.PatientResponse, error) {
 	dob, err := time.Parse("2006-01-02", req.DateOfBirth)
 	if err != nil {
@@ -59,7 +70,7 @@ func (s *patientService) Create(
 
 	patient := &entity.Patient{
 		ID:             uuid.New(),
-		OrganizationID: organizationID,
+		OrganizationID: access.OrganizationID,
 		FirstName:      req.FirstName,
 		LastName:       req.LastName,
 		DateOfBirth:    dob,
@@ -67,7 +78,10 @@ func (s *patientService) Create(
 		Phone:          req.Phone,
 		Address:        req.Address,
 		Status:         status,
-		CreatedBy:      createdBy,
+		CreatedBy:      access.UserID,
+	}
+	if !access.AllPatients {
+		patient.PrimaryClinicianID = &access.UserID
 	}
 
 	if err := s.repo.Create(patient); err != nil {
@@ -80,18 +94,14 @@ func (s *patientService) Create(
 func (s *patientService) Update(
 	ctx context.Context,
 	id uuid.UUID,
-	organizationID uuid.UUID,
+	access entity.Access,
 	req dto.UpdatePatientRequest,
 ) (*dto.PatientResponse, error) {
-	patient, err := s.repo.FindByID(id)
+	patient, err := s.find(id, access)
 	if err != nil {
 		return nil, err
 	}
 
-	if patient.OrganizationID != organizationID {
-		return nil, response.ErrNotFound
-	}
-
 	if req.FirstName != "" {
 		patient.FirstName = req.FirstName
 	}
@@ -125,43 +135,39 @@ func (s *patientService) Update(
 	return s.mapEntityToResponse(patient), nil
 }
 
-func (s *patientService) Delete(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) error {
-	patient, err := s.repo.FindByID(id)
-	if err != nil {
+func (s *patientService) Delete(ctx context.Context, id uuid.UUID, access entity.Access) error {
+	if _, err := s.find(id, access); err != nil {
 		return err
 	}
 
-	if patient.OrganizationID != organizationID {
-		return response.ErrNotFound
-	}
-
 	return s.repo.Delete(id)
 }
 
 func (s *patientService) Get(
 	ctx context.Context,
 	id uuid.UUID,
-	organizationID uuid.UUID,
+	access entity.Access,
 ) (*dto.PatientResponse, error) {
-	patient, err := s.repo.FindByID(id)
+	patient, err := s.find(id, access)
 	if err != nil {
 		return nil, err
 	}
 
-	if patient.OrganizationID != organizationID {
-		return nil, response.ErrNotFound
-	}
-
 	return s.mapEntityToResponse(patient), nil
 }
 
 func (s *patientService) List(
 	ctx context.Context,
-	organizationID uuid.UUID,
+	access entity.Access,
 	page, pageSize int,
 ) ([]dto.PatientResponse, int64, error) {
 	offset := (page - 1) * pageSize
-	patients, total, err := s.repo.List(organizationID, pageSize, offset)
+	patients, total, err := s.repo.List(
+		access.OrganizationID,
+		pageSize,
+		offset,
+		repository.CareTeamScope(access, "id"),
+	)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -174,23 +180,93 @@ func (s *patientService) List(
 	return responses, total, nil
 }
 
-func (s *patientService) GetOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
-	return s.repo.GetOrganizationID(userID)
+// Lookup finds the patients the member may access that exactly match an
+// email, a phone number, or names with date of birth. Demographics are
+// encrypted, so matching goes through their blind indexes.
+func (s *patientService) Lookup(
+	ctx context.Context,
+	access entity.Access,
+	req dto.PatientLookupRequest,
+) ([]dto.PatientResponse, error) {
+	byName := req.FirstName != "" || req.LastName != "" || req.DateOfBirth != ""
+	criteria := 0
+	for _, given := range []bool{req.Email != "", req.Phone != "", byName} {
+		if given {
+			criteria++
+		}
+	}
+	if criteria != 1 {
+		return nil, response.NewBadRequest(
+			"Look up by exactly one of email, phone, or first_name, last_name and date_of_birth",
+		)
+	}
+
+	scope := repository.CareTeamScope(access, "id")
+	var patients []entity.Patient
+	var err error
+	switch {
+	case req.Email != "":
+		patients, err = s.repo.FindByEmail(access.OrganizationID, req.Email, scope)
+	case req.Phone != "":
+		patients, err = s.repo.FindByPhone(access.OrganizationID, req.Phone, scope)
+	default:
+		if req.FirstName == "" || req.LastName == "" || req.DateOfBirth == "" {
+			return nil, response.NewBadRequest("first_name, last_name and date_of_birth are all required")
+		}
+		dob, parseErr := time.Parse("2006-01-02", req.DateOfBirth)
+		if parseErr != nil {
+			return nil, response.NewBadRequest("date_of_birth must be formatted as YYYY-MM-DD")
+		}
+		patients, err = s.repo.FindByNameAndDOB(access.OrganizationID, req.FirstName, req.LastName, dob, scope)
+	}
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	responses := make([]dto.PatientResponse, 0, len(patients))
+	for i := range patients {
+		responses = append(responses, *s.mapEntityToResponse(&patients[i]))
+	}
+	return responses, nil
+}
+
+// find loads a patient of the organization that the member may access.
+// Patients outside the member's care teams are reported as not found.
+func (s *patientService) find(id uuid.UUID, access entity.Access) (*entity.Patient, error) {
+	patient, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if patient.OrganizationID != access.OrganizationID {
+		return nil, response.ErrNotFound
+	}
+
+	allowed, err := s.careTeamRepo.CanAccess(access, patient.ID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if !allowed {
+		return nil, response.ErrNotFound
+	}
+
+	return patient, nil
 }
 
 func (s *patientService) mapEntityToResponse(p *entity.Patient) *dto.PatientResponse {
 	return &dto.PatientResponse{
-		ID:             p.ID,
-		OrganizationID: p.OrganizationID,
-		FirstName:      p.FirstName,
-		LastName:       p.LastName,
-		DateOfBirth:    p.DateOfBirth.Format("2006-01-02"),
-		Email:          p.Email,
-		Phone:          p.Phone,
-		Address:        p.Address,
-		Status:         p.Status,
-		CreatedBy:      p.CreatedBy,
-		CreatedAt:      p.CreatedAt,
-		UpdatedAt:      p.UpdatedAt,
+		ID:                 p.ID,
+		OrganizationID:     p.OrganizationID,
+		FirstName:          p.FirstName,
+		LastName:           p.LastName,
+		DateOfBirth:        p.DateOfBirth.Format("2006-01-02"),
+		Email:              p.Email,
+		Phone:              p.Phone,
+		Address:            p.Address,
+		Status:             p.Status,
+		PrimaryClinicianID: p.PrimaryClinicianID,
+		CreatedBy:          p.CreatedBy,
+		CreatedAt:          p.CreatedAt,
+		UpdatedAt:          p.UpdatedAt,
 	}
 }
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session tracks a signed-in device. The ID is shared with the refresh token
+// family issued at login and TokenID is the jti of the access token that is
+// currently valid for the session. OrganizationID is the organization that
+// token is scoped to.
+type Session struct {
+	ID                uuid.UUID  `gorm:"primaryKey;type:uuid"           json:"id"`
+	UserID            uuid.UUID  `gorm:"type:uuid;not null;index"       json:"user_id"`
+	OrganizationID    uuid.UUID  `gorm:"type:uuid"                      json:"organization_id"`
+	TokenID           uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"-"`
+	IPAddress         *string    `gorm:"type:varchar(45)"               json:"ip_address"`
+	UserAgent         *string    `gorm:"type:text"                      json:"user_agent"`
+	LastSeenAt        time.Time  `gorm:"not null"                       json:"last_seen_at"`
+	ReauthenticatedAt time.Time  `gorm:"not null"                       json:"reauthenticated_at"`
+	ExpiresAt         time.Time  `gorm:"not null"                       json:"expires_at"`
+	RevokedAt         *time.Time `gorm:""                               json:"revoked_at"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime"                 json:"created_at"`
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// IsIdle reports whether the session has seen no activity for longer than
+// timeout
+func (s *Session) IsIdle(now time.Time, timeout time.Duration) bool {
+	return now.Sub(s.LastSeenAt) > timeout
+}
+
+// RecentlyAuthenticated reports whether the user confirmed their password or
+// MFA code on this session within maxAge
+func (s *Session) RecentlyAuthenticated(now time.Time, maxAge time.Duration) bool {
+	return now.Sub(s.ReauthenticatedAt) <= maxAge
+}
+
+// IsActive reports whether the session can still authenticate requests
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
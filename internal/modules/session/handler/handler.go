@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/session/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type SessionHandler struct {
+	svc service.SessionService
+}
+
+func NewSessionHandler(svc service.SessionService) *SessionHandler {
+	return &SessionHandler{svc: svc}
+}
+
+func (h *SessionHandler) ListMySessions(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+	sessionID, _ := c.Get("sessionID")
+	currentSessionID, _ := sessionID.(uuid.UUID)
+
+	resp, err := h.svc.List(userID, currentSessionID)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Sessions retrieved successfully", resp))
+}
+
+func (h *SessionHandler) RevokeMySession(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid session ID", nil)
+		return
+	}
+
+	if err := h.svc.Revoke(userID, sessionID); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Session revoked successfully", nil))
+}
+
+func (h *SessionHandler) Logout(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	sessionIDVal, exists := c.Get("sessionID")
+	if !exists {
+		response.Unauthorized(c, "Session not found")
+		return
+	}
+	sessionID := sessionIDVal.(uuid.UUID)
+
+	if err := h.svc.Revoke(userID, sessionID); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Logged out successfully", nil))
+}
+
+func (h *SessionHandler) LogoutAll(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	count, err := h.svc.RevokeAll(userID)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Signed out of all sessions", map[string]interface{}{
+		"revoked": count,
+	}))
+}
+
+func (h *SessionHandler) RevokeUserSessions(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	adminID := userIDVal.(uuid.UUID)
+
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	targetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", nil)
+		return
+	}
+
+	count, err := h.svc.RevokeUserSessions(orgID, adminID, targetID)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("User sessions revoked successfully", map[string]interface{}{
+		"revoked": count,
+	}))
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/session/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Create(session *entity.Session) error
+	FindByID(id uuid.UUID) (*entity.Session, error)
+	FindByTokenID(tokenID uuid.UUID) (*entity.Session, error)
+	ListActiveByUser(userID uuid.UUID, activeSince time.Time) ([]entity.Session, error)
+	Rotate(id, orgID, tokenID uuid.UUID, expiresAt, activeSince time.Time, ipAddress, userAgent *string) (bool, error)
+	Touch(id uuid.UUID, seenAt time.Time) error
+	MarkReauthenticated(id uuid.UUID, at time.Time) error
+	Revoke(id uuid.UUID) error
+	RevokeAllByUser(userID uuid.UUID) (int64, error)
+	RevokeOthersByUser(userID, keepID uuid.UUID) (int64, error)
+	RevokeByUserInOrganization(userID, orgID uuid.UUID) (int64, error)
+}
+
+type sessionRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewSessionRepository(db *gorm.DB, log logger.Logger) SessionRepository {
+	return &sessionRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *sessionRepository) Create(session *entity.Session) error {
+	if err := r.db.Create(session).Error; err != nil {
+		r.log.Error("Failed to create session", zap.Error(err), zap.String("user_id", session.UserID.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *sessionRepository) FindByID(id uuid.UUID) (*entity.Session, error) {
+	var session entity.Session
+	if err := r.db.First(&session, "id = ?", id).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find session", zap.Error(err), zap.String("id", id.String()))
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) FindByTokenID(tokenID uuid.UUID) (*entity.Session, error) {
+	var session entity.Session
+	if err := r.db.First(&session, "token_id = ?", tokenID).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find session by token", zap.Error(err), zap.String("token_id", tokenID.String()))
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveByUser returns the user's sessions that are neither revoked,
+// expired nor idle since activeSince
+func (r *sessionRepository) ListActiveByUser(userID uuid.UUID, activeSince time.Time) ([]entity.Session, error) {
+	var sessions []entity.Session
+	err := r.db.
+		Where(
+			"user_id = ? AND revoked_at IS NULL AND expires_at > ? AND last_seen_at > ?",
+			userID,
+			time.Now(),
+			activeSince,
+		).
+		Order("last_seen_at desc").
+		Find(&sessions).Error
+	if err != nil {
+		r.log.Error("Failed to list sessions", zap.Error(err), zap.String("user_id", userID.String()))
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Rotate binds a new access token to an active session. It returns false
+// when the session has been revoked in the meantime or has been idle since
+// activeSince. Rotation does not count as activity, so a client refreshing
+// tokens in the background cannot keep an idle session alive.
+func (r *sessionRepository) Rotate(
+	id, orgID, tokenID uuid.UUID,
+	expiresAt, activeSince time.Time,
+	ipAddress, userAgent *string,
+) (bool, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("id = ? AND revoked_at IS NULL AND last_seen_at > ?", id, activeSince).
+		Updates(map[string]interface{}{
+			"organization_id": orgID,
+			"token_id":        tokenID,
+			"expires_at":      expiresAt,
+			"ip_address":      ipAddress,
+			"user_agent":      userAgent,
+		})
+	if result.Error != nil {
+		r.log.Error("Failed to rotate session token", zap.Error(result.Error), zap.String("id", id.String()))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *sessionRepository) Touch(id uuid.UUID, seenAt time.Time) error {
+	err := r.db.Model(&entity.Session{}).Where("id = ?", id).Update("last_seen_at", seenAt).Error
+	if err != nil {
+		r.log.Error("Failed to touch session", zap.Error(err), zap.String("id", id.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *sessionRepository) MarkReauthenticated(id uuid.UUID, at time.Time) error {
+	err := r.db.Model(&entity.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("reauthenticated_at", at).Error
+	if err != nil {
+		r.log.Error("Failed to mark session reauthenticated", zap.Error(err), zap.String("id", id.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *sessionRepository) Revoke(id uuid.UUID) error {
+	err := r.db.Model(&entity.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		r.log.Error("Failed to revoke session", zap.Error(err), zap.String("id", id.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *sessionRepository) RevokeAllByUser(userID uuid.UUID) (int64, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		r.log.Error("Failed to revoke user sessions", zap.Error(result.Error), zap.String("user_id", userID.String()))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// RevokeOthersByUser revokes every session of the user except keepID
+func (r *sessionRepository) RevokeOthersByUser(userID, keepID uuid.UUID) (int64, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		r.log.Error("Failed to revoke other user sessions", zap.Error(result.Error), zap.String("user_id", userID.String()))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// RevokeByUserInOrganization revokes the user's sessions scoped to the
+// organization. Sessions from before the scope was recorded are revoked too,
+// as their organization is unknown.
+func (r *sessionRepository) RevokeByUserInOrganization(userID, orgID uuid.UUID) (int64, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("user_id = ? AND (organization_id = ? OR organization_id IS NULL) AND revoked_at IS NULL", userID, orgID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		r.log.Error(
+			"Failed to revoke organization sessions",
+			zap.Error(result.Error),
+			zap.String("user_id", userID.String()),
+			zap.String("org_id", orgID.String()),
+		)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	organizationEntity "github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	organizationRepository "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/session/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/session/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/session/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// touchInterval limits how often last_seen_at is written for a busy session
+const touchInterval = time.Minute
+
+// ErrSessionIdle is returned for a session that was signed out because it saw
+// no activity for longer than the idle timeout
+var ErrSessionIdle = response.NewUnauthorized("Session expired due to inactivity")
+
+// RoleCoverage reports whether an organization role grants every permission
+// of another. The organization role service implements it; it is declared
+// here because that package depends on this one.
+type RoleCoverage interface {
+	Covers(orgID uuid.UUID, actorRole, role string) (bool, error)
+}
+
+type SessionService interface {
+	Create(userID, orgID, sessionID, tokenID uuid.UUID, expiresAt time.Time, ipAddress, userAgent string) error
+	Rotate(sessionID, orgID, tokenID uuid.UUID, expiresAt time.Time, ipAddress, userAgent string) error
+	Validate(tokenID uuid.UUID) (*entity.Session, error)
+	IsActive(sessionID uuid.UUID) (bool, error)
+	Reauthenticate(sessionID uuid.UUID) error
+	List(userID, currentSessionID uuid.UUID) ([]dto.SessionResponse, error)
+	Revoke(userID, sessionID uuid.UUID) error
+	RevokeAll(userID uuid.UUID) (int64, error)
+	RevokeOthers(userID, currentSessionID uuid.UUID) (int64, error)
+	RevokeInOrganization(userID, orgID uuid.UUID) (int64, error)
+	RevokeUserSessions(orgID, adminID, userID uuid.UUID) (int64, error)
+}
+
+type sessionService struct {
+	repo        repository.SessionRepository
+	orgRepo     organizationRepository.OrganizationRepository
+	roles       RoleCoverage
+	idleTimeout time.Duration
+	log         logger.Logger
+}
+
+func NewSessionService(
+	repo repository.SessionRepository,
+	orgRepo organizationRepository.OrganizationRepository,
+	roles RoleCoverage,
+	idleTimeout time.Duration,
+	log logger.Logger,
+) SessionService {
+	return &sessionService{
+		repo:        repo,
+		orgRepo:     orgRepo,
+		roles:       roles,
+		idleTimeout: idleTimeout,
+		log:         log,
+	}
+}
+
+func (s *sessionService) Create(
+	userID, orgID, sessionID, tokenID uuid.UUID,
+	expiresAt time.Time,
+	ipAddress, userAgent string,
+) error {
+	now := time.Now()
+	session := &entity.Session{
+		ID:                sessionID,
+		UserID:            userID,
+		OrganizationID:    orgID,
+		TokenID:           tokenID,
+		IPAddress:         optionalString(ipAddress),
+		UserAgent:         optionalString(userAgent),
+		LastSeenAt:        now,
+		ReauthenticatedAt: now,
+		ExpiresAt:         expiresAt,
+	}
+
+	return s.repo.Create(session)
+}
+
+func (s *sessionService) Rotate(
+	sessionID, orgID, tokenID uuid.UUID,
+	expiresAt time.Time,
+	ipAddress, userAgent string,
+) error {
+	rotated, err := s.repo.Rotate(
+		sessionID,
+		orgID,
+		tokenID,
+		expiresAt,
+		time.Now().Add(-s.idleTimeout),
+		optionalString(ipAddress),
+		optionalString(userAgent),
+	)
+	if err != nil {
+		return response.ErrInternalServerError
+	}
+	if !rotated {
+		s.log.Warn("Session rotation rejected: session revoked or idle", zap.String("session_id", sessionID.String()))
+		// Make sure an idle session cannot be picked up again later
+		_ = s.repo.Revoke(sessionID)
+		return response.ErrUnauthorized
+	}
+	return nil
+}
+
+// Validate resolves the session bound to an access token jti and rejects
+// revoked or expired sessions. A session idle for longer than the idle timeout
+// is revoked and rejected with ErrSessionIdle.
+func (s *sessionService) Validate(tokenID uuid.UUID) (*entity.Session, error) {
+	session, err := s.repo.FindByTokenID(tokenID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrUnauthorized
+		}
+		return nil, response.ErrInternalServerError
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) {
+		return nil, response.ErrUnauthorized
+	}
+
+	if session.IsIdle(now, s.idleTimeout) {
+		if err := s.repo.Revoke(session.ID); err != nil {
+			return nil, response.ErrInternalServerError
+		}
+		s.log.Info(
+			"Session signed out after inactivity",
+			zap.String("user_id", session.UserID.String()),
+			zap.String("session_id", session.ID.String()),
+		)
+		return nil, ErrSessionIdle
+	}
+
+	if now.Sub(session.LastSeenAt) > touchInterval {
+		if err := s.repo.Touch(session.ID, now); err == nil {
+			session.LastSeenAt = now
+		}
+	}
+
+	return session, nil
+}
+
+// IsActive reports whether the session has neither been revoked nor expired.
+// Unlike Validate it does not apply the idle timeout, so it suits checks on a
+// session other than the one making the request.
+func (s *sessionService) IsActive(sessionID uuid.UUID) (bool, error) {
+	session, err := s.repo.FindByID(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, response.ErrInternalServerError
+	}
+	return session.IsActive(time.Now()), nil
+}
+
+// Reauthenticate records that the user just confirmed their password or MFA
+// code on the session, unlocking sensitive actions for a short while
+func (s *sessionService) Reauthenticate(sessionID uuid.UUID) error {
+	if err := s.repo.MarkReauthenticated(sessionID, time.Now()); err != nil {
+		return response.ErrInternalServerError
+	}
+	return nil
+}
+
+func (s *sessionService) List(userID, currentSessionID uuid.UUID) ([]dto.SessionResponse, error) {
+	sessions, err := s.repo.ListActiveByUser(userID, time.Now().Add(-s.idleTimeout))
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	responses := make([]dto.SessionResponse, 0, len(sessions))
+	for i := range sessions {
+		responses = append(responses, dto.SessionResponse{
+			ID:         sessions[i].ID,
+			IPAddress:  sessions[i].IPAddress,
+			UserAgent:  sessions[i].UserAgent,
+			Current:    sessions[i].ID == currentSessionID,
+			LastSeenAt: sessions[i].LastSeenAt,
+			ExpiresAt:  sessions[i].ExpiresAt,
+			CreatedAt:  sessions[i].CreatedAt,
+		})
+	}
+
+	return responses, nil
+}
+
+func (s *sessionService) Revoke(userID, sessionID uuid.UUID) error {
+	session, err := s.repo.FindByID(sessionID)
+	if err != nil || session.UserID != userID {
+		return response.NewNotFound("Session not found")
+	}
+
+	if err := s.repo.Revoke(sessionID); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	s.log.Info("Session revoked", zap.String("user_id", userID.String()), zap.String("session_id", sessionID.String()))
+	return nil
+}
+
+func (s *sessionService) RevokeAll(userID uuid.UUID) (int64, error) {
+	count, err := s.repo.RevokeAllByUser(userID)
+	if err != nil {
+		return 0, response.ErrInternalServerError
+	}
+
+	s.log.Info("All sessions revoked", zap.String("user_id", userID.String()), zap.Int64("count", count))
+	return count, nil
+}
+
+// RevokeOthers signs the user out everywhere except the current session
+func (s *sessionService) RevokeOthers(userID, currentSessionID uuid.UUID) (int64, error) {
+	count, err := s.repo.RevokeOthersByUser(userID, currentSessionID)
+	if err != nil {
+		return 0, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"Other sessions revoked",
+		zap.String("user_id", userID.String()),
+		zap.String("session_id", currentSessionID.String()),
+		zap.Int64("count", count),
+	)
+	return count, nil
+}
+
+// RevokeInOrganization signs the user out of the sessions currently scoped
+// to the organization, leaving their sessions in other organizations alone
+func (s *sessionService) RevokeInOrganization(userID, orgID uuid.UUID) (int64, error) {
+	count, err := s.repo.RevokeByUserInOrganization(userID, orgID)
+	if err != nil {
+		return 0, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"Organization sessions revoked",
+		zap.String("user_id", userID.String()),
+		zap.String("org_id", orgID.String()),
+		zap.Int64("count", count),
+	)
+	return count, nil
+}
+
+// RevokeUserSessions lets an organization admin sign a member out of the
+// sessions scoped to the admin's organization; sessions in the member's other
+// organizations are left alone. Like other member changes, it does not apply
+// to the admin themselves or the owner, and the admin's role must cover the
+// member's permissions.
+func (s *sessionService) RevokeUserSessions(orgID, adminID, userID uuid.UUID) (int64, error) {
+	if adminID == userID {
+		return 0, response.NewForbidden("You cannot revoke your own sessions here")
+	}
+
+	target, err := s.orgRepo.GetMember(orgID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, response.NewNotFound("User not found")
+		}
+		return 0, response.ErrInternalServerError
+	}
+	if target.Role == organizationEntity.RoleOwner {
+		return 0, response.NewForbidden("The organization owner cannot be changed")
+	}
+
+	admin, err := s.orgRepo.GetMember(orgID, adminID)
+	if err != nil {
+		return 0, response.ErrNotFound
+	}
+	covers, err := s.roles.Covers(orgID, admin.Role, target.Role)
+	if err != nil {
+		return 0, err
+	}
+	if !covers {
+		return 0, response.NewForbidden("You cannot manage a member with permissions you do not have")
+	}
+
+	count, err := s.repo.RevokeByUserInOrganization(userID, orgID)
+	if err != nil {
+		return 0, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"User sessions revoked by admin",
+		zap.String("org_id", orgID.String()),
+		zap.String("admin_id", adminID.String()),
+		zap.String("user_id", userID.String()),
+		zap.Int64("count", count),
+	)
+	return count, nil
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
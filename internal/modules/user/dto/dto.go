@@ -1,10 +1,14 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type RegisterRequest struct {
 	Email        string `json:"email"         binding:"required,email"`
-	Password     string `json:"password"      binding:"required,min=8"`
+	Password     string `json:"password"      binding:"required"`
 	FullName     string `json:"full_name"     binding:"required,min=2"`
 	PracticeName string `json:"practice_name" binding:"required,min=2"`
 }
@@ -15,14 +19,57 @@ type RegisterResponse struct {
 	Role  string    `json:"role"`
 }
 
+type AcceptInvitationRequest struct {
+	Token    string `json:"token"     binding:"required"`
+	FullName string `json:"full_name" binding:"required,min=2"`
+	Password string `json:"password"  binding:"required"`
+}
+
 type LoginRequest struct {
 	Email    string `json:"email"    binding:"required,email"`
 	Password string `json:"password" binding:"required"`
 }
 
+// LoginResponse carries either a token pair or, when a second factor is
+// needed, an MFA challenge token to exchange at /auth/mfa/verify. An expired
+// password yields a token to exchange at /auth/password/expired instead, and
+// a single sign-on login to an existing account a token to confirm at
+// /auth/sso/link.
 type LoginResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken            string   `json:"access_token,omitempty"`
+	RefreshToken           string   `json:"refresh_token,omitempty"`
+	MFARequired            bool     `json:"mfa_required,omitempty"`
+	MFAEnrollmentRequired  bool     `json:"mfa_enrollment_required,omitempty"`
+	MFAToken               string   `json:"mfa_token,omitempty"`
+	RecoveryCodes          []string `json:"recovery_codes,omitempty"`
+	PasswordChangeRequired bool     `json:"password_change_required,omitempty"`
+	PasswordChangeToken    string   `json:"password_change_token,omitempty"`
+	SSOLinkRequired        bool     `json:"sso_link_required,omitempty"`
+	SSOLinkToken           string   `json:"sso_link_token,omitempty"`
+}
+
+type MFAChallengeRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code"      binding:"required"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type SwitchOrganizationRequest struct {
+	OrganizationID uuid.UUID `json:"organization_id" binding:"required"`
+}
+
+// ReauthenticateRequest confirms the signed-in user's identity with either
+// their password or an MFA code
+type ReauthenticateRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
 }
 
 type SetupStatusResponse struct {
@@ -34,14 +81,127 @@ type UpdateProfileRequest struct {
 	FullName string `json:"full_name" binding:"required,min=2"`
 }
 
+type ExpiredPasswordChangeRequest struct {
+	PasswordChangeToken string `json:"password_change_token" binding:"required"`
+	NewPassword         string `json:"new_password"          binding:"required"`
+}
+
 type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=8"`
+	NewPassword string `json:"new_password" binding:"required"`
 }
 
 type UserResponse struct {
-	ID       uuid.UUID `json:"id"`
-	Email    string    `json:"email"`
-	FullName string    `json:"full_name"`
-	Role     string    `json:"role"`
+	ID            uuid.UUID `json:"id"`
+	Email         string    `json:"email"`
+	FullName      string    `json:"full_name"`
+	Role          string    `json:"role"`
+	MFAEnabled    bool      `json:"mfa_enabled"`
+	EmailVerified bool      `json:"email_verified"`
+}
+
+type MFAStatusResponse struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+type MFASetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFADisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code"     binding:"required"`
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// SSOStartRequest picks the organization to sign in to, either directly or
+// by the domain of the user's work email
+type SSOStartRequest struct {
+	OrganizationID *uuid.UUID `json:"organization_id"`
+	Email          string     `json:"email"`
+}
+
+type SSOStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type SSOCallbackRequest struct {
+	Code  string `json:"code"  binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// SSOLinkConfirmRequest links an identity provider account to the existing
+// account with the same email, confirmed with that account's password
+type SSOLinkConfirmRequest struct {
+	LinkToken string `json:"link_token" binding:"required"`
+	Password  string `json:"password"   binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"    binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type MemberAccountResponse struct {
+	ID                    uuid.UUID  `json:"id"`
+	Email                 string     `json:"email"`
+	FullName              string     `json:"full_name"`
+	Role                  string     `json:"role"`
+	MFAEnabled            bool       `json:"mfa_enabled"`
+	EmailVerified         bool       `json:"email_verified"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	SSOOnly               bool       `json:"sso_only"`
+	Active                bool       `json:"active"`
+	DeactivatedAt         *time.Time `json:"deactivated_at,omitempty"`
+	JoinedAt              time.Time  `json:"joined_at"`
+}
+
+type AdminUpdateUserRequest struct {
+	FullName string `json:"full_name" binding:"required,min=2"`
+}
+
+type ReassignClinicianRequest struct {
+	ToUserID uuid.UUID `json:"to_user_id" binding:"required"`
+}
+
+type ReassignmentResponse struct {
+	PatientsReassigned         int64 `json:"patients_reassigned"`
+	CareTeamEntriesTransferred int64 `json:"care_team_entries_transferred"`
+	AppointmentsReassigned     int64 `json:"appointments_reassigned"`
+	CareTeamAccessGranted      int64 `json:"care_team_access_granted"`
+}
+
+type StartImpersonationRequest struct {
+	Reason      string `json:"reason"       binding:"required"`
+	AllowWrites bool   `json:"allow_writes"`
+}
+
+type ImpersonationResponse struct {
+	ID          uuid.UUID `json:"id"`
+	AccessToken string    `json:"access_token"`
+	UserID      uuid.UUID `json:"user_id"`
+	ReadOnly    bool      `json:"read_only"`
+	ExpiresAt   time.Time `json:"expires_at"`
 }
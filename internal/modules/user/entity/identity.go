@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Identity links an account at an external identity provider, identified by
+// issuer and subject, to an OpenMind user
+type Identity struct {
+	ID             uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index"                        json:"user_id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null"                              json:"organization_id"`
+	Issuer         string     `gorm:"type:varchar(500);not null"                      json:"issuer"`
+	Subject        string     `gorm:"type:varchar(255);not null"                      json:"subject"`
+	LastLoginAt    *time.Time `                                                       json:"last_login_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime"                                  json:"created_at"`
+}
+
+func (Identity) TableName() string {
+	return "user_identities"
+}
+
+// SSOLoginRequest holds the PKCE verifier and nonce of an authorization
+// request until the identity provider redirects back. Only the hash of the
+// state parameter is stored.
+type SSOLoginRequest struct {
+	ID             uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null"                              json:"organization_id"`
+	StateHash      string    `gorm:"type:varchar(64);not null;uniqueIndex"           json:"-"`
+	Nonce          string    `gorm:"type:varchar(64);not null"                       json:"-"`
+	CodeVerifier   string    `gorm:"type:varchar(128);not null"                      json:"-"`
+	ExpiresAt      time.Time `gorm:"not null"                                        json:"expires_at"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"                                  json:"created_at"`
+}
+
+func (SSOLoginRequest) TableName() string {
+	return "sso_login_requests"
+}
+
+// SSOLinkRequest holds a provider account that signed in with the email of
+// an existing user. The account is only linked once the user confirms the
+// link with their password. Only the hash of the link token is stored.
+type SSOLinkRequest struct {
+	ID             uuid.UUID                   `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID         uuid.UUID                   `gorm:"type:uuid;not null"                              json:"user_id"`
+	OrganizationID uuid.UUID                   `gorm:"type:uuid;not null"                              json:"organization_id"`
+	Issuer         string                      `gorm:"type:varchar(500);not null"                      json:"issuer"`
+	Subject        string                      `gorm:"type:varchar(255);not null"                      json:"subject"`
+	Groups         datatypes.JSONSlice[string] `gorm:"type:jsonb;not null"                             json:"groups"`
+	TokenHash      string                      `gorm:"type:varchar(64);not null;uniqueIndex"           json:"-"`
+	ExpiresAt      time.Time                   `gorm:"not null"                                        json:"expires_at"`
+	CreatedAt      time.Time                   `gorm:"autoCreateTime"                                  json:"created_at"`
+}
+
+func (SSOLinkRequest) TableName() string {
+	return "sso_link_requests"
+}
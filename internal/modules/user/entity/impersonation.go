@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Impersonation is an administrator using the application as one of their
+// organization's members. It is time-limited and read-only unless the
+// administrator allowed changes when starting it.
+type Impersonation struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null"                               json:"organization_id"`
+	ImpersonatorID uuid.UUID  `gorm:"type:uuid;not null"                               json:"impersonator_id"`
+	AdminSessionID *uuid.UUID `gorm:"type:uuid"                                        json:"-"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null"                               json:"user_id"`
+	Reason         string     `gorm:"type:text;not null"                               json:"reason"`
+	ReadOnly       bool       `gorm:"not null;default:true"                            json:"read_only"`
+	ExpiresAt      time.Time  `gorm:"not null"                                         json:"expires_at"`
+	EndedAt        *time.Time `                                                        json:"ended_at"`
+	CreatedAt      time.Time  `                                                        json:"created_at"`
+}
+
+func (Impersonation) TableName() string {
+	return "user_impersonations"
+}
+
+// IsActive reports whether the impersonation can still be used
+func (i *Impersonation) IsActive(now time.Time) bool {
+	return i.EndedAt == nil && now.Before(i.ExpiresAt)
+}
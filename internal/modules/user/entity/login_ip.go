@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserLoginIP is an IP address a user has signed in from, used to notice
+// sign-ins from somewhere new
+type UserLoginIP struct {
+	UserID      uuid.UUID `gorm:"type:uuid;primaryKey"        json:"user_id"`
+	IPAddress   string    `gorm:"type:varchar(45);primaryKey" json:"ip_address"`
+	FirstSeenAt time.Time `gorm:"not null"                    json:"first_seen_at"`
+	LastSeenAt  time.Time `gorm:"not null"                    json:"last_seen_at"`
+}
+
+func (UserLoginIP) TableName() string {
+	return "user_login_ips"
+}
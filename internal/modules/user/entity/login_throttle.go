@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+)
+
+// LoginThrottle counts recent failed logins for one account email, client
+// IP address or MFA challenge. Key is prefixed with its kind, e.g.
+// "email:jane@example.com", "ip:203.0.113.7" or "mfa:<challenge ID>".
+type LoginThrottle struct {
+	Key           string     `gorm:"primaryKey;type:varchar(320)" json:"key"`
+	Failures      int        `gorm:"not null;default:0"           json:"failures"`
+	LastFailureAt time.Time  `gorm:"not null"                     json:"last_failure_at"`
+	LockedUntil   *time.Time `                                    json:"locked_until"`
+}
+
+func (LoginThrottle) TableName() string {
+	return "login_throttles"
+}
+
+// IsLocked reports whether the key is temporarily locked out
+func (t *LoginThrottle) IsLocked(now time.Time) bool {
+	return t.LockedUntil != nil && now.Before(*t.LockedUntil)
+}
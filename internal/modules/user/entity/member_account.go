@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemberAccount is a user account as seen by the administrators of one of
+// the organizations the user belongs to
+type MemberAccount struct {
+	ID                    uuid.UUID
+	Email                 string
+	FullName              string
+	Role                  string
+	MFAEnabled            bool
+	EmailVerifiedAt       *time.Time
+	PasswordHash          string
+	PasswordResetRequired bool
+	DeactivatedAt         *time.Time
+	JoinedAt              time.Time
+}
+
+// ReassignmentResult counts the records moved from one clinician to another
+type ReassignmentResult struct {
+	Patients        int64
+	CareTeamEntries int64
+	Appointments    int64
+	CareTeamGrants  int64
+}
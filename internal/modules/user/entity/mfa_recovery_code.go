@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MFARecoveryCode is a hashed single-use code that can stand in for a TOTP
+// code when the user has lost their authenticator device
+type MFARecoveryCode struct {
+	ID        uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"                        json:"user_id"`
+	CodeHash  string     `gorm:"type:varchar(64);not null"                       json:"-"`
+	UsedAt    *time.Time `gorm:""                                                json:"used_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"                                  json:"created_at"`
+}
+
+func (MFARecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
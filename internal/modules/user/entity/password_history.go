@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordHistory is a password hash the user had before, kept so recent
+// passwords cannot be reused
+type PasswordHistory struct {
+	ID           uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index"                        json:"user_id"`
+	PasswordHash string    `gorm:"type:varchar(255);not null"                      json:"-"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"                                  json:"created_at"`
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
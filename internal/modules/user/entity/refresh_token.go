@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is the server-side record of an issued refresh token.
+// Every login starts a new family; each refresh rotates the token within
+// that family so a replayed (already rotated) token can be detected.
+type RefreshToken struct {
+	ID         uuid.UUID  `gorm:"primaryKey;type:uuid"      json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index"  json:"user_id"`
+	FamilyID   uuid.UUID  `gorm:"type:uuid;not null;index"  json:"family_id"`
+	TokenHash  string     `gorm:"type:varchar(64);not null" json:"-"`
+	ExpiresAt  time.Time  `gorm:"not null"                  json:"expires_at"`
+	RevokedAt  *time.Time `gorm:""                          json:"revoked_at"`
+	ReplacedBy *uuid.UUID `gorm:"type:uuid"                 json:"replaced_by"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"            json:"created_at"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
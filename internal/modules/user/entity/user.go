@@ -9,23 +9,30 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID           uuid.UUID      `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
-	CreatedAt    time.Time      `gorm:"autoCreateTime"                                  json:"created_at"`
-	UpdatedAt    time.Time      `gorm:"autoUpdateTime"                                  json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index"                                           json:"-"`
-	Email        string         `gorm:"uniqueIndex;not null"                            json:"email"`
-	PasswordHash string         `gorm:"not null"                                        json:"-"` // Never return password hash in JSON
-	Role         string         `gorm:"not null;default:'clinician'"                    json:"role"`
-	FullName     string         `gorm:"not null"                                        json:"full_name"`
+	ID                    uuid.UUID      `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	CreatedAt             time.Time      `gorm:"autoCreateTime"                                  json:"created_at"`
+	UpdatedAt             time.Time      `gorm:"autoUpdateTime"                                  json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `gorm:"index"                                           json:"-"`
+	Email                 string         `gorm:"uniqueIndex;not null"                            json:"email"`
+	PasswordHash          string         `gorm:"not null"                                        json:"-"` // Never return password hash in JSON
+	Role                  string         `gorm:"not null;default:'clinician'"                    json:"role"`
+	FullName              string         `gorm:"not null"                                        json:"full_name"`
+	MFAEnabled            bool           `gorm:"not null;default:false"                          json:"mfa_enabled"`
+	MFASecret             *string        `gorm:"type:text"                                       json:"-"` // TOTP secret encrypted with EncryptionService
+	MFALastUsedStep       int64          `gorm:"not null;default:0"                              json:"-"`
+	EmailVerifiedAt       *time.Time     `                                                       json:"email_verified_at"`
+	PasswordChangedAt     time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP"              json:"password_changed_at"`
+	PasswordResetRequired bool           `gorm:"not null;default:false"                          json:"password_reset_required"`
 }
 
 type Organization struct {
-	ID        uuid.UUID      `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
-	Name      string         `gorm:"not null"                                        json:"name"`
-	Type      string         `gorm:"not null;default:'clinic'"                       json:"type"`
-	CreatedAt time.Time      `gorm:"autoCreateTime"                                  json:"created_at"`
-	UpdatedAt time.Time      `gorm:"autoUpdateTime"                                  json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index"                                           json:"-"`
+	ID         uuid.UUID      `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	Name       string         `gorm:"not null"                                        json:"name"`
+	Type       string         `gorm:"not null;default:'clinic'"                       json:"type"`
+	RequireMFA bool           `gorm:"not null;default:false"                          json:"require_mfa"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime"                                  json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime"                                  json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index"                                           json:"-"`
 }
 
 func (Organization) TableName() string {
@@ -33,10 +40,11 @@ func (Organization) TableName() string {
 }
 
 type OrganizationMember struct {
-	OrganizationID uuid.UUID `gorm:"primaryKey;type:uuid"      json:"organization_id"`
-	UserID         uuid.UUID `gorm:"primaryKey;type:uuid"      json:"user_id"`
-	Role           string    `gorm:"not null;default:'member'" json:"role"`
-	CreatedAt      time.Time `gorm:"autoCreateTime"            json:"created_at"`
+	OrganizationID uuid.UUID  `gorm:"primaryKey;type:uuid"      json:"organization_id"`
+	UserID         uuid.UUID  `gorm:"primaryKey;type:uuid"      json:"user_id"`
+	Role           string     `gorm:"not null;default:'member'" json:"role"`
+	DeactivatedAt  *time.Time `                                 json:"deactivated_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime"            json:"created_at"`
 }
 
 func (OrganizationMember) TableName() string {
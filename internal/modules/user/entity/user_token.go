@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User token purposes
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+)
+
+// UserToken is a single-use token emailed to a user to reset their password
+// or verify their email address. Only the hash of the token is stored.
+type UserToken struct {
+	ID        uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"                        json:"user_id"`
+	Purpose   string     `gorm:"type:varchar(50);not null"                       json:"purpose"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex"           json:"-"`
+	ExpiresAt time.Time  `gorm:"not null"                                        json:"expires_at"`
+	UsedAt    *time.Time `                                                       json:"used_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"                                  json:"created_at"`
+}
+
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type AccountHandler struct {
+	svc service.AccountService
+}
+
+func NewAccountHandler(svc service.AccountService) *AccountHandler {
+	return &AccountHandler{svc: svc}
+}
+
+func (h *AccountHandler) ForgotPassword(_ context.Context, c *app.RequestContext) {
+	var req dto.ForgotPasswordRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	if err := h.svc.ForgotPassword(req.Email); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(
+		consts.StatusOK,
+		response.Success("If an account exists for this email, a password reset link has been sent", nil),
+	)
+}
+
+func (h *AccountHandler) ResetPassword(_ context.Context, c *app.RequestContext) {
+	var req dto.ResetPasswordRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	if err := h.svc.ResetPassword(req.Token, req.Password); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Password reset successfully", nil))
+}
+
+func (h *AccountHandler) VerifyEmail(_ context.Context, c *app.RequestContext) {
+	var req dto.VerifyEmailRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	if err := h.svc.VerifyEmail(req.Token); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Email verified successfully", nil))
+}
+
+func (h *AccountHandler) ResendVerification(_ context.Context, c *app.RequestContext) {
+	var req dto.ResendVerificationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	if err := h.svc.ResendVerification(req.Email); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(
+		consts.StatusOK,
+		response.Success("If this email needs verification, a new link has been sent", nil),
+	)
+}
+
+func (h *AccountHandler) SendVerification(_ context.Context, c *app.RequestContext) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.svc.SendVerification(userID.(uuid.UUID)); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Verification email sent", nil))
+}
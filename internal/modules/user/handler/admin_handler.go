@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type UserAdminHandler struct {
+	svc service.UserAdminService
+}
+
+func NewUserAdminHandler(svc service.UserAdminService) *UserAdminHandler {
+	return &UserAdminHandler{svc: svc}
+}
+
+// List returns the organization's members with the state of their accounts
+func (h *UserAdminHandler) List(_ context.Context, c *app.RequestContext) {
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	users, err := h.svc.List(orgIDVal.(uuid.UUID))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Users retrieved successfully", users))
+}
+
+func (h *UserAdminHandler) Update(_ context.Context, c *app.RequestContext) {
+	orgID, actorID, targetID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	var req dto.AdminUpdateUserRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	user, err := h.svc.UpdateName(orgID, actorID, targetID, req.FullName, c.ClientIP(), string(c.UserAgent()))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("User updated successfully", user))
+}
+
+func (h *UserAdminHandler) Deactivate(_ context.Context, c *app.RequestContext) {
+	orgID, actorID, targetID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Deactivate(orgID, actorID, targetID, c.ClientIP(), string(c.UserAgent())); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("User deactivated successfully", nil))
+}
+
+func (h *UserAdminHandler) Reactivate(_ context.Context, c *app.RequestContext) {
+	orgID, actorID, targetID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Reactivate(orgID, actorID, targetID, c.ClientIP(), string(c.UserAgent())); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("User reactivated successfully", nil))
+}
+
+func (h *UserAdminHandler) ForcePasswordReset(_ context.Context, c *app.RequestContext) {
+	orgID, actorID, targetID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.ForcePasswordReset(orgID, actorID, targetID, c.ClientIP(), string(c.UserAgent())); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Password reset required and reset email sent", nil))
+}
+
+// Reassign moves the user's patients and upcoming appointments to another
+// clinician
+func (h *UserAdminHandler) Reassign(_ context.Context, c *app.RequestContext) {
+	orgID, actorID, fromID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReassignClinicianRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	result, err := h.svc.Reassign(orgID, actorID, fromID, req.ToUserID, c.ClientIP(), string(c.UserAgent()))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Clinician reassigned successfully", result))
+}
+
+// adminTarget reads the acting admin, their organization and the user named
+// in the path, writing the error response itself when one is missing
+func adminTarget(c *app.RequestContext) (orgID, actorID, targetID uuid.UUID, ok bool) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	targetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", nil)
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	return orgIDVal.(uuid.UUID), userIDVal.(uuid.UUID), targetID, true
+}
@@ -2,9 +2,13 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"math"
+	"strconv"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/user/dto"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/user/service"
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
@@ -40,6 +44,28 @@ func (h *AuthHandler) Register(_ context.Context, c *app.RequestContext) {
 	response.Created(c, resp, "User registered successfully")
 }
 
+func (h *AuthHandler) AcceptInvitation(_ context.Context, c *app.RequestContext) {
+	var req dto.AcceptInvitationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	user, err := h.svc.AcceptInvitation(req.Token, req.FullName, req.Password)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	resp := dto.RegisterResponse{
+		ID:    user.ID,
+		Email: user.Email,
+		Role:  user.Role,
+	}
+
+	response.Created(c, resp, "Invitation accepted successfully")
+}
+
 func (h *AuthHandler) Login(_ context.Context, c *app.RequestContext) {
 	var req dto.LoginRequest
 	if err := c.BindAndValidate(&req); err != nil {
@@ -47,8 +73,9 @@ func (h *AuthHandler) Login(_ context.Context, c *app.RequestContext) {
 		return
 	}
 
-	resp, err := h.svc.Login(req.Email, req.Password)
+	resp, err := h.svc.Login(req.Email, req.Password, c.ClientIP(), string(c.UserAgent()))
 	if err != nil {
+		setRetryAfter(c, err)
 		response.HandleError(c, err)
 		return
 	}
@@ -56,6 +83,93 @@ func (h *AuthHandler) Login(_ context.Context, c *app.RequestContext) {
 	c.JSON(consts.StatusOK, response.Success("Login successful", resp))
 }
 
+// setRetryAfter tells a throttled client how long to wait before trying again
+func setRetryAfter(c *app.RequestContext, err error) {
+	var throttled *service.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Response.Header.Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	}
+}
+
+func (h *AuthHandler) Refresh(_ context.Context, c *app.RequestContext) {
+	var req dto.RefreshTokenRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.Refresh(req.RefreshToken, c.ClientIP(), string(c.UserAgent()))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Token refreshed successfully", resp))
+}
+
+func (h *AuthHandler) SwitchOrganization(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	sessionIDVal, exists := c.Get("sessionID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	sessionID := sessionIDVal.(uuid.UUID)
+
+	var req dto.SwitchOrganizationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.SwitchOrganization(userID, sessionID, req.OrganizationID, c.ClientIP(), string(c.UserAgent()))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Organization switched successfully", resp))
+}
+
+// Reauthenticate confirms the user's password or MFA code so that sensitive
+// actions are allowed on the current session for a few minutes
+func (h *AuthHandler) Reauthenticate(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	sessionIDVal, exists := c.Get("sessionID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	sessionID := sessionIDVal.(uuid.UUID)
+
+	var req dto.ReauthenticateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	err := h.svc.Reauthenticate(userID, sessionID, req.Password, req.Code, c.ClientIP(), string(c.UserAgent()))
+	if err != nil {
+		setRetryAfter(c, err)
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Identity confirmed successfully", nil))
+}
+
 func (h *AuthHandler) SetupStatus(_ context.Context, c *app.RequestContext) {
 	resp, err := h.svc.SetupStatus()
 	if err != nil {
@@ -65,3 +179,58 @@ func (h *AuthHandler) SetupStatus(_ context.Context, c *app.RequestContext) {
 
 	c.JSON(consts.StatusOK, response.Success("Setup status retrieved", resp))
 }
+
+func (h *AuthHandler) VerifyMFA(_ context.Context, c *app.RequestContext) {
+	var req dto.MFAVerifyRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.VerifyMFA(req.MFAToken, req.Code, c.ClientIP(), string(c.UserAgent()))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Login successful", resp))
+}
+
+// ChangeExpiredPassword finishes a login that was stopped because the
+// password expired
+func (h *AuthHandler) ChangeExpiredPassword(_ context.Context, c *app.RequestContext) {
+	var req dto.ExpiredPasswordChangeRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.ChangeExpiredPassword(
+		req.PasswordChangeToken,
+		req.NewPassword,
+		c.ClientIP(),
+		string(c.UserAgent()),
+	)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Password changed successfully", resp))
+}
+
+func (h *AuthHandler) SetupMFA(_ context.Context, c *app.RequestContext) {
+	var req dto.MFAChallengeRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.SetupMFA(req.MFAToken)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("MFA setup started", resp))
+}
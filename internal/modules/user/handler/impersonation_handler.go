@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type ImpersonationHandler struct {
+	svc service.ImpersonationService
+}
+
+func NewImpersonationHandler(svc service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{svc: svc}
+}
+
+// Start issues a token that acts as the member named in the path. Use it in
+// place of the administrator's own token until the impersonation ends.
+func (h *ImpersonationHandler) Start(_ context.Context, c *app.RequestContext) {
+	orgID, adminID, targetID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	// Set for signed-in sessions, which the route's step-up check requires
+	sessionIDVal, exists := c.Get("sessionID")
+	if !exists {
+		response.Forbidden(c, "Impersonation requires a signed-in session")
+		return
+	}
+
+	var req dto.StartImpersonationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.Start(
+		orgID,
+		adminID,
+		sessionIDVal.(uuid.UUID),
+		targetID,
+		req.Reason,
+		req.AllowWrites,
+		c.ClientIP(),
+		string(c.UserAgent()),
+	)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Impersonation started", resp))
+}
+
+// End stops the impersonation the request's token belongs to
+func (h *ImpersonationHandler) End(_ context.Context, c *app.RequestContext) {
+	impersonationVal, exists := c.Get("impersonation")
+	if !exists {
+		response.BadRequest(c, "This request is not impersonating a user", nil)
+		return
+	}
+	impersonation := impersonationVal.(*entity.Impersonation)
+
+	if err := h.svc.End(impersonation.ID, c.ClientIP(), string(c.UserAgent())); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Impersonation ended", nil))
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type LockoutHandler struct {
+	svc service.LoginThrottleService
+}
+
+func NewLockoutHandler(svc service.LoginThrottleService) *LockoutHandler {
+	return &LockoutHandler{svc: svc}
+}
+
+// Unlock clears a member's login lockout so they can try again immediately
+func (h *LockoutHandler) Unlock(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	adminID := userIDVal.(uuid.UUID)
+
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	targetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", nil)
+		return
+	}
+
+	if err := h.svc.Unlock(orgID, adminID, targetID, c.ClientIP(), string(c.UserAgent())); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("User unlocked successfully", nil))
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type MFAHandler struct {
+	svc service.MFAService
+}
+
+func NewMFAHandler(svc service.MFAService) *MFAHandler {
+	return &MFAHandler{svc: svc}
+}
+
+func (h *MFAHandler) Status(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	resp, err := h.svc.Status(userID)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("MFA status retrieved successfully", resp))
+}
+
+func (h *MFAHandler) Setup(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	resp, err := h.svc.Setup(userID)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("MFA setup started", resp))
+}
+
+func (h *MFAHandler) Enable(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	var req dto.MFACodeRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.svc.Enable(userID, req.Code)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("MFA enabled successfully", dto.MFARecoveryCodesResponse{
+		RecoveryCodes: codes,
+	}))
+}
+
+func (h *MFAHandler) Disable(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	var req dto.MFADisableRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	if err := h.svc.Disable(userID, req.Password, req.Code); err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("MFA disabled successfully", nil))
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(_ context.Context, c *app.RequestContext) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
+
+	var req dto.MFACodeRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.svc.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Recovery codes regenerated successfully", dto.MFARecoveryCodesResponse{
+		RecoveryCodes: codes,
+	}))
+}
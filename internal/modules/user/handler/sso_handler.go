@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/service"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+)
+
+type SSOHandler struct {
+	svc service.SSOService
+}
+
+func NewSSOHandler(svc service.SSOService) *SSOHandler {
+	return &SSOHandler{svc: svc}
+}
+
+func (h *SSOHandler) Start(_ context.Context, c *app.RequestContext) {
+	var req dto.SSOStartRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.Start(req)
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Continue sign-in with your identity provider", resp))
+}
+
+func (h *SSOHandler) Callback(_ context.Context, c *app.RequestContext) {
+	var req dto.SSOCallbackRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.Callback(req.Code, req.State, c.ClientIP(), string(c.UserAgent()))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Login successful", resp))
+}
+
+func (h *SSOHandler) ConfirmLink(_ context.Context, c *app.RequestContext) {
+	var req dto.SSOLinkConfirmRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.ConfirmLink(req.LinkToken, req.Password, c.ClientIP(), string(c.UserAgent()))
+	if err != nil {
+		response.HandleError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, response.Success("Login successful", resp))
+}
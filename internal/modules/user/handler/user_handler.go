@@ -31,7 +31,14 @@ func (h *UserHandler) GetProfile(_ context.Context, c *app.RequestContext) {
 	}
 	userID := userIDVal.(uuid.UUID)
 
-	resp, err := h.userSvc.GetProfile(userID)
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
+	resp, err := h.userSvc.GetProfile(userID, orgID)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -48,13 +55,20 @@ func (h *UserHandler) UpdateProfile(_ context.Context, c *app.RequestContext) {
 	}
 	userID := userIDVal.(uuid.UUID)
 
+	orgIDVal, exists := c.Get("organizationID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	orgID := orgIDVal.(uuid.UUID)
+
 	var req dto.UpdateProfileRequest
 	if err := c.BindAndValidate(&req); err != nil {
 		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
 		return
 	}
 
-	resp, err := h.userSvc.UpdateProfile(userID, req)
+	resp, err := h.userSvc.UpdateProfile(userID, orgID, req)
 	if err != nil {
 		response.HandleError(c, err)
 		return
@@ -71,13 +85,22 @@ func (h *UserHandler) ChangePassword(_ context.Context, c *app.RequestContext) {
 	}
 	userID := userIDVal.(uuid.UUID)
 
+	sessionIDVal, exists := c.Get("sessionID")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	sessionID := sessionIDVal.(uuid.UUID)
+
 	var req dto.ChangePasswordRequest
 	if err := c.BindAndValidate(&req); err != nil {
 		response.BadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
 		return
 	}
 
-	if err := h.authSvc.ChangePassword(userID, req.OldPassword, req.NewPassword); err != nil {
+	if err := h.authSvc.ChangePassword(
+		userID, sessionID, req.OldPassword, req.NewPassword, c.ClientIP(), string(c.UserAgent()),
+	); err != nil {
 		response.HandleError(c, err)
 		return
 	}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AdminRepository backs the organization's user administration
+type AdminRepository interface {
+	ListMemberAccounts(orgID uuid.UUID) ([]entity.MemberAccount, error)
+	GetMemberAccount(orgID, userID uuid.UUID) (*entity.MemberAccount, error)
+	Reassign(orgID, fromID, toID, actorID uuid.UUID, now time.Time) (*entity.ReassignmentResult, error)
+}
+
+type adminRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewAdminRepository(db *gorm.DB, log logger.Logger) AdminRepository {
+	return &adminRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *adminRepository) ListMemberAccounts(orgID uuid.UUID) ([]entity.MemberAccount, error) {
+	var accounts []entity.MemberAccount
+	err := r.memberAccounts().
+		Where("organization_members.organization_id = ?", orgID).
+		Order("organization_members.created_at ASC").
+		Scan(&accounts).Error
+
+	if err != nil {
+		r.log.Error("Failed to list member accounts", zap.Error(err), zap.String("org_id", orgID.String()))
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+func (r *adminRepository) GetMemberAccount(orgID, userID uuid.UUID) (*entity.MemberAccount, error) {
+	var account entity.MemberAccount
+	err := r.memberAccounts().
+		Where("organization_members.organization_id = ? AND organization_members.user_id = ?", orgID, userID).
+		Take(&account).Error
+
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get member account", zap.Error(err), zap.String("user_id", userID.String()))
+		}
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (r *adminRepository) memberAccounts() *gorm.DB {
+	return r.db.Table("organization_members").
+		Select("users.id, users.email, users.full_name, organization_members.role, users.mfa_enabled, " +
+			"users.email_verified_at, users.password_hash, users.password_reset_required, " +
+			"organization_members.deactivated_at, organization_members.created_at AS joined_at").
+		Joins("JOIN users ON users.id = organization_members.user_id AND users.deleted_at IS NULL")
+}
+
+// Reassign hands a clinician's caseload in the organization over to another
+// clinician in one transaction: the patients they are primary clinician for,
+// their care team places and their upcoming appointments. The new clinician
+// joins the care team of every patient whose appointments they take over.
+func (r *adminRepository) Reassign(
+	orgID, fromID, toID, actorID uuid.UUID,
+	now time.Time,
+) (*entity.ReassignmentResult, error) {
+	var result entity.ReassignmentResult
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		patients := tx.Table("patients").
+			Where("organization_id = ? AND primary_clinician_id = ?", orgID, fromID).
+			Update("primary_clinician_id", toID)
+		if patients.Error != nil {
+			return patients.Error
+		}
+		result.Patients = patients.RowsAffected
+
+		grants := tx.Exec(
+			`INSERT INTO patient_care_team_members (organization_id, patient_id, user_id, granted_by)
+			SELECT m.organization_id, m.patient_id, ?::uuid, ?::uuid
+			FROM patient_care_team_members m
+			JOIN patients p ON p.id = m.patient_id
+			WHERE m.organization_id = ? AND m.user_id = ? AND p.primary_clinician_id IS DISTINCT FROM ?
+			ON CONFLICT DO NOTHING`,
+			toID, actorID, orgID, fromID, toID,
+		)
+		if grants.Error != nil {
+			return grants.Error
+		}
+		result.CareTeamEntries = grants.RowsAffected
+
+		err := tx.Exec(
+			"DELETE FROM patient_care_team_members WHERE organization_id = ? AND user_id = ?",
+			orgID, fromID,
+		).Error
+		if err != nil {
+			return err
+		}
+
+		appointments := tx.Table("appointments").
+			Where("organization_id = ? AND clinician_id = ?", orgID, fromID).
+			Where("start_time > ? AND status != ? AND deleted_at IS NULL", now, "cancelled").
+			Updates(map[string]interface{}{"clinician_id": toID, "updated_at": now})
+		if appointments.Error != nil {
+			return appointments.Error
+		}
+		result.Appointments = appointments.RowsAffected
+
+		access := tx.Exec(
+			`INSERT INTO patient_care_team_members (organization_id, patient_id, user_id, granted_by)
+			SELECT DISTINCT a.organization_id, a.patient_id, ?::uuid, ?::uuid
+			FROM appointments a
+			JOIN patients p ON p.id = a.patient_id
+			WHERE a.organization_id = ? AND a.clinician_id = ? AND a.start_time > ?
+				AND a.status != 'cancelled' AND a.deleted_at IS NULL
+				AND p.primary_clinician_id IS DISTINCT FROM ?
+			ON CONFLICT DO NOTHING`,
+			toID, actorID, orgID, toID, now, toID,
+		)
+		if access.Error != nil {
+			return access.Error
+		}
+		result.CareTeamGrants = access.RowsAffected
+
+		return nil
+	})
+	if err != nil {
+		r.log.Error(
+			"Failed to reassign clinician",
+			zap.Error(err),
+			zap.String("org_id", orgID.String()),
+			zap.String("from", fromID.String()),
+			zap.String("to", toID.String()),
+		)
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdentityRepository interface {
+	Create(identity *entity.Identity) error
+	FindBySubject(issuer, subject string) (*entity.Identity, error)
+	TouchLogin(id uuid.UUID, at time.Time) error
+	CreateLoginRequest(request *entity.SSOLoginRequest) error
+	ConsumeLoginRequest(stateHash string) (*entity.SSOLoginRequest, error)
+	DeleteExpiredLoginRequests(before time.Time) error
+	CreateLinkRequest(request *entity.SSOLinkRequest) error
+	ConsumeLinkRequest(tokenHash string) (*entity.SSOLinkRequest, error)
+	DeleteExpiredLinkRequests(before time.Time) error
+}
+
+type identityRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewIdentityRepository(db *gorm.DB, log logger.Logger) IdentityRepository {
+	return &identityRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *identityRepository) Create(identity *entity.Identity) error {
+	if err := r.db.Create(identity).Error; err != nil {
+		r.log.Error("Failed to create identity", zap.Error(err), zap.String("user_id", identity.UserID.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *identityRepository) FindBySubject(issuer, subject string) (*entity.Identity, error) {
+	var identity entity.Identity
+	if err := r.db.First(&identity, "issuer = ? AND subject = ?", issuer, subject).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find identity", zap.Error(err), zap.String("issuer", issuer))
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *identityRepository) TouchLogin(id uuid.UUID, at time.Time) error {
+	err := r.db.Model(&entity.Identity{}).Where("id = ?", id).Update("last_login_at", at).Error
+	if err != nil {
+		r.log.Error("Failed to update identity last login", zap.Error(err), zap.String("id", id.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *identityRepository) CreateLoginRequest(request *entity.SSOLoginRequest) error {
+	if err := r.db.Create(request).Error; err != nil {
+		r.log.Error("Failed to create SSO login request", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ConsumeLoginRequest deletes and returns the request in one statement so a
+// state value can only ever complete one login
+func (r *identityRepository) ConsumeLoginRequest(stateHash string) (*entity.SSOLoginRequest, error) {
+	var requests []entity.SSOLoginRequest
+	err := r.db.Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&requests).Error
+	if err != nil {
+		r.log.Error("Failed to consume SSO login request", zap.Error(err))
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &requests[0], nil
+}
+
+func (r *identityRepository) DeleteExpiredLoginRequests(before time.Time) error {
+	if err := r.db.Where("expires_at < ?", before).Delete(&entity.SSOLoginRequest{}).Error; err != nil {
+		r.log.Error("Failed to delete expired SSO login requests", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *identityRepository) CreateLinkRequest(request *entity.SSOLinkRequest) error {
+	if err := r.db.Create(request).Error; err != nil {
+		r.log.Error("Failed to create SSO link request", zap.Error(err), zap.String("user_id", request.UserID.String()))
+		return err
+	}
+	return nil
+}
+
+// ConsumeLinkRequest deletes and returns the request in one statement so a
+// link token can only ever be confirmed once
+func (r *identityRepository) ConsumeLinkRequest(tokenHash string) (*entity.SSOLinkRequest, error) {
+	var requests []entity.SSOLinkRequest
+	err := r.db.Clauses(clause.Returning{}).
+		Where("token_hash = ?", tokenHash).
+		Delete(&requests).Error
+	if err != nil {
+		r.log.Error("Failed to consume SSO link request", zap.Error(err))
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &requests[0], nil
+}
+
+func (r *identityRepository) DeleteExpiredLinkRequests(before time.Time) error {
+	if err := r.db.Where("expires_at < ?", before).Delete(&entity.SSOLinkRequest{}).Error; err != nil {
+		r.log.Error("Failed to delete expired SSO link requests", zap.Error(err))
+		return err
+	}
+	return nil
+}
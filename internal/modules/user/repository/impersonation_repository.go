@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ImpersonationRepository interface {
+	Create(impersonation *entity.Impersonation) error
+	FindByID(id uuid.UUID) (*entity.Impersonation, error)
+	End(id uuid.UUID, endedAt time.Time) (bool, error)
+}
+
+type impersonationRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewImpersonationRepository(db *gorm.DB, log logger.Logger) ImpersonationRepository {
+	return &impersonationRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *impersonationRepository) Create(impersonation *entity.Impersonation) error {
+	if err := r.db.Create(impersonation).Error; err != nil {
+		r.log.Error("Failed to create impersonation", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *impersonationRepository) FindByID(id uuid.UUID) (*entity.Impersonation, error) {
+	var impersonation entity.Impersonation
+	if err := r.db.Where("id = ?", id).First(&impersonation).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find impersonation", zap.Error(err), zap.String("id", id.String()))
+		}
+		return nil, err
+	}
+	return &impersonation, nil
+}
+
+// End marks the impersonation as ended. It returns false when it had
+// already ended.
+func (r *impersonationRepository) End(id uuid.UUID, endedAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.Impersonation{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Update("ended_at", endedAt)
+	if result.Error != nil {
+		r.log.Error("Failed to end impersonation", zap.Error(result.Error), zap.String("id", id.String()))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginIPRepository interface {
+	Count(userID uuid.UUID) (int64, error)
+	Record(userID uuid.UUID, ipAddress string, seenAt time.Time) (bool, error)
+}
+
+type loginIPRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewLoginIPRepository(db *gorm.DB, log logger.Logger) LoginIPRepository {
+	return &loginIPRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *loginIPRepository) Count(userID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&entity.UserLoginIP{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		r.log.Error("Failed to count login IPs", zap.Error(err), zap.String("user_id", userID.String()))
+		return 0, err
+	}
+	return count, nil
+}
+
+// Record stores a sign-in from ipAddress. It returns true when the user had
+// never signed in from that address before.
+func (r *loginIPRepository) Record(userID uuid.UUID, ipAddress string, seenAt time.Time) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.UserLoginIP{
+		UserID:      userID,
+		IPAddress:   ipAddress,
+		FirstSeenAt: seenAt,
+		LastSeenAt:  seenAt,
+	})
+	if result.Error != nil {
+		r.log.Error("Failed to record login IP", zap.Error(result.Error), zap.String("user_id", userID.String()))
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	err := r.db.Model(&entity.UserLoginIP{}).
+		Where("user_id = ? AND ip_address = ?", userID, ipAddress).
+		Update("last_seen_at", seenAt).Error
+	if err != nil {
+		r.log.Error("Failed to update login IP", zap.Error(err), zap.String("user_id", userID.String()))
+		return false, err
+	}
+	return false, nil
+}
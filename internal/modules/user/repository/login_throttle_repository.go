@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type LoginThrottleRepository interface {
+	FindByKeys(keys ...string) ([]entity.LoginThrottle, error)
+	RecordFailure(key string, now, windowStart time.Time) (*entity.LoginThrottle, error)
+	Lock(key string, until time.Time) (bool, error)
+	Delete(key string) error
+	DeleteStale(before time.Time) error
+}
+
+type loginThrottleRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewLoginThrottleRepository(db *gorm.DB, log logger.Logger) LoginThrottleRepository {
+	return &loginThrottleRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *loginThrottleRepository) FindByKeys(keys ...string) ([]entity.LoginThrottle, error) {
+	var throttles []entity.LoginThrottle
+	if err := r.db.Where("key IN ?", keys).Find(&throttles).Error; err != nil {
+		r.log.Error("Failed to find login throttles", zap.Error(err))
+		return nil, err
+	}
+	return throttles, nil
+}
+
+// RecordFailure counts a failed login against key in a single upsert so
+// concurrent attempts cannot lose increments. The count starts over when the
+// previous failure is older than windowStart or an earlier lockout expired.
+func (r *loginThrottleRepository) RecordFailure(
+	key string,
+	now, windowStart time.Time,
+) (*entity.LoginThrottle, error) {
+	var throttle entity.LoginThrottle
+	err := r.db.Raw(`
+		INSERT INTO login_throttles (key, failures, last_failure_at)
+		VALUES (@key, 1, @now)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN login_throttles.last_failure_at < @window_start
+					OR login_throttles.locked_until <= @now THEN 1
+				ELSE login_throttles.failures + 1
+			END,
+			locked_until = CASE
+				WHEN login_throttles.locked_until <= @now THEN NULL
+				ELSE login_throttles.locked_until
+			END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING key, failures, last_failure_at, locked_until`,
+		map[string]interface{}{"key": key, "now": now, "window_start": windowStart},
+	).Scan(&throttle).Error
+	if err != nil {
+		r.log.Error("Failed to record login failure", zap.Error(err))
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// Lock locks key until the given time. It returns false when the key was
+// already locked, so a lockout is only reported once.
+func (r *loginThrottleRepository) Lock(key string, until time.Time) (bool, error) {
+	result := r.db.Model(&entity.LoginThrottle{}).
+		Where("key = ? AND (locked_until IS NULL OR locked_until <= ?)", key, time.Now()).
+		Update("locked_until", until)
+	if result.Error != nil {
+		r.log.Error("Failed to lock login throttle", zap.Error(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *loginThrottleRepository) Delete(key string) error {
+	if err := r.db.Where("key = ?", key).Delete(&entity.LoginThrottle{}).Error; err != nil {
+		r.log.Error("Failed to delete login throttle", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteStale removes keys whose last failure is older than before and that
+// are not locked anymore
+func (r *loginThrottleRepository) DeleteStale(before time.Time) error {
+	err := r.db.
+		Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until <= ?)", before, time.Now()).
+		Delete(&entity.LoginThrottle{}).Error
+	if err != nil {
+		r.log.Error("Failed to delete stale login throttles", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type MFARecoveryCodeRepository interface {
+	ReplaceForUser(userID uuid.UUID, codes []entity.MFARecoveryCode) error
+	Consume(userID uuid.UUID, codeHash string) (bool, error)
+	CountUnused(userID uuid.UUID) (int64, error)
+	DeleteByUser(userID uuid.UUID) error
+}
+
+type mfaRecoveryCodeRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewMFARecoveryCodeRepository(db *gorm.DB, log logger.Logger) MFARecoveryCodeRepository {
+	return &mfaRecoveryCodeRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// ReplaceForUser discards any previous codes and stores a fresh set
+func (r *mfaRecoveryCodeRepository) ReplaceForUser(userID uuid.UUID, codes []entity.MFARecoveryCode) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		r.log.Error("Failed to replace MFA recovery codes", zap.Error(err), zap.String("user_id", userID.String()))
+		return err
+	}
+	return nil
+}
+
+// Consume marks a matching unused code as used. It returns false when no
+// unused code matched.
+func (r *mfaRecoveryCodeRepository) Consume(userID uuid.UUID, codeHash string) (bool, error) {
+	result := r.db.Model(&entity.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		r.log.Error("Failed to consume MFA recovery code", zap.Error(result.Error), zap.String("user_id", userID.String()))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *mfaRecoveryCodeRepository) CountUnused(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.MFARecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	if err != nil {
+		r.log.Error("Failed to count MFA recovery codes", zap.Error(err), zap.String("user_id", userID.String()))
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *mfaRecoveryCodeRepository) DeleteByUser(userID uuid.UUID) error {
+	if err := r.db.Where("user_id = ?", userID).Delete(&entity.MFARecoveryCode{}).Error; err != nil {
+		r.log.Error("Failed to delete MFA recovery codes", zap.Error(err), zap.String("user_id", userID.String()))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type PasswordHistoryRepository interface {
+	ListRecent(userID uuid.UUID, limit int) ([]entity.PasswordHistory, error)
+	Add(userID uuid.UUID, passwordHash string, keep int) error
+}
+
+type passwordHistoryRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewPasswordHistoryRepository(db *gorm.DB, log logger.Logger) PasswordHistoryRepository {
+	return &passwordHistoryRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *passwordHistoryRepository) ListRecent(userID uuid.UUID, limit int) ([]entity.PasswordHistory, error) {
+	var history []entity.PasswordHistory
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&history).Error
+	if err != nil {
+		r.log.Error("Failed to list password history", zap.Error(err), zap.String("user_id", userID.String()))
+		return nil, err
+	}
+	return history, nil
+}
+
+// Add stores a previous password hash and drops all but the newest keep entries
+func (r *passwordHistoryRepository) Add(userID uuid.UUID, passwordHash string, keep int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&entity.PasswordHistory{
+			UserID:       userID,
+			PasswordHash: passwordHash,
+		}).Error; err != nil {
+			r.log.Error("Failed to add password history", zap.Error(err), zap.String("user_id", userID.String()))
+			return err
+		}
+
+		err := tx.Where("user_id = ? AND id NOT IN (?)", userID,
+			tx.Model(&entity.PasswordHistory{}).
+				Select("id").
+				Where("user_id = ?", userID).
+				Order("created_at DESC").
+				Limit(keep),
+		).Delete(&entity.PasswordHistory{}).Error
+		if err != nil {
+			r.log.Error("Failed to prune password history", zap.Error(err), zap.String("user_id", userID.String()))
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository interface {
+	Create(token *entity.RefreshToken) error
+	FindByID(id uuid.UUID) (*entity.RefreshToken, error)
+	Rotate(oldID uuid.UUID, next *entity.RefreshToken) (bool, error)
+	RevokeFamily(familyID uuid.UUID) error
+	ReplaceFamilyToken(next *entity.RefreshToken) error
+}
+
+type refreshTokenRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewRefreshTokenRepository(db *gorm.DB, log logger.Logger) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *refreshTokenRepository) Create(token *entity.RefreshToken) error {
+	if err := r.db.Create(token).Error; err != nil {
+		r.log.Error("Failed to create refresh token", zap.Error(err), zap.String("user_id", token.UserID.String()))
+		return err
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) FindByID(id uuid.UUID) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	if err := r.db.First(&token, "id = ?", id).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find refresh token", zap.Error(err), zap.String("id", id.String()))
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate marks the old token as replaced and stores its successor atomically.
+// It returns false when the old token had already been revoked or rotated,
+// which means another request used it first.
+func (r *refreshTokenRepository) Rotate(oldID uuid.UUID, next *entity.RefreshToken) (bool, error) {
+	rotated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", oldID).
+			Updates(map[string]interface{}{
+				"revoked_at":  time.Now(),
+				"replaced_by": next.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		rotated = true
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Failed to rotate refresh token", zap.Error(err), zap.String("id", oldID.String()))
+		return false, err
+	}
+	return rotated, nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(familyID uuid.UUID) error {
+	err := r.db.Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		r.log.Error("Failed to revoke refresh token family", zap.Error(err), zap.String("family_id", familyID.String()))
+		return err
+	}
+	return nil
+}
+
+// ReplaceFamilyToken revokes the family's outstanding refresh token and
+// stores its successor, used when a session is re-issued outside the
+// normal refresh flow
+func (r *refreshTokenRepository) ReplaceFamilyToken(next *entity.RefreshToken) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", next.FamilyID).
+			Updates(map[string]interface{}{
+				"revoked_at":  time.Now(),
+				"replaced_by": next.ID,
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(next).Error
+	})
+	if err != nil {
+		r.log.Error("Failed to replace refresh token", zap.Error(err), zap.String("family_id", next.FamilyID.String()))
+		return err
+	}
+	return nil
+}
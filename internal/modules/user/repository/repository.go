@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
@@ -10,13 +11,25 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrInvitationUnavailable is returned when an invitation was accepted or
+// revoked concurrently with an accept request
+var ErrInvitationUnavailable = errors.New("invitation is no longer available")
+
 type UserRepository interface {
 	Create(user *entity.User) error
 	CreateWithOrganization(user *entity.User, organization *entity.Organization) error
+	CreateFromInvitation(user *entity.User, invitationID, organizationID uuid.UUID, role string) error
+	JoinFromInvitation(userID, invitationID, organizationID uuid.UUID, role string) error
+	CreateWithMembership(user *entity.User, organizationID uuid.UUID, role string) error
+	AddMembership(userID, organizationID uuid.UUID, role string) error
 	FindByEmail(email string) (*entity.User, error)
 	GetByID(id uuid.UUID) (*entity.User, error)
 	Update(user *entity.User) error
 	CountUsers() (int64, error)
+	ClaimMFAStep(userID uuid.UUID, step int64) (bool, error)
+	IsMFARequired(userID uuid.UUID) (bool, error)
+	PasswordMaxAgeDays(userID uuid.UUID) (int, error)
+	FindMembership(userID, organizationID uuid.UUID) (*entity.OrganizationMember, error)
 }
 
 type userRepository struct {
@@ -68,6 +81,84 @@ func (r *userRepository) CreateWithOrganization(user *entity.User, organization
 	})
 }
 
+// CreateFromInvitation consumes the invitation and creates the user as a
+// member of the inviting organization in one transaction
+func (r *userRepository) CreateFromInvitation(
+	user *entity.User,
+	invitationID, organizationID uuid.UUID,
+	role string,
+) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := r.acceptInvitation(tx, invitationID); err != nil {
+			return err
+		}
+
+		if err := tx.Create(user).Error; err != nil {
+			r.log.Error("Failed to create user", zap.Error(err), zap.String("email", user.Email))
+			return err
+		}
+
+		return r.addMember(tx, user.ID, organizationID, role)
+	})
+}
+
+// JoinFromInvitation consumes the invitation and adds an existing user to
+// the inviting organization in one transaction
+func (r *userRepository) JoinFromInvitation(userID, invitationID, organizationID uuid.UUID, role string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := r.acceptInvitation(tx, invitationID); err != nil {
+			return err
+		}
+
+		return r.addMember(tx, userID, organizationID, role)
+	})
+}
+
+// CreateWithMembership creates a user directly inside an existing
+// organization, as when an SSO login provisions a new staff member
+func (r *userRepository) CreateWithMembership(user *entity.User, organizationID uuid.UUID, role string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			r.log.Error("Failed to create user", zap.Error(err), zap.String("email", user.Email))
+			return err
+		}
+
+		return r.addMember(tx, user.ID, organizationID, role)
+	})
+}
+
+func (r *userRepository) AddMembership(userID, organizationID uuid.UUID, role string) error {
+	return r.addMember(r.db, userID, organizationID, role)
+}
+
+func (r *userRepository) acceptInvitation(tx *gorm.DB, invitationID uuid.UUID) error {
+	result := tx.Table("organization_invitations").
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitationID).
+		Update("accepted_at", time.Now())
+	if result.Error != nil {
+		r.log.Error("Failed to accept invitation", zap.Error(result.Error), zap.String("id", invitationID.String()))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvitationUnavailable
+	}
+	return nil
+}
+
+func (r *userRepository) addMember(tx *gorm.DB, userID, organizationID uuid.UUID, role string) error {
+	member := entity.OrganizationMember{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Role:           role,
+	}
+
+	if err := tx.Create(&member).Error; err != nil {
+		r.log.Error("Failed to add user to organization", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
 func (r *userRepository) FindByEmail(email string) (*entity.User, error) {
 	var user entity.User
 	err := r.db.Where("email = ?", email).First(&user).Error
@@ -109,3 +200,68 @@ func (r *userRepository) CountUsers() (int64, error) {
 	}
 	return count, nil
 }
+
+// ClaimMFAStep records a TOTP time step as used. It returns false when the
+// step (or a later one) was already consumed, so a code cannot be replayed.
+func (r *userRepository) ClaimMFAStep(userID uuid.UUID, step int64) (bool, error) {
+	result := r.db.Model(&entity.User{}).
+		Where("id = ? AND mfa_last_used_step < ?", userID, step).
+		Update("mfa_last_used_step", step)
+	if result.Error != nil {
+		r.log.Error("Failed to record MFA step", zap.Error(result.Error), zap.String("user_id", userID.String()))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// IsMFARequired reports whether any organization the user belongs to enforces MFA
+func (r *userRepository) IsMFARequired(userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.Organization{}).
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ? AND organizations.require_mfa = ?", userID, true).
+		Count(&count).Error
+	if err != nil {
+		r.log.Error("Failed to check MFA requirement", zap.Error(err), zap.String("user_id", userID.String()))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// PasswordMaxAgeDays returns the strictest password age limit among the
+// user's organizations, or 0 when none of them expires passwords
+func (r *userRepository) PasswordMaxAgeDays(userID uuid.UUID) (int, error) {
+	var days int
+	err := r.db.Model(&entity.Organization{}).
+		Select("COALESCE(MIN(organizations.password_max_age_days), 0)").
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ? AND organizations.password_max_age_days > 0", userID).
+		Scan(&days).Error
+	if err != nil {
+		r.log.Error("Failed to get password max age", zap.Error(err), zap.String("user_id", userID.String()))
+		return 0, err
+	}
+	return days, nil
+}
+
+// FindMembership returns the user's membership in the organization, or their
+// default (oldest active) membership when organizationID is uuid.Nil. A
+// membership in a given organization is returned even when deactivated.
+func (r *userRepository) FindMembership(userID, organizationID uuid.UUID) (*entity.OrganizationMember, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if organizationID != uuid.Nil {
+		query = query.Where("organization_id = ?", organizationID)
+	} else {
+		query = query.Where("deactivated_at IS NULL")
+	}
+
+	var member entity.OrganizationMember
+	err := query.Order("created_at ASC").First(&member).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find membership", zap.Error(err), zap.String("user_id", userID.String()))
+		}
+		return nil, err
+	}
+	return &member, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserTokenRepository interface {
+	Create(token *entity.UserToken) error
+	FindActive(tokenHash, purpose string) (*entity.UserToken, error)
+	Consume(tokenHash, purpose string) (*entity.UserToken, error)
+}
+
+type userTokenRepository struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewUserTokenRepository(db *gorm.DB, log logger.Logger) UserTokenRepository {
+	return &userTokenRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Create stores a new token and invalidates the user's earlier unused tokens
+// for the same purpose, so only the most recent email works
+func (r *userTokenRepository) Create(token *entity.UserToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("used_at", time.Now()).Error
+		if err != nil {
+			r.log.Error("Failed to invalidate previous user tokens", zap.Error(err))
+			return err
+		}
+
+		if err := tx.Create(token).Error; err != nil {
+			r.log.Error("Failed to create user token", zap.Error(err), zap.String("user_id", token.UserID.String()))
+			return err
+		}
+		return nil
+	})
+}
+
+// FindActive returns an unused, unexpired token without consuming it
+func (r *userTokenRepository) FindActive(tokenHash, purpose string) (*entity.UserToken, error) {
+	var token entity.UserToken
+	err := r.db.
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, time.Now()).
+		First(&token).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to find user token", zap.Error(err))
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Consume marks an unused, unexpired token as used and returns it. The
+// update is a single statement so concurrent requests cannot both succeed.
+func (r *userTokenRepository) Consume(tokenHash, purpose string) (*entity.UserToken, error) {
+	var tokens []entity.UserToken
+	now := time.Now()
+	err := r.db.Model(&tokens).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, now).
+		Update("used_at", now).Error
+	if err != nil {
+		r.log.Error("Failed to consume user token", zap.Error(err))
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &tokens[0], nil
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	sessionService "github.com/sahabatharianmu/OpenMind/internal/modules/session/service"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/mail"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AccountService handles the account recovery and verification flows that
+// work by emailing the user a single-use link
+type AccountService interface {
+	ForgotPassword(email string) error
+	ResetPassword(token, password string) error
+	SendVerification(userID uuid.UUID) error
+	ResendVerification(email string) error
+	VerifyEmail(token string) error
+}
+
+type accountService struct {
+	repo                    repository.UserRepository
+	tokenRepo               repository.UserTokenRepository
+	sessionSvc              sessionService.SessionService
+	policySvc               PasswordPolicyService
+	mailer                  mail.Sender
+	baseURL                 string
+	passwordResetExpiry     time.Duration
+	emailVerificationExpiry time.Duration
+	log                     logger.Logger
+}
+
+func NewAccountService(
+	repo repository.UserRepository,
+	tokenRepo repository.UserTokenRepository,
+	sessionSvc sessionService.SessionService,
+	policySvc PasswordPolicyService,
+	mailer mail.Sender,
+	baseURL string,
+	passwordResetExpiry time.Duration,
+	emailVerificationExpiry time.Duration,
+	log logger.Logger,
+) AccountService {
+	return &accountService{
+		repo:                    repo,
+		tokenRepo:               tokenRepo,
+		sessionSvc:              sessionSvc,
+		policySvc:               policySvc,
+		mailer:                  mailer,
+		baseURL:                 strings.TrimRight(baseURL, "/"),
+		passwordResetExpiry:     passwordResetExpiry,
+		emailVerificationExpiry: emailVerificationExpiry,
+		log:                     log,
+	}
+}
+
+// ForgotPassword emails a reset link when the address belongs to an account.
+// It reports success either way so the endpoint cannot be used to find out
+// which emails are registered.
+func (s *accountService) ForgotPassword(email string) error {
+	user, err := s.repo.FindByEmail(email)
+	if err != nil {
+		s.log.Info("Password reset requested for unknown email", zap.String("email", email))
+		return nil
+	}
+
+	// Accounts provisioned through single sign-on have no password to reset
+	if user.PasswordHash == "" {
+		s.log.Info("Password reset requested for SSO-only account", zap.String("user_id", user.ID.String()))
+		return nil
+	}
+
+	token, expiresAt, err := s.issueToken(user.ID, entity.TokenPurposePasswordReset, s.passwordResetExpiry)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/auth/reset-password?token=%s", s.baseURL, url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Hi %s,\r\n\r\n"+
+			"We received a request to reset the password for your OpenMind account.\r\n\r\n"+
+			"Choose a new password here:\r\n%s\r\n\r\n"+
+			"This link can be used once and expires at %s. "+
+			"If you did not ask to reset your password you can ignore this email.\r\n",
+		user.FullName,
+		link,
+		expiresAt.UTC().Format("January 2, 2006 15:04 MST"),
+	)
+
+	if err := s.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Reset your OpenMind password",
+		Body:    body,
+	}); err != nil {
+		s.log.Error("Failed to send password reset email", zap.Error(err), zap.String("user_id", user.ID.String()))
+		return response.ErrInternalServerError
+	}
+
+	s.log.Info("Password reset email sent", zap.String("user_id", user.ID.String()))
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token. Every session is
+// revoked afterwards since the old password may have been compromised.
+func (s *accountService) ResetPassword(token, password string) error {
+	invalid := response.NewBadRequest("Reset link is invalid or has expired")
+
+	pending, err := s.tokenRepo.FindActive(crypto.HashToken(token), entity.TokenPurposePasswordReset)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invalid
+		}
+		return response.ErrInternalServerError
+	}
+
+	user, err := s.repo.GetByID(pending.UserID)
+	if err != nil {
+		return invalid
+	}
+
+	// Check the policy before using up the link so a rejected password can
+	// be corrected and resubmitted
+	if err := s.policySvc.Check(user, uuid.Nil, password); err != nil {
+		return err
+	}
+
+	if _, err := s.consumeToken(token, entity.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	// Receiving the reset email proves the user controls the address
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.policySvc.Set(user, password); err != nil {
+		return err
+	}
+
+	if _, err := s.sessionSvc.RevokeAll(user.ID); err != nil {
+		s.log.Error("ResetPassword failed to revoke sessions", zap.Error(err), zap.String("user_id", user.ID.String()))
+	}
+
+	s.log.Info("Password reset successfully", zap.String("user_id", user.ID.String()))
+	return nil
+}
+
+// SendVerification emails a new verification link to a user whose address
+// is not verified yet
+func (s *accountService) SendVerification(userID uuid.UUID) error {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return response.ErrNotFound
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return response.NewConflict("Email address is already verified")
+	}
+
+	token, expiresAt, err := s.issueToken(user.ID, entity.TokenPurposeEmailVerification, s.emailVerificationExpiry)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/auth/verify-email?token=%s", s.baseURL, url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Hi %s,\r\n\r\n"+
+			"Please confirm that %s is your email address for OpenMind.\r\n\r\n"+
+			"Verify your email here:\r\n%s\r\n\r\n"+
+			"This link expires on %s. If you did not create an OpenMind account you can ignore this email.\r\n",
+		user.FullName,
+		user.Email,
+		link,
+		expiresAt.Format("January 2, 2006"),
+	)
+
+	if err := s.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Verify your OpenMind email address",
+		Body:    body,
+	}); err != nil {
+		s.log.Error("Failed to send verification email", zap.Error(err), zap.String("user_id", user.ID.String()))
+		return response.ErrInternalServerError
+	}
+
+	s.log.Info("Verification email sent", zap.String("user_id", user.ID.String()))
+	return nil
+}
+
+// ResendVerification is the unauthenticated variant of SendVerification for
+// users who cannot log in until they verify. Like ForgotPassword it never
+// reveals whether the email is registered.
+func (s *accountService) ResendVerification(email string) error {
+	user, err := s.repo.FindByEmail(email)
+	if err != nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	return s.SendVerification(user.ID)
+}
+
+func (s *accountService) VerifyEmail(token string) error {
+	stored, err := s.consumeToken(token, entity.TokenPurposeEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.repo.GetByID(stored.UserID)
+	if err != nil {
+		return response.NewBadRequest("Verification link is invalid or has expired")
+	}
+
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := s.repo.Update(user); err != nil {
+			s.log.Error("VerifyEmail failed: update error", zap.Error(err))
+			return response.ErrInternalServerError
+		}
+	}
+
+	s.log.Info("Email verified", zap.String("user_id", user.ID.String()))
+	return nil
+}
+
+func (s *accountService) issueToken(
+	userID uuid.UUID,
+	purpose string,
+	ttl time.Duration,
+) (string, time.Time, error) {
+	token, err := crypto.GenerateToken()
+	if err != nil {
+		s.log.Error("Failed to generate user token", zap.Error(err))
+		return "", time.Time{}, response.ErrInternalServerError
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	if err := s.tokenRepo.Create(&entity.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: crypto.HashToken(token),
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return "", time.Time{}, response.ErrInternalServerError
+	}
+
+	return token, expiresAt, nil
+}
+
+func (s *accountService) consumeToken(token, purpose string) (*entity.UserToken, error) {
+	stored, err := s.tokenRepo.Consume(crypto.HashToken(token), purpose)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.Warn("User token rejected: invalid, used or expired", zap.String("purpose", purpose))
+			if purpose == entity.TokenPurposePasswordReset {
+				return nil, response.NewBadRequest("Reset link is invalid or has expired")
+			}
+			return nil, response.NewBadRequest("Verification link is invalid or has expired")
+		}
+		return nil, response.ErrInternalServerError
+	}
+	return stored, nil
+}
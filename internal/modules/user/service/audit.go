@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	auditLogService "github.com/sahabatharianmu/OpenMind/internal/modules/audit_log/service"
+	organizationRepository "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	auditResourceUser      = "user"
+	auditResourceIPAddress = "ip_address"
+)
+
+// recordUserAudit writes a security event about the target user. With
+// uuid.Nil as orgID the event goes to every organization the user belongs to.
+// Failures are logged rather than returned, as for other audit entries.
+func recordUserAudit(
+	auditLogSvc auditLogService.AuditLogService,
+	orgRepo organizationRepository.OrganizationRepository,
+	log logger.Logger,
+	targetID, actorID uuid.UUID,
+	action string,
+	details map[string]interface{},
+	ipAddress, userAgent string,
+	orgID uuid.UUID,
+) {
+	orgIDs := []uuid.UUID{orgID}
+	if orgID == uuid.Nil {
+		memberships, err := orgRepo.ListMemberships(targetID)
+		if err != nil {
+			return
+		}
+		orgIDs = orgIDs[:0]
+		for _, m := range memberships {
+			orgIDs = append(orgIDs, m.OrganizationID)
+		}
+	}
+
+	for _, id := range orgIDs {
+		err := auditLogSvc.Log(
+			context.Background(),
+			action,
+			auditResourceUser,
+			&targetID,
+			actorID,
+			id,
+			details,
+			&ipAddress,
+			&userAgent,
+		)
+		if err != nil {
+			log.Error("Failed to write user audit entry", zap.Error(err), zap.String("action", action))
+		}
+	}
+}
+
+// recordSystemAudit writes a security event that concerns no known user, such
+// as the lockout of a client IP. It belongs to no organization, so it carries
+// nil organization and user IDs.
+func recordSystemAudit(
+	auditLogSvc auditLogService.AuditLogService,
+	log logger.Logger,
+	action, resourceType string,
+	details map[string]interface{},
+	ipAddress, userAgent string,
+) {
+	err := auditLogSvc.Log(
+		context.Background(),
+		action,
+		resourceType,
+		nil,
+		uuid.Nil,
+		uuid.Nil,
+		details,
+		&ipAddress,
+		&userAgent,
+	)
+	if err != nil {
+		log.Error("Failed to write system audit entry", zap.Error(err), zap.String("action", action))
+	}
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	auditLogService "github.com/sahabatharianmu/OpenMind/internal/modules/audit_log/service"
+	organizationEntity "github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	organizationRepository "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	organizationService "github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	sessionService "github.com/sahabatharianmu/OpenMind/internal/modules/session/service"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"github.com/sahabatharianmu/OpenMind/pkg/security"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Audit actions recorded for impersonation
+const (
+	AuditActionImpersonationStarted = "impersonation_started"
+	AuditActionImpersonationEnded   = "impersonation_ended"
+)
+
+// ErrImpersonationEnded is returned for an impersonation token whose
+// impersonation has ended, expired, lost its administrator's permission or
+// outlived the administrator's session
+var ErrImpersonationEnded = response.NewUnauthorized("Impersonation has ended")
+
+// ImpersonationService lets administrators "view as" one of their members to
+// reproduce problems the member reports. The administrator must hold every
+// permission the member has, so impersonation never widens their access. An
+// impersonation is tied to the administrator's session and ends with it.
+type ImpersonationService interface {
+	Start(
+		orgID, impersonatorID, adminSessionID, userID uuid.UUID,
+		reason string,
+		allowWrites bool,
+		ipAddress, userAgent string,
+	) (*dto.ImpersonationResponse, error)
+	Validate(id uuid.UUID) (*entity.Impersonation, error)
+	End(id uuid.UUID, ipAddress, userAgent string) error
+}
+
+type impersonationService struct {
+	repo        repository.ImpersonationRepository
+	userRepo    repository.UserRepository
+	orgRepo     organizationRepository.OrganizationRepository
+	roleSvc     organizationService.RoleService
+	sessionSvc  sessionService.SessionService
+	auditLogSvc auditLogService.AuditLogService
+	jwt         *security.JWTService
+	duration    time.Duration
+	log         logger.Logger
+}
+
+func NewImpersonationService(
+	repo repository.ImpersonationRepository,
+	userRepo repository.UserRepository,
+	orgRepo organizationRepository.OrganizationRepository,
+	roleSvc organizationService.RoleService,
+	sessionSvc sessionService.SessionService,
+	auditLogSvc auditLogService.AuditLogService,
+	jwt *security.JWTService,
+	duration time.Duration,
+	log logger.Logger,
+) ImpersonationService {
+	return &impersonationService{
+		repo:        repo,
+		userRepo:    userRepo,
+		orgRepo:     orgRepo,
+		roleSvc:     roleSvc,
+		sessionSvc:  sessionSvc,
+		auditLogSvc: auditLogSvc,
+		jwt:         jwt,
+		duration:    duration,
+		log:         log,
+	}
+}
+
+func (s *impersonationService) Start(
+	orgID, impersonatorID, adminSessionID, userID uuid.UUID,
+	reason string,
+	allowWrites bool,
+	ipAddress, userAgent string,
+) (*dto.ImpersonationResponse, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, response.NewBadRequest("A reason is required to impersonate a user")
+	}
+	if impersonatorID == userID {
+		return nil, response.NewBadRequest("You cannot impersonate yourself")
+	}
+
+	admin, err := s.orgRepo.GetMember(orgID, impersonatorID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	target, err := s.orgRepo.GetMember(orgID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NewNotFound("User not found")
+		}
+		return nil, response.ErrInternalServerError
+	}
+	if target.DeactivatedAt != nil {
+		return nil, response.NewBadRequest("Cannot impersonate a deactivated user")
+	}
+	if target.Role == organizationEntity.RoleOwner {
+		return nil, response.NewForbidden("The organization owner cannot be impersonated")
+	}
+	if err := s.checkCovers(orgID, admin.Role, target.Role); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, response.NewNotFound("User not found")
+	}
+
+	impersonation := &entity.Impersonation{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		ImpersonatorID: impersonatorID,
+		AdminSessionID: &adminSessionID,
+		UserID:         userID,
+		Reason:         reason,
+		ReadOnly:       !allowWrites,
+		ExpiresAt:      time.Now().Add(s.duration),
+	}
+	if err := s.repo.Create(impersonation); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	token, err := s.jwt.GenerateImpersonationToken(
+		impersonation.ID,
+		impersonatorID,
+		userID,
+		orgID,
+		user.Email,
+		target.Role,
+		impersonation.ExpiresAt,
+	)
+	if err != nil {
+		s.log.Error("Impersonation failed: token generation error", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+
+	details := map[string]interface{}{
+		"impersonation_id": impersonation.ID,
+		"reason":           reason,
+		"read_only":        impersonation.ReadOnly,
+		"expires_at":       impersonation.ExpiresAt,
+	}
+	recordUserAudit(
+		s.auditLogSvc, s.orgRepo, s.log, userID, impersonatorID, AuditActionImpersonationStarted, details,
+		ipAddress, userAgent, orgID,
+	)
+
+	s.log.Warn(
+		"Impersonation started",
+		zap.String("org_id", orgID.String()),
+		zap.String("impersonator_id", impersonatorID.String()),
+		zap.String("user_id", userID.String()),
+		zap.Bool("read_only", impersonation.ReadOnly),
+	)
+
+	return &dto.ImpersonationResponse{
+		ID:          impersonation.ID,
+		AccessToken: token,
+		UserID:      userID,
+		ReadOnly:    impersonation.ReadOnly,
+		ExpiresAt:   impersonation.ExpiresAt,
+	}, nil
+}
+
+// Validate returns the impersonation an impersonation token belongs to while
+// it is still running, the administrator's session is still signed in and
+// they may still impersonate. Logging out, revoking the administrator's
+// sessions or resetting their password therefore ends it too.
+func (s *impersonationService) Validate(id uuid.UUID) (*entity.Impersonation, error) {
+	impersonation, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImpersonationEnded
+		}
+		return nil, response.ErrInternalServerError
+	}
+	if !impersonation.IsActive(time.Now()) || impersonation.AdminSessionID == nil {
+		return nil, ErrImpersonationEnded
+	}
+
+	signedIn, err := s.sessionSvc.IsActive(*impersonation.AdminSessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !signedIn {
+		return nil, ErrImpersonationEnded
+	}
+
+	admin, err := s.orgRepo.GetMember(impersonation.OrganizationID, impersonation.ImpersonatorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImpersonationEnded
+		}
+		return nil, response.ErrInternalServerError
+	}
+	if admin.DeactivatedAt != nil {
+		return nil, ErrImpersonationEnded
+	}
+
+	allowed, err := s.roleSvc.HasPermission(
+		impersonation.OrganizationID,
+		admin.Role,
+		organizationEntity.PermUsersImpersonate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrImpersonationEnded
+	}
+
+	return impersonation, nil
+}
+
+func (s *impersonationService) End(id uuid.UUID, ipAddress, userAgent string) error {
+	impersonation, err := s.repo.FindByID(id)
+	if err != nil {
+		return response.NewNotFound("Impersonation not found")
+	}
+
+	ended, err := s.repo.End(id, time.Now())
+	if err != nil {
+		return response.ErrInternalServerError
+	}
+	if !ended {
+		return nil
+	}
+
+	details := map[string]interface{}{"impersonation_id": impersonation.ID}
+	recordUserAudit(
+		s.auditLogSvc, s.orgRepo, s.log, impersonation.UserID, impersonation.ImpersonatorID,
+		AuditActionImpersonationEnded, details, ipAddress, userAgent, impersonation.OrganizationID,
+	)
+
+	s.log.Info(
+		"Impersonation ended",
+		zap.String("impersonator_id", impersonation.ImpersonatorID.String()),
+		zap.String("user_id", impersonation.UserID.String()),
+	)
+	return nil
+}
+
+// checkCovers rejects impersonating a member whose role grants a permission
+// the administrator's own role does not
+func (s *impersonationService) checkCovers(orgID uuid.UUID, adminRole, targetRole string) error {
+	covers, err := s.roleSvc.Covers(orgID, adminRole, targetRole)
+	if err != nil {
+		return err
+	}
+	if !covers {
+		return response.NewForbidden("You cannot impersonate a member with permissions you do not have")
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	auditLogService "github.com/sahabatharianmu/OpenMind/internal/modules/audit_log/service"
+	organizationRepository "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/mail"
+	"go.uber.org/zap"
+)
+
+// AuditActionLoginNewIP is recorded when a user signs in from an IP address
+// they have not used before
+const AuditActionLoginNewIP = "login_new_ip"
+
+// LoginAlertService remembers the IP addresses each user signs in from and,
+// when a sign-in comes from a new one, emails the user and writes an audit
+// entry. The very first sign-in of an account is not reported.
+type LoginAlertService interface {
+	RecordLogin(user *entity.User, ipAddress, userAgent string)
+}
+
+type loginAlertService struct {
+	repo        repository.LoginIPRepository
+	orgRepo     organizationRepository.OrganizationRepository
+	auditLogSvc auditLogService.AuditLogService
+	mailer      mail.Sender
+	log         logger.Logger
+}
+
+func NewLoginAlertService(
+	repo repository.LoginIPRepository,
+	orgRepo organizationRepository.OrganizationRepository,
+	auditLogSvc auditLogService.AuditLogService,
+	mailer mail.Sender,
+	log logger.Logger,
+) LoginAlertService {
+	return &loginAlertService{
+		repo:        repo,
+		orgRepo:     orgRepo,
+		auditLogSvc: auditLogSvc,
+		mailer:      mailer,
+		log:         log,
+	}
+}
+
+// RecordLogin never fails the sign-in; errors are only logged and the alert
+// email is sent asynchronously
+func (s *loginAlertService) RecordLogin(user *entity.User, ipAddress, userAgent string) {
+	if ipAddress == "" {
+		return
+	}
+
+	known, err := s.repo.Count(user.ID)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	isNew, err := s.repo.Record(user.ID, ipAddress, now)
+	if err != nil || !isNew || known == 0 {
+		return
+	}
+
+	s.log.Warn(
+		"Login from new IP address",
+		zap.String("user_id", user.ID.String()),
+		zap.String("ip", ipAddress),
+	)
+
+	details := map[string]interface{}{
+		"email":      user.Email,
+		"ip_address": ipAddress,
+	}
+	recordUserAudit(
+		s.auditLogSvc, s.orgRepo, s.log, user.ID, user.ID, AuditActionLoginNewIP, details, ipAddress, userAgent, uuid.Nil,
+	)
+
+	body := fmt.Sprintf(
+		"Hi %s,\r\n\r\n"+
+			"Your OpenMind account was just signed in to from an IP address "+
+			"it has not been used from before.\r\n\r\n"+
+			"Time: %s\r\nIP address: %s\r\nDevice: %s\r\n\r\n"+
+			"If this was you, no action is needed. If not, change your password right away "+
+			"and sign out of your other sessions from your account settings.\r\n",
+		user.FullName,
+		now.UTC().Format("January 2, 2006 15:04 MST"),
+		ipAddress,
+		userAgent,
+	)
+
+	// Sent in the background so a slow mail server does not hold up the
+	// sign-in
+	userID, email := user.ID, user.Email
+	go func() {
+		if err := s.mailer.Send(mail.Message{
+			To:      email,
+			Subject: "New sign-in to your OpenMind account",
+			Body:    body,
+		}); err != nil {
+			s.log.Error("Failed to send new login alert", zap.Error(err), zap.String("user_id", userID.String()))
+		}
+	}()
+}
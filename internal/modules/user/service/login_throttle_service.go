@@ -0,0 +1,279 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	auditLogService "github.com/sahabatharianmu/OpenMind/internal/modules/audit_log/service"
+	organizationRepository "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+)
+
+// Audit actions recorded for login brute-force protection
+const (
+	AuditActionAccountLocked   = "account_locked"
+	AuditActionAccountUnlocked = "account_unlocked"
+	AuditActionLoginIPLocked   = "login_ip_locked"
+)
+
+// LoginThrottledError is returned while an account email or client IP must
+// wait before trying to log in again. It renders as 429 Too Many Requests.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return "Too many failed login attempts. Please try again later."
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return response.NewAppError(consts.StatusTooManyRequests, e.Error(), nil)
+}
+
+// ErrMFAChallengeExhausted is returned for an MFA challenge that has seen
+// too many wrong codes. The login has to start over with the password.
+var ErrMFAChallengeExhausted = response.NewUnauthorized("Too many invalid verification codes. Please sign in again")
+
+// LoginThrottleOptions configures failed login tracking. ChallengeAttempts
+// is the number of wrong codes an MFA challenge accepts.
+type LoginThrottleOptions struct {
+	FailureWindow      time.Duration
+	DelayThreshold     int
+	IPDelayThreshold   int
+	MaxDelay           time.Duration
+	LockoutThreshold   int
+	IPLockoutThreshold int
+	LockoutDuration    time.Duration
+	ChallengeAttempts  int
+}
+
+// LoginThrottleService tracks failed logins per account email and per client
+// IP. After a few failures each further attempt has to wait progressively
+// longer, and after many the email or IP is locked out for a while. Emails
+// are tracked whether or not an account exists, so responses look the same
+// for unknown addresses. Wrong codes are also counted per MFA challenge, so
+// that a challenge cannot be used to guess codes until it expires.
+type LoginThrottleService interface {
+	Check(email, ipAddress string) error
+	RecordFailure(email, ipAddress, userAgent string)
+	RecordSuccess(email string)
+	CheckChallenge(challengeID string) error
+	RecordChallengeFailure(challengeID string)
+	Unlock(orgID, adminID, userID uuid.UUID, ipAddress, userAgent string) error
+}
+
+type loginThrottleService struct {
+	repo        repository.LoginThrottleRepository
+	userRepo    repository.UserRepository
+	orgRepo     organizationRepository.OrganizationRepository
+	auditLogSvc auditLogService.AuditLogService
+	opts        LoginThrottleOptions
+	log         logger.Logger
+}
+
+func NewLoginThrottleService(
+	repo repository.LoginThrottleRepository,
+	userRepo repository.UserRepository,
+	orgRepo organizationRepository.OrganizationRepository,
+	auditLogSvc auditLogService.AuditLogService,
+	opts LoginThrottleOptions,
+	log logger.Logger,
+) LoginThrottleService {
+	return &loginThrottleService{
+		repo:        repo,
+		userRepo:    userRepo,
+		orgRepo:     orgRepo,
+		auditLogSvc: auditLogSvc,
+		opts:        opts,
+		log:         log,
+	}
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ipAddress string) string {
+	return "ip:" + ipAddress
+}
+
+func challengeKey(challengeID string) string {
+	return "mfa:" + challengeID
+}
+
+// Check rejects the attempt when the email or IP is locked out or has not
+// waited long enough since its last failure. Storage errors fail open so an
+// outage of the throttle table does not block every login.
+func (s *loginThrottleService) Check(email, ipAddress string) error {
+	throttles, err := s.repo.FindByKeys(emailKey(email), ipKey(ipAddress))
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	var wait time.Duration
+	for i := range throttles {
+		throttle := &throttles[i]
+		threshold := s.opts.DelayThreshold
+		if strings.HasPrefix(throttle.Key, "ip:") {
+			threshold = s.opts.IPDelayThreshold
+		}
+
+		var until time.Time
+		switch {
+		case throttle.IsLocked(now):
+			until = *throttle.LockedUntil
+		case throttle.LastFailureAt.Before(now.Add(-s.opts.FailureWindow)):
+			continue
+		case throttle.Failures >= threshold:
+			until = throttle.LastFailureAt.Add(s.delay(throttle.Failures - threshold))
+		}
+
+		if remaining := until.Sub(now); remaining > wait {
+			wait = remaining
+		}
+	}
+
+	if wait > 0 {
+		s.log.Warn("Login throttled", zap.String("email", email), zap.String("ip", ipAddress), zap.Duration("wait", wait))
+		return &LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// delay doubles from one second for each failure past the threshold
+func (s *loginThrottleService) delay(excess int) time.Duration {
+	if excess >= 30 { //nolint:mnd // avoid shifting past the duration range
+		return s.opts.MaxDelay
+	}
+	d := time.Second << excess
+	if d > s.opts.MaxDelay {
+		return s.opts.MaxDelay
+	}
+	return d
+}
+
+func (s *loginThrottleService) RecordFailure(email, ipAddress, userAgent string) {
+	now := time.Now()
+	windowStart := now.Add(-s.opts.FailureWindow)
+
+	if throttle, err := s.repo.RecordFailure(emailKey(email), now, windowStart); err == nil &&
+		throttle.Failures >= s.opts.LockoutThreshold {
+		s.lock(throttle, AuditActionAccountLocked, email, ipAddress, userAgent)
+	}
+
+	if throttle, err := s.repo.RecordFailure(ipKey(ipAddress), now, windowStart); err == nil &&
+		throttle.Failures >= s.opts.IPLockoutThreshold {
+		s.lock(throttle, AuditActionLoginIPLocked, email, ipAddress, userAgent)
+	}
+}
+
+// lock locks the key and records the lockout in the audit log. An email
+// lockout is recorded in each organization of the account, or as a system
+// entry when no account has the email. An IP lockout is a system entry with
+// the IP as its subject, whichever emails were tried from it.
+func (s *loginThrottleService) lock(throttle *entity.LoginThrottle, action, email, ipAddress, userAgent string) {
+	until := time.Now().Add(s.opts.LockoutDuration)
+	locked, err := s.repo.Lock(throttle.Key, until)
+	if err != nil || !locked {
+		return
+	}
+
+	s.log.Warn(
+		"Login locked out after repeated failures",
+		zap.String("key", throttle.Key),
+		zap.Int("failures", throttle.Failures),
+		zap.Time("locked_until", until),
+	)
+
+	details := map[string]interface{}{
+		"failures":     throttle.Failures,
+		"locked_until": until,
+	}
+
+	if action == AuditActionLoginIPLocked {
+		details["ip_address"] = ipAddress
+		details["last_email"] = email
+		recordSystemAudit(s.auditLogSvc, s.log, action, auditResourceIPAddress, details, ipAddress, userAgent)
+		return
+	}
+
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		details["email"] = strings.ToLower(strings.TrimSpace(email))
+		recordSystemAudit(s.auditLogSvc, s.log, action, auditResourceUser, details, ipAddress, userAgent)
+		return
+	}
+
+	details["email"] = user.Email
+	recordUserAudit(s.auditLogSvc, s.orgRepo, s.log, user.ID, user.ID, action, details, ipAddress, userAgent, uuid.Nil)
+}
+
+// RecordSuccess clears the email's failures. IP failures are kept, otherwise
+// an attacker with one valid account could reset them at will.
+func (s *loginThrottleService) RecordSuccess(email string) {
+	_ = s.repo.Delete(emailKey(email))
+	_ = s.repo.DeleteStale(time.Now().Add(-s.opts.FailureWindow))
+}
+
+// CheckChallenge rejects an MFA challenge that has used up its attempts.
+// Like Check, it fails open on storage errors.
+func (s *loginThrottleService) CheckChallenge(challengeID string) error {
+	throttles, err := s.repo.FindByKeys(challengeKey(challengeID))
+	if err != nil {
+		return nil
+	}
+
+	for i := range throttles {
+		if throttles[i].Failures >= s.opts.ChallengeAttempts {
+			s.log.Warn("MFA challenge rejected: too many invalid codes", zap.String("challenge_id", challengeID))
+			return ErrMFAChallengeExhausted
+		}
+	}
+	return nil
+}
+
+// RecordChallengeFailure counts a wrong code against the MFA challenge. The
+// count outlives the challenge, which expires well within the failure window.
+func (s *loginThrottleService) RecordChallengeFailure(challengeID string) {
+	now := time.Now()
+	_, _ = s.repo.RecordFailure(challengeKey(challengeID), now, now.Add(-s.opts.FailureWindow))
+}
+
+// Unlock lets an administrator clear a member's lockout and failure count
+func (s *loginThrottleService) Unlock(orgID, adminID, userID uuid.UUID, ipAddress, userAgent string) error {
+	isMember, err := s.orgRepo.IsMember(orgID, userID)
+	if err != nil {
+		return response.ErrInternalServerError
+	}
+	if !isMember {
+		return response.NewNotFound("User not found")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return response.NewNotFound("User not found")
+	}
+
+	if err := s.repo.Delete(emailKey(user.Email)); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	details := map[string]interface{}{"email": user.Email}
+	recordUserAudit(
+		s.auditLogSvc, s.orgRepo, s.log, userID, adminID, AuditActionAccountUnlocked, details, ipAddress, userAgent, orgID,
+	)
+
+	s.log.Info(
+		"Account login unlocked by admin",
+		zap.String("admin_id", adminID.String()),
+		zap.String("user_id", userID.String()),
+	)
+	return nil
+}
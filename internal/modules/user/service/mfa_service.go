@@ -0,0 +1,299 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"github.com/sahabatharianmu/OpenMind/pkg/security"
+	"go.uber.org/zap"
+)
+
+const recoveryCodeCount = 10
+
+type MFAService interface {
+	Status(userID uuid.UUID) (*dto.MFAStatusResponse, error)
+	Setup(userID uuid.UUID) (*dto.MFASetupResponse, error)
+	Enable(userID uuid.UUID, code string) ([]string, error)
+	Disable(userID uuid.UUID, password, code string) error
+	RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error)
+	Verify(user *entity.User, code string) error
+	IsRequired(userID uuid.UUID) (bool, error)
+}
+
+type mfaService struct {
+	repo            repository.UserRepository
+	recoveryRepo    repository.MFARecoveryCodeRepository
+	encryptService  *crypto.EncryptionService
+	passwordService *crypto.PasswordService
+	issuer          string
+	log             logger.Logger
+}
+
+func NewMFAService(
+	repo repository.UserRepository,
+	recoveryRepo repository.MFARecoveryCodeRepository,
+	encryptService *crypto.EncryptionService,
+	passwordService *crypto.PasswordService,
+	issuer string,
+	log logger.Logger,
+) MFAService {
+	return &mfaService{
+		repo:            repo,
+		recoveryRepo:    recoveryRepo,
+		encryptService:  encryptService,
+		passwordService: passwordService,
+		issuer:          issuer,
+		log:             log,
+	}
+}
+
+func (s *mfaService) Status(userID uuid.UUID) (*dto.MFAStatusResponse, error) {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return nil, response.ErrNotFound
+	}
+
+	required, err := s.IsRequired(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var remaining int64
+	if user.MFAEnabled {
+		remaining, err = s.recoveryRepo.CountUnused(userID)
+		if err != nil {
+			return nil, response.ErrInternalServerError
+		}
+	}
+
+	return &dto.MFAStatusResponse{
+		Enabled:                user.MFAEnabled,
+		Required:               required,
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+// Setup generates a new TOTP secret for the user. MFA stays disabled until
+// the first code is confirmed through Enable.
+func (s *mfaService) Setup(userID uuid.UUID) (*dto.MFASetupResponse, error) {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return nil, response.ErrNotFound
+	}
+
+	if user.MFAEnabled {
+		return nil, response.NewConflict("MFA is already enabled")
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		s.log.Error("MFA setup failed: secret generation error", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+
+	encrypted, err := s.encryptService.Encrypt(secret)
+	if err != nil {
+		s.log.Error("MFA setup failed: secret encryption error", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+
+	user.MFASecret = &encrypted
+	if err := s.repo.Update(user); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info("MFA setup started", zap.String("user_id", userID.String()))
+	return &dto.MFASetupResponse{
+		Secret:          secret,
+		ProvisioningURI: security.TOTPProvisioningURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Enable confirms enrollment with a code from the authenticator app and
+// returns a fresh set of recovery codes, shown to the user only once
+func (s *mfaService) Enable(userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return nil, response.ErrNotFound
+	}
+
+	if user.MFAEnabled {
+		return nil, response.NewConflict("MFA is already enabled")
+	}
+	if user.MFASecret == nil {
+		return nil, response.NewBadRequest("MFA setup has not been started")
+	}
+
+	step, err := s.verifyTOTP(user, code)
+	if err != nil {
+		return nil, err
+	}
+
+	user.MFAEnabled = true
+	user.MFALastUsedStep = step
+	if err := s.repo.Update(user); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	codes, err := s.issueRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("MFA enabled", zap.String("user_id", userID.String()))
+	return codes, nil
+}
+
+func (s *mfaService) Disable(userID uuid.UUID, password, code string) error {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return response.ErrNotFound
+	}
+
+	if !user.MFAEnabled {
+		return response.NewBadRequest("MFA is not enabled")
+	}
+
+	required, err := s.IsRequired(userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return response.NewForbidden("Your organization requires MFA")
+	}
+
+	if verifyErr := s.passwordService.VerifyPassword(password, user.PasswordHash); verifyErr != nil {
+		s.log.Warn("MFA disable failed: invalid password", zap.String("user_id", userID.String()))
+		return response.ErrUnauthorized
+	}
+
+	if err := s.Verify(user, code); err != nil {
+		return err
+	}
+
+	user.MFAEnabled = false
+	user.MFASecret = nil
+	user.MFALastUsedStep = 0
+	if err := s.repo.Update(user); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	if err := s.recoveryRepo.DeleteByUser(userID); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	s.log.Info("MFA disabled", zap.String("user_id", userID.String()))
+	return nil
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return nil, response.ErrNotFound
+	}
+
+	if !user.MFAEnabled {
+		return nil, response.NewBadRequest("MFA is not enabled")
+	}
+
+	if err := s.Verify(user, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.issueRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("MFA recovery codes regenerated", zap.String("user_id", userID.String()))
+	return codes, nil
+}
+
+// Verify accepts either a current TOTP code or an unused recovery code
+func (s *mfaService) Verify(user *entity.User, code string) error {
+	_, err := s.verifyTOTP(user, code)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, response.ErrInternalServerError) {
+		return err
+	}
+
+	consumed, err := s.recoveryRepo.Consume(user.ID, crypto.HashToken(security.NormalizeRecoveryCode(code)))
+	if err != nil {
+		return response.ErrInternalServerError
+	}
+	if !consumed {
+		s.log.Warn("MFA verification failed", zap.String("user_id", user.ID.String()))
+		return response.NewUnauthorized("Invalid verification code")
+	}
+
+	s.log.Warn("MFA recovery code used", zap.String("user_id", user.ID.String()))
+	return nil
+}
+
+func (s *mfaService) IsRequired(userID uuid.UUID) (bool, error) {
+	required, err := s.repo.IsMFARequired(userID)
+	if err != nil {
+		return false, response.ErrInternalServerError
+	}
+	return required, nil
+}
+
+func (s *mfaService) verifyTOTP(user *entity.User, code string) (int64, error) {
+	if user.MFASecret == nil {
+		return 0, response.NewUnauthorized("Invalid verification code")
+	}
+
+	secret, err := s.encryptService.Decrypt(*user.MFASecret)
+	if err != nil {
+		s.log.Error("MFA verification failed: secret decryption error", zap.Error(err))
+		return 0, response.ErrInternalServerError
+	}
+
+	step, ok := security.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return 0, response.NewUnauthorized("Invalid verification code")
+	}
+
+	claimed, err := s.repo.ClaimMFAStep(user.ID, step)
+	if err != nil {
+		return 0, response.ErrInternalServerError
+	}
+	if !claimed {
+		s.log.Warn("MFA verification failed: code already used", zap.String("user_id", user.ID.String()))
+		return 0, response.NewUnauthorized("Verification code has already been used")
+	}
+
+	return step, nil
+}
+
+func (s *mfaService) issueRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	codes, err := security.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		s.log.Error("Failed to generate MFA recovery codes", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+
+	records := make([]entity.MFARecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		records = append(records, entity.MFARecoveryCode{
+			ID:       uuid.New(),
+			UserID:   userID,
+			CodeHash: crypto.HashToken(security.NormalizeRecoveryCode(code)),
+		})
+	}
+
+	if err := s.recoveryRepo.ReplaceForUser(userID, records); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	organizationRepository "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+)
+
+// PasswordPolicyService applies the password rules shared by every flow that
+// sets a password: the configured complexity rules, the breached password
+// lists, reuse of recent passwords and organization password expiry
+type PasswordPolicyService interface {
+	Check(user *entity.User, orgID uuid.UUID, password string) error
+	Set(user *entity.User, password string) error
+	IsExpired(user *entity.User) (bool, error)
+}
+
+type passwordPolicyService struct {
+	repo            repository.UserRepository
+	historyRepo     repository.PasswordHistoryRepository
+	breachedRepo    organizationRepository.BreachedPasswordRepository
+	passwordService *crypto.PasswordService
+	historyCount    int
+	checkBreached   bool
+	log             logger.Logger
+}
+
+func NewPasswordPolicyService(
+	repo repository.UserRepository,
+	historyRepo repository.PasswordHistoryRepository,
+	breachedRepo organizationRepository.BreachedPasswordRepository,
+	passwordService *crypto.PasswordService,
+	historyCount int,
+	checkBreached bool,
+	log logger.Logger,
+) PasswordPolicyService {
+	return &passwordPolicyService{
+		repo:            repo,
+		historyRepo:     historyRepo,
+		breachedRepo:    breachedRepo,
+		passwordService: passwordService,
+		historyCount:    historyCount,
+		checkBreached:   checkBreached,
+		log:             log,
+	}
+}
+
+// Check validates a new password. user is nil for accounts that do not exist
+// yet; orgID names the organization being joined, if any, so its breached
+// password list applies too.
+func (s *passwordPolicyService) Check(user *entity.User, orgID uuid.UUID, password string) error {
+	if err := s.passwordService.ValidatePassword(password); err != nil {
+		return response.NewBadRequest(err.Error())
+	}
+
+	userID := uuid.Nil
+	if user != nil {
+		userID = user.ID
+	}
+
+	if s.checkBreached {
+		breached := crypto.IsBundledBreachedPassword(password)
+		if !breached {
+			found, err := s.breachedRepo.Exists(crypto.BreachedPasswordHash(password), userID, orgID)
+			if err != nil {
+				return response.ErrInternalServerError
+			}
+			breached = found
+		}
+		if breached {
+			s.log.Warn("Password rejected: found in breached password list", zap.String("user_id", userID.String()))
+			return response.NewBadRequest("This password has appeared in a data breach, please choose a different one")
+		}
+	}
+
+	if user != nil && s.historyCount > 0 {
+		if err := s.checkHistory(user, password); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkHistory rejects the current password and the previous historyCount ones
+func (s *passwordPolicyService) checkHistory(user *entity.User, password string) error {
+	reused := response.NewBadRequest("Password was used recently, please choose a different one")
+
+	if user.PasswordHash != "" && s.passwordService.VerifyPassword(password, user.PasswordHash) == nil {
+		return reused
+	}
+
+	history, err := s.historyRepo.ListRecent(user.ID, s.historyCount)
+	if err != nil {
+		return response.ErrInternalServerError
+	}
+	for _, entry := range history {
+		if s.passwordService.VerifyPassword(password, entry.PasswordHash) == nil {
+			return reused
+		}
+	}
+	return nil
+}
+
+// Set checks and stores a new password for an existing user, moving the
+// previous hash into the password history
+func (s *passwordPolicyService) Set(user *entity.User, password string) error {
+	if err := s.Check(user, uuid.Nil, password); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.passwordService.HashPassword(password)
+	if err != nil {
+		s.log.Error("Set password failed: password hashing error", zap.Error(err))
+		return err
+	}
+
+	if user.PasswordHash != "" && s.historyCount > 0 {
+		if err := s.historyRepo.Add(user.ID, user.PasswordHash, s.historyCount); err != nil {
+			return response.ErrInternalServerError
+		}
+	}
+
+	user.PasswordHash = hashedPassword
+	user.PasswordChangedAt = time.Now()
+	user.PasswordResetRequired = false
+	if err := s.repo.Update(user); err != nil {
+		s.log.Error("Set password failed: update error", zap.Error(err))
+		return response.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// IsExpired reports whether the user's password is older than the strictest
+// maximum age among their organizations
+func (s *passwordPolicyService) IsExpired(user *entity.User) (bool, error) {
+	days, err := s.repo.PasswordMaxAgeDays(user.ID)
+	if err != nil {
+		return false, response.ErrInternalServerError
+	}
+	if days == 0 || user.PasswordHash == "" {
+		return false, nil
+	}
+
+	return user.PasswordChangedAt.Before(time.Now().AddDate(0, 0, -days)), nil
+}
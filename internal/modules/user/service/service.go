@@ -1,7 +1,15 @@
 package service
 
 import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/google/uuid"
+	organizationEntity "github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	organizationRepository "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	sessionService "github.com/sahabatharianmu/OpenMind/internal/modules/session/service"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/user/dto"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/user/repository"
@@ -10,32 +18,72 @@ import (
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
 	"github.com/sahabatharianmu/OpenMind/pkg/security"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type AuthService interface {
 	Register(email, password, fullName, practiceName string) (*entity.User, error)
-	Login(email, password string) (*dto.LoginResponse, error)
+	AcceptInvitation(token, fullName, password string) (*entity.User, error)
+	Login(email, password, ipAddress, userAgent string) (*dto.LoginResponse, error)
+	Refresh(refreshToken, ipAddress, userAgent string) (*dto.LoginResponse, error)
+	SwitchOrganization(userID, sessionID, orgID uuid.UUID, ipAddress, userAgent string) (*dto.LoginResponse, error)
+	Reauthenticate(userID, sessionID uuid.UUID, password, code, ipAddress, userAgent string) error
+	VerifyMFA(mfaToken, code, ipAddress, userAgent string) (*dto.LoginResponse, error)
+	SetupMFA(mfaToken string) (*dto.MFASetupResponse, error)
 	SetupStatus() (*dto.SetupStatusResponse, error)
-	ChangePassword(userID uuid.UUID, oldPassword, newPassword string) error
+	ChangePassword(userID, sessionID uuid.UUID, oldPassword, newPassword, ipAddress, userAgent string) error
+	ChangeExpiredPassword(token, newPassword, ipAddress, userAgent string) (*dto.LoginResponse, error)
+	LoginWithSSO(userID, orgID uuid.UUID, ipAddress, userAgent string) (*dto.LoginResponse, error)
+	ConfirmPassword(userID uuid.UUID, password, ipAddress, userAgent string) error
 }
 
 type authService struct {
 	repo            repository.UserRepository
+	refreshRepo     repository.RefreshTokenRepository
+	invitationRepo  organizationRepository.InvitationRepository
+	sessionSvc      sessionService.SessionService
+	mfaSvc          MFAService
+	accountSvc      AccountService
+	throttleSvc     LoginThrottleService
+	policySvc       PasswordPolicyService
+	loginAlertSvc   LoginAlertService
 	jwt             *security.JWTService
 	passwordService *crypto.PasswordService
+	requireVerified bool
 	log             logger.Logger
+
+	dummyHashOnce sync.Once
+	dummyHash     string
 }
 
 func NewAuthService(
 	repo repository.UserRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	invitationRepo organizationRepository.InvitationRepository,
+	sessionSvc sessionService.SessionService,
+	mfaSvc MFAService,
+	accountSvc AccountService,
+	throttleSvc LoginThrottleService,
+	policySvc PasswordPolicyService,
+	loginAlertSvc LoginAlertService,
 	jwt *security.JWTService,
 	passwordService *crypto.PasswordService,
+	requireVerified bool,
 	log logger.Logger,
 ) AuthService {
 	return &authService{
 		repo:            repo,
+		refreshRepo:     refreshRepo,
+		invitationRepo:  invitationRepo,
+		sessionSvc:      sessionSvc,
+		mfaSvc:          mfaSvc,
+		accountSvc:      accountSvc,
+		throttleSvc:     throttleSvc,
+		policySvc:       policySvc,
+		loginAlertSvc:   loginAlertSvc,
 		jwt:             jwt,
 		passwordService: passwordService,
+		requireVerified: requireVerified,
 		log:             log,
 	}
 }
@@ -47,6 +95,10 @@ func (s *authService) Register(email, password, fullName, practiceName string) (
 		return nil, response.ErrConflict
 	}
 
+	if err := s.policySvc.Check(nil, uuid.Nil, password); err != nil {
+		return nil, err
+	}
+
 	hashedPassword, err := s.passwordService.HashPassword(password)
 	if err != nil {
 		s.log.Error("Registration failed: password hashing error", zap.Error(err))
@@ -58,7 +110,7 @@ func (s *authService) Register(email, password, fullName, practiceName string) (
 		Email:        email,
 		PasswordHash: hashedPassword,
 		FullName:     fullName,
-		Role:         "clinician", // Default role
+		Role:         "owner", // Creator owns the new organization
 	}
 
 	organization := &entity.Organization{
@@ -71,6 +123,11 @@ func (s *authService) Register(email, password, fullName, practiceName string) (
 		return nil, err
 	}
 
+	// The account is usable already; a failed email can be resent later
+	if err := s.accountSvc.SendVerification(user.ID); err != nil {
+		s.log.Warn("Registration: failed to send verification email", zap.String("email", email))
+	}
+
 	s.log.Info(
 		"User registered successfully with organization",
 		zap.String("email", email),
@@ -79,31 +136,657 @@ func (s *authService) Register(email, password, fullName, practiceName string) (
 	return user, nil
 }
 
-func (s *authService) Login(email, password string) (*dto.LoginResponse, error) {
+// AcceptInvitation creates the invited user inside the inviting organization
+// instead of starting a new practice. Users who already have an account
+// confirm it with their current password and join as an extra membership.
+func (s *authService) AcceptInvitation(token, fullName, password string) (*entity.User, error) {
+	invitation, err := s.invitationRepo.FindByTokenHash(crypto.HashToken(token))
+	if err != nil || !invitation.IsPending(time.Now()) {
+		s.log.Warn("Accept invitation failed: invalid or expired token")
+		return nil, response.NewNotFound("Invitation is invalid or has expired")
+	}
+
+	if existingUser, _ := s.repo.FindByEmail(invitation.Email); existingUser != nil {
+		return s.joinFromInvitation(existingUser, invitation, password)
+	}
+
+	if err := s.policySvc.Check(nil, invitation.OrganizationID, password); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.passwordService.HashPassword(password)
+	if err != nil {
+		s.log.Error("Accept invitation failed: password hashing error", zap.Error(err))
+		return nil, err
+	}
+
+	// The invitation link was delivered to this address, which verifies it
+	now := time.Now()
+	user := &entity.User{
+		ID:              uuid.New(),
+		Email:           strings.ToLower(invitation.Email),
+		PasswordHash:    hashedPassword,
+		FullName:        fullName,
+		Role:            invitation.Role,
+		EmailVerifiedAt: &now,
+	}
+
+	err = s.repo.CreateFromInvitation(user, invitation.ID, invitation.OrganizationID, invitation.Role)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvitationUnavailable) {
+			return nil, response.NewNotFound("Invitation is invalid or has expired")
+		}
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"User joined organization from invitation",
+		zap.String("email", user.Email),
+		zap.String("org_id", invitation.OrganizationID.String()),
+		zap.String("role", invitation.Role),
+	)
+	return user, nil
+}
+
+func (s *authService) joinFromInvitation(
+	user *entity.User,
+	invitation *organizationEntity.Invitation,
+	password string,
+) (*entity.User, error) {
+	if verifyErr := s.passwordService.VerifyPassword(password, user.PasswordHash); verifyErr != nil {
+		s.log.Warn("Accept invitation failed: invalid password", zap.String("email", user.Email))
+		return nil, response.ErrUnauthorized
+	}
+
+	if _, err := s.repo.FindMembership(user.ID, invitation.OrganizationID); err == nil {
+		return nil, response.NewConflict("You are already a member of this organization")
+	}
+
+	err := s.repo.JoinFromInvitation(user.ID, invitation.ID, invitation.OrganizationID, invitation.Role)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvitationUnavailable) {
+			return nil, response.NewNotFound("Invitation is invalid or has expired")
+		}
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"Existing user joined organization from invitation",
+		zap.String("email", user.Email),
+		zap.String("org_id", invitation.OrganizationID.String()),
+		zap.String("role", invitation.Role),
+	)
+	return user, nil
+}
+
+func (s *authService) Login(email, password, ipAddress, userAgent string) (*dto.LoginResponse, error) {
+	if err := s.throttleSvc.Check(email, ipAddress); err != nil {
+		return nil, err
+	}
+
 	user, err := s.repo.FindByEmail(email)
 	if err != nil {
+		// Spend the same time as a real password check so response times do
+		// not reveal whether the email is registered
+		_ = s.passwordService.VerifyPassword(password, s.timingHash())
+		s.throttleSvc.RecordFailure(email, ipAddress, userAgent)
 		s.log.Warn("Login failed: user not found", zap.String("email", email))
 		return nil, response.ErrUnauthorized
 	}
 
 	if verifyErr := s.passwordService.VerifyPassword(password, user.PasswordHash); verifyErr != nil {
+		s.throttleSvc.RecordFailure(email, ipAddress, userAgent)
 		s.log.Warn("Login failed: invalid password", zap.String("email", email))
 		return nil, response.ErrUnauthorized
 	}
 
-	accessToken, refreshToken, err := s.jwt.GenerateTokens(user.ID, user.Email, user.Role)
+	// Checked only after the password so unverified accounts are not revealed
+	if s.requireVerified && user.EmailVerifiedAt == nil {
+		s.log.Warn("Login failed: email not verified", zap.String("email", email))
+		return nil, response.NewForbidden("Please verify your email address before logging in")
+	}
+
+	if user.PasswordResetRequired {
+		s.log.Warn("Login failed: password reset required", zap.String("email", email))
+		return nil, response.NewForbidden("A password reset is required. Check your email for a reset link")
+	}
+
+	expired, err := s.policySvc.IsExpired(user)
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return s.passwordChangeChallenge(user)
+	}
+
+	return s.completeLogin(user, ipAddress, userAgent)
+}
+
+// completeLogin finishes a login once the password has been accepted. The
+// email's failed attempts are only cleared once the login has fully
+// succeeded, so the lockout also covers the second factor.
+func (s *authService) completeLogin(user *entity.User, ipAddress, userAgent string) (*dto.LoginResponse, error) {
+	if challenge, err := s.secondFactor(user); challenge != nil || err != nil {
+		return challenge, err
+	}
+
+	resp, err := s.issueTokens(user, uuid.Nil, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	s.throttleSvc.RecordSuccess(user.Email)
+
+	s.log.Info("User logged in successfully", zap.String("email", user.Email))
+	return resp, nil
+}
+
+// secondFactor returns an MFA challenge when the user has enrolled or one of
+// their organizations enforces MFA, and nil when the login may go ahead
+func (s *authService) secondFactor(user *entity.User) (*dto.LoginResponse, error) {
+	if user.MFAEnabled {
+		return s.mfaChallenge(user, false)
+	}
+
+	required, err := s.mfaSvc.IsRequired(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		return s.mfaChallenge(user, true)
+	}
+	return nil, nil
+}
+
+func (s *authService) passwordChangeChallenge(user *entity.User) (*dto.LoginResponse, error) {
+	token, err := s.jwt.GeneratePasswordChangeToken(user.ID, user.Email)
+	if err != nil {
+		s.log.Error("Login failed: password change token generation error", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info("Login requires password change: password expired", zap.String("email", user.Email))
+	return &dto.LoginResponse{
+		PasswordChangeRequired: true,
+		PasswordChangeToken:    token,
+	}, nil
+}
+
+// ChangeExpiredPassword sets a new password for a login that was stopped
+// because the password expired, then carries on with the login
+func (s *authService) ChangeExpiredPassword(
+	token, newPassword, ipAddress, userAgent string,
+) (*dto.LoginResponse, error) {
+	claims, err := s.jwt.ValidatePasswordChangeToken(token)
+	if err != nil {
+		s.log.Warn("Expired password change failed: invalid token", zap.Error(err))
+		return nil, response.NewUnauthorized("Password change token is invalid or has expired")
+	}
+
+	user, err := s.repo.GetByID(claims.UserID)
+	if err != nil {
+		return nil, response.ErrUnauthorized
+	}
+
+	// The token outlives a completed change; only honour it while the
+	// password is still the expired one
+	expired, err := s.policySvc.IsExpired(user)
+	if err != nil {
+		return nil, err
+	}
+	if !expired {
+		return nil, response.NewUnauthorized("Password change token is invalid or has expired")
+	}
+
+	if err := s.policySvc.Set(user, newPassword); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Expired password changed", zap.String("user_id", user.ID.String()))
+	return s.completeLogin(user, ipAddress, userAgent)
+}
+
+// timingHash returns a throwaway hash for comparing passwords of unknown
+// emails against
+func (s *authService) timingHash() string {
+	s.dummyHashOnce.Do(func() {
+		hash, err := s.passwordService.HashPassword(uuid.NewString())
+		if err != nil {
+			s.log.Error("Failed to create timing hash", zap.Error(err))
+		}
+		s.dummyHash = hash
+	})
+	return s.dummyHash
+}
+
+// VerifyMFA completes a login started with a password. For users who are
+// enrolling because their organization requires MFA, the first valid code
+// also activates MFA and the recovery codes are returned once. Each
+// challenge accepts a limited number of wrong codes, and wrong codes count
+// towards the login lockout like wrong passwords.
+func (s *authService) VerifyMFA(mfaToken, code, ipAddress, userAgent string) (*dto.LoginResponse, error) {
+	user, challengeID, err := s.userFromChallenge(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.throttleSvc.Check(user.Email, ipAddress); err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if user.MFAEnabled {
+		err = s.mfaSvc.Verify(user, code)
+	} else {
+		recoveryCodes, err = s.mfaSvc.Enable(user.ID, code)
+	}
+	if err != nil {
+		if !errors.Is(err, response.ErrInternalServerError) {
+			s.throttleSvc.RecordChallengeFailure(challengeID)
+			s.throttleSvc.RecordFailure(user.Email, ipAddress, userAgent)
+		}
+		return nil, err
+	}
+
+	resp, err := s.issueTokens(user, uuid.Nil, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	resp.RecoveryCodes = recoveryCodes
+	s.throttleSvc.RecordSuccess(user.Email)
+
+	s.log.Info("User logged in successfully with MFA", zap.String("email", user.Email))
+	return resp, nil
+}
+
+// SetupMFA starts enrollment for a user whose login is blocked until MFA
+// is configured
+func (s *authService) SetupMFA(mfaToken string) (*dto.MFASetupResponse, error) {
+	user, _, err := s.userFromChallenge(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mfaSvc.Setup(user.ID)
+}
+
+func (s *authService) mfaChallenge(user *entity.User, enrollment bool) (*dto.LoginResponse, error) {
+	token, err := s.jwt.GenerateMFAChallengeToken(user.ID, user.Email)
+	if err != nil {
+		s.log.Error("Login failed: MFA challenge generation error", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info("Login requires MFA", zap.String("email", user.Email), zap.Bool("enrollment", enrollment))
+	return &dto.LoginResponse{
+		MFARequired:           !enrollment,
+		MFAEnrollmentRequired: enrollment,
+		MFAToken:              token,
+	}, nil
+}
+
+// userFromChallenge returns the user of a valid MFA challenge that still has
+// attempts left, along with the challenge's ID
+func (s *authService) userFromChallenge(mfaToken string) (*entity.User, string, error) {
+	claims, err := s.jwt.ValidateMFAChallengeToken(mfaToken)
+	if err != nil {
+		s.log.Warn("MFA verification failed: invalid challenge token", zap.Error(err))
+		return nil, "", response.NewUnauthorized("MFA challenge is invalid or has expired")
+	}
+
+	if err := s.throttleSvc.CheckChallenge(claims.ID); err != nil {
+		return nil, "", err
+	}
+
+	user, err := s.repo.GetByID(claims.UserID)
+	if err != nil {
+		return nil, "", response.ErrUnauthorized
+	}
+	return user, claims.ID, nil
+}
+
+// LoginWithSSO starts a session for a user the organization's identity
+// provider has already authenticated. The session can reach the user's other
+// organizations too, so their MFA settings still apply and an MFA challenge
+// is returned like for a password login.
+func (s *authService) LoginWithSSO(userID, orgID uuid.UUID, ipAddress, userAgent string) (*dto.LoginResponse, error) {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return nil, response.ErrUnauthorized
+	}
+
+	if challenge, err := s.secondFactor(user); challenge != nil || err != nil {
+		return challenge, err
+	}
+
+	resp, err := s.issueTokens(user, orgID, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info(
+		"User logged in successfully with SSO",
+		zap.String("email", user.Email),
+		zap.String("org_id", orgID.String()),
+	)
+	return resp, nil
+}
+
+// ConfirmPassword checks the password of a user who is not signed in, such
+// as one linking a single sign-on account. Failures count towards the login
+// lockout.
+func (s *authService) ConfirmPassword(userID uuid.UUID, password, ipAddress, userAgent string) error {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return response.ErrUnauthorized
+	}
+
+	if err := s.throttleSvc.Check(user.Email, ipAddress); err != nil {
+		return err
+	}
+
+	if user.PasswordHash == "" || s.passwordService.VerifyPassword(password, user.PasswordHash) != nil {
+		s.throttleSvc.RecordFailure(user.Email, ipAddress, userAgent)
+		s.log.Warn("Password confirmation failed: invalid password", zap.String("user_id", userID.String()))
+		return response.NewUnauthorized("Invalid password")
+	}
+	s.throttleSvc.RecordSuccess(user.Email)
+	return nil
+}
+
+// issueTokens creates a session and its first access/refresh token pair,
+// scoped to the given organization or, with uuid.Nil, the user's default one
+func (s *authService) issueTokens(
+	user *entity.User,
+	orgID uuid.UUID,
+	ipAddress, userAgent string,
+) (*dto.LoginResponse, error) {
+	member, err := s.membership(user.ID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	pair, err := s.jwt.GenerateTokens(user.ID, member.OrganizationID, user.Email, member.Role)
 	if err != nil {
 		s.log.Error("Login failed: token generation error", zap.Error(err))
 		return nil, response.ErrInternalServerError
 	}
 
-	s.log.Info("User logged in successfully", zap.String("email", email))
+	// Each login starts a new session; its ID doubles as the refresh token family
+	sessionID := uuid.New()
+	if err := s.sessionSvc.Create(
+		user.ID,
+		member.OrganizationID,
+		sessionID,
+		pair.AccessTokenID,
+		pair.RefreshExpiresAt,
+		ipAddress,
+		userAgent,
+	); err != nil {
+		s.log.Error("Login failed: session creation error", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+
+	if err := s.refreshRepo.Create(&entity.RefreshToken{
+		ID:        pair.RefreshTokenID,
+		UserID:    user.ID,
+		FamilyID:  sessionID,
+		TokenHash: crypto.HashToken(pair.RefreshToken),
+		ExpiresAt: pair.RefreshExpiresAt,
+	}); err != nil {
+		s.log.Error("Login failed: refresh token persistence error", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+
+	s.loginAlertSvc.RecordLogin(user, ipAddress, userAgent)
+
 	return &dto.LoginResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
 	}, nil
 }
 
+func (s *authService) Refresh(refreshToken, ipAddress, userAgent string) (*dto.LoginResponse, error) {
+	claims, err := s.jwt.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		s.log.Warn("Refresh failed: invalid refresh token", zap.Error(err))
+		return nil, response.ErrUnauthorized
+	}
+
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		s.log.Warn("Refresh failed: invalid token ID", zap.Error(err))
+		return nil, response.ErrUnauthorized
+	}
+
+	stored, err := s.refreshRepo.FindByID(tokenID)
+	if err != nil || stored.TokenHash != crypto.HashToken(refreshToken) {
+		s.log.Warn("Refresh failed: unknown refresh token", zap.String("token_id", tokenID.String()))
+		return nil, response.ErrUnauthorized
+	}
+
+	// A refresh token that was already rotated or revoked is being replayed.
+	// Treat it as stolen and revoke every token in the family.
+	if stored.RevokedAt != nil {
+		s.revokeFamily(stored, "Refresh token reuse detected")
+		return nil, response.ErrUnauthorized
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		s.log.Warn("Refresh failed: refresh token expired", zap.String("token_id", tokenID.String()))
+		return nil, response.ErrUnauthorized
+	}
+
+	user, err := s.repo.GetByID(stored.UserID)
+	if err != nil {
+		s.log.Warn("Refresh failed: user not found", zap.String("user_id", stored.UserID.String()))
+		return nil, response.ErrUnauthorized
+	}
+
+	// Members who never enrolled must log in again once their organization
+	// starts enforcing MFA
+	if !user.MFAEnabled {
+		required, err := s.mfaSvc.IsRequired(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if required {
+			s.revokeFamily(stored, "Refresh rejected: organization requires MFA enrollment")
+			return nil, response.ErrUnauthorized
+		}
+	}
+
+	// Stay in the organization the session was using; fall back to the
+	// default one if the user has since been removed from it
+	member, err := s.membership(user.ID, claims.OrganizationID)
+	if err != nil && claims.OrganizationID != uuid.Nil {
+		member, err = s.membership(user.ID, uuid.Nil)
+	}
+	if err != nil {
+		s.revokeFamily(stored, "Refresh rejected: user is no longer an organization member")
+		return nil, err
+	}
+
+	pair, err := s.jwt.GenerateTokens(user.ID, member.OrganizationID, user.Email, member.Role)
+	if err != nil {
+		s.log.Error("Refresh failed: token generation error", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+
+	rotated, err := s.refreshRepo.Rotate(stored.ID, &entity.RefreshToken{
+		ID:        pair.RefreshTokenID,
+		UserID:    user.ID,
+		FamilyID:  stored.FamilyID,
+		TokenHash: crypto.HashToken(pair.RefreshToken),
+		ExpiresAt: pair.RefreshExpiresAt,
+	})
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	if !rotated {
+		// Lost the race against another request presenting the same token
+		s.revokeFamily(stored, "Concurrent refresh token reuse detected")
+		return nil, response.ErrUnauthorized
+	}
+
+	// Fails when the session was logged out or revoked by an admin
+	if err := s.sessionSvc.Rotate(
+		stored.FamilyID,
+		member.OrganizationID,
+		pair.AccessTokenID,
+		pair.RefreshExpiresAt,
+		ipAddress,
+		userAgent,
+	); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Tokens refreshed successfully", zap.String("user_id", user.ID.String()))
+	return &dto.LoginResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+	}, nil
+}
+
+// SwitchOrganization re-issues the current session's tokens for another
+// organization the user belongs to
+func (s *authService) SwitchOrganization(
+	userID, sessionID, orgID uuid.UUID,
+	ipAddress, userAgent string,
+) (*dto.LoginResponse, error) {
+	if orgID == uuid.Nil {
+		return nil, response.NewBadRequest("Organization ID is required")
+	}
+
+	member, err := s.membership(userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return nil, response.ErrUnauthorized
+	}
+
+	pair, err := s.jwt.GenerateTokens(user.ID, member.OrganizationID, user.Email, member.Role)
+	if err != nil {
+		s.log.Error("Switch organization failed: token generation error", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+
+	// Fails when the session was logged out or revoked in the meantime
+	if err := s.sessionSvc.Rotate(
+		sessionID,
+		member.OrganizationID,
+		pair.AccessTokenID,
+		pair.RefreshExpiresAt,
+		ipAddress,
+		userAgent,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshRepo.ReplaceFamilyToken(&entity.RefreshToken{
+		ID:        pair.RefreshTokenID,
+		UserID:    user.ID,
+		FamilyID:  sessionID,
+		TokenHash: crypto.HashToken(pair.RefreshToken),
+		ExpiresAt: pair.RefreshExpiresAt,
+	}); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"Switched organization",
+		zap.String("user_id", user.ID.String()),
+		zap.String("org_id", orgID.String()),
+	)
+	return &dto.LoginResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+	}, nil
+}
+
+// Reauthenticate confirms the identity of an already signed-in user with their
+// password or an MFA code and marks the session as recently authenticated, as
+// required by sensitive actions. Failures count towards the login lockout.
+func (s *authService) Reauthenticate(
+	userID, sessionID uuid.UUID,
+	password, code, ipAddress, userAgent string,
+) error {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return response.ErrNotFound
+	}
+
+	if err := s.throttleSvc.Check(user.Email, ipAddress); err != nil {
+		return err
+	}
+
+	switch {
+	case password != "":
+		if user.PasswordHash == "" ||
+			s.passwordService.VerifyPassword(password, user.PasswordHash) != nil {
+			s.throttleSvc.RecordFailure(user.Email, ipAddress, userAgent)
+			s.log.Warn("Reauthentication failed: invalid password", zap.String("user_id", userID.String()))
+			return response.NewUnauthorized("Invalid password")
+		}
+	case code != "":
+		if !user.MFAEnabled {
+			return response.NewBadRequest("MFA is not enabled for this account")
+		}
+		if err := s.mfaSvc.Verify(user, code); err != nil {
+			s.throttleSvc.RecordFailure(user.Email, ipAddress, userAgent)
+			return err
+		}
+	default:
+		return response.NewBadRequest("Password or verification code is required")
+	}
+	s.throttleSvc.RecordSuccess(user.Email)
+
+	if err := s.sessionSvc.Reauthenticate(sessionID); err != nil {
+		return err
+	}
+
+	s.log.Info("User reauthenticated", zap.String("user_id", userID.String()))
+	return nil
+}
+
+// membership returns the membership whose organization and role are carried
+// in issued tokens. The token copy of the role is informational; requests
+// are authorized against the live membership.
+func (s *authService) membership(userID, orgID uuid.UUID) (*entity.OrganizationMember, error) {
+	member, err := s.repo.FindMembership(userID, orgID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.Warn("Token issue rejected: user is not a member", zap.String("user_id", userID.String()))
+			if orgID != uuid.Nil {
+				return nil, response.NewForbidden("You are not a member of this organization")
+			}
+			return nil, response.NewForbidden("You are not an active member of any organization")
+		}
+		return nil, response.ErrInternalServerError
+	}
+	if member.DeactivatedAt != nil {
+		s.log.Warn("Token issue rejected: membership is deactivated", zap.String("user_id", userID.String()))
+		return nil, ErrMembershipDeactivated
+	}
+	return member, nil
+}
+
+func (s *authService) revokeFamily(token *entity.RefreshToken, reason string) {
+	s.log.Warn(
+		reason,
+		zap.String("user_id", token.UserID.String()),
+		zap.String("family_id", token.FamilyID.String()),
+	)
+	if err := s.refreshRepo.RevokeFamily(token.FamilyID); err != nil {
+		s.log.Error("Failed to revoke refresh token family", zap.Error(err))
+	}
+	if err := s.sessionSvc.Revoke(token.UserID, token.FamilyID); err != nil {
+		s.log.Error("Failed to revoke session for refresh token family", zap.Error(err))
+	}
+}
+
 func (s *authService) SetupStatus() (*dto.SetupStatusResponse, error) {
 	count, err := s.repo.CountUsers()
 	if err != nil {
@@ -117,31 +800,37 @@ func (s *authService) SetupStatus() (*dto.SetupStatusResponse, error) {
 	}, nil
 }
 
-func (s *authService) ChangePassword(userID uuid.UUID, oldPassword, newPassword string) error {
+// ChangePassword replaces the password of a signed-in user. Wrong old
+// passwords count towards the login lockout, and the user's other sessions
+// are revoked afterwards since the old password may have been compromised.
+func (s *authService) ChangePassword(
+	userID, sessionID uuid.UUID,
+	oldPassword, newPassword, ipAddress, userAgent string,
+) error {
 	user, err := s.repo.GetByID(userID)
 	if err != nil {
 		s.log.Error("ChangePassword failed: user not found", zap.Error(err))
 		return response.ErrNotFound
 	}
 
+	if err := s.throttleSvc.Check(user.Email, ipAddress); err != nil {
+		return err
+	}
+
 	// Verify old password
 	if verifyErr := s.passwordService.VerifyPassword(oldPassword, user.PasswordHash); verifyErr != nil {
+		s.throttleSvc.RecordFailure(user.Email, ipAddress, userAgent)
 		s.log.Warn("ChangePassword failed: invalid old password", zap.String("user_id", userID.String()))
 		return response.ErrUnauthorized
 	}
+	s.throttleSvc.RecordSuccess(user.Email)
 
-	// Hash new password
-	hashedPassword, err := s.passwordService.HashPassword(newPassword)
-	if err != nil {
-		s.log.Error("ChangePassword failed: password hashing error", zap.Error(err))
+	if err := s.policySvc.Set(user, newPassword); err != nil {
 		return err
 	}
 
-	// Update password
-	user.PasswordHash = hashedPassword
-	if err := s.repo.Update(user); err != nil {
-		s.log.Error("ChangePassword failed: update error", zap.Error(err))
-		return err
+	if _, err := s.sessionSvc.RevokeOthers(user.ID, sessionID); err != nil {
+		s.log.Error("ChangePassword failed to revoke sessions", zap.Error(err), zap.String("user_id", userID.String()))
 	}
 
 	s.log.Info("Password changed successfully", zap.String("user_id", userID.String()))
@@ -0,0 +1,542 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/google/uuid"
+	organizationEntity "github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	organizationRepository "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	organizationService "github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/crypto"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/oidc"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	ssoLoginRequestTTL = 10 * time.Minute
+	ssoLinkRequestTTL  = 10 * time.Minute
+	ssoRequestTimeout  = 15 * time.Second
+)
+
+var errSSOUnavailable = response.NewNotFound("Single sign-on is not available for this organization")
+
+// SSOService signs staff in through their organization's OpenID Connect
+// identity provider using the authorization code flow with PKCE. Unknown
+// users are provisioned into the organization on their first login, while
+// existing accounts must confirm the link with their password.
+type SSOService interface {
+	Start(req dto.SSOStartRequest) (*dto.SSOStartResponse, error)
+	Callback(code, state, ipAddress, userAgent string) (*dto.LoginResponse, error)
+	ConfirmLink(linkToken, password, ipAddress, userAgent string) (*dto.LoginResponse, error)
+}
+
+type ssoService struct {
+	userRepo      repository.UserRepository
+	identityRepo  repository.IdentityRepository
+	ssoConfigRepo organizationRepository.SSOConfigRepository
+	orgRepo       organizationRepository.OrganizationRepository
+	roleSvc       organizationService.RoleService
+	authSvc       AuthService
+	oidcClient    *oidc.Client
+	dataKeySvc    organizationService.DataKeyService
+	redirectURI   string
+	log           logger.Logger
+}
+
+func NewSSOService(
+	userRepo repository.UserRepository,
+	identityRepo repository.IdentityRepository,
+	ssoConfigRepo organizationRepository.SSOConfigRepository,
+	orgRepo organizationRepository.OrganizationRepository,
+	roleSvc organizationService.RoleService,
+	authSvc AuthService,
+	oidcClient *oidc.Client,
+	dataKeySvc organizationService.DataKeyService,
+	baseURL string,
+	log logger.Logger,
+) SSOService {
+	return &ssoService{
+		userRepo:      userRepo,
+		identityRepo:  identityRepo,
+		ssoConfigRepo: ssoConfigRepo,
+		orgRepo:       orgRepo,
+		roleSvc:       roleSvc,
+		authSvc:       authSvc,
+		oidcClient:    oidcClient,
+		dataKeySvc:    dataKeySvc,
+		redirectURI:   strings.TrimRight(baseURL, "/") + organizationService.SSOCallbackPath,
+		log:           log,
+	}
+}
+
+func (s *ssoService) Start(req dto.SSOStartRequest) (*dto.SSOStartResponse, error) {
+	config, err := s.findConfig(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ssoRequestTimeout)
+	defer cancel()
+
+	provider, err := s.oidcClient.Discover(ctx, config.Issuer)
+	if err != nil {
+		s.log.Error("SSO start failed: provider discovery error", zap.Error(err), zap.String("issuer", config.Issuer))
+		return nil, response.NewAppError(consts.StatusBadGateway, "Identity provider is unavailable", err)
+	}
+
+	state, err := crypto.GenerateToken()
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	nonce, err := crypto.GenerateToken()
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	verifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	now := time.Now()
+	// Abandoned sign-ins are cleaned up as new ones start
+	_ = s.identityRepo.DeleteExpiredLoginRequests(now)
+
+	if err := s.identityRepo.CreateLoginRequest(&entity.SSOLoginRequest{
+		ID:             uuid.New(),
+		OrganizationID: config.OrganizationID,
+		StateHash:      crypto.HashToken(state),
+		Nonce:          nonce,
+		CodeVerifier:   verifier,
+		ExpiresAt:      now.Add(ssoLoginRequestTTL),
+	}); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	return &dto.SSOStartResponse{
+		AuthorizationURL: provider.AuthCodeURL(config.ClientID, s.redirectURI, state, nonce, verifier),
+	}, nil
+}
+
+func (s *ssoService) Callback(code, state, ipAddress, userAgent string) (*dto.LoginResponse, error) {
+	request, err := s.identityRepo.ConsumeLoginRequest(crypto.HashToken(state))
+	if err != nil || time.Now().After(request.ExpiresAt) {
+		s.log.Warn("SSO callback rejected: unknown or expired state")
+		return nil, response.NewUnauthorized("Sign-in request is invalid or has expired")
+	}
+
+	config, err := s.ssoConfigRepo.FindByOrganization(request.OrganizationID)
+	if err != nil || !config.Enabled {
+		return nil, errSSOUnavailable
+	}
+
+	claims, err := s.verify(config, request, code)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email == "" || (claims.EmailVerified != nil && !*claims.EmailVerified) {
+		s.log.Warn("SSO login rejected: email missing or unverified", zap.String("subject", claims.Subject))
+		return nil, response.NewForbidden("Your identity provider did not supply a verified email address")
+	}
+	if !config.AllowsEmail(email) {
+		s.log.Warn("SSO login rejected: email domain not allowed", zap.String("email", email))
+		return nil, response.NewForbidden("Your email domain is not allowed to sign in to this organization")
+	}
+
+	groups := claims.Strings(config.GroupsClaim)
+	user, identity, err := s.resolveUser(config, claims, email)
+	if err != nil {
+		return nil, err
+	}
+	if identity == nil {
+		return s.requestLink(config, user, claims.Subject, groups)
+	}
+
+	return s.login(config, user, identity, groups, ipAddress, userAgent)
+}
+
+// ConfirmLink links the provider account of a pending link request once the
+// existing account's password is confirmed, then completes the login
+func (s *ssoService) ConfirmLink(linkToken, password, ipAddress, userAgent string) (*dto.LoginResponse, error) {
+	request, err := s.identityRepo.ConsumeLinkRequest(crypto.HashToken(linkToken))
+	if err != nil || time.Now().After(request.ExpiresAt) {
+		s.log.Warn("SSO link rejected: unknown or expired token")
+		return nil, response.NewUnauthorized("Link request is invalid or has expired")
+	}
+
+	// The identity provider may have been replaced since the request started
+	config, err := s.ssoConfigRepo.FindByOrganization(request.OrganizationID)
+	if err != nil || !config.Enabled || config.Issuer != request.Issuer {
+		return nil, errSSOUnavailable
+	}
+
+	if err := s.authSvc.ConfirmPassword(request.UserID, password, ipAddress, userAgent); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(request.UserID)
+	if err != nil {
+		return nil, response.ErrUnauthorized
+	}
+	if err := s.checkLinkable(config, user); err != nil {
+		return nil, err
+	}
+
+	identity, err := s.link(config, user, request.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.login(config, user, identity, request.Groups, ipAddress, userAgent)
+}
+
+func (s *ssoService) login(
+	config *organizationEntity.SSOConfig,
+	user *entity.User,
+	identity *entity.Identity,
+	groups []string,
+	ipAddress, userAgent string,
+) (*dto.LoginResponse, error) {
+	if err := s.ensureMembership(config, user, groups); err != nil {
+		return nil, err
+	}
+
+	_ = s.identityRepo.TouchLogin(identity.ID, time.Now())
+
+	return s.authSvc.LoginWithSSO(user.ID, config.OrganizationID, ipAddress, userAgent)
+}
+
+func (s *ssoService) findConfig(req dto.SSOStartRequest) (*organizationEntity.SSOConfig, error) {
+	var (
+		config *organizationEntity.SSOConfig
+		err    error
+	)
+	switch {
+	case req.OrganizationID != nil:
+		config, err = s.ssoConfigRepo.FindByOrganization(*req.OrganizationID)
+	case strings.Contains(req.Email, "@"):
+		domain := strings.ToLower(strings.TrimSpace(req.Email[strings.LastIndex(req.Email, "@")+1:]))
+		config, err = s.ssoConfigRepo.FindByDomain(domain)
+	default:
+		return nil, response.NewBadRequest("Organization ID or email is required")
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errSSOUnavailable
+		}
+		return nil, response.ErrInternalServerError
+	}
+	if !config.Enabled {
+		return nil, errSSOUnavailable
+	}
+	return config, nil
+}
+
+// clientSecret decrypts the organization's client secret. Secrets set before
+// organizations had data keys are still under the shared keyring.
+func (s *ssoService) clientSecret(ctx context.Context, config *organizationEntity.SSOConfig) (string, error) {
+	ciphertext, keyID, err := organizationEntity.DecodeClientSecret(config.ClientSecret)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := s.dataKeySvc.Decrypt(ctx, config.OrganizationID, ciphertext, keyID)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// verify exchanges the authorization code and validates the returned ID token
+func (s *ssoService) verify(
+	config *organizationEntity.SSOConfig,
+	request *entity.SSOLoginRequest,
+	code string,
+) (*oidc.Claims, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ssoRequestTimeout)
+	defer cancel()
+
+	provider, err := s.oidcClient.Discover(ctx, config.Issuer)
+	if err != nil {
+		s.log.Error("SSO callback failed: provider discovery error", zap.Error(err), zap.String("issuer", config.Issuer))
+		return nil, response.NewAppError(consts.StatusBadGateway, "Identity provider is unavailable", err)
+	}
+
+	clientSecret, err := s.clientSecret(ctx, config)
+	if err != nil {
+		s.log.Error("SSO callback failed: client secret decryption error", zap.Error(err))
+		return nil, response.ErrInternalServerError
+	}
+
+	idToken, err := s.oidcClient.Exchange(
+		ctx,
+		provider,
+		config.ClientID,
+		clientSecret,
+		s.redirectURI,
+		code,
+		request.CodeVerifier,
+	)
+	if err != nil {
+		s.log.Warn("SSO callback rejected: code exchange failed", zap.Error(err))
+		return nil, response.NewUnauthorized("Sign-in with your identity provider failed")
+	}
+
+	claims, err := s.oidcClient.VerifyIDToken(ctx, provider, idToken, config.ClientID, request.Nonce)
+	if err != nil {
+		s.log.Warn("SSO callback rejected: ID token verification failed", zap.Error(err))
+		return nil, response.NewUnauthorized("Sign-in with your identity provider failed")
+	}
+
+	return claims, nil
+}
+
+// resolveUser finds the user linked to the provider account, or provisions
+// a new one on a first login. When an account with the same email already
+// exists no identity is returned: the email alone does not prove the
+// provider account belongs to that user, so the link must be confirmed.
+func (s *ssoService) resolveUser(
+	config *organizationEntity.SSOConfig,
+	claims *oidc.Claims,
+	email string,
+) (*entity.User, *entity.Identity, error) {
+	identity, err := s.identityRepo.FindBySubject(config.Issuer, claims.Subject)
+	if err == nil {
+		user, err := s.userRepo.GetByID(identity.UserID)
+		if err != nil {
+			return nil, nil, response.ErrUnauthorized
+		}
+		return user, identity, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, response.ErrInternalServerError
+	}
+
+	user, err := s.userRepo.FindByEmail(email)
+	if err == nil {
+		return user, nil, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, response.ErrInternalServerError
+	}
+
+	if user, err = s.provision(config, claims, email); err != nil {
+		return nil, nil, err
+	}
+	identity, err = s.link(config, user, claims.Subject)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, identity, nil
+}
+
+// requestLink starts linking the provider account to an existing account
+// with the same email. The user confirms the link with their password at
+// /auth/sso/link.
+func (s *ssoService) requestLink(
+	config *organizationEntity.SSOConfig,
+	user *entity.User,
+	subject string,
+	groups []string,
+) (*dto.LoginResponse, error) {
+	if err := s.checkLinkable(config, user); err != nil {
+		return nil, err
+	}
+	if user.PasswordHash == "" {
+		s.log.Warn("SSO login rejected: existing account has no password to confirm the link",
+			zap.String("email", user.Email))
+		return nil, response.NewForbidden(
+			"Set a password for your account with a password reset, then sign in with single sign-on again " +
+				"to link your account",
+		)
+	}
+
+	token, err := crypto.GenerateToken()
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	now := time.Now()
+	// Abandoned link requests are cleaned up as new ones start
+	_ = s.identityRepo.DeleteExpiredLinkRequests(now)
+
+	if err := s.identityRepo.CreateLinkRequest(&entity.SSOLinkRequest{
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		OrganizationID: config.OrganizationID,
+		Issuer:         config.Issuer,
+		Subject:        subject,
+		Groups:         datatypes.NewJSONSlice(groups),
+		TokenHash:      crypto.HashToken(token),
+		ExpiresAt:      now.Add(ssoLinkRequestTTL),
+	}); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"SSO login requires link confirmation",
+		zap.String("email", user.Email),
+		zap.String("org_id", config.OrganizationID.String()),
+	)
+	return &dto.LoginResponse{
+		SSOLinkRequired: true,
+		SSOLinkToken:    token,
+	}, nil
+}
+
+// checkLinkable allows linking a provider account only to an existing member
+// of the organization other than its owner. Accounts from other
+// organizations must join through an invitation first, and the owner always
+// signs in with their password.
+func (s *ssoService) checkLinkable(config *organizationEntity.SSOConfig, user *entity.User) error {
+	member, err := s.orgRepo.GetMember(config.OrganizationID, user.ID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.ErrInternalServerError
+		}
+		s.log.Warn(
+			"SSO login rejected: existing account is not a member",
+			zap.String("email", user.Email),
+			zap.String("org_id", config.OrganizationID.String()),
+		)
+		return response.NewForbidden(
+			"An account with this email already exists. Accept an invitation to this organization " +
+				"with your password before signing in with single sign-on",
+		)
+	}
+	if member.Role == organizationEntity.RoleOwner {
+		s.log.Warn(
+			"SSO login rejected: owner accounts cannot be linked",
+			zap.String("email", user.Email),
+			zap.String("org_id", config.OrganizationID.String()),
+		)
+		return response.NewForbidden("The organization owner must sign in with their password")
+	}
+	return nil
+}
+
+func (s *ssoService) link(
+	config *organizationEntity.SSOConfig,
+	user *entity.User,
+	subject string,
+) (*entity.Identity, error) {
+	identity := &entity.Identity{
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		OrganizationID: config.OrganizationID,
+		Issuer:         config.Issuer,
+		Subject:        subject,
+	}
+	if err := s.identityRepo.Create(identity); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"SSO identity linked",
+		zap.String("email", user.Email),
+		zap.String("org_id", config.OrganizationID.String()),
+	)
+	return identity, nil
+}
+
+// provision creates a user for a first-time SSO login. The account has no
+// password, so it can only sign in through the identity provider.
+func (s *ssoService) provision(
+	config *organizationEntity.SSOConfig,
+	claims *oidc.Claims,
+	email string,
+) (*entity.User, error) {
+	role := s.mappedRole(config, claims.Strings(config.GroupsClaim))
+
+	fullName := strings.TrimSpace(claims.Name)
+	if fullName == "" {
+		fullName = email[:strings.Index(email, "@")]
+	}
+
+	// The identity provider has already confirmed the email address
+	now := time.Now()
+	user := &entity.User{
+		ID:              uuid.New(),
+		Email:           email,
+		FullName:        fullName,
+		Role:            role,
+		EmailVerifiedAt: &now,
+	}
+	if err := s.userRepo.CreateWithMembership(user, config.OrganizationID, role); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	s.log.Info(
+		"User provisioned from SSO",
+		zap.String("email", email),
+		zap.String("org_id", config.OrganizationID.String()),
+		zap.String("role", role),
+	)
+	return user, nil
+}
+
+// ensureMembership adds the user to the organization if needed. When the
+// organization maps groups to roles, the role follows the groups on every
+// login; the owner's role is never changed.
+func (s *ssoService) ensureMembership(config *organizationEntity.SSOConfig, user *entity.User, groups []string) error {
+	role := s.mappedRole(config, groups)
+
+	member, err := s.orgRepo.GetMember(config.OrganizationID, user.ID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.ErrInternalServerError
+		}
+		if err := s.userRepo.AddMembership(user.ID, config.OrganizationID, role); err != nil {
+			return response.ErrInternalServerError
+		}
+		s.log.Info(
+			"User joined organization from SSO",
+			zap.String("email", user.Email),
+			zap.String("org_id", config.OrganizationID.String()),
+			zap.String("role", role),
+		)
+		return nil
+	}
+
+	if len(config.GroupRoles.Data()) == 0 || member.Role == organizationEntity.RoleOwner || member.Role == role {
+		return nil
+	}
+	if err := s.orgRepo.UpdateMemberRole(config.OrganizationID, user.ID, role); err != nil {
+		return response.ErrInternalServerError
+	}
+	s.log.Info(
+		"Member role synced from SSO groups",
+		zap.String("email", user.Email),
+		zap.String("org_id", config.OrganizationID.String()),
+		zap.String("from", member.Role),
+		zap.String("to", role),
+	)
+	return nil
+}
+
+// mappedRole resolves the role for the groups, falling back to the default
+// role when a mapped custom role has since been deleted
+func (s *ssoService) mappedRole(config *organizationEntity.SSOConfig, groups []string) string {
+	role := config.RoleForGroups(groups)
+	if role == config.DefaultRole {
+		return role
+	}
+	if assignable, err := s.roleSvc.IsAssignable(config.OrganizationID, role); err != nil || !assignable {
+		s.log.Warn("SSO group maps to an unknown role", zap.String("role", role))
+		return config.DefaultRole
+	}
+	return role
+}
@@ -0,0 +1,404 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	auditLogService "github.com/sahabatharianmu/OpenMind/internal/modules/audit_log/service"
+	organizationEntity "github.com/sahabatharianmu/OpenMind/internal/modules/organization/entity"
+	organizationRepository "github.com/sahabatharianmu/OpenMind/internal/modules/organization/repository"
+	organizationService "github.com/sahabatharianmu/OpenMind/internal/modules/organization/service"
+	sessionService "github.com/sahabatharianmu/OpenMind/internal/modules/session/service"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/repository"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"github.com/sahabatharianmu/OpenMind/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Audit actions recorded by user administration
+const (
+	AuditActionUserDeactivated     = "user_deactivated"
+	AuditActionUserReactivated     = "user_reactivated"
+	AuditActionPasswordResetForced = "password_reset_forced"
+	AuditActionUserNameChanged     = "user_name_changed"
+	AuditActionClinicianReassigned = "clinician_reassigned"
+)
+
+// ErrMembershipDeactivated is returned when a user signs in to or switches to
+// an organization that has deactivated their membership
+var ErrMembershipDeactivated = response.NewForbidden("Your account has been deactivated in this organization")
+
+// UserAdminService lets organization admins manage the accounts of their
+// members. Deactivation applies to the membership in the admin's
+// organization only; name changes and forced password resets apply to the
+// account itself, so they are refused for accounts shared with other
+// organizations.
+type UserAdminService interface {
+	List(orgID uuid.UUID) ([]dto.MemberAccountResponse, error)
+	UpdateName(orgID, actorID, userID uuid.UUID, fullName, ipAddress, userAgent string) (*dto.MemberAccountResponse, error)
+	Deactivate(orgID, actorID, userID uuid.UUID, ipAddress, userAgent string) error
+	Reactivate(orgID, actorID, userID uuid.UUID, ipAddress, userAgent string) error
+	ForcePasswordReset(orgID, actorID, userID uuid.UUID, ipAddress, userAgent string) error
+	Reassign(orgID, actorID, fromID, toID uuid.UUID, ipAddress, userAgent string) (*dto.ReassignmentResponse, error)
+}
+
+type userAdminService struct {
+	repo        repository.UserRepository
+	adminRepo   repository.AdminRepository
+	orgRepo     organizationRepository.OrganizationRepository
+	roleSvc     organizationService.RoleService
+	sessionSvc  sessionService.SessionService
+	accountSvc  AccountService
+	auditLogSvc auditLogService.AuditLogService
+	log         logger.Logger
+}
+
+func NewUserAdminService(
+	repo repository.UserRepository,
+	adminRepo repository.AdminRepository,
+	orgRepo organizationRepository.OrganizationRepository,
+	roleSvc organizationService.RoleService,
+	sessionSvc sessionService.SessionService,
+	accountSvc AccountService,
+	auditLogSvc auditLogService.AuditLogService,
+	log logger.Logger,
+) UserAdminService {
+	return &userAdminService{
+		repo:        repo,
+		adminRepo:   adminRepo,
+		orgRepo:     orgRepo,
+		roleSvc:     roleSvc,
+		sessionSvc:  sessionSvc,
+		accountSvc:  accountSvc,
+		auditLogSvc: auditLogSvc,
+		log:         log,
+	}
+}
+
+func (s *userAdminService) List(orgID uuid.UUID) ([]dto.MemberAccountResponse, error) {
+	accounts, err := s.adminRepo.ListMemberAccounts(orgID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	resp := make([]dto.MemberAccountResponse, 0, len(accounts))
+	for _, a := range accounts {
+		resp = append(resp, toMemberAccountResponse(a))
+	}
+	return resp, nil
+}
+
+func (s *userAdminService) UpdateName(
+	orgID, actorID, userID uuid.UUID,
+	fullName, ipAddress, userAgent string,
+) (*dto.MemberAccountResponse, error) {
+	fullName = strings.TrimSpace(fullName)
+	if len(fullName) < 2 {
+		return nil, response.NewBadRequest("Full name must be at least 2 characters")
+	}
+
+	if _, err := s.manageableAccount(orgID, actorID, userID); err != nil {
+		return nil, err
+	}
+	// The name also shows in the user's other organizations and on their
+	// signed notes there
+	if err := s.checkAccountWide(userID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return nil, response.NewNotFound("User not found")
+	}
+
+	previous := user.FullName
+	user.FullName = fullName
+	if err := s.repo.Update(user); err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	details := map[string]interface{}{"from": previous, "to": fullName}
+	recordUserAudit(
+		s.auditLogSvc, s.orgRepo, s.log, userID, actorID, AuditActionUserNameChanged, details, ipAddress, userAgent, orgID,
+	)
+
+	account, err := s.adminRepo.GetMemberAccount(orgID, userID)
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+	resp := toMemberAccountResponse(*account)
+	return &resp, nil
+}
+
+// Deactivate blocks the member from signing in to or acting in the
+// organization and signs them out of it. Their records are kept, and their
+// sessions in other organizations are left alone.
+func (s *userAdminService) Deactivate(orgID, actorID, userID uuid.UUID, ipAddress, userAgent string) error {
+	account, err := s.manageableAccount(orgID, actorID, userID)
+	if err != nil {
+		return err
+	}
+	if account.DeactivatedAt != nil {
+		return response.NewConflict("User is already deactivated")
+	}
+
+	now := time.Now()
+	if err := s.orgRepo.SetMemberDeactivated(orgID, userID, &now); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	revoked, err := s.sessionSvc.RevokeInOrganization(userID, orgID)
+	if err != nil {
+		s.log.Error("Failed to revoke sessions of deactivated user", zap.Error(err), zap.String("user_id", userID.String()))
+	}
+
+	details := map[string]interface{}{"email": account.Email, "sessions_revoked": revoked}
+	recordUserAudit(
+		s.auditLogSvc, s.orgRepo, s.log, userID, actorID, AuditActionUserDeactivated, details, ipAddress, userAgent, orgID,
+	)
+
+	s.log.Info("User deactivated", zap.String("org_id", orgID.String()), zap.String("user_id", userID.String()))
+	return nil
+}
+
+func (s *userAdminService) Reactivate(orgID, actorID, userID uuid.UUID, ipAddress, userAgent string) error {
+	account, err := s.manageableAccount(orgID, actorID, userID)
+	if err != nil {
+		return err
+	}
+	if account.DeactivatedAt == nil {
+		return response.NewConflict("User is not deactivated")
+	}
+
+	if err := s.orgRepo.SetMemberDeactivated(orgID, userID, nil); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	details := map[string]interface{}{"email": account.Email}
+	recordUserAudit(
+		s.auditLogSvc, s.orgRepo, s.log, userID, actorID, AuditActionUserReactivated, details, ipAddress, userAgent, orgID,
+	)
+
+	s.log.Info("User reactivated", zap.String("org_id", orgID.String()), zap.String("user_id", userID.String()))
+	return nil
+}
+
+// ForcePasswordReset signs the user out everywhere and refuses password
+// sign-in until they choose a new password through the reset link emailed
+// to them. As this locks the account out of every organization it belongs
+// to, it is refused for accounts that are also members elsewhere.
+func (s *userAdminService) ForcePasswordReset(orgID, actorID, userID uuid.UUID, ipAddress, userAgent string) error {
+	if _, err := s.manageableAccount(orgID, actorID, userID); err != nil {
+		return err
+	}
+	if err := s.checkAccountWide(userID); err != nil {
+		return err
+	}
+
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return response.NewNotFound("User not found")
+	}
+	if user.PasswordHash == "" {
+		return response.NewBadRequest("This user signs in with single sign-on and has no password to reset")
+	}
+
+	user.PasswordResetRequired = true
+	if err := s.repo.Update(user); err != nil {
+		return response.ErrInternalServerError
+	}
+
+	revoked, err := s.sessionSvc.RevokeAll(userID)
+	if err != nil {
+		s.log.Error("Failed to revoke sessions for forced password reset", zap.Error(err))
+	}
+
+	details := map[string]interface{}{"email": user.Email, "sessions_revoked": revoked}
+	recordUserAudit(
+		s.auditLogSvc, s.orgRepo, s.log, userID, actorID, AuditActionPasswordResetForced, details,
+		ipAddress, userAgent, orgID,
+	)
+
+	if err := s.accountSvc.ForgotPassword(user.Email); err != nil {
+		return err
+	}
+
+	s.log.Info("Password reset forced", zap.String("org_id", orgID.String()), zap.String("user_id", userID.String()))
+	return nil
+}
+
+// Reassign moves a departing clinician's patients, care team places and
+// upcoming appointments to another active member of the organization. The
+// departing clinician must be manageable by the actor, and the clinician
+// taking over must be someone else whose role grants clinical access within
+// the actor's own permissions, since they gain care team access.
+func (s *userAdminService) Reassign(
+	orgID, actorID, fromID, toID uuid.UUID,
+	ipAddress, userAgent string,
+) (*dto.ReassignmentResponse, error) {
+	if toID == uuid.Nil {
+		return nil, response.NewBadRequest("Clinician to reassign to is required")
+	}
+	if fromID == toID {
+		return nil, response.NewBadRequest("Cannot reassign a clinician to themselves")
+	}
+	if toID == actorID {
+		return nil, response.NewForbidden("You cannot reassign patients to yourself")
+	}
+
+	from, err := s.manageableAccount(orgID, actorID, fromID)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.adminRepo.GetMemberAccount(orgID, toID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NewBadRequest("Clinician to reassign to is not a member of this organization")
+		}
+		return nil, response.ErrInternalServerError
+	}
+	if to.DeactivatedAt != nil {
+		return nil, response.NewBadRequest("Cannot reassign to a deactivated user")
+	}
+	if err := s.checkReassignTarget(orgID, actorID, to); err != nil {
+		return nil, err
+	}
+
+	result, err := s.adminRepo.Reassign(orgID, fromID, toID, actorID, time.Now())
+	if err != nil {
+		return nil, response.ErrInternalServerError
+	}
+
+	details := map[string]interface{}{
+		"from_user_id":                  fromID,
+		"from_email":                    from.Email,
+		"to_user_id":                    toID,
+		"to_email":                      to.Email,
+		"patients_reassigned":           result.Patients,
+		"care_team_entries_transferred": result.CareTeamEntries,
+		"appointments_reassigned":       result.Appointments,
+		"care_team_access_granted":      result.CareTeamGrants,
+	}
+	recordUserAudit(
+		s.auditLogSvc, s.orgRepo, s.log, fromID, actorID, AuditActionClinicianReassigned, details,
+		ipAddress, userAgent, orgID,
+	)
+
+	s.log.Info(
+		"Clinician reassigned",
+		zap.String("org_id", orgID.String()),
+		zap.String("from", fromID.String()),
+		zap.String("to", toID.String()),
+		zap.Int64("patients", result.Patients),
+		zap.Int64("appointments", result.Appointments),
+	)
+
+	return &dto.ReassignmentResponse{
+		PatientsReassigned:         result.Patients,
+		CareTeamEntriesTransferred: result.CareTeamEntries,
+		AppointmentsReassigned:     result.Appointments,
+		CareTeamAccessGranted:      result.CareTeamGrants,
+	}, nil
+}
+
+// checkAccountWide allows changes that affect the account in every
+// organization only when the account belongs to this organization alone.
+// Anyone can create an organization and become its owner, so no role in one
+// organization is trusted with an account shared with others.
+func (s *userAdminService) checkAccountWide(userID uuid.UUID) error {
+	count, err := s.orgRepo.CountMemberships(userID)
+	if err != nil {
+		return response.ErrInternalServerError
+	}
+	if count > 1 {
+		return response.NewForbidden(
+			"This user also belongs to other organizations, so only they can change their account",
+		)
+	}
+	return nil
+}
+
+// checkReassignTarget requires the clinician taking over to hold a role
+// that grants access to patients or notes and that the actor's role covers
+func (s *userAdminService) checkReassignTarget(orgID, actorID uuid.UUID, to *entity.MemberAccount) error {
+	clinical := false
+	for _, p := range []string{organizationEntity.PermPatientsRead, organizationEntity.PermNotesRead} {
+		has, err := s.roleSvc.HasPermission(orgID, to.Role, p)
+		if err != nil {
+			return err
+		}
+		clinical = clinical || has
+	}
+	if !clinical {
+		return response.NewBadRequest("Clinician to reassign to has no access to patients or notes")
+	}
+
+	actor, err := s.orgRepo.GetMember(orgID, actorID)
+	if err != nil {
+		return response.ErrNotFound
+	}
+	covers, err := s.roleSvc.Covers(orgID, actor.Role, to.Role)
+	if err != nil {
+		return err
+	}
+	if !covers {
+		return response.NewForbidden("You cannot reassign patients to a member with permissions you do not have")
+	}
+	return nil
+}
+
+// manageableAccount returns the target's account when the actor may change
+// it: nobody administers their own account, the owner cannot be changed, and
+// the actor's role must cover every permission of the target's role
+func (s *userAdminService) manageableAccount(orgID, actorID, userID uuid.UUID) (*entity.MemberAccount, error) {
+	if actorID == userID {
+		return nil, response.NewForbidden("You cannot change your own account here")
+	}
+
+	account, err := s.adminRepo.GetMemberAccount(orgID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NewNotFound("User not found")
+		}
+		return nil, response.ErrInternalServerError
+	}
+	if account.Role == organizationEntity.RoleOwner {
+		return nil, response.NewForbidden("The organization owner cannot be changed")
+	}
+
+	actor, err := s.orgRepo.GetMember(orgID, actorID)
+	if err != nil {
+		return nil, response.ErrNotFound
+	}
+	covers, err := s.roleSvc.Covers(orgID, actor.Role, account.Role)
+	if err != nil {
+		return nil, err
+	}
+	if !covers {
+		return nil, organizationService.ErrMemberNotCovered
+	}
+
+	return account, nil
+}
+
+func toMemberAccountResponse(a entity.MemberAccount) dto.MemberAccountResponse {
+	return dto.MemberAccountResponse{
+		ID:                    a.ID,
+		Email:                 a.Email,
+		FullName:              a.FullName,
+		Role:                  a.Role,
+		MFAEnabled:            a.MFAEnabled,
+		EmailVerified:         a.EmailVerifiedAt != nil,
+		PasswordResetRequired: a.PasswordResetRequired,
+		SSOOnly:               a.PasswordHash == "",
+		Active:                a.DeactivatedAt == nil,
+		DeactivatedAt:         a.DeactivatedAt,
+		JoinedAt:              a.JoinedAt,
+	}
+}
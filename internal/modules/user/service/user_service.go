@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/google/uuid"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/user/dto"
+	"github.com/sahabatharianmu/OpenMind/internal/modules/user/entity"
 	"github.com/sahabatharianmu/OpenMind/internal/modules/user/repository"
 	"github.com/sahabatharianmu/OpenMind/pkg/logger"
 	"github.com/sahabatharianmu/OpenMind/pkg/response"
@@ -10,8 +11,8 @@ import (
 )
 
 type UserService interface {
-	GetProfile(userID uuid.UUID) (*dto.UserResponse, error)
-	UpdateProfile(userID uuid.UUID, req dto.UpdateProfileRequest) (*dto.UserResponse, error)
+	GetProfile(userID, orgID uuid.UUID) (*dto.UserResponse, error)
+	UpdateProfile(userID, orgID uuid.UUID, req dto.UpdateProfileRequest) (*dto.UserResponse, error)
 }
 
 type userService struct {
@@ -26,22 +27,17 @@ func NewUserService(repo repository.UserRepository, log logger.Logger) UserServi
 	}
 }
 
-func (s *userService) GetProfile(userID uuid.UUID) (*dto.UserResponse, error) {
+func (s *userService) GetProfile(userID, orgID uuid.UUID) (*dto.UserResponse, error) {
 	user, err := s.repo.GetByID(userID)
 	if err != nil {
 		s.log.Error("GetProfile failed", zap.Error(err), zap.String("user_id", userID.String()))
 		return nil, response.ErrNotFound
 	}
 
-	return &dto.UserResponse{
-		ID:       user.ID,
-		Email:    user.Email,
-		FullName: user.FullName,
-		Role:     user.Role,
-	}, nil
+	return s.toResponse(user, orgID), nil
 }
 
-func (s *userService) UpdateProfile(userID uuid.UUID, req dto.UpdateProfileRequest) (*dto.UserResponse, error) {
+func (s *userService) UpdateProfile(userID, orgID uuid.UUID, req dto.UpdateProfileRequest) (*dto.UserResponse, error) {
 	user, err := s.repo.GetByID(userID)
 	if err != nil {
 		s.log.Error("UpdateProfile failed: user not found", zap.Error(err))
@@ -57,10 +53,23 @@ func (s *userService) UpdateProfile(userID uuid.UUID, req dto.UpdateProfileReque
 
 	s.log.Info("Profile updated successfully", zap.String("user_id", userID.String()))
 
+	return s.toResponse(user, orgID), nil
+}
+
+// toResponse reports the user's role in the organization the request is
+// scoped to
+func (s *userService) toResponse(user *entity.User, orgID uuid.UUID) *dto.UserResponse {
+	role := user.Role
+	if member, err := s.repo.FindMembership(user.ID, orgID); err == nil {
+		role = member.Role
+	}
+
 	return &dto.UserResponse{
-		ID:       user.ID,
-		Email:    user.Email,
-		FullName: user.FullName,
-		Role:     user.Role,
-	}, nil
+		ID:            user.ID,
+		Email:         user.Email,
+		FullName:      user.FullName,
+		Role:          role,
+		MFAEnabled:    user.MFAEnabled,
+		EmailVerified: user.EmailVerifiedAt != nil,
+	}
 }
@@ -0,0 +1,20 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// BlindIndex returns an HMAC-SHA256 of value under a key derived from key for
+// the given purpose. Equal values give equal indexes, which allows exact-match
+// lookups of encrypted data without decrypting it, while separate purposes
+// give unrelated indexes for the same value.
+func BlindIndex(key []byte, purpose, value string) string {
+	derive := hmac.New(sha256.New, key)
+	derive.Write([]byte("blind-index:" + purpose))
+
+	mac := hmac.New(sha256.New, derive.Sum(nil))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,82 @@
+package crypto
+
+import (
+	"bufio"
+	"crypto/sha1" //nolint:gosec // SHA-1 is the format breached password lists are published in
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// maxBreachedHashLine bounds a single line of an uploaded hash list
+const maxBreachedHashLine = 1024
+
+//go:embed breached_passwords.txt
+var bundledBreachedHashes string
+
+var (
+	bundledOnce sync.Once
+	bundledSet  map[string]struct{}
+)
+
+// BreachedPasswordHash returns the uppercase hex SHA-1 of password, the key
+// breached password lists are indexed by
+func BreachedPasswordHash(password string) string {
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // see import
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// IsBundledBreachedPassword reports whether password appears in the breached
+// password list shipped with the application
+func IsBundledBreachedPassword(password string) bool {
+	bundledOnce.Do(func() {
+		hashes, _ := ParseBreachedHashes(strings.NewReader(bundledBreachedHashes))
+		bundledSet = make(map[string]struct{}, len(hashes))
+		for _, h := range hashes {
+			bundledSet[h] = struct{}{}
+		}
+	})
+
+	_, found := bundledSet[BreachedPasswordHash(password)]
+	return found
+}
+
+// ParseBreachedHashes reads a list of SHA-1 password hashes, one per line.
+// Lines may carry a ":count" suffix as in the Pwned Passwords download;
+// blank lines and lines starting with # are skipped. Hashes are returned in
+// uppercase without duplicates.
+func ParseBreachedHashes(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, maxBreachedHashLine), maxBreachedHashLine)
+
+	seen := make(map[string]struct{})
+	var hashes []string
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(line, ":")
+		hash = strings.ToUpper(strings.TrimSpace(hash))
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("line %d: not a SHA-1 hash", lineNo)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("line %d: not a SHA-1 hash", lineNo)
+		}
+
+		if _, dup := seen[hash]; !dup {
+			seen[hash] = struct{}{}
+			hashes = append(hashes, hash)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read hash list: %w", err)
+	}
+
+	return hashes, nil
+}
@@ -8,82 +8,194 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/sahabatharianmu/OpenMind/config"
 )
 
-// EncryptionService handles encryption and decryption operations
+// LegacyKeyID identifies the key configured as EncryptionKey. Data written
+// before keys were versioned was encrypted with it.
+const LegacyKeyID = "v1"
+
+// EncryptionService handles encryption and decryption operations. It holds a
+// keyring of versioned keys: new data is encrypted with the active key and
+// existing data is decrypted with the key it was written with, so keys can
+// be rotated without losing access to older data.
 type EncryptionService struct {
-	config *config.Config
+	keys        map[string][]byte
+	activeKeyID string
 }
 
-// NewEncryptionService creates a new encryption service
-func NewEncryptionService(cfg *config.Config) *EncryptionService {
-	return &EncryptionService{
-		config: cfg,
+// NewEncryptionService creates a new encryption service from the configured
+// keyring
+func NewEncryptionService(cfg *config.Config) (*EncryptionService, error) {
+	s := &EncryptionService{
+		keys:        make(map[string][]byte),
+		activeKeyID: cfg.Security.EncryptionActiveKeyID,
+	}
+	if s.activeKeyID == "" {
+		s.activeKeyID = LegacyKeyID
+	}
+
+	if cfg.Security.EncryptionKey != "" {
+		s.keys[LegacyKeyID] = []byte(cfg.Security.EncryptionKey)
+	}
+
+	for _, entry := range cfg.Security.EncryptionKeys {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, key, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || key == "" {
+			return nil, errors.New(`encryption keys must be given as "id:key"`)
+		}
+		if _, exists := s.keys[id]; exists {
+			return nil, fmt.Errorf("duplicate encryption key ID %q", id)
+		}
+		s.keys[id] = []byte(key)
+	}
+
+	if _, ok := s.keys[s.activeKeyID]; !ok {
+		return nil, fmt.Errorf("active encryption key %q is not configured", s.activeKeyID)
 	}
+
+	return s, nil
 }
 
 const KeySize = 32
 
-// Encrypt encrypts a plaintext string using AES-GCM
+// ActiveKeyID returns the ID of the key new data is encrypted with
+func (s *EncryptionService) ActiveKeyID() string {
+	return s.activeKeyID
+}
+
+// HasKey reports whether the keyring holds the key with the given ID
+func (s *EncryptionService) HasKey(keyID string) bool {
+	_, ok := s.keys[keyID]
+	return ok
+}
+
+// Encrypt encrypts a plaintext string using AES-GCM with the active key. The
+// result is prefixed with the key ID so Decrypt can pick the right key.
 func (s *EncryptionService) Encrypt(plaintext string) (string, error) {
-	key := []byte(s.config.Security.EncryptionKey)
-	if len(key) != KeySize {
-		return "", errors.New("encryption key must be 32 bytes")
+	ciphertext, keyID, err := s.EncryptBytes([]byte(plaintext))
+	if err != nil {
+		return "", err
 	}
+	return keyID + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
 
-	block, err := aes.NewCipher(key)
+// Decrypt decrypts a ciphertext string produced by Encrypt. Values without a
+// key ID prefix predate key versioning and use the legacy key.
+func (s *EncryptionService) Decrypt(ciphertext string) (string, error) {
+	keyID, encoded, ok := strings.Cut(ciphertext, ":")
+	if !ok {
+		keyID, encoded = LegacyKeyID, ciphertext
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
 	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %w", err)
+		return "", fmt.Errorf("failed to decode base64: %w", err)
 	}
 
-	aesGCM, err := cipher.NewGCM(block)
+	plaintext, err := s.DecryptBytes(data, keyID)
 	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %w", err)
+		return "", err
 	}
+	return string(plaintext), nil
+}
 
-	nonce := make([]byte, aesGCM.NonceSize())
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", fmt.Errorf("failed to generate nonce: %w", err)
+// EncryptBytes encrypts plaintext using AES-GCM with the active key and
+// returns the nonce followed by the sealed data, along with the key ID
+func (s *EncryptionService) EncryptBytes(plaintext []byte) ([]byte, string, error) {
+	key, err := s.key(s.activeKeyID)
+	if err != nil {
+		return nil, "", err
 	}
 
-	ciphertext := aesGCM.Seal(nonce, nonce, []byte(plaintext), nil)
-	return base64.StdEncoding.EncodeToString(ciphertext), nil
+	ciphertext, err := SealWithKey(key, plaintext)
+	if err != nil {
+		return nil, "", err
+	}
+	return ciphertext, s.activeKeyID, nil
 }
 
-// Decrypt decrypts a ciphertext string using AES-GCM
-func (s *EncryptionService) Decrypt(ciphertext string) (string, error) {
-	key := []byte(s.config.Security.EncryptionKey)
-	if len(key) != KeySize {
-		return "", errors.New("encryption key must be 32 bytes")
+// DecryptBytes decrypts data produced by EncryptBytes with the key it was
+// encrypted with. An empty key ID means the legacy key.
+func (s *EncryptionService) DecryptBytes(ciphertext []byte, keyID string) ([]byte, error) {
+	if keyID == "" {
+		keyID = LegacyKeyID
 	}
 
-	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	key, err := s.key(keyID)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode base64: %w", err)
+		return nil, err
 	}
+	return OpenWithKey(key, ciphertext)
+}
 
-	block, err := aes.NewCipher(key)
+func (s *EncryptionService) key(keyID string) ([]byte, error) {
+	key, ok := s.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("encryption key %q is not configured", keyID)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key %q must be 32 bytes", keyID)
+	}
+	return key, nil
+}
+
+// SealWithKey encrypts plaintext using AES-GCM with a 32-byte key and returns
+// the nonce followed by the sealed data
+func SealWithKey(key, plaintext []byte) ([]byte, error) {
+	aesGCM, err := newGCM(key)
 	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %w", err)
+		return nil, err
 	}
 
-	aesGCM, err := cipher.NewGCM(block)
+	nonce := make([]byte, aesGCM.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return aesGCM.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// OpenWithKey decrypts data produced by SealWithKey
+func OpenWithKey(key, ciphertext []byte) ([]byte, error) {
+	aesGCM, err := newGCM(key)
 	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %w", err)
+		return nil, err
 	}
 
 	nonceSize := aesGCM.NonceSize()
-	if len(data) < nonceSize {
-		return "", errors.New("ciphertext too short")
+	if len(ciphertext) < nonceSize {
+		return nil, errors.New("ciphertext too short")
 	}
 
-	nonce, ciphertextBytes := data[:nonceSize], data[nonceSize:]
-	plaintext, err := aesGCM.Open(nil, nonce, ciphertextBytes, nil)
+	nonce, sealed := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	plaintext, err := aesGCM.Open(nil, nonce, sealed, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to decrypt: %w", err)
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
 	}
 
-	return string(plaintext), nil
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, errors.New("encryption key must be 32 bytes")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aesGCM, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return aesGCM, nil
 }
@@ -0,0 +1,145 @@
+package crypto
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sahabatharianmu/OpenMind/config"
+)
+
+// Key providers selectable with the security.key_provider setting
+const (
+	KeyProviderLocal = "local"
+	KeyProviderFake  = "fake"
+)
+
+// KeyProvider wraps and unwraps data encryption keys with a master key that
+// stays inside the provider. Only wrapped data keys are ever stored, so the
+// database alone cannot decrypt anything. A provider backed by Vault or a
+// cloud KMS can be added by implementing this interface.
+type KeyProvider interface {
+	// KeyID identifies the master key new data keys are wrapped with
+	KeyID() string
+	Wrap(ctx context.Context, dataKey []byte) ([]byte, error)
+	// Unwrap recovers a data key wrapped with the master key identified by
+	// keyID
+	Unwrap(ctx context.Context, wrapped []byte, keyID string) ([]byte, error)
+}
+
+// NewKeyProvider creates the key provider selected in the configuration
+func NewKeyProvider(cfg *config.Config) (KeyProvider, error) {
+	switch cfg.Security.KeyProvider {
+	case KeyProviderLocal, "":
+		masterKey := cfg.Security.MasterKey
+		if cfg.Security.MasterKeyFile != "" {
+			data, err := os.ReadFile(cfg.Security.MasterKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read master key file: %w", err)
+			}
+			masterKey = strings.TrimSpace(string(data))
+		}
+
+		retired := make([][]byte, 0, len(cfg.Security.RetiredMasterKeys))
+		for _, key := range cfg.Security.RetiredMasterKeys {
+			if key = strings.TrimSpace(key); key != "" {
+				retired = append(retired, []byte(key))
+			}
+		}
+		return NewLocalKeyProvider([]byte(masterKey), retired...)
+	case KeyProviderFake:
+		return NewFakeKeyProvider()
+	default:
+		return nil, fmt.Errorf("unknown key provider %q", cfg.Security.KeyProvider)
+	}
+}
+
+// GenerateDataKey returns a new random data encryption key
+func GenerateDataKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	return key, nil
+}
+
+// LocalKeyProvider wraps data keys with AES-GCM under a master key read from
+// the environment or a file. Retired master keys are kept to unwrap data keys
+// wrapped before the master key was changed.
+type LocalKeyProvider struct {
+	masterKey []byte
+	keyID     string
+	retired   map[string][]byte
+}
+
+// NewLocalKeyProvider creates a provider for a 32-byte master key and any
+// retired ones. Key IDs are fingerprints of the keys, so data keys wrapped
+// with a different master key are recognized as such.
+func NewLocalKeyProvider(masterKey []byte, retired ...[]byte) (*LocalKeyProvider, error) {
+	if len(masterKey) == 0 {
+		return nil, errors.New("master key is not configured")
+	}
+	if len(masterKey) != KeySize {
+		return nil, errors.New("master key must be 32 bytes")
+	}
+
+	p := &LocalKeyProvider{
+		masterKey: masterKey,
+		keyID:     localKeyID(masterKey),
+		retired:   make(map[string][]byte, len(retired)),
+	}
+	for _, key := range retired {
+		if len(key) != KeySize {
+			return nil, errors.New("retired master keys must be 32 bytes")
+		}
+		if id := localKeyID(key); id != p.keyID {
+			p.retired[id] = key
+		}
+	}
+	return p, nil
+}
+
+func localKeyID(masterKey []byte) string {
+	sum := sha256.Sum256(masterKey)
+	return KeyProviderLocal + ":" + hex.EncodeToString(sum[:8])
+}
+
+func (p *LocalKeyProvider) KeyID() string {
+	return p.keyID
+}
+
+func (p *LocalKeyProvider) Wrap(_ context.Context, dataKey []byte) ([]byte, error) {
+	return SealWithKey(p.masterKey, dataKey)
+}
+
+func (p *LocalKeyProvider) Unwrap(_ context.Context, wrapped []byte, keyID string) ([]byte, error) {
+	if keyID == p.keyID {
+		return OpenWithKey(p.masterKey, wrapped)
+	}
+	if key, ok := p.retired[keyID]; ok {
+		return OpenWithKey(key, wrapped)
+	}
+	return nil, fmt.Errorf("data key was wrapped with master key %q, which is not configured", keyID)
+}
+
+// NewFakeKeyProvider creates a provider with a random master key held only in
+// memory, for tests and throwaway environments. Data keys it wraps cannot be
+// unwrapped once the process exits.
+func NewFakeKeyProvider() (*LocalKeyProvider, error) {
+	masterKey, err := GenerateDataKey()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(masterKey)
+	return &LocalKeyProvider{
+		masterKey: masterKey,
+		keyID:     KeyProviderFake + ":" + hex.EncodeToString(sum[:8]),
+	}, nil
+}
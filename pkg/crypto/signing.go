@@ -0,0 +1,20 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// GenerateSigningKey returns a new Ed25519 key pair for electronic signatures
+func GenerateSigningKey() (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	return ed25519.GenerateKey(rand.Reader)
+}
+
+// SigningKeyFingerprint identifies a public signing key by the hex SHA-256
+// of its bytes
+func SigningKeyFingerprint(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:])
+}
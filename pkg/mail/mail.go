@@ -0,0 +1,123 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/sahabatharianmu/OpenMind/config"
+	"github.com/sahabatharianmu/OpenMind/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const dialTimeout = 10 * time.Second
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers transactional email
+type Sender interface {
+	Send(msg Message) error
+}
+
+// NewSender returns the sender for the configured email provider. Providers
+// without a client yet fall back to logging so local setups keep working.
+func NewSender(cfg *config.Config, log logger.Logger) Sender {
+	switch cfg.Email.Provider {
+	case "smtp":
+		return &smtpSender{config: cfg.Email, log: log}
+	default:
+		log.Warn("Email provider not supported, emails will only be logged", zap.String("provider", cfg.Email.Provider))
+		return &logSender{log: log}
+	}
+}
+
+type smtpSender struct {
+	config config.EmailConfig
+	log    logger.Logger
+}
+
+func (s *smtpSender) Send(msg Message) error {
+	smtpCfg := s.config.SMTP
+	addr := net.JoinHostPort(smtpCfg.Host, strconv.Itoa(smtpCfg.Port))
+
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, smtpCfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to create SMTP client: %w", err)
+	}
+	defer client.Close()
+
+	if smtpCfg.TLS {
+		if err := client.StartTLS(&tls.Config{ServerName: smtpCfg.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if smtpCfg.Username != "" {
+		auth := smtp.PlainAuth("", smtpCfg.Username, smtpCfg.Password, smtpCfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("failed to authenticate with SMTP server: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.config.FromEmail); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("failed to set recipient: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to open message body: %w", err)
+	}
+	if _, err := w.Write(s.build(msg)); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	s.log.Info("Email sent", zap.String("to", msg.To), zap.String("subject", msg.Subject))
+	return client.Quit()
+}
+
+func (s *smtpSender) build(msg Message) []byte {
+	from := netmail.Address{Name: s.config.FromName, Address: s.config.FromEmail}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(msg.Body)
+	return buf.Bytes()
+}
+
+type logSender struct {
+	log logger.Logger
+}
+
+func (s *logSender) Send(msg Message) error {
+	s.log.Info("Email not delivered (no provider configured)", zap.String("to", msg.To), zap.String("subject", msg.Subject))
+	return nil
+}
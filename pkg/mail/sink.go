@@ -0,0 +1,184 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const sinkIdleTimeout = time.Minute
+
+// ReceivedMessage is an email accepted by a Sink
+type ReceivedMessage struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// Sink is a minimal in-process SMTP server that accepts every message and
+// keeps it in memory. Point the smtp provider at it in tests and local
+// development to capture outgoing email without delivering it. It supports
+// neither TLS nor authentication, so leave smtp.tls off and the username
+// empty.
+type Sink struct {
+	listener net.Listener
+
+	// OnMessage, when set, is called for every message after it is stored
+	OnMessage func(ReceivedMessage)
+
+	mu       sync.Mutex
+	messages []ReceivedMessage
+	wg       sync.WaitGroup
+}
+
+// NewSink starts a sink listening on addr, such as "localhost:2525" or
+// "127.0.0.1:0" for a random free port
+func NewSink(addr string) (*Sink, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start SMTP sink: %w", err)
+	}
+
+	s := &Sink{listener: listener}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr returns the address the sink is listening on
+func (s *Sink) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Messages returns a copy of every message received so far
+func (s *Sink) Messages() []ReceivedMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ReceivedMessage(nil), s.messages...)
+}
+
+// Reset discards the received messages
+func (s *Sink) Reset() {
+	s.mu.Lock()
+	s.messages = nil
+	s.mu.Unlock()
+}
+
+// Close stops accepting connections and waits for open ones to finish
+func (s *Sink) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Sink) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Sink) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	reply := func(line string) {
+		_, _ = w.WriteString(line + "\r\n")
+		_ = w.Flush()
+	}
+
+	reply("220 localhost OpenMind SMTP sink ready")
+
+	var msg ReceivedMessage
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(sinkIdleTimeout))
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			msg = ReceivedMessage{From: addressArg(arg)}
+			reply("250 OK")
+		case "RCPT":
+			msg.To = append(msg.To, addressArg(arg))
+			reply("250 OK")
+		case "DATA":
+			if len(msg.To) == 0 {
+				reply("503 Need RCPT before DATA")
+				continue
+			}
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := readData(r)
+			if err != nil {
+				return
+			}
+			msg.Data = data
+			s.store(msg)
+			msg = ReceivedMessage{}
+			reply("250 OK")
+		case "RSET":
+			msg = ReceivedMessage{}
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (s *Sink) store(msg ReceivedMessage) {
+	s.mu.Lock()
+	s.messages = append(s.messages, msg)
+	s.mu.Unlock()
+
+	if s.OnMessage != nil {
+		s.OnMessage(msg)
+	}
+}
+
+// addressArg extracts the address from "FROM:<a@b>" or "TO:<a@b>"
+func addressArg(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	addr, _, _ = strings.Cut(strings.TrimSpace(addr), " ")
+	return strings.Trim(addr, "<>")
+}
+
+// readData reads a DATA section up to the terminating dot line, undoing dot
+// stuffing
+func readData(r *bufio.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, errors.New("connection closed during DATA")
+		}
+		if line == ".\r\n" || line == ".\n" {
+			return buf.Bytes(), nil
+		}
+		buf.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
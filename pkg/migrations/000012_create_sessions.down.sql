@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_sessions_user;
+DROP INDEX IF EXISTS idx_sessions_token;
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS mfa_recovery_codes;
+
+ALTER TABLE organizations DROP COLUMN IF EXISTS require_mfa;
+
+ALTER TABLE users DROP COLUMN IF EXISTS mfa_last_used_step;
+ALTER TABLE users DROP COLUMN IF EXISTS mfa_secret;
+ALTER TABLE users DROP COLUMN IF EXISTS mfa_enabled;
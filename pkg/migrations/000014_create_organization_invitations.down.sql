@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_organization_invitations_email;
+DROP INDEX IF EXISTS idx_organization_invitations_org;
+DROP TABLE IF EXISTS organization_invitations;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_organization_roles_org_name;
+DROP TABLE IF EXISTS organization_roles;
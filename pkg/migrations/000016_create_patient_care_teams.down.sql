@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_patient_care_team_members_user;
+DROP INDEX IF EXISTS idx_patient_care_team_members_patient_user;
+DROP TABLE IF EXISTS patient_care_team_members;
+DROP INDEX IF EXISTS idx_patients_primary_clinician;
+ALTER TABLE patients DROP COLUMN IF EXISTS primary_clinician_id;
@@ -0,0 +1,44 @@
+-- Care teams: a patient's primary clinician plus staff explicitly granted
+-- access. Members without the patients:all permission only see patients on
+-- their care teams.
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS primary_clinician_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_patients_primary_clinician ON patients(primary_clinician_id);
+
+CREATE TABLE IF NOT EXISTS patient_care_team_members (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    patient_id UUID NOT NULL REFERENCES patients(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    granted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_patient_care_team_members_patient_user ON patient_care_team_members(patient_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_patient_care_team_members_user ON patient_care_team_members(user_id);
+
+-- Keep existing access working: the creator becomes the primary clinician
+-- and clinicians who already see the patient join the care team.
+UPDATE patients SET primary_clinician_id = created_by
+WHERE primary_clinician_id IS NULL AND EXISTS (SELECT 1 FROM users WHERE users.id = patients.created_by);
+
+INSERT INTO patient_care_team_members (organization_id, patient_id, user_id)
+SELECT DISTINCT p.organization_id, p.id, a.clinician_id
+FROM appointments a
+JOIN patients p ON p.id = a.patient_id
+JOIN users u ON u.id = a.clinician_id
+WHERE a.deleted_at IS NULL AND a.clinician_id IS DISTINCT FROM p.primary_clinician_id
+ON CONFLICT DO NOTHING;
+
+INSERT INTO patient_care_team_members (organization_id, patient_id, user_id)
+SELECT DISTINCT p.organization_id, p.id, n.clinician_id
+FROM clinical_notes n
+JOIN patients p ON p.id = n.patient_id
+JOIN users u ON u.id = n.clinician_id
+WHERE n.deleted_at IS NULL AND n.clinician_id IS DISTINCT FROM p.primary_clinician_id
+ON CONFLICT DO NOTHING;
+
+-- Organizations that customized the admin role keep full patient access
+UPDATE organization_roles
+SET permissions = permissions || '["patients:all", "care_team:manage"]'::jsonb
+WHERE name = 'admin' AND NOT permissions ? 'patients:all';
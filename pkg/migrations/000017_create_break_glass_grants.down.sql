@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_patient_break_glass_grants_review;
+DROP INDEX IF EXISTS idx_patient_break_glass_grants_user;
+DROP TABLE IF EXISTS patient_break_glass_grants;
@@ -0,0 +1,22 @@
+-- Break-the-glass grants give a member temporary emergency access to a
+-- patient outside their care teams. Every grant waits for admin review.
+CREATE TABLE IF NOT EXISTS patient_break_glass_grants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    patient_id UUID NOT NULL REFERENCES patients(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    review_note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_patient_break_glass_grants_user ON patient_break_glass_grants(user_id, expires_at);
+CREATE INDEX IF NOT EXISTS idx_patient_break_glass_grants_review ON patient_break_glass_grants(organization_id, reviewed_at);
+
+-- Organizations that customized the admin role can review break-the-glass access
+UPDATE organization_roles
+SET permissions = permissions || '["break_glass:review"]'::jsonb
+WHERE name = 'admin' AND NOT permissions ? 'break_glass:review';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_audit_logs_api_key;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS api_key_id;
+DROP INDEX IF EXISTS idx_organization_api_keys_org;
+DROP TABLE IF EXISTS organization_api_keys;
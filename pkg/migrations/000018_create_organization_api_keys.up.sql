@@ -0,0 +1,21 @@
+-- API keys let external systems call the API on behalf of an organization.
+-- Only the hash of the key is stored; the prefix helps admins recognise it.
+CREATE TABLE IF NOT EXISTS organization_api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    permissions JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_by UUID NOT NULL REFERENCES users(id),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_api_keys_org ON organization_api_keys(organization_id);
+
+-- Calls made with an API key are attributed to the key as well as its creator
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS api_key_id UUID;
+CREATE INDEX IF NOT EXISTS idx_audit_logs_api_key ON audit_logs(api_key_id);
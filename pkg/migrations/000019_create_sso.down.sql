@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_sso_login_requests_expires;
+DROP TABLE IF EXISTS sso_login_requests;
+DROP INDEX IF EXISTS idx_user_identities_user;
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS organization_sso_configs;
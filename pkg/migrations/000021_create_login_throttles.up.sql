@@ -0,0 +1,11 @@
+-- Failed login tracking for brute-force protection. Rows are keyed by the
+-- attempted email or the client IP, so unknown emails are throttled exactly
+-- like registered ones and lockouts do not reveal which accounts exist.
+CREATE TABLE IF NOT EXISTS login_throttles (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_throttles_last_failure ON login_throttles(last_failure_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_breached_password_hashes_hash;
+DROP TABLE IF EXISTS breached_password_hashes;
+DROP INDEX IF EXISTS idx_password_history_user;
+DROP TABLE IF EXISTS password_history;
+ALTER TABLE organizations DROP COLUMN IF EXISTS password_max_age_days;
+ALTER TABLE users DROP COLUMN IF EXISTS password_changed_at;
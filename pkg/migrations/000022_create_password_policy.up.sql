@@ -0,0 +1,25 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- 0 disables password expiry for the organization
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS password_max_age_days INTEGER NOT NULL DEFAULT 0;
+
+-- Previous password hashes, kept to stop users from reusing recent passwords
+CREATE TABLE IF NOT EXISTS password_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_history_user ON password_history(user_id, created_at DESC);
+
+-- SHA-1 hashes of breached passwords uploaded by organization admins, in
+-- addition to the list bundled with the application
+CREATE TABLE IF NOT EXISTS breached_password_hashes (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    hash CHAR(40) NOT NULL,
+    PRIMARY KEY (organization_id, hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_breached_password_hashes_hash ON breached_password_hashes(hash);
@@ -0,0 +1,9 @@
+-- When the session last confirmed the user's password or MFA code. Sensitive
+-- actions require this to be recent. Existing sessions count from sign-in.
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS reauthenticated_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE sessions SET reauthenticated_at = COALESCE(created_at, CURRENT_TIMESTAMP) WHERE reauthenticated_at IS NULL;
+
+ALTER TABLE sessions
+    ALTER COLUMN reauthenticated_at SET DEFAULT CURRENT_TIMESTAMP,
+    ALTER COLUMN reauthenticated_at SET NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_login_ips;
+ALTER TABLE organizations DROP COLUMN IF EXISTS ip_allowlist;
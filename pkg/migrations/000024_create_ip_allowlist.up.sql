@@ -0,0 +1,11 @@
+-- CIDR ranges members may reach the organization from; empty allows any IP
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS ip_allowlist JSONB NOT NULL DEFAULT '[]';
+
+-- IP addresses each user has signed in from, to alert on logins from new ones
+CREATE TABLE IF NOT EXISTS user_login_ips (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip_address VARCHAR(45) NOT NULL,
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, ip_address)
+);
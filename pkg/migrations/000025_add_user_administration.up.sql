@@ -0,0 +1,7 @@
+-- Members deactivated by an organization admin keep their records and
+-- history but can no longer sign in to or act within the organization
+ALTER TABLE organization_members ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP WITH TIME ZONE;
+
+-- Set when an admin forces a password reset: password sign-in is refused
+-- until the user chooses a new password through the emailed reset link
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
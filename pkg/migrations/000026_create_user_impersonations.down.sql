@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_audit_logs_impersonator;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS impersonator_id;
+DROP INDEX IF EXISTS idx_user_impersonations_org;
+DROP TABLE IF EXISTS user_impersonations;
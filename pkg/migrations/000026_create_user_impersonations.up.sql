@@ -0,0 +1,26 @@
+-- Impersonation ("view as") lets an administrator use the application as one
+-- of their members to reproduce what that member sees. Each one is
+-- time-limited and read-only unless the administrator allowed changes.
+CREATE TABLE IF NOT EXISTS user_impersonations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    impersonator_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    read_only BOOLEAN NOT NULL DEFAULT TRUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_impersonations_org ON user_impersonations(organization_id, created_at);
+
+-- Requests made while impersonating are attributed to the impersonated user
+-- and to the administrator behind them
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS impersonator_id UUID;
+CREATE INDEX IF NOT EXISTS idx_audit_logs_impersonator ON audit_logs(impersonator_id);
+
+-- Organizations that customized the admin role can impersonate members
+UPDATE organization_roles
+SET permissions = permissions || '["users:impersonate"]'::jsonb
+WHERE name = 'admin' AND NOT permissions ? 'users:impersonate';
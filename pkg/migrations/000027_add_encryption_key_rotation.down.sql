@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS encryption_key_rotations;
+
+CREATE OR REPLACE FUNCTION prevent_signed_note_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.is_signed = TRUE THEN
+        RAISE EXCEPTION 'Cannot update a signed clinical note. Signed notes are immutable for compliance.';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_clinical_note_attachments_key_id;
+DROP INDEX IF EXISTS idx_clinical_note_addendums_key_id;
+DROP INDEX IF EXISTS idx_clinical_notes_key_id;
+ALTER TABLE clinical_note_attachments DROP COLUMN IF EXISTS key_id;
+ALTER TABLE clinical_note_addendums DROP COLUMN IF EXISTS key_id;
@@ -0,0 +1,46 @@
+-- Track the encryption key of addendums and attachments, as notes already do
+ALTER TABLE clinical_note_addendums ADD COLUMN IF NOT EXISTS key_id VARCHAR(255) NOT NULL DEFAULT 'v1';
+ALTER TABLE clinical_note_attachments ADD COLUMN IF NOT EXISTS key_id VARCHAR(255) NOT NULL DEFAULT 'v1';
+UPDATE clinical_notes SET key_id = 'v1' WHERE key_id IS NULL OR key_id = '';
+
+CREATE INDEX IF NOT EXISTS idx_clinical_notes_key_id ON clinical_notes(key_id);
+CREATE INDEX IF NOT EXISTS idx_clinical_note_addendums_key_id ON clinical_note_addendums(key_id);
+CREATE INDEX IF NOT EXISTS idx_clinical_note_attachments_key_id ON clinical_note_attachments(key_id);
+
+-- Signed notes stay immutable, except for re-encryption under a new key,
+-- which changes only the ciphertext, nonce and key ID
+CREATE OR REPLACE FUNCTION prevent_signed_note_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.is_signed = TRUE THEN
+        IF NEW.key_id IS DISTINCT FROM OLD.key_id
+            AND to_jsonb(NEW) - 'content_encrypted' - 'nonce' - 'key_id'
+                = to_jsonb(OLD) - 'content_encrypted' - 'nonce' - 'key_id' THEN
+            RETURN NEW;
+        END IF;
+        RAISE EXCEPTION 'Cannot update a signed clinical note. Signed notes are immutable for compliance.';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Progress of re-encrypting clinical data under a new key. A run that is
+-- interrupted resumes from its cursors on the next start.
+CREATE TABLE IF NOT EXISTS encryption_key_rotations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    target_key_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    notes_cursor UUID,
+    addendums_cursor UUID,
+    attachments_cursor UUID,
+    notes_rotated BIGINT NOT NULL DEFAULT 0,
+    addendums_rotated BIGINT NOT NULL DEFAULT 0,
+    attachments_rotated BIGINT NOT NULL DEFAULT 0,
+    failures BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_encryption_key_rotations_status ON encryption_key_rotations(status);
@@ -0,0 +1,16 @@
+-- Per-organization data encryption keys, stored only wrapped by the master
+-- key. Destroying a key clears wrapped_key, which crypto-shreds everything
+-- encrypted with it.
+CREATE TABLE IF NOT EXISTS organization_data_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    wrapped_key BYTEA,
+    master_key_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    destroyed_at TIMESTAMP WITH TIME ZONE,
+    destroyed_by UUID REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- An organization has at most one key in use at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_data_keys_active
+    ON organization_data_keys(organization_id) WHERE destroyed_at IS NULL;
@@ -0,0 +1,14 @@
+-- Fails once rows have been encrypted, rather than losing their demographics
+ALTER TABLE patients ALTER COLUMN first_name SET NOT NULL;
+ALTER TABLE patients ALTER COLUMN last_name SET NOT NULL;
+ALTER TABLE patients ALTER COLUMN date_of_birth SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_patients_name_dob_index;
+DROP INDEX IF EXISTS idx_patients_phone_index;
+DROP INDEX IF EXISTS idx_patients_email_index;
+
+ALTER TABLE patients DROP COLUMN IF EXISTS name_dob_index;
+ALTER TABLE patients DROP COLUMN IF EXISTS phone_index;
+ALTER TABLE patients DROP COLUMN IF EXISTS email_index;
+ALTER TABLE patients DROP COLUMN IF EXISTS key_id;
+ALTER TABLE patients DROP COLUMN IF EXISTS demographics_encrypted;
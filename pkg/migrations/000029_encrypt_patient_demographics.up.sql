@@ -0,0 +1,17 @@
+-- Patient demographics are encrypted with the organization's data key. The
+-- blind indexes are keyed hashes that keep exact-match lookups by email,
+-- phone and name with date of birth possible. The application encrypts
+-- existing rows on startup and empties their plaintext columns.
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS demographics_encrypted BYTEA;
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS key_id VARCHAR(255);
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS email_index VARCHAR(64);
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS phone_index VARCHAR(64);
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS name_dob_index VARCHAR(64);
+
+ALTER TABLE patients ALTER COLUMN first_name DROP NOT NULL;
+ALTER TABLE patients ALTER COLUMN last_name DROP NOT NULL;
+ALTER TABLE patients ALTER COLUMN date_of_birth DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_patients_email_index ON patients(organization_id, email_index);
+CREATE INDEX IF NOT EXISTS idx_patients_phone_index ON patients(organization_id, phone_index);
+CREATE INDEX IF NOT EXISTS idx_patients_name_dob_index ON patients(organization_id, name_dob_index);
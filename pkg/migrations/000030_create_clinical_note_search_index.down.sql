@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS clinical_note_search_tokens;
+DROP TABLE IF EXISTS clinical_note_search_documents;
+ALTER TABLE organizations DROP COLUMN IF EXISTS note_search_enabled;
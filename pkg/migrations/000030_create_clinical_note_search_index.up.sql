@@ -0,0 +1,22 @@
+-- Opt-in keyword search of encrypted clinical notes. Tokens are keyed
+-- hashes of the normalized words of a note under the organization's data
+-- key, so the index never holds plaintext. A document row marks a note as
+-- indexed, including notes without any words.
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS note_search_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS clinical_note_search_documents (
+    note_id UUID PRIMARY KEY REFERENCES clinical_notes(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    indexed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS clinical_note_search_tokens (
+    note_id UUID NOT NULL REFERENCES clinical_notes(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    token VARCHAR(64) NOT NULL,
+    frequency INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (note_id, token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_clinical_note_search_documents_org ON clinical_note_search_documents(organization_id);
+CREATE INDEX IF NOT EXISTS idx_clinical_note_search_tokens_org_token ON clinical_note_search_tokens(organization_id, token);
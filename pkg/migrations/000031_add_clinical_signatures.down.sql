@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS trigger_prevent_addendum_update ON clinical_note_addendums;
+DROP FUNCTION IF EXISTS prevent_addendum_update();
+
+ALTER TABLE clinical_note_addendums DROP COLUMN IF EXISTS signing_key_fingerprint;
+ALTER TABLE clinical_note_addendums DROP COLUMN IF EXISTS signature;
+ALTER TABLE clinical_note_addendums DROP COLUMN IF EXISTS signed_by;
+
+ALTER TABLE clinical_notes DROP COLUMN IF EXISTS signing_key_fingerprint;
+ALTER TABLE clinical_notes DROP COLUMN IF EXISTS signature;
+ALTER TABLE clinical_notes DROP COLUMN IF EXISTS signed_by;
+
+DROP TABLE IF EXISTS clinician_signing_keys;
@@ -0,0 +1,46 @@
+-- Per-clinician Ed25519 keys for electronic signatures of notes and
+-- addendums. The private key is stored encrypted with the organization's
+-- data key; the public key stays readable so signatures can always be
+-- verified.
+CREATE TABLE IF NOT EXISTS clinician_signing_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    public_key BYTEA NOT NULL,
+    private_key_encrypted BYTEA NOT NULL,
+    key_id VARCHAR(255) NOT NULL,
+    fingerprint VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- A clinician has at most one key in use per organization
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clinician_signing_keys_active
+    ON clinician_signing_keys(organization_id, user_id) WHERE revoked_at IS NULL;
+
+ALTER TABLE clinical_notes ADD COLUMN IF NOT EXISTS signed_by UUID REFERENCES users(id);
+ALTER TABLE clinical_notes ADD COLUMN IF NOT EXISTS signature BYTEA;
+ALTER TABLE clinical_notes ADD COLUMN IF NOT EXISTS signing_key_fingerprint VARCHAR(64);
+
+ALTER TABLE clinical_note_addendums ADD COLUMN IF NOT EXISTS signed_by UUID REFERENCES users(id);
+ALTER TABLE clinical_note_addendums ADD COLUMN IF NOT EXISTS signature BYTEA;
+ALTER TABLE clinical_note_addendums ADD COLUMN IF NOT EXISTS signing_key_fingerprint VARCHAR(64);
+
+-- Addendums are signed when written, so like signed notes they may only be
+-- re-encrypted under a new key
+CREATE OR REPLACE FUNCTION prevent_addendum_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF to_jsonb(NEW) - 'content_encrypted' - 'nonce' - 'key_id'
+        = to_jsonb(OLD) - 'content_encrypted' - 'nonce' - 'key_id' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'Cannot update a clinical note addendum. Addendums are immutable for compliance.';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_prevent_addendum_update ON clinical_note_addendums;
+CREATE TRIGGER trigger_prevent_addendum_update
+    BEFORE UPDATE ON clinical_note_addendums
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_addendum_update();
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS clinical_note_reviews;
+
+DROP INDEX IF EXISTS idx_clinical_notes_status;
+ALTER TABLE clinical_notes DROP COLUMN IF EXISTS co_signing_key_fingerprint;
+ALTER TABLE clinical_notes DROP COLUMN IF EXISTS co_signature;
+ALTER TABLE clinical_notes DROP COLUMN IF EXISTS co_signed_at;
+ALTER TABLE clinical_notes DROP COLUMN IF EXISTS co_signed_by;
+ALTER TABLE clinical_notes DROP COLUMN IF EXISTS submitted_at;
+ALTER TABLE clinical_notes DROP COLUMN IF EXISTS status;
+
+DROP TABLE IF EXISTS clinical_supervisions;
@@ -0,0 +1,48 @@
+-- Supervision of trainee clinicians, such as associates and interns, whose
+-- notes are only signed once their supervisor co-signs them
+CREATE TABLE IF NOT EXISTS clinical_supervisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    supervisor_id UUID NOT NULL REFERENCES users(id),
+    supervisee_id UUID NOT NULL REFERENCES users(id),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ended_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    CHECK (supervisor_id <> supervisee_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clinical_supervisions_active
+    ON clinical_supervisions(organization_id, supervisor_id, supervisee_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_clinical_supervisions_supervisee
+    ON clinical_supervisions(organization_id, supervisee_id) WHERE ended_at IS NULL;
+
+-- Review state of notes: draft, pending_review, returned, signed or co_signed
+ALTER TABLE clinical_notes ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'draft';
+ALTER TABLE clinical_notes ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE clinical_notes ADD COLUMN IF NOT EXISTS co_signed_by UUID REFERENCES users(id);
+ALTER TABLE clinical_notes ADD COLUMN IF NOT EXISTS co_signed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE clinical_notes ADD COLUMN IF NOT EXISTS co_signature BYTEA;
+ALTER TABLE clinical_notes ADD COLUMN IF NOT EXISTS co_signing_key_fingerprint VARCHAR(64);
+
+-- Signed notes are immutable, so the trigger is lifted while their status is
+-- set
+ALTER TABLE clinical_notes DISABLE TRIGGER trigger_prevent_signed_note_update;
+UPDATE clinical_notes SET status = 'signed' WHERE is_signed = TRUE;
+ALTER TABLE clinical_notes ENABLE TRIGGER trigger_prevent_signed_note_update;
+
+CREATE INDEX IF NOT EXISTS idx_clinical_notes_status ON clinical_notes(organization_id, status);
+
+-- Supervisor decisions on submitted notes. Comments are encrypted with the
+-- organization's data key, like the notes themselves.
+CREATE TABLE IF NOT EXISTS clinical_note_reviews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    note_id UUID NOT NULL REFERENCES clinical_notes(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(id),
+    action VARCHAR(20) NOT NULL,
+    comment_encrypted BYTEA,
+    key_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_clinical_note_reviews_note_id ON clinical_note_reviews(note_id);
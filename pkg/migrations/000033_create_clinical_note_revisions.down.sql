@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trigger_prevent_note_revision_change ON clinical_note_revisions;
+DROP FUNCTION IF EXISTS prevent_note_revision_change();
+
+DROP TABLE IF EXISTS clinical_note_revisions;
@@ -0,0 +1,39 @@
+-- Every save of an unsigned note is kept as an encrypted revision, so that
+-- earlier versions of a draft can be viewed and compared
+CREATE TABLE IF NOT EXISTS clinical_note_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    note_id UUID NOT NULL REFERENCES clinical_notes(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    revision_number INTEGER NOT NULL,
+    author_id UUID NOT NULL REFERENCES users(id),
+    autosave BOOLEAN NOT NULL DEFAULT FALSE,
+    content_encrypted BYTEA NOT NULL,
+    key_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (note_id, revision_number)
+);
+
+-- Revisions never change, except for re-encryption under a new key, and none
+-- are added once their note is signed
+CREATE OR REPLACE FUNCTION prevent_note_revision_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        IF EXISTS (SELECT 1 FROM clinical_notes WHERE id = NEW.note_id AND is_signed = TRUE) THEN
+            RAISE EXCEPTION 'Cannot add a revision to a signed clinical note. Signed notes are immutable for compliance.';
+        END IF;
+        RETURN NEW;
+    END IF;
+    IF to_jsonb(NEW) - 'content_encrypted' - 'key_id'
+        = to_jsonb(OLD) - 'content_encrypted' - 'key_id' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'Cannot update a clinical note revision. Revisions are immutable.';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_prevent_note_revision_change ON clinical_note_revisions;
+CREATE TRIGGER trigger_prevent_note_revision_change
+    BEFORE INSERT OR UPDATE ON clinical_note_revisions
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_note_revision_change();
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_organization_sso_domains_verified;
+DROP TABLE IF EXISTS organization_sso_domains;
@@ -0,0 +1,24 @@
+-- Email domains an organization claims for single sign-on. A domain only
+-- routes sign-ins to the organization once ownership has been proven with a
+-- DNS TXT record carrying the verification token, and only one organization
+-- can hold a verified claim.
+CREATE TABLE IF NOT EXISTS organization_sso_domains (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organization_sso_configs(organization_id) ON DELETE CASCADE,
+    domain VARCHAR(255) NOT NULL,
+    verification_token VARCHAR(64) NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (organization_id, domain)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_sso_domains_verified
+    ON organization_sso_domains(domain) WHERE verified_at IS NOT NULL;
+
+-- Domains claimed before verification existed were never proven and must be
+-- verified like new ones
+INSERT INTO organization_sso_domains (organization_id, domain, verification_token)
+SELECT c.organization_id, d.domain, md5(random()::text || clock_timestamp()::text || d.domain)
+FROM organization_sso_configs c
+CROSS JOIN LATERAL jsonb_array_elements_text(c.allowed_domains) AS d(domain)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_sso_link_requests_expires;
+DROP TABLE IF EXISTS sso_link_requests;
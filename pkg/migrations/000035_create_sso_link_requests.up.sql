@@ -0,0 +1,16 @@
+-- Provider accounts waiting for an existing user with the same email to
+-- confirm the link with their password. Each is consumed by its
+-- confirmation.
+CREATE TABLE IF NOT EXISTS sso_link_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    issuer VARCHAR(500) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    groups JSONB NOT NULL DEFAULT '[]'::jsonb,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sso_link_requests_expires ON sso_link_requests(expires_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_sessions_organization;
+ALTER TABLE sessions DROP COLUMN IF EXISTS organization_id;
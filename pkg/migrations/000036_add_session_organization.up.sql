@@ -0,0 +1,6 @@
+-- The organization a session's current access token is scoped to, so a
+-- member deactivated in one organization is only signed out of that one.
+-- Sessions started before this is recorded stay NULL until they rotate.
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS organization_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_organization ON sessions(user_id, organization_id);
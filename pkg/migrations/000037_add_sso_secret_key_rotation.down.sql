@@ -0,0 +1,2 @@
+ALTER TABLE encryption_key_rotations DROP COLUMN IF EXISTS sso_secrets_rotated;
+ALTER TABLE encryption_key_rotations DROP COLUMN IF EXISTS sso_secrets_cursor;
@@ -0,0 +1,3 @@
+-- Progress of moving SSO client secrets to organization data keys
+ALTER TABLE encryption_key_rotations ADD COLUMN IF NOT EXISTS sso_secrets_cursor UUID;
+ALTER TABLE encryption_key_rotations ADD COLUMN IF NOT EXISTS sso_secrets_rotated BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+-- Set when an organization's data key is destroyed; no new data key is
+-- created for it afterwards
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS data_shredded_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE organizations o SET data_shredded_at = k.destroyed_at
+FROM organization_data_keys k
+WHERE k.organization_id = o.id AND k.destroyed_at IS NOT NULL AND o.data_shredded_at IS NULL;
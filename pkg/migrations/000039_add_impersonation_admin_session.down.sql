@@ -0,0 +1 @@
+ALTER TABLE user_impersonations DROP COLUMN IF EXISTS admin_session_id;
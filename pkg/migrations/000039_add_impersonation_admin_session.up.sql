@@ -0,0 +1,6 @@
+-- An impersonation ends with the administrator's session it was started from
+ALTER TABLE user_impersonations ADD COLUMN IF NOT EXISTS admin_session_id UUID;
+
+-- Running impersonations cannot be tied to a session; end them
+UPDATE user_impersonations SET ended_at = NOW()
+WHERE ended_at IS NULL AND admin_session_id IS NULL;
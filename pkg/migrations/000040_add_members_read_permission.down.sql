@@ -0,0 +1,2 @@
+UPDATE organization_roles
+SET permissions = permissions - 'members:read';
@@ -0,0 +1,5 @@
+-- Listing members now requires members:read. Every role could list them
+-- before, so customized roles keep that access.
+UPDATE organization_roles
+SET permissions = permissions || '["members:read"]'::jsonb
+WHERE NOT permissions ? 'members:read';
@@ -0,0 +1,393 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	httpTimeout = 10 * time.Second
+	// metadataTTL bounds how long discovery documents and signing keys are
+	// cached. Unknown key IDs always trigger a refresh so key rotation at the
+	// identity provider is picked up immediately.
+	metadataTTL   = time.Hour
+	maxBodySize   = 1 << 20
+	verifierBytes = 32
+)
+
+// ErrInvalidIDToken is returned when an ID token fails signature or claim checks
+var ErrInvalidIDToken = errors.New("invalid ID token")
+
+// Provider is the subset of an OpenID Provider's discovery document the
+// authorization code flow needs
+type Provider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Claims are the verified claims of an ID token
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified *bool
+	Name          string
+	Raw           map[string]interface{}
+}
+
+// Strings returns a claim holding a list of strings, such as group
+// memberships. A single string value is returned as a one-element list.
+func (c *Claims) Strings(name string) []string {
+	switch v := c.Raw[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+type cachedProvider struct {
+	provider  *Provider
+	fetchedAt time.Time
+}
+
+type cachedKeys struct {
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// Client talks to OpenID Providers. It is safe for concurrent use and caches
+// discovery documents and signing keys per issuer.
+type Client struct {
+	httpClient *http.Client
+	mu         sync.Mutex
+	providers  map[string]cachedProvider
+	keys       map[string]cachedKeys
+}
+
+// NewClient creates an OIDC client
+func NewClient() *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: httpTimeout},
+		providers:  make(map[string]cachedProvider),
+		keys:       make(map[string]cachedKeys),
+	}
+}
+
+// Discover loads the provider metadata published under the issuer's
+// /.well-known/openid-configuration
+func (c *Client) Discover(ctx context.Context, issuer string) (*Provider, error) {
+	issuer = strings.TrimRight(issuer, "/")
+
+	c.mu.Lock()
+	cached, ok := c.providers[issuer]
+	c.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < metadataTTL {
+		return cached.provider, nil
+	}
+
+	var provider Provider
+	if err := c.getJSON(ctx, issuer+"/.well-known/openid-configuration", &provider); err != nil {
+		return nil, fmt.Errorf("failed to load provider metadata: %w", err)
+	}
+	if strings.TrimRight(provider.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("provider metadata issuer %q does not match %q", provider.Issuer, issuer)
+	}
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, errors.New("provider metadata is missing required endpoints")
+	}
+
+	c.mu.Lock()
+	c.providers[issuer] = cachedProvider{provider: &provider, fetchedAt: time.Now()}
+	c.mu.Unlock()
+
+	return &provider, nil
+}
+
+// AuthCodeURL builds the URL that starts an authorization code flow with a
+// PKCE S256 challenge
+func (p *Provider) AuthCodeURL(clientID, redirectURI, state, nonce, codeVerifier string) string {
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {clientID},
+		"redirect_uri":          {redirectURI},
+		"scope":                 {"openid email profile"},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {CodeChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(p.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return p.AuthorizationEndpoint + separator + params.Encode()
+}
+
+// Exchange trades an authorization code for the raw ID token
+func (c *Client) Exchange(
+	ctx context.Context,
+	provider *Provider,
+	clientID, clientSecret, redirectURI, code, codeVerifier string,
+) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {codeVerifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxBodySize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %d: %s %s", resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", errors.New("token response did not include an ID token")
+	}
+
+	return body.IDToken, nil
+}
+
+// VerifyIDToken checks the ID token's signature against the provider's
+// published keys, and its issuer, audience, expiry and nonce
+func (c *Client) VerifyIDToken(
+	ctx context.Context,
+	provider *Provider,
+	rawIDToken, clientID, nonce string,
+) (*Claims, error) {
+	raw := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(
+		rawIDToken,
+		raw,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return c.signingKey(ctx, provider, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(provider.Issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if tokenNonce, _ := raw["nonce"].(string); tokenNonce == "" || tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	claims := &Claims{Raw: raw}
+	claims.Subject, _ = raw["sub"].(string)
+	claims.Email, _ = raw["email"].(string)
+	claims.Name, _ = raw["name"].(string)
+	if verified, ok := raw["email_verified"].(bool); ok {
+		claims.EmailVerified = &verified
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+// GenerateCodeVerifier returns a random PKCE code verifier
+func GenerateCodeVerifier() (string, error) {
+	b := make([]byte, verifierBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge derives the S256 PKCE challenge of a code verifier
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (c *Client) signingKey(ctx context.Context, provider *Provider, kid string) (interface{}, error) {
+	c.mu.Lock()
+	cached, ok := c.keys[provider.JWKSURI]
+	c.mu.Unlock()
+
+	fresh := ok && time.Since(cached.fetchedAt) < metadataTTL
+	if key := lookupKey(cached.keys, kid); fresh && key != nil {
+		return key, nil
+	}
+
+	keys, err := c.fetchKeys(ctx, provider.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.keys[provider.JWKSURI] = cachedKeys{keys: keys, fetchedAt: time.Now()}
+	c.mu.Unlock()
+
+	if key := lookupKey(keys, kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("no signing key found for key ID %q", kid)
+}
+
+// lookupKey finds the key by ID. Tokens without a key ID are accepted when
+// the provider publishes a single key.
+func lookupKey(keys map[string]interface{}, kid string) interface{} {
+	if key, ok := keys[kid]; ok {
+		return key
+	}
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+	return nil
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (c *Client) fetchKeys(ctx context.Context, jwksURI string) (map[string]interface{}, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := c.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Skip key types we cannot use rather than failing every login
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key parameter: %w", err)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (c *Client) getJSON(ctx context.Context, endpoint string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxBodySize)).Decode(target)
+}
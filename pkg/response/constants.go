@@ -1,13 +1,15 @@
 package response
 
 const (
-	ErrorCodeValidation         = "VALIDATION_ERROR"
-	ErrorCodeAuthentication     = "AUTHENTICATION_ERROR"
-	ErrorCodeAuthorization      = "AUTHORIZATION_ERROR"
-	ErrorCodeNotFound           = "NOT_FOUND"
-	ErrorCodeConflict           = "CONFLICT"
-	ErrorCodeInternal           = "INTERNAL_ERROR"
-	ErrorCodeBadRequest         = "BAD_REQUEST"
-	ErrorCodeRateLimit          = "RATE_LIMIT_EXCEEDED"
-	ErrorCodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	ErrorCodeValidation               = "VALIDATION_ERROR"
+	ErrorCodeAuthentication           = "AUTHENTICATION_ERROR"
+	ErrorCodeAuthorization            = "AUTHORIZATION_ERROR"
+	ErrorCodeNotFound                 = "NOT_FOUND"
+	ErrorCodeConflict                 = "CONFLICT"
+	ErrorCodeInternal                 = "INTERNAL_ERROR"
+	ErrorCodeBadRequest               = "BAD_REQUEST"
+	ErrorCodeRateLimit                = "RATE_LIMIT_EXCEEDED"
+	ErrorCodeServiceUnavailable       = "SERVICE_UNAVAILABLE"
+	ErrorCodeSessionIdle              = "SESSION_IDLE_TIMEOUT"
+	ErrorCodeReauthenticationRequired = "REAUTHENTICATION_REQUIRED"
 )
@@ -83,6 +83,18 @@ func Forbidden(c *app.RequestContext, message string) {
 	Error(c, consts.StatusForbidden, ErrorCodeAuthorization, message, nil)
 }
 
+// ReauthenticationRequired rejects a sensitive action until the user confirms
+// their password or MFA code again
+func ReauthenticationRequired(c *app.RequestContext) {
+	Error(
+		c,
+		consts.StatusForbidden,
+		ErrorCodeReauthenticationRequired,
+		"Please confirm your password or verification code to continue",
+		nil,
+	)
+}
+
 // NotFound creates a not found response
 func NotFound(c *app.RequestContext, resource string) {
 	message := "Resource not found"
@@ -177,6 +189,8 @@ func getErrorCode(statusCode int) string {
 		return ErrorCodeNotFound
 	case consts.StatusConflict:
 		return ErrorCodeConflict
+	case consts.StatusTooManyRequests:
+		return ErrorCodeRateLimit
 	default:
 		return ErrorCodeInternal
 	}
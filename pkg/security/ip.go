@@ -0,0 +1,67 @@
+package security
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// ParseCIDRs parses a list of CIDR ranges. A bare IP address is accepted as a
+// single-host range. The returned ranges are in canonical form.
+func ParseCIDRs(entries []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address or CIDR range: %q", entry)
+			}
+			bits := net.IPv6len * 8
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, net.IPv4len*8
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP address or CIDR range: %q", entry)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// IPInRanges reports whether ipAddress falls within any of the ranges
+func IPInRanges(nets []*net.IPNet, ipAddress string) bool {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIPFunc resolves the client IP from X-Forwarded-For or X-Real-IP only
+// when the request comes from one of the trusted proxies, and from the
+// connection otherwise. Hertz trusts these headers from any peer by default,
+// which would let clients spoof their address past IP allowlists.
+func ClientIPFunc(trustedProxies []string) (app.ClientIP, error) {
+	nets, err := ParseCIDRs(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.ClientIPWithOption(app.ClientIPOptions{
+		RemoteIPHeaders: []string{"X-Forwarded-For", "X-Real-IP"},
+		TrustedCIDRs:    nets,
+	}), nil
+}
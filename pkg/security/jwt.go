@@ -10,14 +10,36 @@ import (
 	"github.com/sahabatharianmu/OpenMind/config"
 )
 
-// JWTClaims represents the claims in a JWT token
+// Token types carried in the token_type claim
+const (
+	TokenTypeAccess         = "access"
+	TokenTypeRefresh        = "refresh"
+	TokenTypeMFAChallenge   = "mfa_challenge"
+	TokenTypePasswordChange = "password_change"
+)
+
+// JWTClaims represents the claims in a JWT token. ImpersonatorID is set on
+// impersonation tokens, which act as UserID on behalf of that administrator.
 type JWTClaims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
-	Role   string    `json:"role"`
+	UserID         uuid.UUID  `json:"user_id"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	TokenType      string     `json:"token_type"`
+	ImpersonatorID *uuid.UUID `json:"impersonator_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// TokenPair holds a newly issued access and refresh token together with
+// the identifiers needed to track them server-side
+type TokenPair struct {
+	AccessToken      string
+	AccessTokenID    uuid.UUID
+	RefreshToken     string
+	RefreshTokenID   uuid.UUID
+	RefreshExpiresAt time.Time
+}
+
 // JWTService handles JWT token operations
 type JWTService struct {
 	config *config.Config
@@ -30,100 +52,195 @@ func NewJWTService(cfg *config.Config) *JWTService {
 	}
 }
 
-// GenerateTokens generates both access and refresh tokens
+// GenerateTokens generates both access and refresh tokens scoped to the
+// user's active organization
 func (s *JWTService) GenerateTokens(
-	userID uuid.UUID,
+	userID, organizationID uuid.UUID,
 	email, role string,
-) (string, string, error) {
+) (*TokenPair, error) {
 	now := time.Now()
+	accessTokenID := uuid.New()
+	refreshTokenID := uuid.New()
+	refreshExpiresAt := now.Add(s.config.Security.JWTRefreshExpiry)
 
 	accessClaims := JWTClaims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Email:          email,
+		Role:           role,
+		TokenType:      TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.config.Security.JWTAccessExpiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    s.config.Application.Name,
 			Subject:   userID.String(),
-			ID:        uuid.New().String(),
+			ID:        accessTokenID.String(),
 		},
 	}
 
-	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).
-		SignedString([]byte(s.config.Security.JWTSecretKey))
+	accessToken, err := s.sign(accessClaims)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to generate access token: %w", err)
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	refreshClaims := jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(now.Add(s.config.Security.JWTRefreshExpiry)),
-		IssuedAt:  jwt.NewNumericDate(now),
-		NotBefore: jwt.NewNumericDate(now),
-		Issuer:    s.config.Application.Name,
-		Subject:   userID.String(),
-		ID:        uuid.New().String(),
+	refreshClaims := JWTClaims{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		TokenType:      TokenTypeRefresh,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(refreshExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    s.config.Application.Name,
+			Subject:   userID.String(),
+			ID:        refreshTokenID.String(),
+		},
 	}
 
-	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).
-		SignedString([]byte(s.config.Security.JWTSecretKey))
+	refreshToken, err := s.sign(refreshClaims)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	return accessToken, refreshToken, nil
+	return &TokenPair{
+		AccessToken:      accessToken,
+		AccessTokenID:    accessTokenID,
+		RefreshToken:     refreshToken,
+		RefreshTokenID:   refreshTokenID,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
 }
 
-// ValidateToken validates a JWT token and returns the claims
-func (s *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(s.config.Security.JWTSecretKey), nil
-	})
+// GenerateImpersonationToken issues an access token that acts as the user on
+// behalf of the impersonating administrator. It has no refresh token and
+// expires with the impersonation.
+func (s *JWTService) GenerateImpersonationToken(
+	impersonationID, impersonatorID, userID, organizationID uuid.UUID,
+	email, role string,
+	expiresAt time.Time,
+) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Email:          email,
+		Role:           role,
+		TokenType:      TokenTypeAccess,
+		ImpersonatorID: &impersonatorID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    s.config.Application.Name,
+			Subject:   userID.String(),
+			ID:        impersonationID.String(),
+		},
+	}
 
+	token, err := s.sign(claims)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse token: %w", err)
+		return "", fmt.Errorf("failed to generate impersonation token: %w", err)
+	}
+	return token, nil
+}
+
+// GenerateMFAChallengeToken issues a short-lived token proving the password
+// step of a login succeeded. It cannot be used to access protected routes.
+func (s *JWTService) GenerateMFAChallengeToken(userID uuid.UUID, email string) (string, error) {
+	token, err := s.challenge(userID, email, TokenTypeMFAChallenge)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate MFA challenge token: %w", err)
 	}
+	return token, nil
+}
 
-	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
-		return claims, nil
+// GeneratePasswordChangeToken issues a short-lived token for a login that
+// was stopped because the password expired. Like MFA challenges it cannot be
+// used to access protected routes.
+func (s *JWTService) GeneratePasswordChangeToken(userID uuid.UUID, email string) (string, error) {
+	token, err := s.challenge(userID, email, TokenTypePasswordChange)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate password change token: %w", err)
 	}
+	return token, nil
+}
 
-	return nil, errors.New("invalid token")
+// ValidatePasswordChangeToken validates a password change token and returns the claims
+func (s *JWTService) ValidatePasswordChangeToken(tokenString string) (*JWTClaims, error) {
+	return s.validate(tokenString, TokenTypePasswordChange)
 }
 
-// RefreshToken generates a new access token from a refresh token
-func (s *JWTService) RefreshToken(refreshToken string) (string, error) {
-	token, err := jwt.ParseWithClaims(
-		refreshToken,
-		&jwt.RegisteredClaims{},
-		func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(s.config.Security.JWTSecretKey), nil
+func (s *JWTService) challenge(userID uuid.UUID, email, tokenType string) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		UserID:    userID,
+		Email:     email,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.config.Security.MFAChallengeExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    s.config.Application.Name,
+			Subject:   userID.String(),
+			ID:        uuid.New().String(),
 		},
-	)
+	}
+
+	return s.sign(claims)
+}
+
+// ValidateMFAChallengeToken validates an MFA challenge token and returns the claims
+func (s *JWTService) ValidateMFAChallengeToken(tokenString string) (*JWTClaims, error) {
+	return s.validate(tokenString, TokenTypeMFAChallenge)
+}
 
+// ValidateToken validates an access token and returns the claims
+func (s *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
+	return s.validate(tokenString, TokenTypeAccess)
+}
+
+// ValidateRefreshToken validates a refresh token and returns the claims.
+// Whether the token is still usable is decided by the caller against the
+// server-side token store.
+func (s *JWTService) ValidateRefreshToken(tokenString string) (*JWTClaims, error) {
+	claims, err := s.validate(tokenString, TokenTypeRefresh)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse refresh token: %w", err)
+		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*jwt.RegisteredClaims); ok && token.Valid {
-		// Generate new access token
-		_, err := uuid.Parse(claims.Subject)
-		if err != nil {
-			return "", fmt.Errorf("invalid subject in refresh token: %w", err)
+	if _, err := uuid.Parse(claims.Subject); err != nil {
+		return nil, fmt.Errorf("invalid subject in refresh token: %w", err)
+	}
+
+	return claims, nil
+}
+
+func (s *JWTService) sign(claims JWTClaims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).
+		SignedString([]byte(s.config.Security.JWTSecretKey))
+}
+
+func (s *JWTService) validate(tokenString, tokenType string) (*JWTClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
+		return []byte(s.config.Security.JWTSecretKey), nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
 
-		// For refresh token, we need to get user details from database
-		// This will be implemented when we have the user repository
-		// For now, we'll return an error
-		return "", errors.New("user details required for token refresh")
+	if claims.TokenType != tokenType {
+		return nil, fmt.Errorf("unexpected token type: %s", claims.TokenType)
 	}
 
-	return "", errors.New("invalid refresh token")
+	return claims, nil
 }
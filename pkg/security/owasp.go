@@ -139,6 +139,7 @@ func RateLimitingMiddleware(requests int, window time.Duration) app.HandlerFunc
 
 	clients := make(map[string]*clientInfo)
 	var mu sync.RWMutex
+	lastSweep := time.Now()
 
 	return func(ctx context.Context, c *app.RequestContext) {
 		ip := c.ClientIP()
@@ -147,6 +148,17 @@ func RateLimitingMiddleware(requests int, window time.Duration) app.HandlerFunc
 		defer mu.Unlock()
 
 		now := time.Now()
+
+		// Forget clients whose window has passed so the map does not grow forever
+		if now.Sub(lastSweep) > window {
+			for key, info := range clients {
+				if now.Sub(info.window) > window {
+					delete(clients, key)
+				}
+			}
+			lastSweep = now
+		}
+
 		client, exists := clients[ip]
 
 		if !exists || now.Sub(client.window) > window {
@@ -0,0 +1,119 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // RFC 6238 authenticator apps use HMAC-SHA1
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP parameters compatible with common authenticator apps (RFC 6238)
+const (
+	TOTPDigits     = 6
+	TOTPPeriod     = 30 * time.Second
+	totpSecretSize = 20
+	// totpSkew accepts codes from one step before and after the current one
+	// to tolerate clock drift between server and device
+	totpSkew = 1
+
+	recoveryCodeBytes = 5
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a new random base32-encoded TOTP secret
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+	return base32NoPadding.EncodeToString(secret), nil
+}
+
+// TOTPProvisioningURI builds the otpauth:// URI rendered as a QR code by
+// authenticator apps
+func TOTPProvisioningURI(issuer, accountName, secret string) string {
+	label := url.PathEscape(issuer + ":" + accountName)
+
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", TOTPDigits))
+	params.Set("period", fmt.Sprintf("%d", int(TOTPPeriod.Seconds())))
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// ValidateTOTP checks a code against the secret at the given time and
+// returns the matched time step so callers can reject replays
+func ValidateTOTP(secret, code string, at time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	current := at.Unix() / int64(TOTPPeriod.Seconds())
+	for offset := int64(-totpSkew); offset <= totpSkew; offset++ {
+		step := current + offset
+		expected := totpCode(key, step)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// GenerateRecoveryCodes returns n random single-use recovery codes
+// formatted as xxxxxxxx-xxxxxxxx
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		raw := make([]byte, recoveryCodeBytes*2)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		encoded := strings.ToLower(base32NoPadding.EncodeToString(raw))
+		codes = append(codes, encoded[:8]+"-"+encoded[8:16])
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode strips formatting so codes can be typed with or
+// without the separator and in any case
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
+
+func totpCode(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step)) //nolint:gosec // time steps are positive
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226 section 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < TOTPDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", TOTPDigits, value%mod)
+}